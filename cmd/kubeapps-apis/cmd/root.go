@@ -5,8 +5,14 @@ package cmd
 
 import (
 	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/server"
@@ -32,11 +38,27 @@ func newRootCmd() *cobra.Command {
 
 The api service serves both gRPC and HTTP requests for the configured APIs.`,
 
-		PreRun: func(cmd *cobra.Command, args []string) {
-			log.InfoS("The component 'kubeapps-apis' has been configured with", "serverOptions", serveOpts)
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// The server does not start without the options of the config file,
+			// which may enable the security features.
+			if cfgFile != "" {
+				opts, err := loadServeOptions(cmd.Flags(), cfgFile)
+				if err != nil {
+					return fmt.Errorf("unable to apply the config file: %w", err)
+				}
+				serveOpts = opts
+			}
+			serveOpts.Version = version
+			log.InfoS("The component 'kubeapps-apis' has been configured with", "serverOptions", server.RedactedServeOptions(serveOpts))
+			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return server.Serve(serveOpts)
+			// The server is shut down gracefully when the pod is terminated.
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return server.Serve(ctx, serveOpts, func() (core.ServeOptions, error) {
+				return loadServeOptions(cmd.Flags(), cfgFile)
+			})
 		},
 		Version: "devel",
 	}
@@ -62,17 +84,106 @@ func init() {
 }
 
 func setFlags(c *cobra.Command) {
-	c.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
-	c.Flags().IntVar(&serveOpts.Port, "port", 50051, "The port on which to run this api server. Both gRPC and HTTP requests will be served on this port.")
-	c.Flags().StringSliceVar(&serveOpts.PluginDirs, "plugin-dir", []string{"."}, "A directory to be scanned for .so plugins. May be specified multiple times.")
-	c.Flags().StringVar(&serveOpts.ClustersConfigPath, "clusters-config-path", "", "Configuration for clusters")
-	c.Flags().StringVar(&serveOpts.PluginConfigPath, "plugin-config-path", "", "Configuration for plugins")
-	c.Flags().StringVar(&serveOpts.PinnipedProxyURL, "pinniped-proxy-url", "http://kubeapps-internal-pinniped-proxy.kubeapps:3333", "internal url to be used for requests to clusters configured for credential proxying via pinniped")
-	c.Flags().StringVar(&serveOpts.PinnipedProxyCACert, "pinniped-proxy-ca-cert", "", "Path to certificate authority to use with requests to pinniped-proxy service")
-	c.Flags().StringVar(&serveOpts.GlobalHelmReposNamespace, "global-repos-namespace", "kubeapps", "Namespace of global repositories for the helm plugin")
-	c.Flags().BoolVar(&serveOpts.UnsafeLocalDevKubeconfig, "unsafe-local-dev-kubeconfig", false, "if true, it will use the local kubeconfig at the KUBECONFIG env var instead of using the inCluster configuration.")
-	c.Flags().Float32Var(&serveOpts.QPS, "kube-api-qps", 10.0, "set Kubernetes API client QPS limit")
-	c.Flags().IntVar(&serveOpts.Burst, "kube-api-burst", 15, "set Kubernetes API client Burst limit")
+	c.PersistentFlags().StringVar(&cfgFile, "config", "", "Config file of the options, overridden by those set on the command line. On SIGHUP, the config file is reloaded and its log-verbosity, maintenance-mode and maintenance-write-methods are applied. The other options only take effect after a restart.")
+	addServeFlags(c.Flags(), &serveOpts)
+}
+
+// addServeFlags adds the flags of the serve options to the given flag set.
+func addServeFlags(flags *pflag.FlagSet, opts *core.ServeOptions) {
+	flags.IntVar(&opts.Port, "port", 50051, "The port on which to run this api server. Both gRPC and HTTP requests will be served on this port.")
+	flags.StringSliceVar(&opts.PluginDirs, "plugin-dir", []string{"."}, "A directory to be scanned for .so plugins. May be specified multiple times.")
+	flags.StringVar(&opts.ClustersConfigPath, "clusters-config-path", "", "Configuration for clusters")
+	flags.StringVar(&opts.DefaultNamespacePolicy, "default-namespace-policy", "", "Policy resolving the namespace of the requests omitting it: \"error\" rejects them, except the lists, \"default\" uses the --default-namespace and \"all\" all the namespaces, for the reads only, the writes using the --default-namespace, if any. The plugins handle the omitted namespaces themselves if empty.")
+	flags.StringVar(&opts.DefaultNamespace, "default-namespace", "", "Namespace of the requests omitting it, with the \"default\" namespace policy, or of the writes omitting it, with the \"all\" namespace policy.")
+	flags.IntVar(&opts.PluginRegistrationConcurrency, "plugin-registration-concurrency", 1, "Number of plugins registered concurrently on startup, so that a slow plugin does not delay the others. The plugins must then be safe to register concurrently, which the in-tree plugins are not known to be. 1 registers them one after the other.")
+	flags.StringVar(&opts.PackagesFixturePath, "packages-fixture-path", "", "JSON fixture file of the canned available packages served by the fixtures.packages plugin, for the end-to-end tests. Only allowed with --unsafe-local-dev-kubeconfig.")
+	flags.DurationVar(&opts.WaitForAPIServer, "wait-for-api-server", 0, "Maximum duration to wait on startup for the API server to be reachable before registering the plugins, failing to start after it. 0 disables the wait.")
+	flags.BoolVar(&opts.ValidateClusters, "validate-clusters", false, "Fail to start when the API server of a cluster of the clusters config is unreachable.")
+	flags.StringVar(&opts.PluginConfigPath, "plugin-config-path", "", "Configuration for plugins")
+	flags.StringVar(&opts.PinnipedProxyURL, "pinniped-proxy-url", "http://kubeapps-internal-pinniped-proxy.kubeapps:3333", "internal url to be used for requests to clusters configured for credential proxying via pinniped")
+	flags.StringVar(&opts.PinnipedProxyCACert, "pinniped-proxy-ca-cert", "", "Path to certificate authority to use with requests to pinniped-proxy service")
+	flags.StringVar(&opts.GlobalHelmReposNamespace, "global-repos-namespace", "kubeapps", "Namespace of global repositories for the helm plugin")
+	flags.BoolVar(&opts.UnsafeLocalDevKubeconfig, "unsafe-local-dev-kubeconfig", false, "if true, it will use the local kubeconfig at the KUBECONFIG env var instead of using the inCluster configuration.")
+	flags.Float32Var(&opts.QPS, "kube-api-qps", 10.0, "set Kubernetes API client QPS limit")
+	flags.IntVar(&opts.Burst, "kube-api-burst", 15, "set Kubernetes API client Burst limit")
+	flags.DurationVar(&opts.KubernetesClientTimeout, "kube-api-timeout", 30*time.Second, "Timeout of the requests of the in-cluster Kubernetes clients of the server, which use its service account. 0 disables it.")
+	flags.StringArrayVar(&opts.PluginGatewayAddrs, "plugin-gateway-addrs", nil, "Address dialed by the REST gateway for the requests of a plugin, rather than the listen address, in the form <plugin name>=<host>:<port>, such as fluxv2.packages=kubeapps-flux-plugin:50051. Can be repeated for several plugins.")
+	flags.StringArrayVar(&opts.PluginNamespaces, "plugin-namespaces", nil, "Restricts a plugin to the given namespaces, in the form <plugin name>=<namespace>[,<namespace>...], such as fluxv2.packages=team-a,team-b. Can be repeated for several plugins. Plugins not listed are enabled in all namespaces.")
+	flags.StringVar(&opts.AdminToken, "admin-token", "", "Bearer token required to use the admin endpoints under /admin/. The admin endpoints are disabled if empty.")
+	flags.StringVar(&opts.MetricsAuthToken, "metrics-auth-token", "", "Bearer token required to scrape the /metrics endpoint. The metrics are open to all if empty, unless --metrics-require-client-cert is set.")
+	flags.StringVar(&opts.ChannelzAddr, "channelz-addr", "", "Address, such as localhost:50052, of the unauthenticated gRPC server exposing the channelz service, to inspect the gRPC channels of the gateway with the gRPC debugging tools. Disabled if empty.")
+	flags.BoolVar(&opts.MetricsBuildInfo, "metrics-build-info", false, "Expose the kubeapps_apis_build_info gauge, labeled with the version and commit of the build, and the kubeapps_apis_up gauge on the /metrics endpoint.")
+	flags.BoolVar(&opts.MetricsRequireClientCert, "metrics-require-client-cert", false, "Serve the /metrics endpoint to the scrapers presenting a client certificate verified against the TLS client CA, as well as to those with the metrics auth token, if any.")
+	flags.IntVar(&opts.LogVerbosity, "log-verbosity", 3, "Verbosity of the logs. It is reloaded from the config file on SIGHUP.")
+	flags.DurationVar(&opts.LogLevelResetAfter, "log-level-reset-after", 15*time.Minute, "Duration after which a log verbosity changed with the /admin/loglevel endpoint is reset to its initial value.")
+	flags.BoolVar(&opts.MaintenanceMode, "maintenance-mode", false, "if true, the server starts in maintenance mode, rejecting write requests. It can be toggled at runtime with the /admin/maintenance endpoint.")
+	flags.StringSliceVar(&opts.MaintenanceWriteMethods, "maintenance-write-methods", []string{"Create", "Update", "Delete"}, "Prefixes of the names of the methods considered as writes, which are rejected in maintenance mode.")
+	flags.IntVar(&opts.PluginReadRetryMaxAttempts, "plugin-read-retry-max-attempts", 1, "Maximum number of attempts for read-only requests to packaging plugins. 1 disables retries.")
+	flags.DurationVar(&opts.PluginReadRetryInitialBackoff, "plugin-read-retry-initial-backoff", 100*time.Millisecond, "Backoff before the first retry of a read-only request to a packaging plugin")
+	flags.DurationVar(&opts.PluginReadRetryMaxBackoff, "plugin-read-retry-max-backoff", 1*time.Second, "Maximum backoff between retries of a read-only request to a packaging plugin")
+	flags.StringSliceVar(&opts.PluginReadRetryCodes, "plugin-read-retry-codes", []string{"unavailable"}, "Error codes for which read-only requests to packaging plugins are retried. May be specified multiple times.")
+	flags.Float64Var(&opts.PluginReadRetryBudgetMaxTokens, "plugin-read-retry-budget-max-tokens", 10, "Size of the global retry budget for read-only requests. Retries stop when less than half of the tokens remain.")
+	flags.Float64Var(&opts.PluginReadRetryBudgetTokenRatio, "plugin-read-retry-budget-token-ratio", 0.1, "Tokens added to the global retry budget for each successful read-only request")
+	flags.StringVar(&opts.AuditLogSink, "audit-log-sink", "", "Where to write the audit log of the mutating operations: \"stdout\" or the path of a file. The audit log is disabled when empty.")
+	flags.StringSliceVar(&opts.AuditLogMethods, "audit-log-methods", []string{"Create", "Update", "Delete", "Add", "Rollback"}, "Prefixes of the names of the methods considered as mutating operations in the audit log.")
+	flags.BoolVar(&opts.ImpersonateUsers, "impersonate-users", false, "Validate the bearer tokens of the requests with a TokenReview so that plugins can make their calls to the API server impersonating the caller.")
+	flags.IntVar(&opts.MaxReceiveMessageSize, "max-receive-message-size", 4*1024*1024, "Maximum size, in bytes, of the messages received by the server with any protocol, including gRPC-web. 0 disables the limit.")
+	flags.IntVar(&opts.GRPCCompressionLevel, "grpc-compression-level", -1, "Level of the gzip compression of the messages, from 1 (best speed) to 9 (best compression), 0 for none, -2 for Huffman only and -1 for the default level.")
+	flags.DurationVar(&opts.StartupWarningThreshold, "startup-warning-threshold", time.Minute, "Duration of the startup, until the plugins and core services are registered, after which a warning naming the current startup step is logged. 0 disables the warning.")
+	flags.BoolVar(&opts.StrictGatewayRoutes, "strict-gateway-routes", false, "Fail to start when a gateway route is registered more than once, such as by two plugins, rather than only logging an error.")
+	flags.BoolVar(&opts.RequireAtLeastOnePlugin, "require-at-least-one-plugin", false, "Fail to start when no plugin is registered from the plugin dirs, rather than serving an empty API.")
+	flags.BoolVar(&opts.ValidateOpenAPI, "validate-openapi", false, "Log the discrepancies between the registered services and the OpenAPI document on startup. Intended for development.")
+	flags.DurationVar(&opts.CacheFreshTTL, "cache-fresh-ttl", 0, "Duration during which the cached available package summaries are served as is. 0 disables the cache.")
+	flags.DurationVar(&opts.CacheMaxStale, "cache-max-stale", 0, "Duration, after --cache-fresh-ttl, during which the cached available package summaries are served while being refreshed in the background")
+	flags.BoolVar(&opts.PersistCache, "persist-cache", false, "Persist the cached available package summaries across restarts in the --cache-snapshot-path file, such as on a mounted volume, restoring them on startup.")
+	flags.StringVar(&opts.CacheSnapshotPath, "cache-snapshot-path", "/var/cache/kubeapps-apis/summaries.snapshot", "Path of the snapshot file of the cached available package summaries, used with --persist-cache")
+	flags.DurationVar(&opts.CacheSnapshotInterval, "cache-snapshot-interval", time.Minute, "Interval at which the snapshot of the cached available package summaries is saved, used with --persist-cache")
+	flags.BoolVar(&opts.PartialResults, "partial-results", false, "Return the results of the other plugins with warnings, rather than an error, when some plugins fail during aggregated reads.")
+	flags.BoolVar(&opts.EnableRESTGateway, "enable-rest-gateway", true, "Serve the REST API with the gateway. When false, the API is only served with the gRPC, gRPC-web and connect protocols.")
+	flags.DurationVar(&opts.ShutdownGracePeriod, "shutdown-grace-period", 30*time.Second, "Duration during which the in-flight requests are drained on shutdown, after the server stops accepting connections.")
+	flags.DurationVar(&opts.ShutdownHardTimeout, "shutdown-hard-timeout", time.Minute, "Duration, from the start of the shutdown, after which the connections with requests still in flight are force-closed.")
+	flags.DurationVar(&opts.RequestTimeout, "request-timeout", 0, "Default timeout of the unary requests. 0 disables it.")
+	flags.StringArrayVar(&opts.PluginTimeouts, "plugin-timeouts", nil, "Timeout of the requests of a method or plugin, overriding the default request timeout, in the form <method name or plugin name>=<duration>, such as fluxv2.packages=2m. Can be repeated. Also applies to the streaming requests.")
+	flags.StringArrayVar(&opts.DisabledMethods, "disabled-methods", nil, "Full name of a method disabled on this server, whose requests fail with an Unimplemented error, such as /kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage. Can be repeated.")
+	flags.StringVar(&opts.MinAPIVersion, "min-api-version", "", "Minimum API version, such as 2.7.0, of the clients sending the X-Kubeapps-Api-Version header. The requests of the older clients fail with a FailedPrecondition error. No minimum if empty.")
+	flags.BoolVar(&opts.RequireAuth, "require-auth", false, "Fail the requests without a bearer token with an Unauthenticated error before they reach the plugins, except for those of the public methods.")
+	flags.StringArrayVar(&opts.PublicMethods, "public-methods", core.DefaultPublicMethods, "Full name of a method which does not require a bearer token with --require-auth, such as /kubeappsapis.core.plugins.v1alpha1.PluginsService/GetConfiguredPlugins. Can be repeated. Defaults to the health checks and GetConfiguredPlugins.")
+	flags.StringVar(&opts.MaxAPIVersion, "max-api-version", "", "Maximum API version, such as 2.9.0, of the clients sending the X-Kubeapps-Api-Version header. The requests of the newer clients fail with a FailedPrecondition error. No maximum if empty.")
+	flags.IntVar(&opts.DailyQuota, "daily-quota", 0, "Maximum number of requests of the --daily-quota-methods per user and per day (UTC), the callers whose identity is not known sharing the same quota. Requires --impersonate-users or --tls-client-ca-file. 0 disables the quota.")
+	flags.StringSliceVar(&opts.DailyQuotaMethods, "daily-quota-methods", []string{"CreateInstalledPackage"}, "Prefixes of the names of the methods subject to the daily quota.")
+	flags.StringVar(&opts.DailyQuotaRedisAddr, "daily-quota-redis-addr", "", "Address of the Redis server counting the requests subject to the daily quota for all the replicas, with the password of the REDIS_PASSWORD environment variable. If empty, the requests are counted in memory by each replica.")
+	flags.BoolVar(&opts.DailyQuotaFailOpen, "daily-quota-fail-open", false, "If true, the requests subject to the daily quota are let through when they cannot be counted, such as while Redis is down, rather than failed as unavailable. Either way, they are counted by the kubeapps_apis_daily_quota_store_errors_total metric.")
+	flags.DurationVar(&opts.OperatorLogoCacheTTL, "operator-logo-cache-ttl", 10*time.Minute, "Duration during which the operator icons proxied to the API server are cached. 0 disables the cache.")
+	flags.Float64Var(&opts.OperatorLogoQPS, "operator-logo-qps", 5, "Maximum number of API server calls per second made by the operator logo proxy, whose requests are throttled beyond it. 0 disables the limit.")
+	flags.IntVar(&opts.OperatorLogoBurst, "operator-logo-burst", 10, "Maximum burst of API server calls made by the operator logo proxy")
+	flags.StringArrayVar(&opts.CacheControl, "cache-control", nil, "Cache-Control header of the gateway responses of the methods with the given name prefix, in the form <method name prefix>=<directives>, such as \"GetAvailablePackageVersions=public, max-age=60\". Can be repeated. The responses of the other methods are not cached (no-store).")
+	flags.StringSliceVar(&opts.PluginPriority, "plugin-priority", []string{}, "Names of the plugins, from the highest priority, such as \"helm.packages,fluxv2.packages\". Only the available package of the plugin of highest priority is returned when several plugins have a package with the same name.")
+	flags.StringVar(&opts.TLSCertFile, "tls-cert-file", "", "Path to the TLS certificate used to serve the API. If empty, the API is served without TLS.")
+	flags.StringVar(&opts.TLSKeyFile, "tls-key-file", "", "Path to the private key of the TLS certificate used to serve the API")
+	flags.StringVar(&opts.TLSMinVersion, "tls-min-version", "1.2", "Minimum TLS version accepted when serving over TLS and used by the gateway, one of 1.2 or 1.3.")
+	flags.StringSliceVar(&opts.TLSCipherSuites, "tls-cipher-suites", nil, "Comma-separated allowlist of the TLS cipher suites, such as TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. If empty, the secure cipher suites of Go are used.")
+	flags.StringVar(&opts.TLSClientCAFile, "tls-client-ca-file", "", "Path to a CA certificate used to verify client certificates (mTLS). Requires --tls-cert-file.")
+	flags.StringVar(&opts.GatewayTokenFile, "gateway-token-file", "", "Path to the file of a bearer token attached by the gateway, as the x-gateway-authorization metadata, to the calls it proxies. The file is read for each call so that the token can be rotated.")
+	flags.StringArrayVar(&opts.GatewayExposedMethods, "gateway-exposed-methods", nil, "Full name of a method reachable through the REST gateway, such as /kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries, or /<service full name>/* for all the methods of a service. Can be repeated. If not set, all the methods are exposed.")
+	flags.BoolVar(&opts.EnableLeaderElection, "enable-leader-election", false, "if true, only the replica elected as leader through a Kubernetes lease will run the watch-heavy background work of the plugins.")
+	flags.StringVar(&opts.LeaderElectionNamespace, "leader-election-namespace", "", "Namespace of the leader election lease. Defaults to the namespace of the pod.")
+	flags.StringVar(&opts.LeaderElectionLeaseName, "leader-election-lease-name", "kubeapps-apis", "Name of the leader election lease")
+	flags.StringArrayVar(&opts.RedactPatterns, "redact-patterns", core.DefaultRedactPatterns, "Regular expression whose matches are redacted from the logs and the audit records, or only its submatches if it has groups. Can be repeated. Defaults to the credentials embedded in the URLs.")
+	flags.StringVar(&opts.RequestLogMessageFormat, "request-log-message-format", "json", "Encoding of the request messages logged at the verbosity 5 for debugging, with their sensitive fields redacted: json or prototext.")
+	flags.StringArrayVar(&opts.PluginLogLevels, "plugin-log-levels", nil, "Log verbosity of a plugin, overriding the global verbosity for the requests it handles, in the form <plugin name>=<level>, such as helm.packages=4. Can be repeated.")
+	flags.BoolVar(&opts.LogRequestClientIPs, "log-request-client-ips", true, "if true, the peer address and the client IP address of each request will be logged.")
+	flags.IntVar(&opts.LogSampleRate, "log-sample-rate", 1, "Log only 1 in N successful calls of each method. The failed calls and the slow ones are always logged. 0 or 1 logs every call.")
+	flags.DurationVar(&opts.LogSlowRequestThreshold, "log-slow-request-threshold", time.Second, "Duration from which the successful calls are always logged, regardless of --log-sample-rate. 0 disables it.")
+	flags.StringSliceVar(&opts.TrustedProxies, "trusted-proxies", []string{}, "CIDRs of the proxies in front of the server trusted to set the X-Forwarded-* headers, which resolve the client IP address and the external URL of the server. By default, the address of the direct peer is used.")
+	flags.IntVar(&opts.MaxProcs, "max-procs", 0, "GOMAXPROCS of the server. 0 sets it from the CPU quota of the container, unless the GOMAXPROCS environment variable is set.")
+	flags.DurationVar(&opts.ConnectionIdleTimeout, "connection-idle-timeout", 2*time.Minute, "Duration after which idle connections, including new connections on which the client sends nothing, are closed. 0 disables the timeout.")
+	flags.IntVar(&opts.ConnectionLogVerbosity, "connection-log-verbosity", 4, "Log verbosity at which the opening and closing of the connections are logged, with the address of their peer.")
+	flags.IntVar(&opts.MaxHeaderBytes, "max-header-bytes", 0, "Maximum size, in bytes, of the request headers, such as the forwarded repository credentials. 0 uses the default of 1MB.")
+	flags.Int32Var(&opts.HTTP2InitialWindowSize, "http2-initial-window-size", 0, "Initial HTTP/2 flow-control window, in bytes, of each stream, of at least 65535. Larger windows improve the throughput over high-latency links. 0 uses the default.")
+	flags.Int32Var(&opts.HTTP2InitialConnWindowSize, "http2-initial-conn-window-size", 0, "Initial HTTP/2 flow-control window, in bytes, of each connection, of at least 65535. 0 uses the default.")
+	flags.BoolVar(&opts.JSONUseProtoNames, "json-use-proto-names", false, "if true, the gateway will use the original proto field names (snake_case) instead of lowerCamelCase in JSON responses.")
+	flags.IntVar(&opts.MaxConcurrentMarshals, "max-concurrent-marshals", 0, "Maximum number of REST gateway responses marshaled concurrently, the others waiting for their turn, so that the memory used by the serialization of large responses is bounded. 0 disables the limit.")
+	flags.StringArrayVar(&opts.PluginJSONOptions, "plugin-json-options", nil, "JSON marshaling options of the gateway responses of a plugin, overriding the global ones, in the form <plugin name>=<option>[,<option>...], such as fluxv2.packages=use-proto-names,emit-unpopulated. The options are use-proto-names, use-camel-case, emit-unpopulated and use-enum-numbers. Can be repeated.")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -89,3 +200,52 @@ func initConfig() {
 		log.Errorf("Using config file: %v", viper.ConfigFileUsed())
 	}
 }
+
+// loadServeOptions returns the serve options resulting from the values of the
+// config file, if any, overridden by the flags set on the command line. Keys of
+// the config file are the names of the flags, such as "maintenance-mode".
+func loadServeOptions(cmdFlags *pflag.FlagSet, configFile string) (core.ServeOptions, error) {
+	opts := core.ServeOptions{}
+	flags := pflag.NewFlagSet("kubeapps-apis", pflag.ContinueOnError)
+	addServeFlags(flags, &opts)
+
+	if configFile != "" {
+		config := viper.New()
+		config.SetConfigFile(configFile)
+		if err := config.ReadInConfig(); err != nil {
+			return opts, fmt.Errorf("unable to read the config file %q: %w", configFile, err)
+		}
+		var err error
+		flags.VisitAll(func(f *pflag.Flag) {
+			if err != nil || !config.IsSet(f.Name) {
+				return
+			}
+			if slice, ok := f.Value.(pflag.SliceValue); ok {
+				err = slice.Replace(config.GetStringSlice(f.Name))
+			} else {
+				err = f.Value.Set(config.GetString(f.Name))
+			}
+			if err != nil {
+				err = fmt.Errorf("invalid value for %q in the config file %q: %w", f.Name, configFile, err)
+			}
+		})
+		if err != nil {
+			return opts, err
+		}
+	}
+
+	var err error
+	cmdFlags.Visit(func(f *pflag.Flag) {
+		target := flags.Lookup(f.Name)
+		if err != nil || target == nil {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			err = target.Value.(pflag.SliceValue).Replace(slice.GetSlice())
+		} else {
+			err = target.Value.Set(f.Value.String())
+		}
+	})
+	opts.Version = version
+	return opts, err
+}
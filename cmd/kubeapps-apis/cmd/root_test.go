@@ -5,14 +5,25 @@ package cmd
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	log "k8s.io/klog/v2"
 )
 
 func TestParseFlagsCorrect(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("port: 900\n"), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
 	var tests = []struct {
 		name        string
 		args        []string
@@ -22,10 +33,16 @@ func TestParseFlagsCorrect(t *testing.T) {
 		{
 			"all arguments are captured",
 			[]string{
-				"--config", "file",
+				"--config", configFile,
 				"--port", "901",
 				"--plugin-dir", "foo01",
 				"--clusters-config-path", "foo02",
+				"--validate-clusters", "true",
+				"--wait-for-api-server", "30s",
+				"--plugin-registration-concurrency", "2",
+				"--default-namespace-policy", "default",
+				"--default-namespace", "foo14",
+				"--packages-fixture-path", "/fixtures/packages.json",
 				"--pinniped-proxy-url", "foo03",
 				"--pinniped-proxy-ca-cert", "foo06",
 				"--global-repos-namespace", "kubeapps-global",
@@ -33,18 +50,180 @@ func TestParseFlagsCorrect(t *testing.T) {
 				"--plugin-config-path", "foo05",
 				"--kube-api-qps", "1.0",
 				"--kube-api-burst", "1",
+				"--kube-api-timeout", "5s",
+				"--json-use-proto-names", "true",
+				"--plugin-json-options", "fluxv2.packages=use-camel-case",
+				"--max-concurrent-marshals", "4",
+				"--log-request-client-ips=false",
+				"--request-log-message-format", "prototext",
+				"--redact-patterns", "token=(\\w+)",
+				"--log-sample-rate", "10",
+				"--log-slow-request-threshold", "2s",
+				"--plugin-log-levels", "helm.packages=4",
+				"--trusted-proxies", "10.0.0.0/8,192.168.1.1",
+				"--max-procs", "2",
+				"--plugin-priority", "helm.packages,fluxv2.packages",
+				"--enable-rest-gateway=false",
+				"--shutdown-grace-period", "10s",
+				"--shutdown-hard-timeout", "20s",
+				"--request-timeout", "30s",
+				"--plugin-timeouts", "fluxv2.packages=2m",
+				"--disabled-methods", "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage",
+				"--min-api-version", "2.7.0",
+				"--max-api-version", "2.9.0",
+				"--require-auth", "true",
+				"--public-methods", "/grpc.health.v1.Health/Check",
+				"--daily-quota", "10",
+				"--daily-quota-methods", "CreateInstalledPackage,UpdateInstalledPackage",
+				"--daily-quota-redis-addr", "redis:6379",
+				"--daily-quota-fail-open",
+				"--cache-control", "GetAvailablePackageVersions=public, max-age=60",
+				"--operator-logo-cache-ttl", "1h",
+				"--operator-logo-qps", "2.5",
+				"--operator-logo-burst", "4",
+				"--connection-idle-timeout", "30s",
+				"--connection-log-verbosity", "2",
+				"--max-header-bytes", "65536",
+				"--http2-initial-window-size", "1048576",
+				"--http2-initial-conn-window-size", "4194304",
+				"--require-at-least-one-plugin", "true",
+				"--strict-gateway-routes", "true",
+				"--startup-warning-threshold", "2m",
+				"--max-receive-message-size", "1024",
+				"--grpc-compression-level", "1",
+				"--tls-cert-file", "foo07",
+				"--tls-key-file", "foo08",
+				"--tls-client-ca-file", "foo09",
+				"--tls-min-version", "1.3",
+				"--tls-cipher-suites", "TLS_AES_128_GCM_SHA256,TLS_AES_256_GCM_SHA384",
+				"--gateway-token-file", "foo10",
+				"--gateway-exposed-methods", "/kubeappsapis.core.packages.v1alpha1.PackagesService/*",
+				"--enable-leader-election", "true",
+				"--leader-election-namespace", "foo10",
+				"--leader-election-lease-name", "foo11",
+				"--plugin-namespaces", "fluxv2.packages=foo13,foo14",
+				"--plugin-gateway-addrs", "fluxv2.packages=foo15:50051",
+				"--admin-token", "foo12",
+				"--metrics-auth-token", "foo13",
+				"--metrics-build-info",
+				"--channelz-addr", "localhost:50052",
+				"--metrics-require-client-cert",
+				"--log-verbosity", "4",
+				"--log-level-reset-after", "5m",
+				"--maintenance-mode=true",
+				"--maintenance-write-methods", "Create,Delete",
+				"--plugin-read-retry-max-attempts", "3",
+				"--plugin-read-retry-initial-backoff", "50ms",
+				"--plugin-read-retry-max-backoff", "2s",
+				"--plugin-read-retry-codes", "unavailable,deadline_exceeded",
+				"--plugin-read-retry-budget-max-tokens", "20",
+				"--plugin-read-retry-budget-token-ratio", "0.5",
+				"--cache-fresh-ttl", "30s",
+				"--cache-max-stale", "5m",
+				"--persist-cache", "true",
+				"--cache-snapshot-path", "foo16",
+				"--cache-snapshot-interval", "30s",
+				"--partial-results", "true",
+				"--validate-openapi", "true",
+				"--impersonate-users", "true",
+				"--audit-log-sink", "stdout",
+				"--audit-log-methods", "Create,Delete",
 			},
 			core.ServeOptions{
-				Port:                     901,
-				PluginDirs:               []string{"foo01"},
-				ClustersConfigPath:       "foo02",
-				PinnipedProxyURL:         "foo03",
-				PinnipedProxyCACert:      "foo06",
-				UnsafeLocalDevKubeconfig: true,
-				GlobalHelmReposNamespace: "kubeapps-global",
-				PluginConfigPath:         "foo05",
-				QPS:                      1.0,
-				Burst:                    1,
+				Port:                            901,
+				PluginDirs:                      []string{"foo01"},
+				ClustersConfigPath:              "foo02",
+				ValidateClusters:                true,
+				WaitForAPIServer:                30 * time.Second,
+				PluginRegistrationConcurrency:   2,
+				DefaultNamespacePolicy:          "default",
+				DefaultNamespace:                "foo14",
+				PackagesFixturePath:             "/fixtures/packages.json",
+				PinnipedProxyURL:                "foo03",
+				PinnipedProxyCACert:             "foo06",
+				UnsafeLocalDevKubeconfig:        true,
+				GlobalHelmReposNamespace:        "kubeapps-global",
+				PluginConfigPath:                "foo05",
+				QPS:                             1.0,
+				Burst:                           1,
+				KubernetesClientTimeout:         5 * time.Second,
+				JSONUseProtoNames:               true,
+				PluginJSONOptions:               []string{"fluxv2.packages=use-camel-case"},
+				MaxConcurrentMarshals:           4,
+				LogRequestClientIPs:             false,
+				RequestLogMessageFormat:         "prototext",
+				RedactPatterns:                  []string{"token=(\\w+)"},
+				LogSampleRate:                   10,
+				LogSlowRequestThreshold:         2 * time.Second,
+				PluginLogLevels:                 []string{"helm.packages=4"},
+				TrustedProxies:                  []string{"10.0.0.0/8", "192.168.1.1"},
+				MaxProcs:                        2,
+				ShutdownGracePeriod:             10 * time.Second,
+				ShutdownHardTimeout:             20 * time.Second,
+				RequestTimeout:                  30 * time.Second,
+				PluginTimeouts:                  []string{"fluxv2.packages=2m"},
+				DisabledMethods:                 []string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage"},
+				MinAPIVersion:                   "2.7.0",
+				MaxAPIVersion:                   "2.9.0",
+				RequireAuth:                     true,
+				PublicMethods:                   []string{"/grpc.health.v1.Health/Check"},
+				DailyQuota:                      10,
+				DailyQuotaMethods:               []string{"CreateInstalledPackage", "UpdateInstalledPackage"},
+				DailyQuotaRedisAddr:             "redis:6379",
+				DailyQuotaFailOpen:              true,
+				CacheControl:                    []string{"GetAvailablePackageVersions=public, max-age=60"},
+				OperatorLogoCacheTTL:            time.Hour,
+				OperatorLogoQPS:                 2.5,
+				OperatorLogoBurst:               4,
+				PluginPriority:                  []string{"helm.packages", "fluxv2.packages"},
+				ConnectionIdleTimeout:           30 * time.Second,
+				ConnectionLogVerbosity:          2,
+				MaxHeaderBytes:                  65536,
+				HTTP2InitialWindowSize:          1048576,
+				HTTP2InitialConnWindowSize:      4194304,
+				RequireAtLeastOnePlugin:         true,
+				StrictGatewayRoutes:             true,
+				StartupWarningThreshold:         2 * time.Minute,
+				MaxReceiveMessageSize:           1024,
+				GRPCCompressionLevel:            1,
+				TLSCertFile:                     "foo07",
+				TLSKeyFile:                      "foo08",
+				TLSClientCAFile:                 "foo09",
+				TLSMinVersion:                   "1.3",
+				TLSCipherSuites:                 []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"},
+				GatewayTokenFile:                "foo10",
+				GatewayExposedMethods:           []string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/*"},
+				EnableLeaderElection:            true,
+				LeaderElectionNamespace:         "foo10",
+				LeaderElectionLeaseName:         "foo11",
+				PluginNamespaces:                []string{"fluxv2.packages=foo13,foo14"},
+				PluginGatewayAddrs:              []string{"fluxv2.packages=foo15:50051"},
+				AdminToken:                      "foo12",
+				MetricsAuthToken:                "foo13",
+				MetricsRequireClientCert:        true,
+				MetricsBuildInfo:                true,
+				ChannelzAddr:                    "localhost:50052",
+				Version:                         "devel",
+				LogVerbosity:                    4,
+				LogLevelResetAfter:              5 * time.Minute,
+				MaintenanceMode:                 true,
+				MaintenanceWriteMethods:         []string{"Create", "Delete"},
+				PluginReadRetryMaxAttempts:      3,
+				PluginReadRetryInitialBackoff:   50 * time.Millisecond,
+				PluginReadRetryMaxBackoff:       2 * time.Second,
+				PluginReadRetryCodes:            []string{"unavailable", "deadline_exceeded"},
+				PluginReadRetryBudgetMaxTokens:  20,
+				PluginReadRetryBudgetTokenRatio: 0.5,
+				CacheFreshTTL:                   30 * time.Second,
+				CacheMaxStale:                   5 * time.Minute,
+				PersistCache:                    true,
+				CacheSnapshotPath:               "foo16",
+				CacheSnapshotInterval:           30 * time.Second,
+				PartialResults:                  true,
+				ValidateOpenAPI:                 true,
+				ImpersonateUsers:                true,
+				AuditLogSink:                    "stdout",
+				AuditLogMethods:                 []string{"Create", "Delete"},
 			},
 			true,
 		},
@@ -72,3 +251,111 @@ func TestParseFlagsCorrect(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadServeOptions(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	config := `
+port: 902
+maintenance-mode: true
+maintenance-write-methods:
+  - Create
+  - Rollback
+log-verbosity: 5
+`
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	cmd := newRootCmd()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addServeFlags(flags, &core.ServeOptions{})
+	cmd.Flags().AddFlagSet(flags)
+	if err := cmd.Flags().Parse([]string{"--port", "901", "--admin-token", "foo"}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	opts, err := loadServeOptions(cmd.Flags(), configFile)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// The flags set on the command line override the config file.
+	if got, want := opts.Port, 901; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	if got, want := opts.AdminToken, "foo"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := opts.MaintenanceMode, true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+	if got, want := opts.MaintenanceWriteMethods, []string{"Create", "Rollback"}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if got, want := opts.LogVerbosity, 5; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	// The options set neither on the command line nor in the config file keep
+	// their default.
+	if got, want := opts.EnableRESTGateway, true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+	// Options set neither in the config file nor on the command line keep their default.
+	if got, want := opts.LeaderElectionLeaseName, "kubeapps-apis"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestLoadServeOptionsFailsForInvalidConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("port: foo\n"), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := loadServeOptions(pflag.NewFlagSet("test", pflag.ContinueOnError), configFile); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+	if _, err := loadServeOptions(pflag.NewFlagSet("test", pflag.ContinueOnError), filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+}
+
+func TestPreRunRedactsTheLoggedOptions(t *testing.T) {
+	logs := &bytes.Buffer{}
+	log.LogToStderr(false)
+	log.SetOutput(logs)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.LogToStderr(true)
+	}()
+
+	cmd := newRootCmd()
+	setFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--admin-token", "admin-secret", "--metrics-auth-token", "metrics-secret"}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := cmd.PreRunE(cmd, nil); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	log.Flush()
+
+	if !strings.Contains(logs.String(), "has been configured with") {
+		t.Fatalf("got: %q, want the options to be logged", logs.String())
+	}
+	for _, secret := range []string{"admin-secret", "metrics-secret"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("got: %q, want %q to be redacted", logs.String(), secret)
+		}
+	}
+}
+
+func TestPreRunFailsForInvalidConfig(t *testing.T) {
+	cmd := newRootCmd()
+	setFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer func() { cfgFile = "" }()
+	if err := cmd.PreRunE(cmd, nil); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+}
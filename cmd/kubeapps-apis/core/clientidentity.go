@@ -0,0 +1,65 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// ClientCertIdentity is the identity presented by a client in a verified TLS
+// client certificate.
+type ClientCertIdentity struct {
+	CommonName     string
+	DNSNames       []string
+	EmailAddresses []string
+	URIs           []string
+}
+
+// String returns a compact representation of the identity, suitable for logging.
+func (id *ClientCertIdentity) String() string {
+	if id == nil {
+		return ""
+	}
+	sans := append(append(append([]string{}, id.DNSNames...), id.EmailAddresses...), id.URIs...)
+	if len(sans) == 0 {
+		return fmt.Sprintf("CN=%s", id.CommonName)
+	}
+	return fmt.Sprintf("CN=%s SAN=%s", id.CommonName, strings.Join(sans, ","))
+}
+
+type clientCertIdentityKey struct{}
+
+// ClientCertIdentityFromTLS returns the identity of the verified client certificate
+// of the given connection state, or nil if the client did not present a
+// certificate that was verified against the configured client CA.
+func ClientCertIdentityFromTLS(state *tls.ConnectionState) *ClientCertIdentity {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cert := state.VerifiedChains[0][0]
+	id := &ClientCertIdentity{
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+	}
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+	return id
+}
+
+// ContextWithClientCertIdentity returns a copy of ctx carrying the given identity.
+func ContextWithClientCertIdentity(ctx context.Context, id *ClientCertIdentity) context.Context {
+	return context.WithValue(ctx, clientCertIdentityKey{}, id)
+}
+
+// ClientCertIdentityFromContext returns the client certificate identity of the
+// request, if the request was received over mTLS with a verified client certificate.
+func ClientCertIdentityFromContext(ctx context.Context) (*ClientCertIdentity, bool) {
+	id, ok := ctx.Value(clientCertIdentityKey{}).(*ClientCertIdentity)
+	return id, ok && id != nil
+}
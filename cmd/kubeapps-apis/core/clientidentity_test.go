@@ -0,0 +1,76 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientCertIdentityFromTLS(t *testing.T) {
+	spiffeURI, err := url.Parse("spiffe://cluster.local/ns/kubeapps/sa/client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "client"},
+		DNSNames:       []string{"client.kubeapps.svc"},
+		EmailAddresses: []string{"client@example.com"},
+		URIs:           []*url.URL{spiffeURI},
+	}
+
+	testCases := []struct {
+		name     string
+		state    *tls.ConnectionState
+		expected *ClientCertIdentity
+	}{
+		{
+			name:     "no TLS connection",
+			state:    nil,
+			expected: nil,
+		},
+		{
+			name:     "TLS connection without a verified client certificate",
+			state:    &tls.ConnectionState{PeerCertificates: []*x509.Certificate{clientCert}},
+			expected: nil,
+		},
+		{
+			name: "TLS connection with a verified client certificate",
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{clientCert},
+				VerifiedChains:   [][]*x509.Certificate{{clientCert}},
+			},
+			expected: &ClientCertIdentity{
+				CommonName:     "client",
+				DNSNames:       []string{"client.kubeapps.svc"},
+				EmailAddresses: []string{"client@example.com"},
+				URIs:           []string{"spiffe://cluster.local/ns/kubeapps/sa/client"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := ClientCertIdentityFromTLS(tc.state)
+			if got, want := id, tc.expected; !cmp.Equal(want, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+
+			ctx := ContextWithClientCertIdentity(context.Background(), id)
+			got, ok := ClientCertIdentityFromContext(ctx)
+			if want := tc.expected != nil; ok != want {
+				t.Fatalf("got: %t, want: %t", ok, want)
+			}
+			if !cmp.Equal(tc.expected, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(tc.expected, got))
+			}
+		})
+	}
+}
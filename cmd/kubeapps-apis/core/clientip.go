@@ -0,0 +1,94 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// TrustedProxies are the networks of the proxies in front of the server, whose
+// X-Forwarded-For entries can be trusted to resolve the client IP address.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses the trusted proxies from CIDRs, such as "10.0.0.0/8",
+// or from single IP addresses.
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	proxies := TrustedProxies{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q, expected a CIDR or an IP address", value)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, expected a CIDR or an IP address: %w", value, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// isTrusted returns whether the address belongs to a trusted proxy. The loopback
+// addresses are not trusted unless configured, since any process of the pod can
+// connect over the loopback interface.
+func (tp TrustedProxies) isTrusted(ip net.IP) bool {
+	for _, network := range tp {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrustedPeer returns whether the peer, such as "10.0.0.1:54321", is a trusted
+// proxy, so that the X-Forwarded-* headers it sets can be trusted.
+func (tp TrustedProxies) IsTrustedPeer(peerAddr string) bool {
+	peerIP := peerAddr
+	if host, _, err := net.SplitHostPort(peerAddr); err == nil {
+		peerIP = host
+	}
+	ip := net.ParseIP(peerIP)
+	return ip != nil && tp.isTrusted(ip)
+}
+
+// ClientIP returns the IP address of the client which originated the request.
+// The X-Forwarded-For entries, followed by the address of the peer, are walked
+// from the closest hop, and the first address which is not a trusted proxy is
+// returned, since any entry before it could have been forged by the client.
+// Without trusted proxies, this is the address of the peer.
+func (tp TrustedProxies) ClientIP(peerAddr string, forwardedFor []string) string {
+	peerIP := peerAddr
+	if host, _, err := net.SplitHostPort(peerAddr); err == nil {
+		peerIP = host
+	}
+
+	addrs := []string{}
+	for _, value := range forwardedFor {
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	addrs = append(addrs, peerIP)
+
+	for i := len(addrs) - 1; i > 0; i-- {
+		ip := net.ParseIP(addrs[i])
+		if ip == nil || !tp.isTrusted(ip) {
+			return addrs[i]
+		}
+	}
+	return addrs[0]
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		peerAddr       string
+		forwardedFor   []string
+		expectedIP     string
+	}{
+		{
+			name:       "returns the peer IP without forwarded addresses",
+			peerAddr:   "10.0.0.1:51234",
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:         "ignores the forwarded addresses without trusted proxies",
+			peerAddr:     "10.0.0.1:51234",
+			forwardedFor: []string{"1.2.3.4"},
+			expectedIP:   "10.0.0.1",
+		},
+		{
+			name:           "skips the trusted proxies",
+			trustedProxies: []string{"10.0.0.0/8"},
+			peerAddr:       "10.0.0.1:51234",
+			forwardedFor:   []string{"6.6.6.6, 1.2.3.4, 10.0.0.2"},
+			expectedIP:     "1.2.3.4",
+		},
+		{
+			name:           "trusts single IP addresses",
+			trustedProxies: []string{"10.0.0.1"},
+			peerAddr:       "10.0.0.1:51234",
+			forwardedFor:   []string{"1.2.3.4, 10.0.0.2"},
+			expectedIP:     "10.0.0.2",
+		},
+		{
+			name:         "does not trust a loopback peer without trusted proxies",
+			peerAddr:     "127.0.0.1:51234",
+			forwardedFor: []string{"6.6.6.6, 10.0.0.1"},
+			expectedIP:   "127.0.0.1",
+		},
+		{
+			name:           "does not trust an IPv6 loopback peer which is not a trusted proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			peerAddr:       "[::1]:51234",
+			forwardedFor:   []string{"6.6.6.6", "1.2.3.4, 10.0.0.1"},
+			expectedIP:     "::1",
+		},
+		{
+			name:           "returns the first address when every hop is trusted",
+			trustedProxies: []string{"0.0.0.0/0"},
+			peerAddr:       "10.0.0.1:51234",
+			forwardedFor:   []string{"1.2.3.4"},
+			expectedIP:     "1.2.3.4",
+		},
+		{
+			name:           "stops at a forwarded entry which is not an IP address",
+			trustedProxies: []string{"10.0.0.0/8"},
+			peerAddr:       "10.0.0.1:51234",
+			forwardedFor:   []string{"1.2.3.4, unknown"},
+			expectedIP:     "unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trustedProxies, err := ParseTrustedProxies(tc.trustedProxies)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := trustedProxies.ClientIP(tc.peerAddr, tc.forwardedFor), tc.expectedIP; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesFailsForInvalidValues(t *testing.T) {
+	for _, value := range []string{"10.0.0.0/33", "proxy.example.com"} {
+		if _, err := ParseTrustedProxies([]string{value}); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestIsTrustedPeer(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	testCases := []struct {
+		peerAddr string
+		expected bool
+	}{
+		{"10.1.2.3:54321", true},
+		{"127.0.0.1:54321", false},
+		{"192.168.1.1:54321", false},
+		{"not-an-ip", false},
+	}
+	for _, tc := range testCases {
+		if got, want := trustedProxies.IsTrustedPeer(tc.peerAddr), tc.expected; got != want {
+			t.Errorf("%q: got: %t, want: %t", tc.peerAddr, got, want)
+		}
+	}
+}
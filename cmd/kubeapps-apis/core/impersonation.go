@@ -0,0 +1,56 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bufbuild/connect-go"
+	"k8s.io/client-go/rest"
+)
+
+// CallerIdentity is the identity of the caller of a request, as authenticated
+// by the Kubernetes API server when reviewing the bearer token of the request.
+type CallerIdentity struct {
+	Username string
+	UID      string
+	Groups   []string
+	Extra    map[string][]string
+}
+
+type callerIdentityKey struct{}
+
+// ContextWithCallerIdentity returns a copy of ctx carrying the given identity.
+// It must only be used with identities validated by the API server.
+func ContextWithCallerIdentity(ctx context.Context, id *CallerIdentity) context.Context {
+	return context.WithValue(ctx, callerIdentityKey{}, id)
+}
+
+// CallerIdentityFromContext returns the validated identity of the caller of the
+// request, which is only available when the impersonation of users is enabled.
+func CallerIdentityFromContext(ctx context.Context) (*CallerIdentity, bool) {
+	id, ok := ctx.Value(callerIdentityKey{}).(*CallerIdentity)
+	return id, ok && id != nil && id.Username != ""
+}
+
+// ImpersonatingConfig returns a copy of the given config, usually the config
+// of the service account, which impersonates the caller of the request so that
+// the API server audits the calls as made by the caller. It fails unless the
+// identity of the caller was validated, so that a forged identity can never be
+// impersonated with the privileges of the service account.
+func ImpersonatingConfig(ctx context.Context, config *rest.Config) (*rest.Config, error) {
+	id, ok := CallerIdentityFromContext(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("Unable to impersonate the caller without a validated identity"))
+	}
+	impersonatingConfig := rest.CopyConfig(config)
+	impersonatingConfig.Impersonate = rest.ImpersonationConfig{
+		UserName: id.Username,
+		UID:      id.UID,
+		Groups:   id.Groups,
+		Extra:    id.Extra,
+	}
+	return impersonatingConfig, nil
+}
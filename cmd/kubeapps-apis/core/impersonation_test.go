@@ -0,0 +1,74 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/rest"
+)
+
+func TestImpersonatingConfig(t *testing.T) {
+	serviceAccountConfig := &rest.Config{Host: "https://kubernetes.default", BearerToken: "service-account-token"}
+
+	testCases := []struct {
+		name                string
+		ctx                 context.Context
+		expectedErrorCode   connect.Code
+		expectedImpersonate rest.ImpersonationConfig
+	}{
+		{
+			name:              "fails without a caller identity",
+			ctx:               context.Background(),
+			expectedErrorCode: connect.CodeUnauthenticated,
+		},
+		{
+			name:              "fails with an identity without username",
+			ctx:               ContextWithCallerIdentity(context.Background(), &CallerIdentity{Groups: []string{"system:masters"}}),
+			expectedErrorCode: connect.CodeUnauthenticated,
+		},
+		{
+			name: "impersonates the caller identity",
+			ctx: ContextWithCallerIdentity(context.Background(), &CallerIdentity{
+				Username: "jane",
+				UID:      "1234",
+				Groups:   []string{"developers"},
+				Extra:    map[string][]string{"scopes": {"view"}},
+			}),
+			expectedImpersonate: rest.ImpersonationConfig{
+				UserName: "jane",
+				UID:      "1234",
+				Groups:   []string{"developers"},
+				Extra:    map[string][]string{"scopes": {"view"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := ImpersonatingConfig(tc.ctx, serviceAccountConfig)
+			if got, want := connect.CodeOf(err), tc.expectedErrorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.expectedErrorCode != 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want: error with code %+v", tc.expectedErrorCode)
+				}
+				return
+			}
+			if got, want := config.Impersonate, tc.expectedImpersonate; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if got, want := config.BearerToken, serviceAccountConfig.BearerToken; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+			if serviceAccountConfig.Impersonate.UserName != "" {
+				t.Errorf("expected the given config to be left unchanged")
+			}
+		})
+	}
+}
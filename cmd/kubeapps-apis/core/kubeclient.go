@@ -0,0 +1,24 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// InClusterConfig returns the in-cluster config of the service account of the
+// server, whose requests fail after the timeout, so that a slow API server
+// does not hang the requests depending on them. The timeout is disabled when
+// 0. It is only meant for the short-lived requests, since it also bounds the
+// watches.
+func InClusterConfig(timeout time.Duration) (*rest.Config, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = timeout
+	return config, nil
+}
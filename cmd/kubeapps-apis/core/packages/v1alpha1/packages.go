@@ -8,22 +8,65 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
 
 	. "github.com/ahmetb/go-linq/v3"
 	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
 	pluginsv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/plugins/v1alpha1"
 	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
 	connectpackages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugins/pkg/connecterror"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	log "k8s.io/klog/v2"
 )
 
+const (
+	// maxDetailsBatchSize is the maximum number of available package details which
+	// can be requested in a single GetAvailablePackageDetailsBatch request.
+	maxDetailsBatchSize = 100
+	// detailsBatchWorkers is the maximum number of concurrent requests to the plugins
+	// when fetching a batch of available package details.
+	detailsBatchWorkers = 10
+	// detailChunkSize is the maximum size, in bytes, of the chunks of the large
+	// text fields of the streamed available package details.
+	detailChunkSize = 64 * 1024
+	// resourceRefsBatchSize is the number of resource refs sent in each response
+	// of the streamed resource refs of the plugins not streaming them.
+	resourceRefsBatchSize = 100
+	// AllNamespaces is the namespace requesting the available packages of every
+	// namespace accessible to the user.
+	AllNamespaces = "*"
+)
+
+// NamespacesLister returns the names of the namespaces of the cluster which the
+// user of the request can access.
+type NamespacesLister func(ctx context.Context, headers http.Header, cluster string) ([]string, error)
+
 // pkgPluginWithServer stores the plugin detail together with its implementation.
 type pkgPluginWithServer struct {
 	plugin *v1alpha1.Plugin
 	server connectpackages.PackagesServiceHandler
+	// namespace is set when the plugin is requested for a single namespace of a
+	// request across all namespaces.
+	namespace string
+}
+
+// offsetKey returns the key of the page offset of the plugin, which includes the
+// namespace when the plugin is requested for a single namespace.
+func (p pkgPluginWithServer) offsetKey() string {
+	if p.namespace == "" {
+		return p.plugin.Name
+	}
+	return p.plugin.Name + "/" + p.namespace
 }
 
 // packagesServer implements the API defined in proto/kubeappsapis/core/packages/v1alpha1/packages.proto
@@ -33,9 +76,32 @@ type packagesServer struct {
 	// pluginsWithServers is a slice of all registered pluginsWithServers which satisfy the core.packages.v1alpha1
 	// interface.
 	pluginsWithServers []pkgPluginWithServer
+
+	// pluginNamespaces restricts some plugins to the namespaces in which they are enabled.
+	pluginNamespaces pluginsv1alpha1.PluginNamespaces
+
+	// partialResults enables the partial results of the aggregated reads for
+	// every request, rather than only when requested.
+	partialResults bool
+
+	// pluginPriority are the names of the plugins, from the highest priority,
+	// whose results take precedence in the aggregated results. When set, the
+	// available packages with the same name are only returned for the plugin
+	// of highest priority.
+	pluginPriority []string
+
+	// namespacesLister lists the namespaces accessible to the user when the
+	// available packages are requested across all namespaces.
+	namespacesLister NamespacesLister
 }
 
-func NewPackagesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer) (*packagesServer, error) {
+func NewPackagesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer, pluginNamespaces pluginsv1alpha1.PluginNamespaces, retryPolicy RetryPolicy, cachePolicy CachePolicy, partialResults bool, pluginPriority []string, namespacesLister NamespacesLister) (*packagesServer, error) {
+	// A single retrier is shared by all plugins so that the retry budget is global.
+	var retrier *readRetrier
+	if retryPolicy.MaxAttempts > 1 {
+		retrier = newReadRetrier(retryPolicy)
+	}
+
 	// Verify that each plugin is indeed a packaging plugin while
 	// casting.
 	pluginsWithServer := make([]pkgPluginWithServer, len(pkgingPlugins))
@@ -44,6 +110,15 @@ func NewPackagesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer) (*packa
 		if !ok {
 			return nil, fmt.Errorf("unable to convert plugin %v to core PackagesServicesServer", p)
 		}
+		if retrier != nil {
+			pkgsSrv = retryingPackagesServer{PackagesServiceHandler: pkgsSrv, retrier: retrier}
+		}
+		// The cache wraps the retries so that the background refreshes are retried too.
+		if cachePolicy.FreshTTL > 0 {
+			cache := newCachingPackagesServer(pkgsSrv, cachePolicy)
+			cachePolicy.Admin.add(p.Plugin.GetName(), cache)
+			pkgsSrv = cache
+		}
 		pluginsWithServer[i] = pkgPluginWithServer{
 			plugin: p.Plugin,
 			server: pkgsSrv,
@@ -52,16 +127,35 @@ func NewPackagesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer) (*packa
 	}
 	return &packagesServer{
 		pluginsWithServers: pluginsWithServer,
+		pluginNamespaces:   pluginNamespaces,
+		partialResults:     partialResults,
+		pluginPriority:     pluginPriority,
+		namespacesLister:   namespacesLister,
 	}, nil
 }
 
 // GetAvailablePackageSummaries returns the packages based on the request.
+//
+// When the namespace of the request is AllNamespaces, each plugin is requested
+// for each of the namespaces accessible to the user and the results are merged,
+// each package keeping the namespace of its reference.
 func (s packagesServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (*connect.Response[packages.GetAvailablePackageSummariesResponse], error) {
 	log.InfoS("+core GetAvailablePackageSummaries", "cluster", request.Msg.GetContext().GetCluster(), "namespace", request.Msg.GetContext().GetNamespace())
 
 	pageSize := request.Msg.GetPaginationOptions().GetPageSize()
 
-	summariesWithOffsets, err := fanInAvailablePackageSummaries(ctx, s.pluginsWithServers, request)
+	pkgPlugins := s.pluginsEnabledIn(request.Msg.GetContext().GetNamespace())
+	if request.Msg.GetContext().GetNamespace() == AllNamespaces {
+		var err error
+		pkgPlugins, err = s.pluginsAcrossNamespaces(ctx, request.Header(), request.Msg.GetContext().GetCluster())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dedupe := len(s.pluginPriority) > 0
+	totals := newTotalCounts()
+	summariesWithOffsets, err := fanInAvailablePackageSummaries(ctx, pkgPlugins, request, s.partialResults || request.Msg.GetPartialResults(), dedupe, totals)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to request results from registered plugins: %w", err))
 	}
@@ -71,7 +165,11 @@ func (s packagesServer) GetAvailablePackageSummaries(ctx context.Context, reques
 	var pkgWithOffsets availableSummaryWithOffsets
 	for pkgWithOffsets = range summariesWithOffsets {
 		if pkgWithOffsets.err != nil {
-			return nil, err
+			return nil, pkgWithOffsets.err
+		}
+		if pkgWithOffsets.availablePackageSummary == nil {
+			// Only the warnings are sent once the plugins are exhausted.
+			continue
 		}
 		pkgs = append(pkgs, pkgWithOffsets.availablePackageSummary)
 		categories = append(categories, pkgWithOffsets.categories...)
@@ -97,11 +195,17 @@ func (s packagesServer) GetAvailablePackageSummaries(ctx context.Context, reques
 	// Delete duplicate categories and sort by name
 	From(categories).Distinct().OrderBy(func(i interface{}) interface{} { return i }).ToSlice(&categories)
 
-	return connect.NewResponse(&packages.GetAvailablePackageSummariesResponse{
+	response := connect.NewResponse(&packages.GetAvailablePackageSummariesResponse{
 		AvailablePackageSummaries: pkgs,
 		Categories:                categories,
 		NextPageToken:             nextPageToken,
-	}), nil
+		Warnings:                  pkgWithOffsets.warnings,
+	})
+	// The total counts of the plugins do not account for the results skipped
+	// when deduping them.
+	totalCount, totalKnown := totals.total(pkgPlugins, pkgPluginWithServer.offsetKey)
+	setPaginationTrailers(response.Trailer(), nextPageToken, totalCount, totalKnown && !dedupe)
+	return response, nil
 }
 
 // GetAvailablePackageDetail returns the package details based on the request.
@@ -117,9 +221,16 @@ func (s packagesServer) GetAvailablePackageDetail(ctx context.Context, request *
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.AvailablePackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetAvailablePackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
+	fieldMask := request.Msg.GetFieldMask()
+	if len(fieldMask.GetPaths()) > 0 && !fieldMask.IsValid(&packages.AvailablePackageDetail{}) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Invalid field mask %q for the available package detail", fieldMask.GetPaths()))
+	}
 
 	// Get the response from the requested plugin
-	response, err := pluginWithServer.server.GetAvailablePackageDetail(ctx, request)
+	response, err := pluginWithServer.server.GetAvailablePackageDetail(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to get the available package detail for the package %q using the plugin %q: %w", request.Msg.AvailablePackageRef.Identifier, request.Msg.AvailablePackageRef.Plugin.Name, err))
 	}
@@ -129,19 +240,153 @@ func (s packagesServer) GetAvailablePackageDetail(ctx context.Context, request *
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Invalid available package detail response from the plugin %v: %w", pluginWithServer.plugin.Name, err))
 	}
 
+	// Only the fields of the mask are returned, if any, reducing the size of
+	// the responses of the large charts.
+	availablePackageDetail := response.Msg.AvailablePackageDetail
+	if len(fieldMask.GetPaths()) > 0 {
+		availablePackageDetail = applyFieldMask(availablePackageDetail, fieldMask).(*packages.AvailablePackageDetail)
+	}
+
 	// Build the response
 	return connect.NewResponse(&packages.GetAvailablePackageDetailResponse{
-		AvailablePackageDetail: response.Msg.AvailablePackageDetail,
+		AvailablePackageDetail: availablePackageDetail,
 	}), nil
 }
 
+// applyFieldMask returns a copy of the message with only the fields of the
+// mask, whose paths must be valid for the message. The copy shares the values
+// of the fields with the message.
+func applyFieldMask(m proto.Message, fieldMask *fieldmaskpb.FieldMask) proto.Message {
+	// The normalized paths do not overlap, so a field is never both shared
+	// and partially copied.
+	fieldMask = proto.Clone(fieldMask).(*fieldmaskpb.FieldMask)
+	fieldMask.Normalize()
+	src := m.ProtoReflect()
+	dst := src.New()
+	for _, path := range fieldMask.GetPaths() {
+		copyFieldPath(src, dst, strings.Split(path, "."))
+	}
+	return dst.Interface()
+}
+
+// copyFieldPath copies the field of the path, such as ["version", "pkg_version"],
+// from the source message to the destination one.
+func copyFieldPath(src, dst protoreflect.Message, path []string) {
+	fd := src.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil || !src.Has(fd) {
+		return
+	}
+	if len(path) == 1 {
+		dst.Set(fd, src.Get(fd))
+		return
+	}
+	copyFieldPath(src.Get(fd).Message(), dst.Mutable(fd).Message(), path[1:])
+}
+
+// GetAvailablePackageDetailsBatch returns the available package details for each of the
+// requests, fetching them concurrently from the plugins with a bounded number of workers.
+func (s packagesServer) GetAvailablePackageDetailsBatch(ctx context.Context, request *connect.Request[packages.GetAvailablePackageDetailsBatchRequest]) (*connect.Response[packages.GetAvailablePackageDetailsBatchResponse], error) {
+	log.InfoS("+core GetAvailablePackageDetailsBatch", "requests", len(request.Msg.GetRequests()))
+
+	if len(request.Msg.GetRequests()) > maxDetailsBatchSize {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Unable to fetch more than %d available package details in a batch, got %d", maxDetailsBatchSize, len(request.Msg.GetRequests())))
+	}
+
+	details := make([]*packages.AvailablePackageDetail, len(request.Msg.GetRequests()))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(detailsBatchWorkers)
+	// The headers are read once since the request initializes them lazily.
+	header := request.Header()
+	for i, detailRequest := range request.Msg.GetRequests() {
+		i, detailRequest := i, detailRequest
+		g.Go(func() error {
+			// Each request keeps the headers of the batch so that plugins can authenticate it.
+			req := connect.NewRequest(detailRequest)
+			for k, v := range header {
+				req.Header()[k] = v
+			}
+			response, err := s.GetAvailablePackageDetail(gctx, req)
+			if err != nil {
+				return err
+			}
+			details[i] = response.Msg.GetAvailablePackageDetail()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&packages.GetAvailablePackageDetailsBatchResponse{
+		AvailablePackageDetails: details,
+	}), nil
+}
+
+// StreamAvailablePackageDetail streams the available package detail, sending
+// its large text fields in chunks after the rest of the detail so that the
+// size of the messages stays bounded for the large charts.
+func (s packagesServer) StreamAvailablePackageDetail(ctx context.Context, request *connect.Request[packages.GetAvailablePackageDetailRequest], stream *connect.ServerStream[packages.StreamAvailablePackageDetailResponse]) error {
+	log.InfoS("+core StreamAvailablePackageDetail", "cluster", request.Msg.GetAvailablePackageRef().GetContext().GetCluster(), "namespace", request.Msg.GetAvailablePackageRef().GetContext().GetNamespace())
+
+	response, err := s.GetAvailablePackageDetail(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	// The copy shares the strings of the detail, which are not copied.
+	detail := proto.Clone(response.Msg.GetAvailablePackageDetail()).(*packages.AvailablePackageDetail)
+	largeFields := []struct {
+		name  string
+		value string
+	}{
+		{"readme", detail.GetReadme()},
+		{"default_values", detail.GetDefaultValues()},
+		{"values_schema", detail.GetValuesSchema()},
+	}
+	detail.Readme, detail.DefaultValues, detail.ValuesSchema = "", "", ""
+
+	if err := stream.Send(&packages.StreamAvailablePackageDetailResponse{AvailablePackageDetail: detail}); err != nil {
+		return err
+	}
+	for _, field := range largeFields {
+		for _, chunk := range splitText(field.value, detailChunkSize) {
+			if err := stream.Send(&packages.StreamAvailablePackageDetailResponse{
+				Chunk: &packages.AvailablePackageDetailChunk{Field: field.name, Data: chunk},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitText splits the text in chunks of at most size bytes, without splitting
+// the UTF-8 encoded characters.
+func splitText(text string, size int) []string {
+	chunks := []string{}
+	for len(text) > 0 {
+		end := len(text)
+		if end > size {
+			end = size
+			for end > 0 && !utf8.RuneStart(text[end]) {
+				end--
+			}
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return chunks
+}
+
 // GetInstalledPackageSummaries returns the installed package summaries based on the request.
 func (s packagesServer) GetInstalledPackageSummaries(ctx context.Context, request *connect.Request[packages.GetInstalledPackageSummariesRequest]) (*connect.Response[packages.GetInstalledPackageSummariesResponse], error) {
 	log.InfoS("+core GetInstalledPackageSummaries", "cluster", request.Msg.GetContext().GetCluster(), "namespace", request.Msg.GetContext().GetNamespace())
 
 	pageSize := request.Msg.GetPaginationOptions().GetPageSize()
 
-	summariesWithOffsets, err := fanInInstalledPackageSummaries(ctx, s.pluginsWithServers, request)
+	pkgPlugins := s.pluginsEnabledIn(request.Msg.GetContext().GetNamespace())
+	totals := newTotalCounts()
+	summariesWithOffsets, err := fanInInstalledPackageSummaries(ctx, pkgPlugins, request, s.partialResults || request.Msg.GetPartialResults(), totals)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to request results from registered plugins: %w", err))
 	}
@@ -151,6 +396,10 @@ func (s packagesServer) GetInstalledPackageSummaries(ctx context.Context, reques
 		if pkgWithOffsets.err != nil {
 			return nil, pkgWithOffsets.err
 		}
+		if pkgWithOffsets.installedPackageSummary == nil {
+			// Only the warnings are sent once the plugins are exhausted.
+			continue
+		}
 		pkgs = append(pkgs, pkgWithOffsets.installedPackageSummary)
 		if pageSize > 0 && len(pkgs) >= int(pageSize) {
 			break
@@ -171,10 +420,14 @@ func (s packagesServer) GetInstalledPackageSummaries(ctx context.Context, reques
 		}
 	}
 
-	return connect.NewResponse(&packages.GetInstalledPackageSummariesResponse{
+	response := connect.NewResponse(&packages.GetInstalledPackageSummariesResponse{
 		InstalledPackageSummaries: pkgs,
 		NextPageToken:             nextPageToken,
-	}), nil
+		Warnings:                  pkgWithOffsets.warnings,
+	})
+	totalCount, totalKnown := totals.total(pkgPlugins, func(pkgPlugin pkgPluginWithServer) string { return pkgPlugin.plugin.GetName() })
+	setPaginationTrailers(response.Trailer(), nextPageToken, totalCount, totalKnown)
+	return response, nil
 }
 
 // GetInstalledPackageDetail returns the package versions based on the request.
@@ -190,9 +443,12 @@ func (s packagesServer) GetInstalledPackageDetail(ctx context.Context, request *
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.InstalledPackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetInstalledPackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
-	response, err := pluginWithServer.server.GetInstalledPackageDetail(ctx, request)
+	response, err := pluginWithServer.server.GetInstalledPackageDetail(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to get the installed package detail for the package %q using the plugin %q: %w", request.Msg.InstalledPackageRef.Identifier, request.Msg.InstalledPackageRef.Plugin.Name, err))
 	}
@@ -221,10 +477,13 @@ func (s packagesServer) GetAvailablePackageVersions(ctx context.Context, request
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.AvailablePackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetAvailablePackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
 	ctxForPlugin := updateContextWithAuthz(ctx, request.Header())
-	response, err := pluginWithServer.server.GetAvailablePackageVersions(ctxForPlugin, request)
+	response, err := pluginWithServer.server.GetAvailablePackageVersions(core.ContextWithPluginName(ctxForPlugin, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to get the available package versions for the package %q using the plugin %q: %w", request.Msg.AvailablePackageRef.Identifier, request.Msg.AvailablePackageRef.Plugin.Name, err))
 	}
@@ -256,10 +515,13 @@ func (s *packagesServer) GetInstalledPackageResourceRefs(ctx context.Context, re
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Unable to retrieve the plugin %v", request.Msg.InstalledPackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetInstalledPackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
 	ctxForPlugin := updateContextWithAuthz(ctx, request.Header())
-	response, err := pluginWithServer.server.GetInstalledPackageResourceRefs(ctxForPlugin, request)
+	response, err := pluginWithServer.server.GetInstalledPackageResourceRefs(core.ContextWithPluginName(ctxForPlugin, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		log.Errorf("Unable to get the resource refs for the package %q using the plugin %q: %v", request.Msg.InstalledPackageRef.Identifier, request.Msg.InstalledPackageRef.Plugin.Name, err)
 
@@ -273,6 +535,59 @@ func (s *packagesServer) GetInstalledPackageResourceRefs(ctx context.Context, re
 	return response, nil
 }
 
+// StreamInstalledPackageResourceRefs streams the references for the Kubernetes
+// resources created by an installed package. The plugins streaming them send
+// the references as they discover them, while the references of the other
+// plugins are sent in batches once fetched.
+func (s *packagesServer) StreamInstalledPackageResourceRefs(ctx context.Context, request *connect.Request[packages.GetInstalledPackageResourceRefsRequest], stream *connect.ServerStream[packages.StreamInstalledPackageResourceRefsResponse]) error {
+	pkgRef := request.Msg.GetInstalledPackageRef()
+	identifier := pkgRef.GetIdentifier()
+	log.InfoS("+core StreamInstalledPackageResourceRefs", "cluster", pkgRef.GetContext().GetCluster(), "namespace", pkgRef.GetContext().GetNamespace(), "identifier", identifier)
+
+	if pkgRef.GetPlugin() == nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Unable to retrieve the plugin (missing InstalledPackageRef.Plugin)"))
+	}
+
+	// Retrieve the plugin with server matching the requested plugin name
+	pluginWithServer := s.getPluginWithServer(pkgRef.Plugin)
+	if pluginWithServer == nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Unable to retrieve the plugin %v", pkgRef.Plugin))
+	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, pkgRef.GetContext().GetNamespace()); err != nil {
+		return err
+	}
+
+	ctxForPlugin := core.ContextWithPluginName(updateContextWithAuthz(ctx, request.Header()), pluginWithServer.plugin.GetName())
+	err := pluginWithServer.server.StreamInstalledPackageResourceRefs(ctxForPlugin, request, stream)
+	if connect.CodeOf(err) != connect.CodeUnimplemented {
+		if err != nil {
+			log.Errorf("Unable to stream the resource refs for the package %q using the plugin %q: %v", identifier, pkgRef.Plugin.Name, err)
+			errCode := connect.CodeOf(err)
+			return connect.NewError(errCode, fmt.Errorf("Unable to stream the resource refs for the package %q using the plugin %q: %v", identifier, pkgRef.Plugin.Name, errCode))
+		}
+		return nil
+	}
+
+	response, err := s.GetInstalledPackageResourceRefs(ctx, request)
+	if err != nil {
+		return err
+	}
+	refs := response.Msg.GetResourceRefs()
+	for start := 0; start == 0 || start < len(refs); start += resourceRefsBatchSize {
+		end := start + resourceRefsBatchSize
+		if end > len(refs) {
+			end = len(refs)
+		}
+		if err := stream.Send(&packages.StreamInstalledPackageResourceRefsResponse{
+			Context:      response.Msg.GetContext(),
+			ResourceRefs: refs[start:end],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateInstalledPackage creates an installed package using configured plugins.
 func (s packagesServer) CreateInstalledPackage(ctx context.Context, request *connect.Request[packages.CreateInstalledPackageRequest]) (*connect.Response[packages.CreateInstalledPackageResponse], error) {
 	log.InfoS("+core CreateInstalledPackage", "cluster", request.Msg.GetTargetContext().GetCluster(), "namespace", request.Msg.GetTargetContext().GetNamespace())
@@ -286,10 +601,13 @@ func (s packagesServer) CreateInstalledPackage(ctx context.Context, request *con
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.AvailablePackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetTargetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
 	ctxForPlugin := updateContextWithAuthz(ctx, request.Header())
-	response, err := pluginWithServer.server.CreateInstalledPackage(ctxForPlugin, request)
+	response, err := pluginWithServer.server.CreateInstalledPackage(core.ContextWithPluginName(ctxForPlugin, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to create the installed package for the package %q using the plugin %q: %w", request.Msg.AvailablePackageRef.Identifier, request.Msg.AvailablePackageRef.Plugin.Name, err))
 	}
@@ -315,10 +633,13 @@ func (s packagesServer) UpdateInstalledPackage(ctx context.Context, request *con
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.InstalledPackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetInstalledPackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
 	ctxForPlugin := updateContextWithAuthz(ctx, request.Header())
-	response, err := pluginWithServer.server.UpdateInstalledPackage(ctxForPlugin, request)
+	response, err := pluginWithServer.server.UpdateInstalledPackage(core.ContextWithPluginName(ctxForPlugin, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to update the installed package for the package %q using the plugin %q: %w", request.Msg.InstalledPackageRef.Identifier, request.Msg.InstalledPackageRef.Plugin.Name, err))
 	}
@@ -344,10 +665,13 @@ func (s packagesServer) DeleteInstalledPackage(ctx context.Context, request *con
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.InstalledPackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetInstalledPackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
 	ctxForPlugin := updateContextWithAuthz(ctx, request.Header())
-	response, err := pluginWithServer.server.DeleteInstalledPackage(ctxForPlugin, request)
+	response, err := pluginWithServer.server.DeleteInstalledPackage(core.ContextWithPluginName(ctxForPlugin, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to delete the installed packagefor the package %q using the plugin %q: %w", request.Msg.InstalledPackageRef.Identifier, request.Msg.InstalledPackageRef.Plugin.Name, err))
 	}
@@ -367,10 +691,13 @@ func (s packagesServer) GetAvailablePackageMetadatas(ctx context.Context, reques
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.AvailablePackageRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetAvailablePackageRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
 	ctxForPlugin := updateContextWithAuthz(ctx, request.Header())
-	response, err := pluginWithServer.server.GetAvailablePackageMetadatas(ctxForPlugin, request)
+	response, err := pluginWithServer.server.GetAvailablePackageMetadatas(core.ContextWithPluginName(ctxForPlugin, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to get the available package metadatas for the package %q using the plugin %q: %w", request.Msg.AvailablePackageRef.Identifier, request.Msg.AvailablePackageRef.Plugin.Name, err))
 	}
@@ -392,6 +719,66 @@ func (s packagesServer) getPluginWithServer(plugin *v1alpha1.Plugin) *pkgPluginW
 	return nil
 }
 
+// pluginsEnabledIn returns the plugins enabled in the given namespace, ordered
+// by priority. The plugins without a priority follow in the registration order.
+func (s packagesServer) pluginsEnabledIn(namespace string) []pkgPluginWithServer {
+	enabled := []pkgPluginWithServer{}
+	for _, p := range s.pluginsByPriority() {
+		if s.pluginNamespaces.IsEnabled(p.plugin, namespace) {
+			enabled = append(enabled, p)
+		}
+	}
+	return enabled
+}
+
+// pluginsByPriority returns the plugins ordered by priority. The plugins without
+// a priority follow in the registration order.
+func (s packagesServer) pluginsByPriority() []pkgPluginWithServer {
+	ordered := append([]pkgPluginWithServer{}, s.pluginsWithServers...)
+	if len(s.pluginPriority) > 0 {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return pluginRank(ordered[i].plugin, s.pluginPriority) < pluginRank(ordered[j].plugin, s.pluginPriority)
+		})
+	}
+	return ordered
+}
+
+// pluginsAcrossNamespaces returns, for each plugin ordered by priority, the
+// plugin requested for each of the namespaces accessible to the user in which
+// it is enabled.
+func (s packagesServer) pluginsAcrossNamespaces(ctx context.Context, headers http.Header, cluster string) ([]pkgPluginWithServer, error) {
+	if s.namespacesLister == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unable to request the available packages across all namespaces"))
+	}
+	namespaces, err := s.namespacesLister(ctx, headers, cluster)
+	if err != nil {
+		return nil, connecterror.FromK8sError("list", "Namespaces", "", err)
+	}
+	sort.Strings(namespaces)
+
+	pkgPlugins := []pkgPluginWithServer{}
+	for _, p := range s.pluginsByPriority() {
+		for _, namespace := range namespaces {
+			if s.pluginNamespaces.IsEnabled(p.plugin, namespace) {
+				p.namespace = namespace
+				pkgPlugins = append(pkgPlugins, p)
+			}
+		}
+	}
+	return pkgPlugins, nil
+}
+
+// pluginRank returns the position of the plugin in the priority, or the length
+// of the priority when the plugin is not in it.
+func pluginRank(plugin *v1alpha1.Plugin, pluginPriority []string) int {
+	for i, name := range pluginPriority {
+		if name == plugin.GetName() {
+			return i
+		}
+	}
+	return len(pluginPriority)
+}
+
 func updateContextWithAuthz(ctx context.Context, h http.Header) context.Context {
 	// Add authz to context metadata for untransitioned plugins.
 	// TODO: Remove once plugins transitioned.
@@ -0,0 +1,304 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/prometheus/client_golang/prometheus"
+	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	connectpackages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
+	"google.golang.org/protobuf/proto"
+	log "k8s.io/klog/v2"
+)
+
+// CachePolicy configures the stale-while-revalidate cache of the available
+// package summaries returned by the packaging plugins.
+type CachePolicy struct {
+	// FreshTTL is the duration during which a cached response is served as is.
+	// A value of 0 disables the cache.
+	FreshTTL time.Duration
+	// MaxStale is the duration, after FreshTTL, during which a cached response
+	// is still served instantly while it is refreshed in the background. Past
+	// it, the request waits for the plugin.
+	MaxStale time.Duration
+	// Metrics, when set, counts the requests served from the cache.
+	Metrics *CacheMetrics
+	// Admin, when set, reports the statistics of the caches and flushes them.
+	Admin *CacheAdmin
+}
+
+// The results of the requests to the cache.
+const (
+	cacheResultHit   = "hit"
+	cacheResultStale = "stale"
+	cacheResultMiss  = "miss"
+)
+
+// CacheMetrics counts the requests to the cache, labeled by method and result,
+// and the requests coalesced into a refresh already in progress, labeled by
+// method, so that the reduction of the load on the plugins can be observed.
+type CacheMetrics struct {
+	requests  *prometheus.CounterVec
+	coalesced *prometheus.CounterVec
+}
+
+// NewCacheMetrics returns the cache metrics, registered with the registerer.
+func NewCacheMetrics(registerer prometheus.Registerer) *CacheMetrics {
+	m := &CacheMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kubeapps_apis",
+			Name:      "cache_requests_total",
+			Help:      "Requests to the cache of the plugin responses, by result: hit, stale or miss.",
+		}, []string{"method", "result"}),
+		coalesced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kubeapps_apis",
+			Name:      "coalesced_requests_total",
+			Help:      "Requests coalesced into a request to the plugins already in progress.",
+		}, []string{"method"}),
+	}
+	registerer.MustRegister(m.requests, m.coalesced)
+	return m
+}
+
+func (m *CacheMetrics) observeRequest(method, result string) {
+	if m != nil {
+		m.requests.WithLabelValues(method, result).Inc()
+	}
+}
+
+func (m *CacheMetrics) observeCoalesced(method string) {
+	if m != nil {
+		m.coalesced.WithLabelValues(method).Inc()
+	}
+}
+
+// CacheStats are the statistics of the cache of a plugin, since it was created.
+type CacheStats struct {
+	Entries   int   `json:"entries"`
+	Hits      int64 `json:"hits"`
+	StaleHits int64 `json:"staleHits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	// MemoryBytes estimates the memory used by the cached responses from their
+	// encoded size.
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// CacheAdmin reports the statistics of the caches of the plugins and flushes
+// them, so that the reports of stale data can be diagnosed.
+type CacheAdmin struct {
+	mu     sync.Mutex
+	caches map[string]cachingPackagesServer
+}
+
+// NewCacheAdmin returns a cache admin, to which the caches are added when the
+// packages server is created.
+func NewCacheAdmin() *CacheAdmin {
+	return &CacheAdmin{caches: map[string]cachingPackagesServer{}}
+}
+
+func (a *CacheAdmin) add(plugin string, cache cachingPackagesServer) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.caches[plugin] = cache
+}
+
+// Stats returns the statistics of the caches, by plugin name.
+func (a *CacheAdmin) Stats() map[string]CacheStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := map[string]CacheStats{}
+	for plugin, cache := range a.caches {
+		stats[plugin] = cache.stats()
+	}
+	return stats
+}
+
+// Flush removes every cached response, returning their number, so that the
+// next requests are served by the plugins.
+func (a *CacheAdmin) Flush() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	flushed := 0
+	for plugin, cache := range a.caches {
+		n := cache.flush()
+		log.Infof("+core flushed %d cached responses of the plugin %q", n, plugin)
+		flushed += n
+	}
+	return flushed
+}
+
+// summariesCacheEntry is a cached response of a plugin.
+type summariesCacheEntry struct {
+	response *packages.GetAvailablePackageSummariesResponse
+	// header holds the headers of the response, such as its total count.
+	header     http.Header
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// cachingPackagesServer wraps a plugin's packages server, caching the available
+// package summaries. Responses are cached per Authorization header and request,
+// since they depend on the permissions of the user. Errors are never cached.
+// All other methods are passed through to the wrapped server untouched.
+type cachingPackagesServer struct {
+	connectpackages.PackagesServiceHandler
+	policy CachePolicy
+
+	mu      *sync.Mutex
+	entries map[string]*summariesCacheEntry
+	// counters are the statistics of the cache, guarded by mu. The entries and
+	// memory are computed when reported.
+	counters *CacheStats
+	// now returns the current time, and is only replaced in tests.
+	now func() time.Time
+}
+
+func newCachingPackagesServer(server connectpackages.PackagesServiceHandler, policy CachePolicy) cachingPackagesServer {
+	return cachingPackagesServer{
+		PackagesServiceHandler: server,
+		policy:                 policy,
+		mu:                     &sync.Mutex{},
+		entries:                map[string]*summariesCacheEntry{},
+		counters:               &CacheStats{},
+		now:                    time.Now,
+	}
+}
+
+// summariesMethod is the method label of the metrics of the cached summaries.
+const summariesMethod = "GetAvailablePackageSummaries"
+
+// summariesCacheKey returns the key of the cached response for the request. It
+// is a hash of the Authorization header and of the request, so that the
+// tokens of the users are not kept, such as in the cache snapshots.
+func summariesCacheKey(request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (string, error) {
+	msg, err := proto.MarshalOptions{Deterministic: true}.Marshal(request.Msg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(request.Header().Get("Authorization") + "\x00" + string(msg)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s cachingPackagesServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (*connect.Response[packages.GetAvailablePackageSummariesResponse], error) {
+	key, err := summariesCacheKey(request)
+	if err != nil {
+		return s.PackagesServiceHandler.GetAvailablePackageSummaries(ctx, request)
+	}
+
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	if ok {
+		age := s.now().Sub(entry.fetchedAt)
+		switch {
+		case age < s.policy.FreshTTL:
+			s.counters.Hits++
+			response := entry.connectResponse()
+			s.mu.Unlock()
+			s.policy.Metrics.observeRequest(summariesMethod, cacheResultHit)
+			return response, nil
+		case age < s.policy.FreshTTL+s.policy.MaxStale:
+			s.counters.StaleHits++
+			coalesced := entry.refreshing
+			if !entry.refreshing {
+				entry.refreshing = true
+				// The refresh outlives the request, keeping its values only.
+				go s.refresh(context.WithoutCancel(ctx), key, request)
+			}
+			response := entry.connectResponse()
+			s.mu.Unlock()
+			s.policy.Metrics.observeRequest(summariesMethod, cacheResultStale)
+			if coalesced {
+				s.policy.Metrics.observeCoalesced(summariesMethod)
+			}
+			log.V(4).Infof("+core serving available package summaries cached %s ago while refreshing them", age)
+			return response, nil
+		}
+	}
+	s.counters.Misses++
+	s.mu.Unlock()
+	s.policy.Metrics.observeRequest(summariesMethod, cacheResultMiss)
+
+	response, err := s.PackagesServiceHandler.GetAvailablePackageSummaries(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	s.store(key, response)
+	return response, nil
+}
+
+// refresh fetches the response for the request, replacing the cached one. The
+// stale response is kept if the refresh fails, until it is too old to be served.
+func (s cachingPackagesServer) refresh(ctx context.Context, key string, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) {
+	response, err := s.PackagesServiceHandler.GetAvailablePackageSummaries(ctx, request)
+	if err != nil {
+		log.Errorf("Unable to refresh the cached available package summaries: %v", err)
+		s.mu.Lock()
+		if entry, ok := s.entries[key]; ok {
+			entry.refreshing = false
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.store(key, response)
+}
+
+// store caches the response, evicting the entries too old to be served.
+func (s cachingPackagesServer) store(key string, response *connect.Response[packages.GetAvailablePackageSummariesResponse]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for k, entry := range s.entries {
+		if now.Sub(entry.fetchedAt) >= s.policy.FreshTTL+s.policy.MaxStale && !entry.refreshing {
+			delete(s.entries, k)
+			s.counters.Evictions++
+		}
+	}
+	s.entries[key] = &summariesCacheEntry{
+		response:  proto.Clone(response.Msg).(*packages.GetAvailablePackageSummariesResponse),
+		header:    response.Header().Clone(),
+		fetchedAt: now,
+	}
+}
+
+// connectResponse returns a copy of the cached response, with its headers.
+func (e *summariesCacheEntry) connectResponse() *connect.Response[packages.GetAvailablePackageSummariesResponse] {
+	response := connect.NewResponse(proto.Clone(e.response).(*packages.GetAvailablePackageSummariesResponse))
+	for key, values := range e.header {
+		response.Header()[key] = append([]string{}, values...)
+	}
+	return response
+}
+
+// stats returns the statistics of the cache.
+func (s cachingPackagesServer) stats() CacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := *s.counters
+	stats.Entries = len(s.entries)
+	for key, entry := range s.entries {
+		stats.MemoryBytes += int64(len(key) + proto.Size(entry.response))
+	}
+	return stats
+}
+
+// flush removes every cached response, returning their number. The refreshes
+// in progress store their response once done.
+func (s cachingPackagesServer) flush() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.entries)
+	clear(s.entries)
+	return n
+}
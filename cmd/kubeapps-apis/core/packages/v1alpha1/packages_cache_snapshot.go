@@ -0,0 +1,212 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"google.golang.org/protobuf/proto"
+	log "k8s.io/klog/v2"
+)
+
+// cacheSnapshotVersion is the version of the format of the cache snapshots.
+// The snapshots of another version are ignored.
+const cacheSnapshotVersion = 1
+
+// cacheSnapshot is the file to which the caches are persisted. Its checksum is
+// the SHA-256 of the payload, so that a truncated or corrupted snapshot is
+// detected on load rather than restoring wrong responses.
+type cacheSnapshot struct {
+	Version  int    `json:"version"`
+	Checksum string `json:"checksum"`
+	// Payload is the JSON of the cached responses by plugin name.
+	Payload []byte `json:"payload"`
+}
+
+// cacheSnapshotEntry is a cached response of a plugin in a snapshot. The key
+// is a hash, so that the tokens of the users are not persisted.
+type cacheSnapshotEntry struct {
+	Key       string      `json:"key"`
+	Response  []byte      `json:"response"`
+	Header    http.Header `json:"header,omitempty"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+}
+
+// SaveSnapshot writes the cached responses of the plugins to the file at the
+// given path, such as on a mounted volume, returning their number. The file is
+// replaced atomically, so that a snapshot being written is never loaded.
+func (a *CacheAdmin) SaveSnapshot(path string) (int, error) {
+	a.mu.Lock()
+	entries := map[string][]cacheSnapshotEntry{}
+	saved := 0
+	for plugin, cache := range a.caches {
+		pluginEntries, err := cache.snapshotEntries()
+		if err != nil {
+			a.mu.Unlock()
+			return 0, fmt.Errorf("unable to snapshot the cache of the plugin %q: %w", plugin, err)
+		}
+		entries[plugin] = pluginEntries
+		saved += len(pluginEntries)
+	}
+	a.mu.Unlock()
+
+	payload, err := json.Marshal(entries)
+	if err != nil {
+		return 0, err
+	}
+	checksum := sha256.Sum256(payload)
+	data, err := json.Marshal(cacheSnapshot{
+		Version:  cacheSnapshotVersion,
+		Checksum: hex.EncodeToString(checksum[:]),
+		Payload:  payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The cached responses are those of the users, so the snapshot is only
+	// readable by the server.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	return saved, nil
+}
+
+// LoadSnapshot restores the cached responses of the plugins from the snapshot
+// at the given path, returning their number. There is nothing to restore when
+// the snapshot does not exist yet. The snapshot is rejected as a whole when its
+// version or checksum does not match, while the responses of the plugins which
+// are no longer registered, or too old to be served, are skipped.
+func (a *CacheAdmin) LoadSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	snapshot := cacheSnapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("invalid cache snapshot: %w", err)
+	}
+	if snapshot.Version != cacheSnapshotVersion {
+		return 0, fmt.Errorf("unsupported cache snapshot version %d, expected %d", snapshot.Version, cacheSnapshotVersion)
+	}
+	checksum := sha256.Sum256(snapshot.Payload)
+	if hex.EncodeToString(checksum[:]) != snapshot.Checksum {
+		return 0, fmt.Errorf("invalid cache snapshot checksum")
+	}
+	entries := map[string][]cacheSnapshotEntry{}
+	if err := json.Unmarshal(snapshot.Payload, &entries); err != nil {
+		return 0, fmt.Errorf("invalid cache snapshot: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	restored := 0
+	for plugin, pluginEntries := range entries {
+		cache, ok := a.caches[plugin]
+		if !ok {
+			continue
+		}
+		n, err := cache.restoreEntries(pluginEntries)
+		if err != nil {
+			return restored, fmt.Errorf("invalid cache snapshot of the plugin %q: %w", plugin, err)
+		}
+		restored += n
+	}
+	return restored, nil
+}
+
+// PersistSnapshots saves a snapshot of the caches at each interval until the
+// context is done.
+func (a *CacheAdmin) PersistSnapshots(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		saved, err := a.SaveSnapshot(path)
+		if err != nil {
+			log.Errorf("Unable to save the cache snapshot %q: %v", path, err)
+			continue
+		}
+		log.V(4).Infof("+core saved %d cached responses to the snapshot %q", saved, path)
+	}
+}
+
+// snapshotEntries returns the cached responses which can still be served.
+func (s cachingPackagesServer) snapshotEntries() ([]cacheSnapshotEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	entries := []cacheSnapshotEntry{}
+	for key, entry := range s.entries {
+		if now.Sub(entry.fetchedAt) >= s.policy.FreshTTL+s.policy.MaxStale {
+			continue
+		}
+		response, err := proto.Marshal(entry.response)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, cacheSnapshotEntry{
+			Key:       key,
+			Response:  response,
+			Header:    entry.header,
+			FetchedAt: entry.fetchedAt,
+		})
+	}
+	return entries, nil
+}
+
+// restoreEntries caches the responses of a snapshot which can still be served,
+// keeping the responses cached since, returning their number.
+func (s cachingPackagesServer) restoreEntries(entries []cacheSnapshotEntry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	restored := 0
+	for _, e := range entries {
+		if _, ok := s.entries[e.Key]; ok || now.Sub(e.FetchedAt) >= s.policy.FreshTTL+s.policy.MaxStale {
+			continue
+		}
+		response := &packages.GetAvailablePackageSummariesResponse{}
+		if err := proto.Unmarshal(e.Response, response); err != nil {
+			return restored, err
+		}
+		s.entries[e.Key] = &summariesCacheEntry{
+			response:  response,
+			header:    e.Header,
+			fetchedAt: e.FetchedAt,
+		}
+		restored++
+	}
+	return restored, nil
+}
@@ -0,0 +1,408 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	corev1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugin_test"
+)
+
+// countingPackagingPluginServer counts the calls to GetAvailablePackageSummaries,
+// signaling each of them, and fails them while err is set.
+type countingPackagingPluginServer struct {
+	*plugin_test.TestPackagingPluginServer
+	mu    sync.Mutex
+	calls int
+	err   error
+	done  chan struct{}
+}
+
+func (s *countingPackagingPluginServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageSummariesRequest]) (*connect.Response[corev1.GetAvailablePackageSummariesResponse], error) {
+	defer func() { s.done <- struct{}{} }()
+	s.mu.Lock()
+	s.calls++
+	err := s.err
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.TestPackagingPluginServer.GetAvailablePackageSummaries(ctx, request)
+}
+
+func (s *countingPackagingPluginServer) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestCachedAvailablePackageSummaries(t *testing.T) {
+	testCases := []struct {
+		name string
+		// age of the cached response when it is requested again.
+		age time.Duration
+		// pluginError fails the calls to the plugin after the first one.
+		pluginError        error
+		expectedCalls      int
+		expectedBackground bool
+		expectedErrorCode  connect.Code
+		// expectedResult is the result of the second request in the metrics.
+		expectedResult string
+	}{
+		{
+			name:           "serves a fresh response from the cache",
+			age:            10 * time.Second,
+			expectedCalls:  1,
+			expectedResult: cacheResultHit,
+		},
+		{
+			name:               "serves a stale response while refreshing it",
+			age:                time.Minute,
+			expectedCalls:      2,
+			expectedBackground: true,
+			expectedResult:     cacheResultStale,
+		},
+		{
+			name:               "serves a stale response when the refresh fails",
+			age:                time.Minute,
+			pluginError:        connect.NewError(connect.CodeUnavailable, fmt.Errorf("boom")),
+			expectedCalls:      2,
+			expectedBackground: true,
+			expectedResult:     cacheResultStale,
+		},
+		{
+			name:           "waits for the plugin past the maximum staleness",
+			age:            10 * time.Minute,
+			expectedCalls:  2,
+			expectedResult: cacheResultMiss,
+		},
+		{
+			name:              "does not serve a response past the maximum staleness when the plugin fails",
+			age:               10 * time.Minute,
+			pluginError:       connect.NewError(connect.CodeUnavailable, fmt.Errorf("boom")),
+			expectedCalls:     2,
+			expectedErrorCode: connect.CodeUnavailable,
+			expectedResult:    cacheResultMiss,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin := &countingPackagingPluginServer{
+				TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+				done:                      make(chan struct{}, 2),
+			}
+			now := time.Now()
+			metrics := NewCacheMetrics(prometheus.NewRegistry())
+			server := newCachingPackagesServer(plugin, CachePolicy{FreshTTL: 30 * time.Second, MaxStale: 5 * time.Minute, Metrics: metrics})
+			server.now = func() time.Time { return now }
+
+			request := connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: "default"},
+			})
+			if _, err := server.GetAvailablePackageSummaries(context.Background(), request); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			<-plugin.done
+
+			plugin.mu.Lock()
+			plugin.err = tc.pluginError
+			plugin.mu.Unlock()
+			now = now.Add(tc.age)
+			response, err := server.GetAvailablePackageSummaries(context.Background(), request)
+
+			if got, want := connect.CodeOf(err), tc.expectedErrorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.expectedErrorCode != 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want: error with code %+v", tc.expectedErrorCode)
+				}
+			} else if got, want := len(response.Msg.GetAvailablePackageSummaries()), 2; got != want {
+				t.Errorf("got: %d summaries, want: %d", got, want)
+			}
+			if tc.expectedCalls > 1 {
+				select {
+				case <-plugin.done:
+				case <-time.After(time.Second):
+					t.Fatalf("timed out waiting for the call to the plugin")
+				}
+			}
+			if got, want := plugin.callCount(), tc.expectedCalls; got != want {
+				t.Errorf("got: %d calls, want: %d", got, want)
+			}
+			// The first request is always a miss.
+			expectedCount := 1.0
+			if tc.expectedResult == cacheResultMiss {
+				expectedCount = 2.0
+			}
+			if got, want := testutil.ToFloat64(metrics.requests.WithLabelValues(summariesMethod, tc.expectedResult)), expectedCount; got != want {
+				t.Errorf("got: %v %s requests, want: %v", got, tc.expectedResult, want)
+			}
+		})
+	}
+}
+
+func TestCachedAvailablePackageSummariesCoalescedMetrics(t *testing.T) {
+	plugin := &countingPackagingPluginServer{
+		TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+		done:                      make(chan struct{}, 1),
+	}
+	metrics := NewCacheMetrics(prometheus.NewRegistry())
+	server := newCachingPackagesServer(plugin, CachePolicy{FreshTTL: 30 * time.Second, MaxStale: 5 * time.Minute, Metrics: metrics})
+
+	// A stale response already being refreshed.
+	request := connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{})
+	key, err := summariesCacheKey(request)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server.entries[key] = &summariesCacheEntry{
+		response:   &corev1.GetAvailablePackageSummariesResponse{},
+		fetchedAt:  time.Now().Add(-time.Minute),
+		refreshing: true,
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := server.GetAvailablePackageSummaries(context.Background(), request); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	if got, want := plugin.callCount(), 0; got != want {
+		t.Errorf("got: %d calls, want: %d", got, want)
+	}
+	if got, want := testutil.ToFloat64(metrics.coalesced.WithLabelValues(summariesMethod)), 2.0; got != want {
+		t.Errorf("got: %v coalesced requests, want: %v", got, want)
+	}
+}
+
+func TestCachedAvailablePackageSummariesPerUser(t *testing.T) {
+	plugin := &countingPackagingPluginServer{
+		TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+		done:                      make(chan struct{}, 3),
+	}
+	server := newCachingPackagesServer(plugin, CachePolicy{FreshTTL: time.Minute})
+
+	for _, token := range []string{"Bearer foo", "Bearer bar", "Bearer foo"} {
+		request := connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{})
+		request.Header().Set("Authorization", token)
+		if _, err := server.GetAvailablePackageSummaries(context.Background(), request); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	// The response of a user is not served to another one.
+	if got, want := plugin.callCount(), 2; got != want {
+		t.Errorf("got: %d calls, want: %d", got, want)
+	}
+}
+
+func TestCachedAvailablePackageSummariesHeaders(t *testing.T) {
+	plugin := totalCountPackagingPluginServer{makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer)}
+	server := newCachingPackagesServer(plugin, CachePolicy{FreshTTL: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		response, err := server.GetAvailablePackageSummaries(context.Background(), connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{}))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		// The cached response keeps the total count reported by the plugin.
+		if got, want := response.Header().Get(core.TotalCountHeader), "2"; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	}
+}
+
+func TestCacheAdmin(t *testing.T) {
+	plugin := &countingPackagingPluginServer{
+		TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+		done:                      make(chan struct{}, 3),
+	}
+	admin := NewCacheAdmin()
+	now := time.Now()
+	server := newCachingPackagesServer(plugin, CachePolicy{FreshTTL: 30 * time.Second, Admin: admin})
+	server.now = func() time.Time { return now }
+	admin.add("mock1", server)
+
+	// A miss for each user, then a hit, then a miss once the responses are too
+	// old, evicting both of them when the new response is stored.
+	for _, token := range []string{"Bearer foo", "Bearer bar", "Bearer bar", "Bearer foo"} {
+		if token == "Bearer foo" && plugin.callCount() > 0 {
+			now = now.Add(time.Minute)
+		}
+		request := connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{})
+		request.Header().Set("Authorization", token)
+		if _, err := server.GetAvailablePackageSummaries(context.Background(), request); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	stats := admin.Stats()["mock1"]
+	if stats.MemoryBytes <= 0 {
+		t.Errorf("got: %d memory bytes, want a positive estimate", stats.MemoryBytes)
+	}
+	stats.MemoryBytes = 0
+	if got, want := stats, (CacheStats{Entries: 1, Hits: 1, Misses: 3, Evictions: 2}); got != want {
+		t.Errorf("got: %+v, want: %+v", got, want)
+	}
+
+	if got, want := admin.Flush(), 1; got != want {
+		t.Errorf("got: %d flushed responses, want: %d", got, want)
+	}
+	if got, want := admin.Stats()["mock1"].Entries, 0; got != want {
+		t.Errorf("got: %d entries, want: %d", got, want)
+	}
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	newCache := func(now time.Time) (*countingPackagingPluginServer, cachingPackagesServer, *CacheAdmin) {
+		plugin := &countingPackagingPluginServer{
+			TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+			done:                      make(chan struct{}, 3),
+		}
+		admin := NewCacheAdmin()
+		server := newCachingPackagesServer(plugin, CachePolicy{FreshTTL: 30 * time.Second, MaxStale: 5 * time.Minute, Admin: admin})
+		server.now = func() time.Time { return now }
+		admin.add("mock1", server)
+		return plugin, server, admin
+	}
+	request := func() *connect.Request[corev1.GetAvailablePackageSummariesRequest] {
+		request := connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{})
+		request.Header().Set("Authorization", "Bearer foo")
+		return request
+	}
+
+	now := time.Now()
+	_, server, admin := newCache(now)
+	if _, err := server.GetAvailablePackageSummaries(context.Background(), request()); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	path := filepath.Join(t.TempDir(), "summaries.snapshot")
+	saved, err := admin.SaveSnapshot(path)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := saved, 1; got != want {
+		t.Errorf("got: %d saved entries, want: %d", got, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if strings.Contains(string(data), "Bearer foo") {
+		t.Errorf("got the token of the user in the snapshot")
+	}
+
+	testCases := []struct {
+		name             string
+		snapshot         func(t *testing.T) string
+		age              time.Duration
+		expectedRestored int
+		expectedErr      bool
+		expectedCalls    int
+	}{
+		{
+			name:             "restores the cached responses",
+			snapshot:         func(t *testing.T) string { return path },
+			expectedRestored: 1,
+		},
+		{
+			name:          "skips the responses too old to be served",
+			snapshot:      func(t *testing.T) string { return path },
+			age:           10 * time.Minute,
+			expectedCalls: 1,
+		},
+		{
+			name:          "nothing to restore without snapshot",
+			snapshot:      func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing") },
+			expectedCalls: 1,
+		},
+		{
+			name: "rejects a corrupted snapshot",
+			snapshot: func(t *testing.T) string {
+				snapshot := cacheSnapshot{}
+				if err := json.Unmarshal(data, &snapshot); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				snapshot.Payload[len(snapshot.Payload)-2] ^= 0xff
+				return writeSnapshot(t, snapshot)
+			},
+			expectedErr:   true,
+			expectedCalls: 1,
+		},
+		{
+			name: "rejects a snapshot of another version",
+			snapshot: func(t *testing.T) string {
+				snapshot := cacheSnapshot{}
+				if err := json.Unmarshal(data, &snapshot); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				snapshot.Version++
+				return writeSnapshot(t, snapshot)
+			},
+			expectedErr:   true,
+			expectedCalls: 1,
+		},
+		{
+			name: "rejects a truncated snapshot",
+			snapshot: func(t *testing.T) string {
+				truncated := filepath.Join(t.TempDir(), "truncated")
+				if err := os.WriteFile(truncated, data[:len(data)/2], 0600); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				return truncated
+			},
+			expectedErr:   true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin, server, admin := newCache(now.Add(tc.age))
+
+			restored, err := admin.LoadSnapshot(tc.snapshot(t))
+			if got, want := err != nil, tc.expectedErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if got, want := restored, tc.expectedRestored; got != want {
+				t.Errorf("got: %d restored entries, want: %d", got, want)
+			}
+
+			if _, err := server.GetAvailablePackageSummaries(context.Background(), request()); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := plugin.callCount(), tc.expectedCalls; got != want {
+				t.Errorf("got: %d calls, want: %d", got, want)
+			}
+		})
+	}
+}
+
+// writeSnapshot writes the cache snapshot to a temporary file, returning its path.
+func writeSnapshot(t *testing.T, snapshot cacheSnapshot) string {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	path := filepath.Join(t.TempDir(), "summaries.snapshot")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return path
+}
@@ -6,10 +6,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
 	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugins/pkg/paginate"
+	"google.golang.org/protobuf/proto"
 )
 
 const CompleteToken = -1
@@ -22,6 +27,11 @@ func getPluginPageOffsets(po *packages.PaginationOptions, numPlugins int) (map[s
 	if numPlugins > 1 {
 		pluginPageSize = pluginPageSize / (numPlugins - 1)
 	}
+	// With more plugins than the page size, such as when requesting each plugin
+	// for each namespace, request at least one item rather than all of them.
+	if corePageSize > 0 && pluginPageSize < 1 {
+		pluginPageSize = 1
+	}
 	pluginPageOffsets := map[string]int{}
 	if po.GetPageToken() != "" {
 		err := json.Unmarshal([]byte(po.GetPageToken()), &pluginPageOffsets)
@@ -32,13 +42,70 @@ func getPluginPageOffsets(po *packages.PaginationOptions, numPlugins int) (map[s
 	return pluginPageOffsets, pluginPageSize, nil
 }
 
+// totalCounts collects the total counts of results reported by the plugins
+// with their first response to a request, in the TotalCountHeader.
+type totalCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newTotalCounts() *totalCounts {
+	return &totalCounts{counts: map[string]int{}}
+}
+
+// report records the total count reported by the plugin in the headers of its
+// response, if any.
+func (t *totalCounts) report(key string, header http.Header) {
+	count, err := strconv.Atoi(header.Get(core.TotalCountHeader))
+	if err != nil || count < 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.counts[key]; !ok {
+		t.counts[key] = count
+	}
+}
+
+// total returns the sum of the total counts of the plugins, if each of them
+// reported one. The plugins requested for a single namespace, when requesting
+// across all namespaces, may return the same packages, so that their total
+// counts are not summed.
+func (t *totalCounts) total(pkgPlugins []pkgPluginWithServer, key func(pkgPluginWithServer) string) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := 0
+	for _, pkgPlugin := range pkgPlugins {
+		count, ok := t.counts[key(pkgPlugin)]
+		if !ok || pkgPlugin.namespace != "" {
+			return 0, false
+		}
+		total += count
+	}
+	return total, true
+}
+
+// setPaginationTrailers sets the trailers of the response of a list method with
+// the next page token and, when known, the total count of the results.
+func setPaginationTrailers(trailer http.Header, nextPageToken string, totalCount int, totalKnown bool) {
+	if nextPageToken != "" {
+		trailer.Set(core.NextPageTokenTrailer, nextPageToken)
+	}
+	if totalKnown {
+		trailer.Set(core.TotalCountHeader, strconv.Itoa(totalCount))
+	}
+}
+
 // availableSummaryWithOffsets is the channel type for the results of the combined
 // core results after fanning in from the plugins.
 type availableSummaryWithOffsets struct {
 	availablePackageSummary *packages.AvailablePackageSummary
 	categories              []string
 	nextItemOffsets         map[string]int
-	err                     error
+	// warnings are the failures of the plugins skipped so far, when partial
+	// results are requested.
+	warnings []*packages.PluginWarning
+	err      error
 }
 
 // fanInAvailablePackageSummaries fans in the results from the separate plugins
@@ -56,7 +123,24 @@ type availableSummaryWithOffsets struct {
 // pagination of individual plugins, it will be possible that this returns
 // duplicates or missing data if data is added or removed between paginated
 // requests.
-func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginWithServer, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (<-chan availableSummaryWithOffsets, error) {
+//
+// When partialResults is true, a plugin returning an error is skipped and its
+// failure is reported in the warnings of the subsequent results, rather than
+// failing the whole request. The failed plugin is considered exhausted so that
+// the following pages do not include its remaining results either.
+//
+// The results with the same name are sent in the order of the plugins. When
+// dedupe is true, only the results of the first plugin with a given name are
+// sent, the results with that name from the other plugins being skipped.
+//
+// A plugin requested for a single namespace, when requesting across all
+// namespaces, is skipped without a warning if the user is denied access to the
+// namespace. Since such a plugin may return the same package for several
+// namespaces, such as the packages of a global namespace, the results with the
+// same name and reference are only sent once.
+//
+// The total counts reported by the plugins are collected in totals.
+func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginWithServer, request *connect.Request[packages.GetAvailablePackageSummariesRequest], partialResults bool, dedupe bool, totals *totalCounts) (<-chan availableSummaryWithOffsets, error) {
 	summariesCh := make(chan availableSummaryWithOffsets)
 
 	pluginPageOffsets, pluginPageSize, err := getPluginPageOffsets(request.Msg.GetPaginationOptions(), len(pkgPlugins))
@@ -73,13 +157,19 @@ func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 			FilterOptions: request.Msg.FilterOptions,
 			PaginationOptions: &packages.PaginationOptions{
 				PageSize:  int32(pluginPageSize),
-				PageToken: fmt.Sprintf("%d", pluginPageOffsets[pluginWithSrv.plugin.Name]),
+				PageToken: fmt.Sprintf("%d", pluginPageOffsets[pluginWithSrv.offsetKey()]),
 			},
 		}
+		if pluginWithSrv.namespace != "" {
+			r.Context = &packages.Context{
+				Cluster:   request.Msg.GetContext().GetCluster(),
+				Namespace: pluginWithSrv.namespace,
+			}
+		}
 		connectRequest := connect.NewRequest(r)
 		connectRequest.Header().Set("Authorization", request.Header().Get("Authorization"))
 
-		ch, err := sendAvailablePackageSummariesForPlugin(ctx, pluginWithSrv, connectRequest)
+		ch, err := sendAvailablePackageSummariesForPlugin(ctx, pluginWithSrv, connectRequest, totals)
 		if err != nil {
 			return nil, err
 		}
@@ -92,10 +182,15 @@ func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 	go func() {
 		numSent := 0
 		nextItems := make([]*availableSummaryWithOffset, len(fanInput))
+		failed := make([]bool, len(fanInput))
+		var warnings []*packages.PluginWarning
+		// The name, plugin name and reference of the last result sent, for deduping.
+		var sentName, sentPlugin string
+		var sentRef *packages.AvailablePackageReference
 		for {
 			// Populate the empty next items from each channel.
 			for i, ch := range fanInput {
-				if nextItems[i] == nil {
+				if nextItems[i] == nil && !failed[i] {
 					// If the channel is closed, the value will remain nil.
 					//nolint:ineffassign
 					ok := true
@@ -104,16 +199,30 @@ func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 						// If the channel was closed, we reached the last item for that
 						// plugin. We need to recognise when all plugins have exhausted
 						// itemsoffsets
-						pluginName := pkgPlugins[i].plugin.Name
-						pluginPageOffsets[pluginName] = CompleteToken
+						pluginPageOffsets[pkgPlugins[i].offsetKey()] = CompleteToken
+					}
+
+					if nextItems[i] != nil && nextItems[i].err != nil && pkgPlugins[i].namespace != "" && isAccessDenied(nextItems[i].err) {
+						failed[i] = true
+						pluginPageOffsets[pkgPlugins[i].offsetKey()] = CompleteToken
+						nextItems[i] = nil
 					}
 
 					if nextItems[i] != nil && nextItems[i].err != nil {
-						summariesCh <- availableSummaryWithOffsets{
-							err: nextItems[i].err,
+						if !partialResults {
+							summariesCh <- availableSummaryWithOffsets{
+								err: nextItems[i].err,
+							}
+							close(summariesCh)
+							return
 						}
-						close(summariesCh)
-						return
+						failed[i] = true
+						pluginPageOffsets[pkgPlugins[i].offsetKey()] = CompleteToken
+						warnings = append(warnings, &packages.PluginWarning{
+							Plugin:  pkgPlugins[i].plugin,
+							Message: nextItems[i].err.Error(),
+						})
+						nextItems[i] = nil
 					}
 				}
 			}
@@ -128,24 +237,47 @@ func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 				}
 			}
 
-			// If there is no non-nil value left, we're done.
+			// If there is no non-nil value left, we're done, though the
+			// warnings still need to be sent when no result carried them.
 			if minIndex == -1 {
+				if len(warnings) > 0 {
+					summariesCh <- availableSummaryWithOffsets{
+						nextItemOffsets: pluginPageOffsets,
+						warnings:        warnings,
+					}
+				}
 				close(summariesCh)
 				return
 			}
 
 			// Otherwise, we find the minimum item of the next items from each channel.
+			// Ties are won by the first plugin.
 			for i, s := range nextItems {
 				if s != nil && s.availablePackageSummary.GetName() < nextItems[minIndex].availablePackageSummary.GetName() {
 					minIndex = i
 				}
 			}
-			pluginName := nextItems[minIndex].availablePackageSummary.GetAvailablePackageRef().GetPlugin().GetName()
-			pluginPageOffsets[pluginName] = nextItems[minIndex].nextItemOffset
+			pluginPageOffsets[pkgPlugins[minIndex].offsetKey()] = nextItems[minIndex].nextItemOffset
+			summary := nextItems[minIndex].availablePackageSummary
+			if isDuplicateSummary(summary, sentName, sentPlugin, sentRef, dedupe) {
+				nextItems[minIndex] = nil
+				continue
+			}
+			sentName, sentPlugin, sentRef = summary.GetName(), summary.GetAvailablePackageRef().GetPlugin().GetName(), summary.GetAvailablePackageRef()
+			// The duplicates waiting from the other plugins are skipped before
+			// sending, so that they are not sent with the next page when this is
+			// the last result of the page.
+			for i, s := range nextItems {
+				if i != minIndex && s != nil && isDuplicateSummary(s.availablePackageSummary, sentName, sentPlugin, sentRef, dedupe) {
+					pluginPageOffsets[pkgPlugins[i].offsetKey()] = s.nextItemOffset
+					nextItems[i] = nil
+				}
+			}
 			summariesCh <- availableSummaryWithOffsets{
 				availablePackageSummary: nextItems[minIndex].availablePackageSummary,
 				categories:              nextItems[minIndex].categories,
 				nextItemOffsets:         pluginPageOffsets,
+				warnings:                warnings,
 			}
 			// Ensure the item will get replaced on the next round.
 			nextItems[minIndex] = nil
@@ -161,6 +293,26 @@ func fanInAvailablePackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 	return summariesCh, nil
 }
 
+// isDuplicateSummary returns whether the summary duplicates the result with the
+// given name, plugin name and reference, either by having the same name and
+// reference or, when dedupe is true, by having the same name for another plugin.
+func isDuplicateSummary(summary *packages.AvailablePackageSummary, name, pluginName string, ref *packages.AvailablePackageReference, dedupe bool) bool {
+	if summary.GetName() != name || pluginName == "" {
+		return false
+	}
+	if dedupe && summary.GetAvailablePackageRef().GetPlugin().GetName() != pluginName {
+		return true
+	}
+	return proto.Equal(summary.GetAvailablePackageRef(), ref)
+}
+
+// isAccessDenied returns whether the error denies the user access, rather than
+// being a failure of the plugin.
+func isAccessDenied(err error) bool {
+	code := connect.CodeOf(err)
+	return code == connect.CodePermissionDenied || code == connect.CodeUnauthenticated
+}
+
 // availableSummaryWithOffset is the channel type for the single result from a
 // single plugin.
 type availableSummaryWithOffset struct {
@@ -171,8 +323,9 @@ type availableSummaryWithOffset struct {
 }
 
 // sendAvailablePackageSummariesForPlugin returns a channel and sends the
-// available package summaries returned by the plugin for the given request.
-func sendAvailablePackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPluginWithServer, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (<-chan *availableSummaryWithOffset, error) {
+// available package summaries returned by the plugin for the given request,
+// reporting its total count to totals.
+func sendAvailablePackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPluginWithServer, request *connect.Request[packages.GetAvailablePackageSummariesRequest], totals *totalCounts) (<-chan *availableSummaryWithOffset, error) {
 	summaryCh := make(chan *availableSummaryWithOffset)
 
 	itemOffset, err := paginate.ItemOffsetFromPageToken(request.Msg.GetPaginationOptions().GetPageToken())
@@ -192,12 +345,13 @@ func sendAvailablePackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPl
 	// improvement.
 	go func() {
 		for {
-			response, err := pkgPlugin.server.GetAvailablePackageSummaries(ctx, request)
+			response, err := pkgPlugin.server.GetAvailablePackageSummaries(core.ContextWithPluginName(ctx, pkgPlugin.plugin.GetName()), request)
 			if err != nil {
 				summaryCh <- &availableSummaryWithOffset{err: err}
 				close(summaryCh)
 				return
 			}
+			totals.report(pkgPlugin.offsetKey(), response.Header())
 			categories := response.Msg.Categories
 			for _, summary := range response.Msg.AvailablePackageSummaries {
 				itemOffset = itemOffset + 1
@@ -219,6 +373,8 @@ func sendAvailablePackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPl
 				summaryCh <- &availableSummaryWithOffset{
 					err: fmt.Errorf("inconsistent item offset: got: %q, expected: %d", response.Msg.GetNextPageToken(), itemOffset),
 				}
+				close(summaryCh)
+				return
 			}
 			request.Msg.PaginationOptions.PageToken = response.Msg.GetNextPageToken()
 		}
@@ -236,7 +392,10 @@ func sendAvailablePackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPl
 type installedSummaryWithOffsets struct {
 	installedPackageSummary *packages.InstalledPackageSummary
 	nextItemOffsets         map[string]int
-	err                     error
+	// warnings are the failures of the plugins skipped so far, when partial
+	// results are requested.
+	warnings []*packages.PluginWarning
+	err      error
 }
 
 // fanInInstalledPackageSummaries fans in the results from the separate plugins
@@ -254,7 +413,14 @@ type installedSummaryWithOffsets struct {
 // pagination of individual plugins, it will be possible that this returns
 // duplicates or missing data if data is added or removed between paginated
 // requests.
-func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginWithServer, request *connect.Request[packages.GetInstalledPackageSummariesRequest]) (<-chan installedSummaryWithOffsets, error) {
+//
+// When partialResults is true, a plugin returning an error is skipped and its
+// failure is reported in the warnings of the subsequent results, rather than
+// failing the whole request. The failed plugin is considered exhausted so that
+// the following pages do not include its remaining results either.
+//
+// The total counts reported by the plugins are collected in totals.
+func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginWithServer, request *connect.Request[packages.GetInstalledPackageSummariesRequest], partialResults bool, totals *totalCounts) (<-chan installedSummaryWithOffsets, error) {
 	summariesCh := make(chan installedSummaryWithOffsets)
 
 	pluginPageOffsets, pluginPageSize, err := getPluginPageOffsets(request.Msg.GetPaginationOptions(), len(pkgPlugins))
@@ -276,7 +442,7 @@ func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 		connectRequest := connect.NewRequest(r)
 		connectRequest.Header().Set("Authorization", request.Header().Get("Authorization"))
 
-		ch, err := sendInstalledPackageSummariesForPlugin(ctx, pluginWithSrv, connectRequest)
+		ch, err := sendInstalledPackageSummariesForPlugin(ctx, pluginWithSrv, connectRequest, totals)
 		if err != nil {
 			return nil, err
 		}
@@ -289,10 +455,12 @@ func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 	go func() {
 		numSent := 0
 		nextItems := make([]*installedSummaryWithOffset, len(fanInput))
+		failed := make([]bool, len(fanInput))
+		var warnings []*packages.PluginWarning
 		for {
 			// Populate the empty next items from each channel.
 			for i, ch := range fanInput {
-				if nextItems[i] == nil {
+				if nextItems[i] == nil && !failed[i] {
 					// If the channel is closed, the value will remain nil.
 					//nolint:ineffassign
 					ok := true
@@ -306,11 +474,20 @@ func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 					}
 
 					if nextItems[i] != nil && nextItems[i].err != nil {
-						summariesCh <- installedSummaryWithOffsets{
-							err: nextItems[i].err,
+						if !partialResults {
+							summariesCh <- installedSummaryWithOffsets{
+								err: nextItems[i].err,
+							}
+							close(summariesCh)
+							return
 						}
-						close(summariesCh)
-						return
+						failed[i] = true
+						pluginPageOffsets[pkgPlugins[i].plugin.Name] = CompleteToken
+						warnings = append(warnings, &packages.PluginWarning{
+							Plugin:  pkgPlugins[i].plugin,
+							Message: nextItems[i].err.Error(),
+						})
+						nextItems[i] = nil
 					}
 				}
 			}
@@ -325,8 +502,15 @@ func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 				}
 			}
 
-			// If there is no non-nil value left, we're done.
+			// If there is no non-nil value left, we're done, though the
+			// warnings still need to be sent when no result carried them.
 			if minIndex == -1 {
+				if len(warnings) > 0 {
+					summariesCh <- installedSummaryWithOffsets{
+						nextItemOffsets: pluginPageOffsets,
+						warnings:        warnings,
+					}
+				}
 				close(summariesCh)
 				return
 			}
@@ -342,6 +526,7 @@ func fanInInstalledPackageSummaries(ctx context.Context, pkgPlugins []pkgPluginW
 			summariesCh <- installedSummaryWithOffsets{
 				installedPackageSummary: nextItems[minIndex].installedPackageSummary,
 				nextItemOffsets:         pluginPageOffsets,
+				warnings:                warnings,
 			}
 			// Ensure the item will get replaced on the next round.
 			nextItems[minIndex] = nil
@@ -366,8 +551,9 @@ type installedSummaryWithOffset struct {
 }
 
 // sendInstalledPackageSummariesForPlugin returns a channel and sends the
-// available package summaries returned by the plugin for the given request.
-func sendInstalledPackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPluginWithServer, request *connect.Request[packages.GetInstalledPackageSummariesRequest]) (<-chan *installedSummaryWithOffset, error) {
+// available package summaries returned by the plugin for the given request,
+// reporting its total count to totals.
+func sendInstalledPackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPluginWithServer, request *connect.Request[packages.GetInstalledPackageSummariesRequest], totals *totalCounts) (<-chan *installedSummaryWithOffset, error) {
 	summaryCh := make(chan *installedSummaryWithOffset)
 
 	itemOffset, err := paginate.ItemOffsetFromPageToken(request.Msg.GetPaginationOptions().GetPageToken())
@@ -387,12 +573,13 @@ func sendInstalledPackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPl
 	// improvement.
 	go func() {
 		for {
-			response, err := pkgPlugin.server.GetInstalledPackageSummaries(ctx, request)
+			response, err := pkgPlugin.server.GetInstalledPackageSummaries(core.ContextWithPluginName(ctx, pkgPlugin.plugin.GetName()), request)
 			if err != nil {
 				summaryCh <- &installedSummaryWithOffset{err: err}
 				close(summaryCh)
 				return
 			}
+			totals.report(pkgPlugin.plugin.GetName(), response.Header())
 			for _, summary := range response.Msg.InstalledPackageSummaries {
 				itemOffset = itemOffset + 1
 				summaryCh <- &installedSummaryWithOffset{
@@ -410,6 +597,8 @@ func sendInstalledPackageSummariesForPlugin(ctx context.Context, pkgPlugin pkgPl
 				summaryCh <- &installedSummaryWithOffset{
 					err: fmt.Errorf("inconsistent item offset: got: %q, expected: %d", response.Msg.GetNextPageToken(), itemOffset),
 				}
+				close(summaryCh)
+				return
 			}
 			request.Msg.PaginationOptions.PageToken = response.Msg.GetNextPageToken()
 		}
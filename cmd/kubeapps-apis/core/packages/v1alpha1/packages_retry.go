@@ -0,0 +1,200 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	connectpackages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
+	log "k8s.io/klog/v2"
+)
+
+// RetryPolicy configures how read-only requests to the packaging plugins are
+// retried when they fail with a transient error. Only idempotent reads are ever
+// retried: writes are always sent to the plugin exactly once.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry. It is doubled for
+	// each subsequent retry, up to MaxBackoff, and a random jitter is applied.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RetryableCodes are the error codes for which a request will be retried.
+	RetryableCodes []connect.Code
+	// BudgetMaxTokens and BudgetTokenRatio configure the global retry budget,
+	// which follows the same approach as the gRPC retry throttling: each failed
+	// attempt removes a token, each successful one adds BudgetTokenRatio tokens,
+	// and retries are only allowed while more than half of the tokens remain.
+	// This prevents retries from amplifying the load on plugins during an outage.
+	BudgetMaxTokens  float64
+	BudgetTokenRatio float64
+}
+
+// ParseRetryableCodes converts the given code names (e.g. "unavailable") to connect codes.
+func ParseRetryableCodes(names []string) ([]connect.Code, error) {
+	codes := make([]connect.Code, len(names))
+	for i, name := range names {
+		if err := codes[i].UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("invalid retryable code %q: %w", name, err)
+		}
+	}
+	return codes, nil
+}
+
+// retryBudget is a token bucket shared by all the plugins, limiting the
+// amount of retries that can be issued.
+type retryBudget struct {
+	mu         sync.Mutex
+	tokens     float64
+	maxTokens  float64
+	tokenRatio float64
+}
+
+func newRetryBudget(maxTokens, tokenRatio float64) *retryBudget {
+	return &retryBudget{
+		tokens:     maxTokens,
+		maxTokens:  maxTokens,
+		tokenRatio: tokenRatio,
+	}
+}
+
+func (b *retryBudget) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.tokenRatio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+// onFailure records a failed attempt and returns whether a retry is allowed.
+func (b *retryBudget) onFailure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens--
+	if b.tokens < 0 {
+		b.tokens = 0
+	}
+	return b.tokens > b.maxTokens/2
+}
+
+// readRetrier retries idempotent calls according to a RetryPolicy.
+type readRetrier struct {
+	policy    RetryPolicy
+	retryable map[connect.Code]bool
+	budget    *retryBudget
+}
+
+func newReadRetrier(policy RetryPolicy) *readRetrier {
+	retryable := make(map[connect.Code]bool, len(policy.RetryableCodes))
+	for _, c := range policy.RetryableCodes {
+		retryable[c] = true
+	}
+	return &readRetrier{
+		policy:    policy,
+		retryable: retryable,
+		budget:    newRetryBudget(policy.BudgetMaxTokens, policy.BudgetTokenRatio),
+	}
+}
+
+// backoff returns the duration to wait before the given retry (starting at 1).
+func (r *readRetrier) backoff(retry int) time.Duration {
+	backoff := r.policy.InitialBackoff
+	for i := 1; i < retry && backoff < r.policy.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if r.policy.MaxBackoff > 0 && backoff > r.policy.MaxBackoff {
+		backoff = r.policy.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	// Full jitter, to avoid retries from different requests being synchronized.
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
+
+// withRetries calls fn until it succeeds, fails with a non-retryable error, the
+// maximum number of attempts is reached, the retry budget is exhausted or the
+// context is done.
+func withRetries[T any](ctx context.Context, r *readRetrier, method string, fn func() (*connect.Response[T], error)) (*connect.Response[T], error) {
+	for attempt := 1; ; attempt++ {
+		response, err := fn()
+		if err == nil {
+			r.budget.onSuccess()
+			return response, nil
+		}
+		if !r.retryable[connect.CodeOf(err)] {
+			return nil, err
+		}
+		if !r.budget.onFailure() || attempt >= r.policy.MaxAttempts {
+			return nil, err
+		}
+
+		backoff := r.backoff(attempt)
+		log.V(4).Infof("+core retrying %s after %s (attempt %d): %v", method, backoff, attempt, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryingPackagesServer wraps a plugin's packages server, retrying the read-only
+// methods. All other methods are passed through to the wrapped server untouched.
+type retryingPackagesServer struct {
+	connectpackages.PackagesServiceHandler
+	retrier *readRetrier
+}
+
+func (s retryingPackagesServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (*connect.Response[packages.GetAvailablePackageSummariesResponse], error) {
+	return withRetries(ctx, s.retrier, "GetAvailablePackageSummaries", func() (*connect.Response[packages.GetAvailablePackageSummariesResponse], error) {
+		return s.PackagesServiceHandler.GetAvailablePackageSummaries(ctx, request)
+	})
+}
+
+func (s retryingPackagesServer) GetAvailablePackageDetail(ctx context.Context, request *connect.Request[packages.GetAvailablePackageDetailRequest]) (*connect.Response[packages.GetAvailablePackageDetailResponse], error) {
+	return withRetries(ctx, s.retrier, "GetAvailablePackageDetail", func() (*connect.Response[packages.GetAvailablePackageDetailResponse], error) {
+		return s.PackagesServiceHandler.GetAvailablePackageDetail(ctx, request)
+	})
+}
+
+func (s retryingPackagesServer) GetAvailablePackageVersions(ctx context.Context, request *connect.Request[packages.GetAvailablePackageVersionsRequest]) (*connect.Response[packages.GetAvailablePackageVersionsResponse], error) {
+	return withRetries(ctx, s.retrier, "GetAvailablePackageVersions", func() (*connect.Response[packages.GetAvailablePackageVersionsResponse], error) {
+		return s.PackagesServiceHandler.GetAvailablePackageVersions(ctx, request)
+	})
+}
+
+func (s retryingPackagesServer) GetAvailablePackageMetadatas(ctx context.Context, request *connect.Request[packages.GetAvailablePackageMetadatasRequest]) (*connect.Response[packages.GetAvailablePackageMetadatasResponse], error) {
+	return withRetries(ctx, s.retrier, "GetAvailablePackageMetadatas", func() (*connect.Response[packages.GetAvailablePackageMetadatasResponse], error) {
+		return s.PackagesServiceHandler.GetAvailablePackageMetadatas(ctx, request)
+	})
+}
+
+func (s retryingPackagesServer) GetInstalledPackageSummaries(ctx context.Context, request *connect.Request[packages.GetInstalledPackageSummariesRequest]) (*connect.Response[packages.GetInstalledPackageSummariesResponse], error) {
+	return withRetries(ctx, s.retrier, "GetInstalledPackageSummaries", func() (*connect.Response[packages.GetInstalledPackageSummariesResponse], error) {
+		return s.PackagesServiceHandler.GetInstalledPackageSummaries(ctx, request)
+	})
+}
+
+func (s retryingPackagesServer) GetInstalledPackageDetail(ctx context.Context, request *connect.Request[packages.GetInstalledPackageDetailRequest]) (*connect.Response[packages.GetInstalledPackageDetailResponse], error) {
+	return withRetries(ctx, s.retrier, "GetInstalledPackageDetail", func() (*connect.Response[packages.GetInstalledPackageDetailResponse], error) {
+		return s.PackagesServiceHandler.GetInstalledPackageDetail(ctx, request)
+	})
+}
+
+func (s retryingPackagesServer) GetInstalledPackageResourceRefs(ctx context.Context, request *connect.Request[packages.GetInstalledPackageResourceRefsRequest]) (*connect.Response[packages.GetInstalledPackageResourceRefsResponse], error) {
+	return withRetries(ctx, s.retrier, "GetInstalledPackageResourceRefs", func() (*connect.Response[packages.GetInstalledPackageResourceRefsResponse], error) {
+		return s.PackagesServiceHandler.GetInstalledPackageResourceRefs(ctx, request)
+	})
+}
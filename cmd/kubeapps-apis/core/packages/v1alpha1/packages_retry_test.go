@@ -0,0 +1,173 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	corev1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugin_test"
+)
+
+// flakyPackagingPluginServer fails the first `failures` calls with errorCode.
+type flakyPackagingPluginServer struct {
+	*plugin_test.TestPackagingPluginServer
+	failures  int
+	errorCode connect.Code
+	calls     int
+}
+
+func (s *flakyPackagingPluginServer) fail() error {
+	s.calls++
+	if s.calls <= s.failures {
+		return connect.NewError(s.errorCode, fmt.Errorf("flaky plugin"))
+	}
+	return nil
+}
+
+func (s *flakyPackagingPluginServer) GetAvailablePackageDetail(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailRequest]) (*connect.Response[corev1.GetAvailablePackageDetailResponse], error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.TestPackagingPluginServer.GetAvailablePackageDetail(ctx, request)
+}
+
+func (s *flakyPackagingPluginServer) CreateInstalledPackage(ctx context.Context, request *connect.Request[corev1.CreateInstalledPackageRequest]) (*connect.Response[corev1.CreateInstalledPackageResponse], error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.TestPackagingPluginServer.CreateInstalledPackage(ctx, request)
+}
+
+func TestRetriesForReads(t *testing.T) {
+	defaultPolicy := RetryPolicy{
+		MaxAttempts:      3,
+		RetryableCodes:   []connect.Code{connect.CodeUnavailable},
+		BudgetMaxTokens:  10,
+		BudgetTokenRatio: 0.1,
+	}
+
+	testCases := []struct {
+		name              string
+		policy            RetryPolicy
+		failures          int
+		errorCode         connect.Code
+		expectedCalls     int
+		expectedErrorCode connect.Code
+	}{
+		{
+			name:          "retries a retryable error until it succeeds",
+			policy:        defaultPolicy,
+			failures:      2,
+			errorCode:     connect.CodeUnavailable,
+			expectedCalls: 3,
+		},
+		{
+			name:              "does not retry a non-retryable error",
+			policy:            defaultPolicy,
+			failures:          1,
+			errorCode:         connect.CodeNotFound,
+			expectedCalls:     1,
+			expectedErrorCode: connect.CodeNotFound,
+		},
+		{
+			name:              "gives up after the maximum number of attempts",
+			policy:            defaultPolicy,
+			failures:          5,
+			errorCode:         connect.CodeUnavailable,
+			expectedCalls:     3,
+			expectedErrorCode: connect.CodeUnavailable,
+		},
+		{
+			name: "does not retry when the retry budget is exhausted",
+			policy: RetryPolicy{
+				MaxAttempts:      3,
+				RetryableCodes:   []connect.Code{connect.CodeUnavailable},
+				BudgetMaxTokens:  2,
+				BudgetTokenRatio: 0.1,
+			},
+			failures:          5,
+			errorCode:         connect.CodeUnavailable,
+			expectedCalls:     1,
+			expectedErrorCode: connect.CodeUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin := &plugins.Plugin{Name: "mock1", Version: "v1alpha1"}
+			flaky := &flakyPackagingPluginServer{
+				TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+				failures:                  tc.failures,
+				errorCode:                 tc.errorCode,
+			}
+			server := retryingPackagesServer{
+				PackagesServiceHandler: flaky,
+				retrier:                newReadRetrier(tc.policy),
+			}
+
+			_, err := server.GetAvailablePackageDetail(context.Background(), connect.NewRequest(&corev1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &corev1.AvailablePackageReference{
+					Identifier: "pkg-1",
+					Plugin:     plugin,
+				},
+			}))
+
+			if got, want := connect.CodeOf(err), tc.expectedErrorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.expectedErrorCode != 0 && err == nil {
+				t.Fatalf("got: nil, want: error with code %+v", tc.expectedErrorCode)
+			}
+			if got, want := flaky.calls, tc.expectedCalls; got != want {
+				t.Errorf("got: %d calls, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestWritesAreNeverRetried(t *testing.T) {
+	flaky := &flakyPackagingPluginServer{
+		TestPackagingPluginServer: makeDefaultTestPackagingPlugin("mock1").server.(*plugin_test.TestPackagingPluginServer),
+		failures:                  1,
+		errorCode:                 connect.CodeUnavailable,
+	}
+	server := retryingPackagesServer{
+		PackagesServiceHandler: flaky,
+		retrier: newReadRetrier(RetryPolicy{
+			MaxAttempts:      3,
+			RetryableCodes:   []connect.Code{connect.CodeUnavailable},
+			BudgetMaxTokens:  10,
+			BudgetTokenRatio: 0.1,
+		}),
+	}
+
+	_, err := server.CreateInstalledPackage(context.Background(), connect.NewRequest(&corev1.CreateInstalledPackageRequest{}))
+
+	if got, want := connect.CodeOf(err), connect.CodeUnavailable; got != want {
+		t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+	}
+	if got, want := flaky.calls, 1; got != want {
+		t.Errorf("got: %d calls, want: %d", got, want)
+	}
+}
+
+func TestParseRetryableCodes(t *testing.T) {
+	codes, err := ParseRetryableCodes([]string{"unavailable", "deadline_exceeded"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := codes, []connect.Code{connect.CodeUnavailable, connect.CodeDeadlineExceeded}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	if _, err := ParseRetryableCodes([]string{"not-a-code"}); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+}
@@ -5,14 +5,22 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/bufbuild/connect-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	pluginsv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/plugins/v1alpha1"
 	corev1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	connectpackages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
 	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugin_test"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 const (
@@ -30,6 +38,7 @@ var ignoreUnexportedOpts = cmpopts.IgnoreUnexported(
 	corev1.AvailablePackageSummary{},
 	corev1.Context{},
 	corev1.GetAvailablePackageDetailResponse{},
+	corev1.GetAvailablePackageDetailsBatchResponse{},
 	corev1.GetAvailablePackageMetadatasResponse{},
 	corev1.GetAvailablePackageSummariesResponse{},
 	corev1.GetAvailablePackageVersionsResponse{},
@@ -45,6 +54,7 @@ var ignoreUnexportedOpts = cmpopts.IgnoreUnexported(
 	corev1.Maintainer{},
 	corev1.PackageAppVersion{},
 	corev1.PackageMetadata{},
+	corev1.PluginWarning{},
 	corev1.VersionReference{},
 	corev1.ResourceRef{},
 	plugins.Plugin{},
@@ -356,6 +366,54 @@ func TestGetAvailablePackageDetail(t *testing.T) {
 			expectedResponse: &corev1.GetAvailablePackageDetailResponse{},
 			errorCode:        connect.CodeNotFound,
 		},
+		{
+			name: "it should only return the fields of the field mask",
+			configuredPlugins: []pkgPluginWithServer{
+				mockedPackagingPlugin1,
+			},
+			request: &corev1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &corev1.AvailablePackageReference{
+					Context: &corev1.Context{
+						Cluster:   "",
+						Namespace: globalPackagingNamespace,
+					},
+					Identifier: "pkg-1",
+					Plugin:     mockedPackagingPlugin1.plugin,
+				},
+				FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "version.pkg_version", "version", "icon_url"}},
+			},
+
+			expectedResponse: &corev1.GetAvailablePackageDetailResponse{
+				AvailablePackageDetail: &corev1.AvailablePackageDetail{
+					Name: "pkg-1",
+					Version: &corev1.PackageAppVersion{
+						PkgVersion: plugin_test.DefaultPkgVersion,
+						AppVersion: plugin_test.DefaultAppVersion,
+					},
+					IconUrl: plugin_test.DefaultIconURL,
+				},
+			},
+		},
+		{
+			name: "it should fail with an invalid field mask",
+			configuredPlugins: []pkgPluginWithServer{
+				mockedPackagingPlugin1,
+			},
+			request: &corev1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &corev1.AvailablePackageReference{
+					Context: &corev1.Context{
+						Cluster:   "",
+						Namespace: globalPackagingNamespace,
+					},
+					Identifier: "pkg-1",
+					Plugin:     mockedPackagingPlugin1.plugin,
+				},
+				FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"readme.size"}},
+			},
+
+			expectedResponse: &corev1.GetAvailablePackageDetailResponse{},
+			errorCode:        connect.CodeInvalidArgument,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -378,6 +436,604 @@ func TestGetAvailablePackageDetail(t *testing.T) {
 	}
 }
 
+func TestStreamAvailablePackageDetail(t *testing.T) {
+	server := &packagesServer{
+		pluginsWithServers: []pkgPluginWithServer{mockedPackagingPlugin1},
+	}
+	mux := http.NewServeMux()
+	mux.Handle(connectpackages.NewPackagesServiceHandler(server))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client := connectpackages.NewPackagesServiceClient(ts.Client(), ts.URL)
+	stream, err := client.StreamAvailablePackageDetail(context.Background(), connect.NewRequest(&corev1.GetAvailablePackageDetailRequest{
+		AvailablePackageRef: &corev1.AvailablePackageReference{
+			Context: &corev1.Context{
+				Cluster:   "",
+				Namespace: globalPackagingNamespace,
+			},
+			Identifier: "pkg-1",
+			Plugin:     mockedPackagingPlugin1.plugin,
+		},
+	}))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer stream.Close()
+
+	// The detail is sent first, without its large text fields, which are
+	// reassembled from the chunks.
+	if !stream.Receive() {
+		t.Fatalf("got no response: %+v", stream.Err())
+	}
+	detail := stream.Msg().GetAvailablePackageDetail()
+	if got := detail.GetReadme() + detail.GetDefaultValues() + detail.GetValuesSchema(); got != "" {
+		t.Errorf("got: %q, want the large text fields to be chunked", got)
+	}
+	fields := map[string]string{}
+	for stream.Receive() {
+		chunk := stream.Msg().GetChunk()
+		fields[chunk.GetField()] += chunk.GetData()
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	detail.Readme, detail.DefaultValues, detail.ValuesSchema = fields["readme"], fields["default_values"], fields["values_schema"]
+
+	if got, want := detail, plugin_test.MakeAvailablePackageDetail("pkg-1", mockedPackagingPlugin1.plugin); !cmp.Equal(got, want, ignoreUnexportedOpts) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		size     int
+		expected []string
+	}{
+		{
+			name:     "empty text",
+			text:     "",
+			size:     4,
+			expected: []string{},
+		},
+		{
+			name:     "text smaller than a chunk",
+			text:     "abc",
+			size:     4,
+			expected: []string{"abc"},
+		},
+		{
+			name:     "text split in chunks",
+			text:     "abcdefghij",
+			size:     4,
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "multi-byte characters are not split",
+			text:     "abéé",
+			size:     3,
+			expected: []string{"ab", "é", "é"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := splitText(tc.text, tc.size), tc.expected; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestGetAvailablePackageDetailsBatch(t *testing.T) {
+	detailRequest := func(identifier string, plugin *plugins.Plugin) *corev1.GetAvailablePackageDetailRequest {
+		return &corev1.GetAvailablePackageDetailRequest{
+			AvailablePackageRef: &corev1.AvailablePackageReference{
+				Context: &corev1.Context{
+					Cluster:   "",
+					Namespace: globalPackagingNamespace,
+				},
+				Identifier: identifier,
+				Plugin:     plugin,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		configuredPlugins []pkgPluginWithServer
+		errorCode         connect.Code
+		request           *corev1.GetAvailablePackageDetailsBatchRequest
+		expectedResponse  *corev1.GetAvailablePackageDetailsBatchResponse
+	}{
+		{
+			name: "it should successfully call the core GetAvailablePackageDetailsBatch operation",
+			configuredPlugins: []pkgPluginWithServer{
+				mockedPackagingPlugin1,
+				mockedPackagingPlugin2,
+			},
+			request: &corev1.GetAvailablePackageDetailsBatchRequest{
+				Requests: []*corev1.GetAvailablePackageDetailRequest{
+					detailRequest("pkg-1", mockedPackagingPlugin1.plugin),
+					detailRequest("pkg-1", mockedPackagingPlugin2.plugin),
+				},
+			},
+			expectedResponse: &corev1.GetAvailablePackageDetailsBatchResponse{
+				AvailablePackageDetails: []*corev1.AvailablePackageDetail{
+					plugin_test.MakeAvailablePackageDetail("pkg-1", mockedPackagingPlugin1.plugin),
+					plugin_test.MakeAvailablePackageDetail("pkg-1", mockedPackagingPlugin2.plugin),
+				},
+			},
+		},
+		{
+			name: "it should return an empty batch when there are no requests",
+			configuredPlugins: []pkgPluginWithServer{
+				mockedPackagingPlugin1,
+			},
+			request: &corev1.GetAvailablePackageDetailsBatchRequest{},
+			expectedResponse: &corev1.GetAvailablePackageDetailsBatchResponse{
+				AvailablePackageDetails: []*corev1.AvailablePackageDetail{},
+			},
+		},
+		{
+			name: "it should fail when any of the packages is not present in a plugin",
+			configuredPlugins: []pkgPluginWithServer{
+				mockedPackagingPlugin1,
+				mockedNotFoundPackagingPlugin,
+			},
+			request: &corev1.GetAvailablePackageDetailsBatchRequest{
+				Requests: []*corev1.GetAvailablePackageDetailRequest{
+					detailRequest("pkg-1", mockedPackagingPlugin1.plugin),
+					detailRequest("pkg-1", mockedNotFoundPackagingPlugin.plugin),
+				},
+			},
+			errorCode: connect.CodeNotFound,
+		},
+		{
+			name: "it should fail when the batch is too large",
+			configuredPlugins: []pkgPluginWithServer{
+				mockedPackagingPlugin1,
+			},
+			request: &corev1.GetAvailablePackageDetailsBatchRequest{
+				Requests: make([]*corev1.GetAvailablePackageDetailRequest, maxDetailsBatchSize+1),
+			},
+			errorCode: connect.CodeInvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &packagesServer{
+				pluginsWithServers: tc.configuredPlugins,
+			}
+			response, err := server.GetAvailablePackageDetailsBatch(context.Background(), connect.NewRequest(tc.request))
+
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+
+			if tc.errorCode == 0 {
+				if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, ignoreUnexportedOpts) {
+					t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+				}
+			} else if err == nil {
+				t.Fatalf("got: nil, want: %+v", tc.errorCode)
+			}
+		})
+	}
+}
+
+func TestPluginNamespaces(t *testing.T) {
+	server := &packagesServer{
+		pluginsWithServers: []pkgPluginWithServer{
+			mockedPackagingPlugin1,
+			mockedPackagingPlugin2,
+		},
+		pluginNamespaces: pluginsv1alpha1.PluginNamespaces{
+			mockedPackagingPlugin2.plugin.Name: {"team-a"},
+		},
+	}
+
+	t.Run("it should fail for a plugin not enabled in the namespace", func(t *testing.T) {
+		_, err := server.GetAvailablePackageDetail(context.Background(), connect.NewRequest(&corev1.GetAvailablePackageDetailRequest{
+			AvailablePackageRef: &corev1.AvailablePackageReference{
+				Context:    &corev1.Context{Namespace: "team-b"},
+				Identifier: "pkg-1",
+				Plugin:     mockedPackagingPlugin2.plugin,
+			},
+		}))
+		if got, want := connect.CodeOf(err), connect.CodeUnimplemented; got != want {
+			t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+		}
+	})
+
+	t.Run("it should succeed for a plugin enabled in the namespace", func(t *testing.T) {
+		_, err := server.GetAvailablePackageDetail(context.Background(), connect.NewRequest(&corev1.GetAvailablePackageDetailRequest{
+			AvailablePackageRef: &corev1.AvailablePackageReference{
+				Context:    &corev1.Context{Namespace: "team-a"},
+				Identifier: "pkg-1",
+				Plugin:     mockedPackagingPlugin2.plugin,
+			},
+		}))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+	})
+
+	t.Run("it should only aggregate the plugins enabled in the namespace", func(t *testing.T) {
+		enabled := server.pluginsEnabledIn("team-b")
+		if got, want := len(enabled), 1; got != want {
+			t.Fatalf("got: %d, want: %d", got, want)
+		}
+		if got, want := enabled[0].plugin.Name, mockedPackagingPlugin1.plugin.Name; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+}
+
+func TestPartialResults(t *testing.T) {
+	configuredPlugins := []pkgPluginWithServer{
+		mockedPackagingPlugin1,
+		mockedNotFoundPackagingPlugin,
+	}
+	expectedWarnings := []*corev1.PluginWarning{
+		{
+			Plugin:  mockedNotFoundPackagingPlugin.plugin,
+			Message: "not_found: Non-OK response",
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		partialResults   bool
+		request          *corev1.GetAvailablePackageSummariesRequest
+		errorCode        connect.Code
+		expectedResponse *corev1.GetAvailablePackageSummariesResponse
+	}{
+		{
+			name: "it should fail when a plugin fails without partial results",
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: globalPackagingNamespace},
+			},
+			errorCode: connect.CodeNotFound,
+		},
+		{
+			name: "it should return the other plugins' results with warnings when requested",
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context:        &corev1.Context{Namespace: globalPackagingNamespace},
+				PartialResults: true,
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("pkg-1", mockedPackagingPlugin1.plugin),
+					plugin_test.MakeAvailablePackageSummary("pkg-2", mockedPackagingPlugin1.plugin),
+				},
+				Categories: []string{"cat-1"},
+				Warnings:   expectedWarnings,
+			},
+		},
+		{
+			name:           "it should return the other plugins' results with warnings when enabled for the server",
+			partialResults: true,
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: globalPackagingNamespace},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("pkg-1", mockedPackagingPlugin1.plugin),
+					plugin_test.MakeAvailablePackageSummary("pkg-2", mockedPackagingPlugin1.plugin),
+				},
+				Categories: []string{"cat-1"},
+				Warnings:   expectedWarnings,
+			},
+		},
+		{
+			name: "it should return the warnings with a full page of results",
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context:           &corev1.Context{Namespace: globalPackagingNamespace},
+				PaginationOptions: &corev1.PaginationOptions{PageSize: 1},
+				PartialResults:    true,
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("pkg-1", mockedPackagingPlugin1.plugin),
+				},
+				Categories:    []string{"cat-1"},
+				NextPageToken: `{"bad-plugin":-1,"mock1":1}`,
+				Warnings:      expectedWarnings,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &packagesServer{
+				pluginsWithServers: configuredPlugins,
+				partialResults:     tc.partialResults,
+			}
+			response, err := server.GetAvailablePackageSummaries(context.Background(), connect.NewRequest(tc.request))
+
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.errorCode != 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want: error with code %+v", tc.errorCode)
+				}
+				return
+			}
+			if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, ignoreUnexportedOpts) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+			}
+		})
+	}
+
+	t.Run("it should return the warnings for installed packages when every plugin fails", func(t *testing.T) {
+		server := &packagesServer{
+			pluginsWithServers: []pkgPluginWithServer{mockedNotFoundPackagingPlugin},
+		}
+		response, err := server.GetInstalledPackageSummaries(context.Background(), connect.NewRequest(&corev1.GetInstalledPackageSummariesRequest{
+			Context:        &corev1.Context{Namespace: globalPackagingNamespace},
+			PartialResults: true,
+		}))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		want := &corev1.GetInstalledPackageSummariesResponse{
+			InstalledPackageSummaries: []*corev1.InstalledPackageSummary{},
+			Warnings:                  expectedWarnings,
+		}
+		if got := response.Msg; !cmp.Equal(got, want, ignoreUnexportedOpts) {
+			t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+		}
+	})
+}
+
+func TestPluginPriority(t *testing.T) {
+	makePlugin := func(pluginName string, pkgNames ...string) pkgPluginWithServer {
+		pluginDetails := &plugins.Plugin{Name: pluginName, Version: "v1alpha1"}
+		packagingPluginServer := &plugin_test.TestPackagingPluginServer{Plugin: pluginDetails}
+		for _, pkgName := range pkgNames {
+			packagingPluginServer.AvailablePackageSummaries = append(packagingPluginServer.AvailablePackageSummaries,
+				plugin_test.MakeAvailablePackageSummary(pkgName, pluginDetails))
+		}
+		return pkgPluginWithServer{
+			plugin: pluginDetails,
+			server: packagingPluginServer,
+		}
+	}
+	// The plugins are registered in the reverse order of their priority, and
+	// both serve a package named "apache".
+	fluxPlugin := makePlugin("fluxv2.packages", "apache", "nginx")
+	helmPlugin := makePlugin("helm.packages", "apache", "wordpress")
+
+	testCases := []struct {
+		name             string
+		pluginPriority   []string
+		request          *corev1.GetAvailablePackageSummariesRequest
+		expectedResponse *corev1.GetAvailablePackageSummariesResponse
+	}{
+		{
+			name: "it should return the packages with the same name of every plugin without priority",
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: globalPackagingNamespace},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("apache", fluxPlugin.plugin),
+					plugin_test.MakeAvailablePackageSummary("apache", helmPlugin.plugin),
+					plugin_test.MakeAvailablePackageSummary("nginx", fluxPlugin.plugin),
+					plugin_test.MakeAvailablePackageSummary("wordpress", helmPlugin.plugin),
+				},
+				Categories: []string{},
+			},
+		},
+		{
+			name:           "it should only return the package of the plugin of highest priority",
+			pluginPriority: []string{"helm.packages", "fluxv2.packages"},
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: globalPackagingNamespace},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("apache", helmPlugin.plugin),
+					plugin_test.MakeAvailablePackageSummary("nginx", fluxPlugin.plugin),
+					plugin_test.MakeAvailablePackageSummary("wordpress", helmPlugin.plugin),
+				},
+				Categories: []string{},
+			},
+		},
+		{
+			name:           "it should skip the duplicates of the other plugins for the next page",
+			pluginPriority: []string{"helm.packages"},
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context:           &corev1.Context{Namespace: globalPackagingNamespace},
+				PaginationOptions: &corev1.PaginationOptions{PageSize: 1},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("apache", helmPlugin.plugin),
+				},
+				Categories:    []string{},
+				NextPageToken: `{"fluxv2.packages":1,"helm.packages":1}`,
+			},
+		},
+		{
+			name:           "it should continue from the skipped duplicates on the next page",
+			pluginPriority: []string{"helm.packages"},
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: globalPackagingNamespace},
+				PaginationOptions: &corev1.PaginationOptions{
+					PageSize:  1,
+					PageToken: `{"fluxv2.packages":1,"helm.packages":1}`,
+				},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					plugin_test.MakeAvailablePackageSummary("nginx", fluxPlugin.plugin),
+				},
+				Categories:    []string{},
+				NextPageToken: `{"fluxv2.packages":2,"helm.packages":1}`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &packagesServer{
+				pluginsWithServers: []pkgPluginWithServer{fluxPlugin, helmPlugin},
+				pluginPriority:     tc.pluginPriority,
+			}
+			response, err := server.GetAvailablePackageSummaries(context.Background(), connect.NewRequest(tc.request))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, ignoreUnexportedOpts) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+			}
+		})
+	}
+}
+
+// namespacedPackagingPluginServer serves the available packages of each of its
+// namespaces, denying access to the other namespaces.
+type namespacedPackagingPluginServer struct {
+	*plugin_test.TestPackagingPluginServer
+	namespaces map[string]*plugin_test.TestPackagingPluginServer
+}
+
+func (s namespacedPackagingPluginServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageSummariesRequest]) (*connect.Response[corev1.GetAvailablePackageSummariesResponse], error) {
+	server, ok := s.namespaces[request.Msg.GetContext().GetNamespace()]
+	if !ok {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("Forbidden"))
+	}
+	return server.GetAvailablePackageSummaries(ctx, request)
+}
+
+func TestGetAvailablePackageSummariesAcrossNamespaces(t *testing.T) {
+	pluginDetails := &plugins.Plugin{Name: "mock1", Version: "v1alpha1"}
+	makeSummary := func(name, namespace string) *corev1.AvailablePackageSummary {
+		summary := plugin_test.MakeAvailablePackageSummary(name, pluginDetails)
+		summary.AvailablePackageRef.Context.Namespace = namespace
+		return summary
+	}
+	// The global package is returned for every namespace.
+	namespacedPlugin := pkgPluginWithServer{
+		plugin: pluginDetails,
+		server: namespacedPackagingPluginServer{
+			TestPackagingPluginServer: plugin_test.NewTestPackagingPlugin(pluginDetails),
+			namespaces: map[string]*plugin_test.TestPackagingPluginServer{
+				"ns-1": {AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					makeSummary("apache", "ns-1"),
+					makeSummary("wordpress", globalPackagingNamespace),
+				}},
+				"ns-2": {AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					makeSummary("apache", "ns-2"),
+					makeSummary("nginx", "ns-2"),
+					makeSummary("wordpress", globalPackagingNamespace),
+				}},
+			},
+		},
+	}
+	namespacesLister := func(ctx context.Context, headers http.Header, cluster string) ([]string, error) {
+		return []string{"ns-3", "ns-2", "ns-1"}, nil
+	}
+
+	testCases := []struct {
+		name             string
+		namespacesLister NamespacesLister
+		request          *corev1.GetAvailablePackageSummariesRequest
+		expectedResponse *corev1.GetAvailablePackageSummariesResponse
+		errorCode        connect.Code
+	}{
+		{
+			name:             "it should return the packages of every accessible namespace",
+			namespacesLister: namespacesLister,
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: AllNamespaces},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					makeSummary("apache", "ns-1"),
+					makeSummary("apache", "ns-2"),
+					makeSummary("nginx", "ns-2"),
+					makeSummary("wordpress", globalPackagingNamespace),
+				},
+				Categories: []string{},
+			},
+		},
+		{
+			name:             "it should paginate across the namespaces",
+			namespacesLister: namespacesLister,
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context:           &corev1.Context{Namespace: AllNamespaces},
+				PaginationOptions: &corev1.PaginationOptions{PageSize: 2},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					makeSummary("apache", "ns-1"),
+					makeSummary("apache", "ns-2"),
+				},
+				Categories:    []string{},
+				NextPageToken: `{"mock1/ns-1":1,"mock1/ns-2":1,"mock1/ns-3":-1}`,
+			},
+		},
+		{
+			name:             "it should continue from the offsets of each namespace on the next page",
+			namespacesLister: namespacesLister,
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: AllNamespaces},
+				PaginationOptions: &corev1.PaginationOptions{
+					PageSize:  2,
+					PageToken: `{"mock1/ns-1":1,"mock1/ns-2":1,"mock1/ns-3":-1}`,
+				},
+			},
+			expectedResponse: &corev1.GetAvailablePackageSummariesResponse{
+				AvailablePackageSummaries: []*corev1.AvailablePackageSummary{
+					makeSummary("nginx", "ns-2"),
+					makeSummary("wordpress", globalPackagingNamespace),
+				},
+				Categories:    []string{},
+				NextPageToken: `{"mock1/ns-1":2,"mock1/ns-2":3,"mock1/ns-3":-1}`,
+			},
+		},
+		{
+			name: "it should return an unimplemented error without a namespaces lister",
+			request: &corev1.GetAvailablePackageSummariesRequest{
+				Context: &corev1.Context{Namespace: AllNamespaces},
+			},
+			errorCode: connect.CodeUnimplemented,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &packagesServer{
+				pluginsWithServers: []pkgPluginWithServer{namespacedPlugin},
+				namespacesLister:   tc.namespacesLister,
+			}
+			response, err := server.GetAvailablePackageSummaries(context.Background(), connect.NewRequest(tc.request))
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %v, want: %v", got, want)
+			}
+			if tc.errorCode != 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want: %v", tc.errorCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, ignoreUnexportedOpts) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+			}
+		})
+	}
+}
+
 func TestGetAvailablePackageMetadatas(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -521,6 +1177,88 @@ func TestGetInstalledPackageSummaries(t *testing.T) {
 	}
 }
 
+// totalCountPackagingPluginServer reports the total count of its summaries.
+type totalCountPackagingPluginServer struct {
+	*plugin_test.TestPackagingPluginServer
+}
+
+func (s totalCountPackagingPluginServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageSummariesRequest]) (*connect.Response[corev1.GetAvailablePackageSummariesResponse], error) {
+	response, err := s.TestPackagingPluginServer.GetAvailablePackageSummaries(ctx, request)
+	if err == nil {
+		response.Header().Set(core.TotalCountHeader, strconv.Itoa(len(s.AvailablePackageSummaries)))
+	}
+	return response, err
+}
+
+func (s totalCountPackagingPluginServer) GetInstalledPackageSummaries(ctx context.Context, request *connect.Request[corev1.GetInstalledPackageSummariesRequest]) (*connect.Response[corev1.GetInstalledPackageSummariesResponse], error) {
+	response, err := s.TestPackagingPluginServer.GetInstalledPackageSummaries(ctx, request)
+	if err == nil {
+		response.Header().Set(core.TotalCountHeader, strconv.Itoa(len(s.InstalledPackageSummaries)))
+	}
+	return response, err
+}
+
+func TestPaginationTrailers(t *testing.T) {
+	withTotalCount := func(p pkgPluginWithServer) pkgPluginWithServer {
+		p.server = totalCountPackagingPluginServer{p.server.(*plugin_test.TestPackagingPluginServer)}
+		return p
+	}
+	mock1, mock2 := withTotalCount(makeDefaultTestPackagingPlugin("mock1")), withTotalCount(makeDefaultTestPackagingPlugin("mock2"))
+
+	testCases := []struct {
+		name                  string
+		configuredPlugins     []pkgPluginWithServer
+		pageSize              int32
+		expectedTotalCount    string
+		expectedNextPageToken string
+	}{
+		{
+			name:                  "sums the total counts of the plugins",
+			configuredPlugins:     []pkgPluginWithServer{mock1, mock2},
+			pageSize:              2,
+			expectedTotalCount:    "4",
+			expectedNextPageToken: `{"mock1":1,"mock2":1}`,
+		},
+		{
+			name:               "has no next page token on the last page",
+			configuredPlugins:  []pkgPluginWithServer{mock1, mock2},
+			expectedTotalCount: "4",
+		},
+		{
+			name:                  "has no total count unless all the plugins report one",
+			configuredPlugins:     []pkgPluginWithServer{mock1, makeDefaultTestPackagingPlugin("mock2")},
+			pageSize:              2,
+			expectedNextPageToken: `{"mock1":1,"mock2":1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &packagesServer{pluginsWithServers: tc.configuredPlugins}
+			pkgContext := &corev1.Context{Namespace: globalPackagingNamespace}
+			pagination := &corev1.PaginationOptions{PageSize: tc.pageSize}
+
+			availableResponse, err := server.GetAvailablePackageSummaries(context.Background(), connect.NewRequest(&corev1.GetAvailablePackageSummariesRequest{Context: pkgContext, PaginationOptions: pagination}))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			installedResponse, err := server.GetInstalledPackageSummaries(context.Background(), connect.NewRequest(&corev1.GetInstalledPackageSummariesRequest{Context: pkgContext, PaginationOptions: pagination}))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			for _, trailer := range []http.Header{availableResponse.Trailer(), installedResponse.Trailer()} {
+				if got, want := trailer.Get(core.TotalCountHeader), tc.expectedTotalCount; got != want {
+					t.Errorf("got: %q, want: %q", got, want)
+				}
+				if got, want := trailer.Get(core.NextPageTokenTrailer), tc.expectedNextPageToken; got != want {
+					t.Errorf("got: %q, want: %q", got, want)
+				}
+			}
+		})
+	}
+}
+
 func TestGetInstalledPackageDetail(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -996,3 +1734,98 @@ func TestGetInstalledPackageResourceRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamInstalledPackageResourceRefs(t *testing.T) {
+	installedPlugin := &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"}
+	pluginResourceRefs := func(count int) []*corev1.ResourceRef {
+		refs := []*corev1.ResourceRef{}
+		for i := 0; i < count; i++ {
+			refs = append(refs, &corev1.ResourceRef{ApiVersion: "v1", Kind: "ConfigMap", Name: fmt.Sprintf("config-%d", i)})
+		}
+		return refs
+	}
+
+	testCases := []struct {
+		name               string
+		pluginResourceRefs []*corev1.ResourceRef
+		plugin             *plugins.Plugin
+		statusCode         connect.Code
+		expectedBatchSizes []int
+	}{
+		{
+			name:               "sends the resource refs of the plugins not streaming them in batches",
+			pluginResourceRefs: pluginResourceRefs(2*resourceRefsBatchSize + 50),
+			plugin:             installedPlugin,
+			expectedBatchSizes: []int{resourceRefsBatchSize, resourceRefsBatchSize, 50},
+		},
+		{
+			name:               "sends a single response without resource refs",
+			plugin:             installedPlugin,
+			expectedBatchSizes: []int{0},
+		},
+		{
+			name:       "returns an invalid argument if the plugin cannot be found",
+			plugin:     &plugins.Plugin{Name: "other-plugin.packages", Version: "v1alpha1"},
+			statusCode: connect.CodeInvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &packagesServer{
+				pluginsWithServers: []pkgPluginWithServer{
+					{
+						plugin: installedPlugin,
+						server: &plugin_test.TestPackagingPluginServer{
+							Plugin:       installedPlugin,
+							ResourceRefs: tc.pluginResourceRefs,
+						},
+					},
+				},
+			}
+			mux := http.NewServeMux()
+			mux.Handle(connectpackages.NewPackagesServiceHandler(server))
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			client := connectpackages.NewPackagesServiceClient(ts.Client(), ts.URL)
+			stream, err := client.StreamInstalledPackageResourceRefs(context.Background(), connect.NewRequest(&corev1.GetInstalledPackageResourceRefsRequest{
+				InstalledPackageRef: &corev1.InstalledPackageReference{
+					Context:    &corev1.Context{Cluster: "default", Namespace: "my-ns"},
+					Identifier: "installed-pkg-1",
+					Plugin:     tc.plugin,
+				},
+			}))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			defer stream.Close()
+
+			batchSizes := []int{}
+			refs := []*corev1.ResourceRef{}
+			for stream.Receive() {
+				if got, want := stream.Msg().GetContext(), (&corev1.Context{Cluster: "default", Namespace: "my-ns"}); !cmp.Equal(got, want, ignoreUnexportedOpts) {
+					t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts))
+				}
+				batchSizes = append(batchSizes, len(stream.Msg().GetResourceRefs()))
+				refs = append(refs, stream.Msg().GetResourceRefs()...)
+			}
+			if got, want := connect.CodeOf(stream.Err()), tc.statusCode; stream.Err() != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, stream.Err())
+			}
+			if tc.statusCode != 0 {
+				if stream.Err() == nil {
+					t.Fatalf("got: nil, want: %+v", tc.statusCode)
+				}
+				return
+			}
+
+			if got, want := batchSizes, tc.expectedBatchSizes; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if got, want := refs, tc.pluginResourceRefs; !cmp.Equal(got, want, ignoreUnexportedOpts, cmpopts.EquateEmpty()) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedOpts, cmpopts.EquateEmpty()))
+			}
+		})
+	}
+}
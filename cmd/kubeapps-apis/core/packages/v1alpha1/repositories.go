@@ -12,6 +12,7 @@ import (
 	. "github.com/ahmetb/go-linq/v3"
 	"github.com/bufbuild/connect-go"
 
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
 	pluginsv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/plugins/v1alpha1"
 	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
 	packagesconnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
@@ -33,9 +34,12 @@ type repositoriesServer struct {
 	// pluginsWithServers is a slice of all registered pluginsWithServers which satisfy the core.packages.v1alpha1
 	// interface.
 	pluginsWithServers []repoPluginsWithServer
+
+	// pluginNamespaces restricts some plugins to the namespaces in which they are enabled.
+	pluginNamespaces pluginsv1alpha1.PluginNamespaces
 }
 
-func NewRepositoriesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer) (*repositoriesServer, error) {
+func NewRepositoriesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer, pluginNamespaces pluginsv1alpha1.PluginNamespaces) (*repositoriesServer, error) {
 	// Verify that each plugin is indeed a packaging plugin while
 	// casting.
 	pluginsWithServer := make([]repoPluginsWithServer, len(pkgingPlugins))
@@ -52,6 +56,7 @@ func NewRepositoriesServer(pkgingPlugins []pluginsv1alpha1.PluginWithServer) (*r
 	}
 	return &repositoriesServer{
 		pluginsWithServers: pluginsWithServer,
+		pluginNamespaces:   pluginNamespaces,
 	}, nil
 }
 
@@ -67,9 +72,12 @@ func (s repositoriesServer) AddPackageRepository(ctx context.Context, request *c
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
-	response, err := pluginWithServer.server.AddPackageRepository(ctx, request)
+	response, err := pluginWithServer.server.AddPackageRepository(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to add package repository %q using the plugin %q: %w", request.Msg.Name, request.Msg.Plugin.Name, err))
 	}
@@ -94,9 +102,12 @@ func (s repositoriesServer) GetPackageRepositoryDetail(ctx context.Context, requ
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.PackageRepoRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetPackageRepoRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
-	response, err := pluginWithServer.server.GetPackageRepositoryDetail(ctx, request)
+	response, err := pluginWithServer.server.GetPackageRepositoryDetail(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to get the package repository detail for the repository %q using the plugin %q: %w", request.Msg.PackageRepoRef.Identifier, request.Msg.PackageRepoRef.Plugin.Name, err))
 	}
@@ -119,8 +130,8 @@ func (s repositoriesServer) GetPackageRepositorySummaries(ctx context.Context, r
 	// Aggregate the response for each plugin
 	summaries := []*packages.PackageRepositorySummary{}
 	// TODO: We can do these in parallel in separate go routines.
-	for _, p := range s.pluginsWithServers {
-		response, err := p.server.GetPackageRepositorySummaries(ctx, request)
+	for _, p := range s.pluginsEnabledIn(request.Msg.GetContext().GetNamespace()) {
+		response, err := p.server.GetPackageRepositorySummaries(core.ContextWithPluginName(ctx, p.plugin.GetName()), request)
 		if err != nil {
 			return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Invalid GetPackageRepositorySummaries response from the plugin %v: %w", p.plugin.Name, err))
 		}
@@ -165,9 +176,12 @@ func (s repositoriesServer) UpdatePackageRepository(ctx context.Context, request
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.PackageRepoRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetPackageRepoRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
-	response, err := pluginWithServer.server.UpdatePackageRepository(ctx, request)
+	response, err := pluginWithServer.server.UpdatePackageRepository(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to update the package repository %q using the plugin %q: %w",
 			request.Msg.PackageRepoRef.Identifier, request.Msg.PackageRepoRef.Plugin.Name, err))
@@ -194,9 +208,12 @@ func (s repositoriesServer) DeletePackageRepository(ctx context.Context, request
 	if pluginWithServer == nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.PackageRepoRef.Plugin))
 	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetPackageRepoRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
 
 	// Get the response from the requested plugin
-	response, err := pluginWithServer.server.DeletePackageRepository(ctx, request)
+	response, err := pluginWithServer.server.DeletePackageRepository(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to delete the package repository %q using the plugin %q: %w",
 			request.Msg.PackageRepoRef.Identifier, request.Msg.PackageRepoRef.Plugin.Name, err))
@@ -207,15 +224,16 @@ func (s repositoriesServer) DeletePackageRepository(ctx context.Context, request
 
 func (s repositoriesServer) GetPackageRepositoryPermissions(ctx context.Context, request *connect.Request[packages.GetPackageRepositoryPermissionsRequest]) (*connect.Response[packages.GetPackageRepositoryPermissionsResponse], error) {
 	log.InfoS("+core GetPackageRepositoryPermissions", "cluster", request.Msg.GetContext().GetCluster(), "namespace", request.Msg.GetContext().GetNamespace())
-	resultsChannel := make(chan *connect.Response[packages.GetPackageRepositoryPermissionsResponse], len(s.pluginsWithServers))
+	enabledPlugins := s.pluginsEnabledIn(request.Msg.GetContext().GetNamespace())
+	resultsChannel := make(chan *connect.Response[packages.GetPackageRepositoryPermissionsResponse], len(enabledPlugins))
 	var wg sync.WaitGroup
 
-	for _, p := range s.pluginsWithServers {
+	for _, p := range enabledPlugins {
 		wg.Add(1)
 		go func(repoPlugin repoPluginsWithServer) {
 			defer wg.Done()
 
-			response, err := repoPlugin.server.GetPackageRepositoryPermissions(ctx, request)
+			response, err := repoPlugin.server.GetPackageRepositoryPermissions(core.ContextWithPluginName(ctx, repoPlugin.plugin.GetName()), request)
 			if err != nil {
 				log.Errorf("+core error finding repository permissions in plugin %s: [%v]", repoPlugin.plugin.Name, err)
 				return
@@ -241,6 +259,101 @@ func (s repositoriesServer) GetPackageRepositoryPermissions(ctx context.Context,
 	}), nil
 }
 
+// GetRepositoryTypes returns the types of the package repositories which can be
+// added with each of the plugins, so that clients do not hardcode them.
+func (s repositoriesServer) GetRepositoryTypes(ctx context.Context, request *connect.Request[packages.GetRepositoryTypesRequest]) (*connect.Response[packages.GetRepositoryTypesResponse], error) {
+	log.InfoS("+core GetRepositoryTypes", "cluster", request.Msg.GetContext().GetCluster(), "namespace", request.Msg.GetContext().GetNamespace())
+	enabledPlugins := s.pluginsEnabledIn(request.Msg.GetContext().GetNamespace())
+	resultsChannel := make(chan *connect.Response[packages.GetRepositoryTypesResponse], len(enabledPlugins))
+	var wg sync.WaitGroup
+
+	for _, p := range enabledPlugins {
+		wg.Add(1)
+		go func(repoPlugin repoPluginsWithServer) {
+			defer wg.Done()
+
+			response, err := repoPlugin.server.GetRepositoryTypes(core.ContextWithPluginName(ctx, repoPlugin.plugin.GetName()), request)
+			if err != nil {
+				log.Errorf("+core error finding repository types in plugin %s: [%v]", repoPlugin.plugin.Name, err)
+				return
+			}
+			resultsChannel <- response
+		}(p)
+	}
+	go func() {
+		wg.Wait()
+		close(resultsChannel)
+	}()
+
+	var repositoryTypes []*packages.PackageRepositoryTypes
+	for pluginResult := range resultsChannel {
+		repositoryTypes = append(repositoryTypes, pluginResult.Msg.RepositoryTypes...)
+	}
+	sort.Slice(repositoryTypes, func(i, j int) bool {
+		return pluginsv1alpha1.ComparePlugin(repositoryTypes[i].Plugin, repositoryTypes[j].Plugin)
+	})
+
+	return connect.NewResponse(&packages.GetRepositoryTypesResponse{
+		RepositoryTypes: repositoryTypes,
+	}), nil
+}
+
+// InvalidatePackageRepositoryCache requests that the plugin owning the package repository
+// refreshes any cached index for it, without waiting for the next scheduled resync.
+func (s repositoriesServer) InvalidatePackageRepositoryCache(ctx context.Context, request *connect.Request[packages.InvalidatePackageRepositoryCacheRequest]) (*connect.Response[packages.InvalidatePackageRepositoryCacheResponse], error) {
+	log.InfoS("+core InvalidatePackageRepositoryCache", "cluster", request.Msg.GetPackageRepoRef().GetContext().GetCluster(), "namespace", request.Msg.GetPackageRepoRef().GetContext().GetNamespace(), "id", request.Msg.GetPackageRepoRef().GetIdentifier())
+
+	if request.Msg.GetPackageRepoRef().GetPlugin() == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Unable to retrieve the plugin (missing PackageRepoRef.Plugin)"))
+	}
+
+	// Retrieve the plugin with server matching the requested plugin name
+	pluginWithServer := s.getPluginWithServer(request.Msg.PackageRepoRef.Plugin)
+	if pluginWithServer == nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.PackageRepoRef.Plugin))
+	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetPackageRepoRef().GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
+
+	// Get the response from the requested plugin
+	response, err := pluginWithServer.server.InvalidatePackageRepositoryCache(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to invalidate the cache of the package repository %q using the plugin %q: %w",
+			request.Msg.PackageRepoRef.Identifier, request.Msg.PackageRepoRef.Plugin.Name, err))
+	}
+
+	return response, nil
+}
+
+// ValidatePackageRepository has the plugin which would own the package repository
+// check that its metadata can be fetched with the given credentials, without
+// adding it, so that bad credentials are reported before the repository is created.
+func (s repositoriesServer) ValidatePackageRepository(ctx context.Context, request *connect.Request[packages.AddPackageRepositoryRequest]) (*connect.Response[packages.ValidatePackageRepositoryResponse], error) {
+	log.InfoS("+core ValidatePackageRepository", "name", request.Msg.GetName(), "cluster", request.Msg.GetContext().GetCluster(), "namespace", request.Msg.GetContext().GetNamespace())
+
+	if request.Msg.GetPlugin() == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Unable to retrieve the plugin (missing request.Plugin)"))
+	}
+
+	// Retrieve the plugin with server matching the requested plugin name
+	pluginWithServer := s.getPluginWithServer(request.Msg.Plugin)
+	if pluginWithServer == nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to get the plugin %v", request.Msg.Plugin))
+	}
+	if err := s.pluginNamespaces.CheckEnabled(pluginWithServer.plugin, request.Msg.GetContext().GetNamespace()); err != nil {
+		return nil, err
+	}
+
+	// Get the response from the requested plugin
+	response, err := pluginWithServer.server.ValidatePackageRepository(core.ContextWithPluginName(ctx, pluginWithServer.plugin.GetName()), request)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeOf(err), fmt.Errorf("Unable to validate the package repository %q using the plugin %q: %w", request.Msg.Name, request.Msg.Plugin.Name, err))
+	}
+
+	return response, nil
+}
+
 // getPluginWithServer returns the *pkgPluginsWithServer from a given packagesServer
 // matching the plugin name
 func (s repositoriesServer) getPluginWithServer(plugin *v1alpha1.Plugin) *repoPluginsWithServer {
@@ -251,3 +364,14 @@ func (s repositoriesServer) getPluginWithServer(plugin *v1alpha1.Plugin) *repoPl
 	}
 	return nil
 }
+
+// pluginsEnabledIn returns the plugins enabled in the given namespace.
+func (s repositoriesServer) pluginsEnabledIn(namespace string) []repoPluginsWithServer {
+	enabled := []repoPluginsWithServer{}
+	for _, p := range s.pluginsWithServers {
+		if s.pluginNamespaces.IsEnabled(p.plugin, namespace) {
+			enabled = append(enabled, p)
+		}
+	}
+	return enabled
+}
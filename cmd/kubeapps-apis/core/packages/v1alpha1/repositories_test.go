@@ -32,6 +32,9 @@ var ignoreUnexportedRepoOpts = cmpopts.IgnoreUnexported(
 	corev1.UpdatePackageRepositoryResponse{},
 	corev1.GetPackageRepositoryPermissionsResponse{},
 	corev1.PackageRepositoriesPermissions{},
+	corev1.GetRepositoryTypesResponse{},
+	corev1.PackageRepositoryTypes{},
+	corev1.ValidatePackageRepositoryResponse{},
 )
 
 func makeDefaultTestRepositoriesPlugin(pluginName string) repoPluginsWithServer {
@@ -431,6 +434,177 @@ func TestDeletePackageRepository(t *testing.T) {
 	}
 }
 
+func TestInvalidatePackageRepositoryCache(t *testing.T) {
+
+	testCases := []struct {
+		name              string
+		configuredPlugins []*plugins.Plugin
+		pluginErrorCode   connect.Code
+		errorCode         connect.Code
+		request           *corev1.InvalidatePackageRepositoryCacheRequest
+	}{
+		{
+			name: "invalidates the package repository cache",
+			configuredPlugins: []*plugins.Plugin{
+				{Name: "plugin-1", Version: "v1alpha1"},
+				{Name: "plugin-1", Version: "v1alpha2"},
+			},
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Context:    &corev1.Context{Cluster: "default", Namespace: "my-ns"},
+					Identifier: "repo-1",
+					Plugin:     &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"},
+				},
+			},
+		},
+		{
+			name:      "returns invalid argument if plugin not specified in request",
+			errorCode: connect.CodeInvalidArgument,
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Identifier: "repo-1",
+				},
+			},
+		},
+		{
+			name:      "returns internal error if unable to find the plugin",
+			errorCode: connect.CodeInternal,
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Identifier: "repo-1",
+					Plugin:     &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"},
+				},
+			},
+		},
+		{
+			name: "returns the plugin error code if the plugin fails",
+			configuredPlugins: []*plugins.Plugin{
+				{Name: "plugin-1", Version: "v1alpha1"},
+			},
+			pluginErrorCode: connect.CodeUnimplemented,
+			errorCode:       connect.CodeUnimplemented,
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Context:    &corev1.Context{Cluster: "default", Namespace: "my-ns"},
+					Identifier: "repo-1",
+					Plugin:     &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configuredPluginServers := []repoPluginsWithServer{}
+			for _, p := range tc.configuredPlugins {
+				configuredPluginServers = append(configuredPluginServers, repoPluginsWithServer{
+					plugin: p,
+					server: plugin_test.TestRepositoriesPluginServer{Plugin: p, ErrorCode: tc.pluginErrorCode},
+				})
+			}
+
+			server := &repositoriesServer{
+				pluginsWithServers: configuredPluginServers,
+			}
+
+			_, err := server.InvalidatePackageRepositoryCache(context.Background(), connect.NewRequest(tc.request))
+
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.errorCode != 0 && err == nil {
+				t.Fatalf("got: nil, want: error with code %+v", tc.errorCode)
+			}
+		})
+	}
+}
+
+func TestValidatePackageRepository(t *testing.T) {
+
+	testCases := []struct {
+		name              string
+		configuredPlugins []*plugins.Plugin
+		pluginErrorCode   connect.Code
+		errorCode         connect.Code
+		request           *corev1.AddPackageRepositoryRequest
+		expectedResponse  *corev1.ValidatePackageRepositoryResponse
+	}{
+		{
+			name: "validates the package repository with the requested plugin",
+			configuredPlugins: []*plugins.Plugin{
+				{Name: "plugin-1", Version: "v1alpha1"},
+				{Name: "plugin-2", Version: "v1alpha1"},
+			},
+			request: &corev1.AddPackageRepositoryRequest{
+				Context: &corev1.Context{Cluster: "default", Namespace: "my-ns"},
+				Name:    "repo-1",
+				Plugin:  &plugins.Plugin{Name: "plugin-2", Version: "v1alpha1"},
+			},
+			expectedResponse: &corev1.ValidatePackageRepositoryResponse{Valid: true},
+		},
+		{
+			name:      "returns invalid argument if plugin not specified in request",
+			errorCode: connect.CodeInvalidArgument,
+			request: &corev1.AddPackageRepositoryRequest{
+				Name: "repo-1",
+			},
+		},
+		{
+			name:      "returns internal error if unable to find the plugin",
+			errorCode: connect.CodeInternal,
+			request: &corev1.AddPackageRepositoryRequest{
+				Name:   "repo-1",
+				Plugin: &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"},
+			},
+		},
+		{
+			name: "returns the plugin error code if the plugin fails",
+			configuredPlugins: []*plugins.Plugin{
+				{Name: "plugin-1", Version: "v1alpha1"},
+			},
+			pluginErrorCode: connect.CodeUnimplemented,
+			errorCode:       connect.CodeUnimplemented,
+			request: &corev1.AddPackageRepositoryRequest{
+				Context: &corev1.Context{Cluster: "default", Namespace: "my-ns"},
+				Name:    "repo-1",
+				Plugin:  &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configuredPluginServers := []repoPluginsWithServer{}
+			for _, p := range tc.configuredPlugins {
+				configuredPluginServers = append(configuredPluginServers, repoPluginsWithServer{
+					plugin: p,
+					server: plugin_test.TestRepositoriesPluginServer{Plugin: p, ErrorCode: tc.pluginErrorCode},
+				})
+			}
+
+			server := &repositoriesServer{
+				pluginsWithServers: configuredPluginServers,
+			}
+
+			response, err := server.ValidatePackageRepository(context.Background(), connect.NewRequest(tc.request))
+
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.errorCode != 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want: error with code %+v", tc.errorCode)
+				}
+				return
+			}
+
+			if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, ignoreUnexportedRepoOpts) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedRepoOpts))
+			}
+		})
+	}
+}
+
 func TestGetPackageRepositoryPermissions(t *testing.T) {
 
 	testCases := []struct {
@@ -505,3 +679,64 @@ func TestGetPackageRepositoryPermissions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRepositoryTypes(t *testing.T) {
+
+	testCases := []struct {
+		name              string
+		configuredPlugins []*plugins.Plugin
+		request           *corev1.GetRepositoryTypesRequest
+		expectedResponse  *corev1.GetRepositoryTypesResponse
+	}{
+		{
+			name: "returns the repository types of all plugins",
+			configuredPlugins: []*plugins.Plugin{
+				{Name: "plugin-2", Version: "v1alpha1"},
+				{Name: "plugin-1", Version: "v1alpha1"},
+			},
+			request: &corev1.GetRepositoryTypesRequest{},
+			expectedResponse: &corev1.GetRepositoryTypesResponse{
+				RepositoryTypes: []*corev1.PackageRepositoryTypes{
+					{
+						Plugin: &plugins.Plugin{Name: "plugin-1", Version: "v1alpha1"},
+						Types:  []string{"plugin-1"},
+					},
+					{
+						Plugin: &plugins.Plugin{Name: "plugin-2", Version: "v1alpha1"},
+						Types:  []string{"plugin-2"},
+					},
+				},
+			},
+		},
+		{
+			name:             "returns empty set when no plugins",
+			request:          &corev1.GetRepositoryTypesRequest{},
+			expectedResponse: &corev1.GetRepositoryTypesResponse{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var configuredPluginServers []repoPluginsWithServer
+			for _, p := range tc.configuredPlugins {
+				configuredPluginServers = append(configuredPluginServers, repoPluginsWithServer{
+					plugin: p,
+					server: plugin_test.TestRepositoriesPluginServer{Plugin: p},
+				})
+			}
+
+			server := &repositoriesServer{
+				pluginsWithServers: configuredPluginServers,
+			}
+
+			response, err := server.GetRepositoryTypes(context.Background(), connect.NewRequest(tc.request))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, ignoreUnexportedRepoOpts) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexportedRepoOpts))
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+const (
+	// TotalCountHeader is the header in which the plugins report, with the
+	// responses of the list methods such as GetAvailablePackageSummaries, the
+	// total number of results matching the request across all the pages, when
+	// they know it. The core packages service sends the total of the plugins in
+	// the trailer of the same name, which the gateway sends as a header.
+	TotalCountHeader = "Total-Count"
+
+	// NextPageTokenTrailer is the trailer of the responses of the list methods
+	// of the core packages service with their next page token, which the
+	// gateway sends as a header, so that the clients can paginate without
+	// reading the response message first.
+	NextPageTokenTrailer = "Next-Page-Token"
+)
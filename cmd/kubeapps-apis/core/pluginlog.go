@@ -0,0 +1,79 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	log "k8s.io/klog/v2"
+)
+
+type pluginNameKey struct{}
+
+// ContextWithPluginName returns a copy of ctx tagged with the name of the plugin
+// handling the request, such as "helm.packages", so that the logs of the
+// request can be filtered by plugin.
+func ContextWithPluginName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, pluginNameKey{}, name)
+}
+
+// PluginNameFromContext returns the name of the plugin the context is tagged
+// with, if any.
+func PluginNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(pluginNameKey{}).(string)
+	return name, ok && name != ""
+}
+
+// PluginLogLevels maps the names of the plugins to the verbosity of their logs,
+// overriding the global verbosity.
+type PluginLogLevels map[string]log.Level
+
+// ParsePluginLogLevels parses the log levels of the plugins from values such as
+// "helm.packages=4".
+func ParsePluginLogLevels(values []string) (PluginLogLevels, error) {
+	levels := PluginLogLevels{}
+	for _, value := range values {
+		pluginName, levelValue, found := strings.Cut(value, "=")
+		pluginName = strings.TrimSpace(pluginName)
+		if !found || pluginName == "" {
+			return nil, fmt.Errorf("invalid plugin log level %q, expected <plugin name>=<level>", value)
+		}
+		level, err := strconv.ParseInt(strings.TrimSpace(levelValue), 10, 32)
+		if err != nil || level < 0 {
+			return nil, fmt.Errorf("invalid plugin log level %q, expected a non-negative level such as 4", value)
+		}
+		levels[pluginName] = log.Level(level)
+	}
+	return levels, nil
+}
+
+// pluginLogLevels holds the log levels of the plugins, which are global like
+// the verbosity of klog.
+var pluginLogLevels atomic.Pointer[PluginLogLevels]
+
+// SetPluginLogLevels sets the log levels of the plugins used by V.
+func SetPluginLogLevels(levels PluginLogLevels) {
+	pluginLogLevels.Store(&levels)
+}
+
+// V is like klog.V, but the verbosity of the plugin the context is tagged with,
+// when configured, takes precedence over the global verbosity. For instance,
+// the logs of a busy plugin can be kept quiet while another one is verbose.
+func V(ctx context.Context, level log.Level) log.Verbose {
+	if name, ok := PluginNameFromContext(ctx); ok {
+		if levels := pluginLogLevels.Load(); levels != nil {
+			if pluginLevel, ok := (*levels)[name]; ok {
+				if level > pluginLevel {
+					return log.Verbose{}
+				}
+				return log.V(0)
+			}
+		}
+	}
+	return log.V(level)
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	log "k8s.io/klog/v2"
+)
+
+func TestParsePluginLogLevels(t *testing.T) {
+	testCases := []struct {
+		name      string
+		values    []string
+		expected  PluginLogLevels
+		expectErr bool
+	}{
+		{
+			name:     "no values",
+			values:   nil,
+			expected: PluginLogLevels{},
+		},
+		{
+			name:   "levels of several plugins",
+			values: []string{"helm.packages=4", " fluxv2.packages = 0 "},
+			expected: PluginLogLevels{
+				"helm.packages":   4,
+				"fluxv2.packages": 0,
+			},
+		},
+		{
+			name:      "missing level",
+			values:    []string{"helm.packages"},
+			expectErr: true,
+		},
+		{
+			name:      "missing plugin name",
+			values:    []string{"=4"},
+			expectErr: true,
+		},
+		{
+			name:      "negative level",
+			values:    []string{"helm.packages=-1"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			levels, err := ParsePluginLogLevels(tc.values)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if got, want := levels, tc.expected; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestV(t *testing.T) {
+	SetPluginLogLevels(PluginLogLevels{"helm.packages": 4, "fluxv2.packages": 0})
+	t.Cleanup(func() { SetPluginLogLevels(nil) })
+
+	// The global verbosity of the tests is 0.
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		level    log.Level
+		expected bool
+	}{
+		{
+			name:     "untagged context follows the global verbosity",
+			ctx:      context.Background(),
+			level:    0,
+			expected: true,
+		},
+		{
+			name:     "untagged context above the global verbosity",
+			ctx:      context.Background(),
+			level:    3,
+			expected: false,
+		},
+		{
+			name:     "verbose plugin within its level",
+			ctx:      ContextWithPluginName(context.Background(), "helm.packages"),
+			level:    4,
+			expected: true,
+		},
+		{
+			name:     "verbose plugin above its level",
+			ctx:      ContextWithPluginName(context.Background(), "helm.packages"),
+			level:    5,
+			expected: false,
+		},
+		{
+			name:     "quiet plugin",
+			ctx:      ContextWithPluginName(context.Background(), "fluxv2.packages"),
+			level:    1,
+			expected: false,
+		},
+		{
+			name:     "plugin without a level follows the global verbosity",
+			ctx:      ContextWithPluginName(context.Background(), "kapp_controller.packages"),
+			level:    3,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := V(tc.ctx, tc.level).Enabled(), tc.expected; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+		})
+	}
+}
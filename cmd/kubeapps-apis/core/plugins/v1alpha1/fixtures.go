@@ -0,0 +1,118 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/bufbuild/connect-go"
+	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	packagesConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
+	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// fixturesPluginName is the name of the plugin serving the canned packages of
+// a fixture file.
+const fixturesPluginName = "fixtures.packages"
+
+// packagesFixture is the JSON fixture file of the fixtures plugin. The
+// summaries and details are in the JSON format of the API responses.
+type packagesFixture struct {
+	AvailablePackageSummaries []json.RawMessage `json:"availablePackageSummaries"`
+	AvailablePackageDetails   []json.RawMessage `json:"availablePackageDetails"`
+}
+
+// fixturesPackagesServer is a packaging plugin serving the canned available
+// packages of a fixture file, so that the dashboard end-to-end tests have
+// deterministic packages without a cluster. The other methods are
+// unimplemented.
+type fixturesPackagesServer struct {
+	packagesConnect.UnimplementedPackagesServiceHandler
+
+	summaries []*packages.AvailablePackageSummary
+	details   []*packages.AvailablePackageDetail
+}
+
+// newFixturesPlugin returns the fixtures plugin serving the packages of the
+// fixture file at the given path. The package references of the fixture are
+// those of the plugin, so that the requests for them are routed back to it.
+func newFixturesPlugin(path string) (PluginWithServer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PluginWithServer{}, fmt.Errorf("unable to read the packages fixture %q: %w", path, err)
+	}
+	fixture := packagesFixture{}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return PluginWithServer{}, fmt.Errorf("invalid packages fixture %q: %w", path, err)
+	}
+
+	pluginDetail := &plugins.Plugin{Name: fixturesPluginName, Version: "v1alpha1"}
+	server := &fixturesPackagesServer{}
+	for i, raw := range fixture.AvailablePackageSummaries {
+		summary := &packages.AvailablePackageSummary{}
+		if err := protojson.Unmarshal(raw, summary); err != nil {
+			return PluginWithServer{}, fmt.Errorf("invalid available package summary %d of the packages fixture %q: %w", i, path, err)
+		}
+		if summary.AvailablePackageRef == nil {
+			summary.AvailablePackageRef = &packages.AvailablePackageReference{}
+		}
+		summary.AvailablePackageRef.Plugin = pluginDetail
+		server.summaries = append(server.summaries, summary)
+	}
+	for i, raw := range fixture.AvailablePackageDetails {
+		detail := &packages.AvailablePackageDetail{}
+		if err := protojson.Unmarshal(raw, detail); err != nil {
+			return PluginWithServer{}, fmt.Errorf("invalid available package detail %d of the packages fixture %q: %w", i, path, err)
+		}
+		if detail.AvailablePackageRef == nil {
+			detail.AvailablePackageRef = &packages.AvailablePackageReference{}
+		}
+		detail.AvailablePackageRef.Plugin = pluginDetail
+		server.details = append(server.details, detail)
+	}
+	return PluginWithServer{Plugin: pluginDetail, Server: server}, nil
+}
+
+// GetAvailablePackageSummaries returns all the summaries of the fixture, in
+// a single page.
+func (s *fixturesPackagesServer) GetAvailablePackageSummaries(ctx context.Context, request *connect.Request[packages.GetAvailablePackageSummariesRequest]) (*connect.Response[packages.GetAvailablePackageSummariesResponse], error) {
+	response := &packages.GetAvailablePackageSummariesResponse{}
+	categories := map[string]bool{}
+	for _, summary := range s.summaries {
+		response.AvailablePackageSummaries = append(response.AvailablePackageSummaries, proto.Clone(summary).(*packages.AvailablePackageSummary))
+		for _, category := range summary.Categories {
+			categories[category] = true
+		}
+	}
+	for category := range categories {
+		response.Categories = append(response.Categories, category)
+	}
+	sort.Strings(response.Categories)
+	return connect.NewResponse(response), nil
+}
+
+// GetAvailablePackageDetail returns the detail of the fixture with the
+// identifier of the requested package and, if requested, its version.
+func (s *fixturesPackagesServer) GetAvailablePackageDetail(ctx context.Context, request *connect.Request[packages.GetAvailablePackageDetailRequest]) (*connect.Response[packages.GetAvailablePackageDetailResponse], error) {
+	identifier := request.Msg.GetAvailablePackageRef().GetIdentifier()
+	pkgVersion := request.Msg.GetPkgVersion()
+	for _, detail := range s.details {
+		if detail.GetAvailablePackageRef().GetIdentifier() != identifier {
+			continue
+		}
+		if pkgVersion != "" && detail.GetVersion().GetPkgVersion() != pkgVersion {
+			continue
+		}
+		return connect.NewResponse(&packages.GetAvailablePackageDetailResponse{
+			AvailablePackageDetail: proto.Clone(detail).(*packages.AvailablePackageDetail),
+		}), nil
+	}
+	return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("Unable to find the package %q with version %q in the packages fixture", identifier, pkgVersion))
+}
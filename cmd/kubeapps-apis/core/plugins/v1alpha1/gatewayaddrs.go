@@ -0,0 +1,48 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	log "k8s.io/klog/v2"
+)
+
+// PluginGatewayAddrs maps the names of the plugins to the host:port the
+// gateway dials for their requests, rather than the listen address of this
+// server, such as for a plugin served out of process. The plugins which are
+// not included are dialed on the listen address.
+type PluginGatewayAddrs map[string]string
+
+// ParsePluginGatewayAddrs parses the gateway addresses of the plugins from
+// values such as "fluxv2.packages=kubeapps-flux-plugin.kubeapps.svc:50051".
+func ParsePluginGatewayAddrs(values []string) (PluginGatewayAddrs, error) {
+	addrs := PluginGatewayAddrs{}
+	for _, value := range values {
+		pluginName, addr, found := strings.Cut(value, "=")
+		pluginName, addr = strings.TrimSpace(pluginName), strings.TrimSpace(addr)
+		if !found || pluginName == "" {
+			return nil, fmt.Errorf("invalid plugin gateway address %q, expected <plugin name>=<host>:<port>", value)
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("invalid plugin gateway address %q, expected <plugin name>=<host>:<port>: %w", value, err)
+		}
+		addrs[pluginName] = addr
+	}
+	return addrs, nil
+}
+
+// gatewayArgsFor returns the gateway args of the plugin, dialing its gateway
+// address when one is configured.
+func (a PluginGatewayAddrs) gatewayArgsFor(pluginDetail *plugins.Plugin, gwArgs core.GatewayHandlerArgs) core.GatewayHandlerArgs {
+	if addr, ok := a[pluginDetail.GetName()]; ok {
+		log.InfoS("Dialing the plugin from the gateway at its configured address", "plugin", pluginDetail.GetName(), "addr", addr)
+		gwArgs.Addr = addr
+	}
+	return gwArgs
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+)
+
+// HTTPRoutesRegisterer is implemented by the plugins serving REST routes of
+// their own on the gateway, besides the http rules of their services, such as
+// a proxy of images from the cluster.
+type HTTPRoutesRegisterer interface {
+	RegisterHTTPRoutes(mux core.HTTPRouteMux) error
+}
+
+// RegisterHTTPRoutes registers the routes of the plugins implementing
+// HTTPRoutesRegisterer with the mux returned for each plugin.
+func (s *PluginsServer) RegisterHTTPRoutes(muxFor func(plugin *plugins.Plugin) core.HTTPRouteMux) error {
+	for _, p := range s.GetPluginsSatisfyingInterface(reflect.TypeOf((*HTTPRoutesRegisterer)(nil)).Elem()) {
+		if err := p.Server.(HTTPRoutesRegisterer).RegisterHTTPRoutes(muxFor(p.Plugin)); err != nil {
+			return fmt.Errorf("unable to register the HTTP routes of plugin %q: %w", p.Plugin.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+)
+
+type routingServer struct {
+	pattern string
+	err     error
+}
+
+func (s routingServer) RegisterHTTPRoutes(mux core.HTTPRouteMux) error {
+	if s.err != nil {
+		return s.err
+	}
+	return mux.HandlePath(http.MethodGet, s.pattern, nil)
+}
+
+// recordingRouteMux records the routes registered by the plugins.
+type recordingRouteMux struct {
+	pluginName string
+	routes     *[]string
+}
+
+func (m recordingRouteMux) HandlePath(method, pattern string, handler runtime.HandlerFunc) error {
+	*m.routes = append(*m.routes, m.pluginName+" "+method+" "+pattern)
+	return nil
+}
+
+func TestRegisterHTTPRoutes(t *testing.T) {
+	s := &PluginsServer{
+		pluginsWithServers: []PluginWithServer{
+			{Plugin: &plugins.Plugin{Name: "operators.packages"}, Server: routingServer{pattern: "/operators/{name}/logo"}},
+			{Plugin: &plugins.Plugin{Name: "helm.packages"}, Server: struct{}{}},
+		},
+	}
+	routes := []string{}
+	muxFor := func(plugin *plugins.Plugin) core.HTTPRouteMux {
+		return recordingRouteMux{pluginName: plugin.GetName(), routes: &routes}
+	}
+
+	if err := s.RegisterHTTPRoutes(muxFor); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := routes, []string{"operators.packages GET /operators/{name}/logo"}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	s.pluginsWithServers = append(s.pluginsWithServers, PluginWithServer{Plugin: &plugins.Plugin{Name: "failing"}, Server: routingServer{err: fmt.Errorf("boom")}})
+	if err := s.RegisterHTTPRoutes(muxFor); err == nil || !strings.Contains(err.Error(), `"failing"`) {
+		t.Errorf("got: %v, want: an error of the plugin %q", err, "failing")
+	}
+}
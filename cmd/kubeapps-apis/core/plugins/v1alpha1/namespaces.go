@@ -0,0 +1,60 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+)
+
+// PluginNamespaces maps the names of the plugins scoped to some namespaces to
+// the namespaces in which they are enabled. Plugins which are not included
+// are enabled in every namespace.
+type PluginNamespaces map[string][]string
+
+// ParsePluginNamespaces parses the plugin namespaces from values such as
+// "fluxv2.packages=team-a,team-b".
+func ParsePluginNamespaces(values []string) (PluginNamespaces, error) {
+	pluginNamespaces := PluginNamespaces{}
+	for _, value := range values {
+		pluginName, namespaces, found := strings.Cut(value, "=")
+		if !found || pluginName == "" || namespaces == "" {
+			return nil, fmt.Errorf("invalid plugin namespaces %q, expected <plugin name>=<namespace>[,<namespace>...]", value)
+		}
+		for _, namespace := range strings.Split(namespaces, ",") {
+			if namespace = strings.TrimSpace(namespace); namespace != "" {
+				pluginNamespaces[pluginName] = append(pluginNamespaces[pluginName], namespace)
+			}
+		}
+	}
+	return pluginNamespaces, nil
+}
+
+// IsEnabled returns whether the plugin is enabled in the given namespace. A
+// plugin scoped to some namespaces is not enabled for requests across all
+// namespaces.
+func (pn PluginNamespaces) IsEnabled(plugin *plugins.Plugin, namespace string) bool {
+	namespaces, scoped := pn[plugin.GetName()]
+	if !scoped {
+		return true
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEnabled returns an Unimplemented error if the plugin is not enabled in
+// the given namespace.
+func (pn PluginNamespaces) CheckEnabled(plugin *plugins.Plugin, namespace string) error {
+	if !pn.IsEnabled(plugin, namespace) {
+		return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("The plugin %q is not enabled in the namespace %q", plugin.GetName(), namespace))
+	}
+	return nil
+}
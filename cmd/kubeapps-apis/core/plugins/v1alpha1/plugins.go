@@ -5,6 +5,7 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
@@ -15,13 +16,20 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/bufbuild/connect-go"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
 	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugins/pkg/resources"
 	"github.com/vmware-tanzu/kubeapps/pkg/kube"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	log "k8s.io/klog/v2"
@@ -32,7 +40,21 @@ const (
 	grpcRegisterFunction    = "RegisterWithGRPCServer"
 	gatewayRegisterFunction = "RegisterHTTPHandlerFromEndpoint"
 	pluginDetailFunction    = "GetPluginDetail"
-	clustersCAFilesPrefix   = "/etc/additional-clusters-cafiles"
+	// The capabilities function is optional, plugins without it declaring none.
+	pluginCapabilitiesFunction = "GetPluginCapabilities"
+	// The dependencies function is optional, plugins without it depending on
+	// no other plugin.
+	pluginDependenciesFunction = "GetPluginDependencies"
+	clustersCAFilesPrefix      = "/etc/additional-clusters-cafiles"
+	// The maximum number of concurrent access reviews when filtering the
+	// namespaces accessible to a user.
+	accessibleNamespacesWorkers = 10
+	// The timeout of the request made to each configured cluster when
+	// validating the clusters config on startup.
+	clusterValidationTimeout = 10 * time.Second
+	// The interval between the discovery calls made while waiting for the API
+	// server to be reachable on startup.
+	apiServerPollInterval = 2 * time.Second
 )
 
 // GRPCPluginRegistrationOptions defines the single argument that
@@ -51,12 +73,23 @@ type GRPCPluginRegistrationOptions struct {
 	Mux *http.ServeMux
 
 	LocalPort int
+
+	// LeaderElected is closed once this replica is elected as leader, or straight
+	// away if leader election is disabled. Plugins should wait for it before
+	// starting watch-heavy background work, such as watching resources in the cluster.
+	LeaderElected <-chan struct{}
+
+	// HandlerOptions must be passed to the connect handlers registered by the
+	// plugin, so that the interceptors of the core server also apply to them.
+	HandlerOptions []connect.HandlerOption
 }
 
 // PluginWithServer keeps a record of a GRPC server and its plugin detail.
 type PluginWithServer struct {
 	Plugin *plugins.Plugin
 	Server interface{}
+	// Capabilities declared by the plugin at registration, if any.
+	Capabilities *plugins.PluginCapabilities
 }
 
 // PluginsServer implements the API defined in "plugins.proto"
@@ -68,9 +101,27 @@ type PluginsServer struct {
 
 	// The parsed config for clusters in a multi-cluster setup.
 	clustersConfig kube.ClustersConfig
+
+	// Closed once this replica is elected as leader, see GRPCPluginRegistrationOptions.
+	leaderElected <-chan struct{}
+
+	// The options passed to the connect handlers registered by the plugins.
+	handlerOptions []connect.HandlerOption
+
+	// The plugins restricted to some namespaces.
+	pluginNamespaces PluginNamespaces
+
+	// The plugins dialed by the gateway at another address than this server's.
+	pluginGatewayAddrs PluginGatewayAddrs
+
+	// The config getter shared with the plugins, initialised when registering them.
+	configGetter core.KubernetesConfigGetter
+
+	// The timeout of the requests made with the service account of the server.
+	kubernetesClientTimeout time.Duration
 }
 
-func NewPluginsServer(serveOpts core.ServeOptions, gwArgs core.GatewayHandlerArgs, mux *http.ServeMux) (*PluginsServer, error) {
+func NewPluginsServer(serveOpts core.ServeOptions, gwArgs core.GatewayHandlerArgs, mux *http.ServeMux, leaderElected <-chan struct{}, handlerOptions []connect.HandlerOption) (*PluginsServer, error) {
 	// Store the serveOptions in the global 'pluginsServeOpts' variable
 
 	// Find all .so plugins in the specified plugins directory.
@@ -79,7 +130,11 @@ func NewPluginsServer(serveOpts core.ServeOptions, gwArgs core.GatewayHandlerArg
 		log.Fatalf("Failed to check for plugins: %v", err)
 	}
 
-	ps := &PluginsServer{}
+	ps := &PluginsServer{
+		leaderElected:           leaderElected,
+		handlerOptions:          handlerOptions,
+		kubernetesClientTimeout: serveOpts.KubernetesClientTimeout,
+	}
 
 	// get the parsed kube.ClustersConfig from the serveOpts
 	clustersConfig, err := getClustersConfigFromServeOpts(serveOpts)
@@ -88,6 +143,39 @@ func NewPluginsServer(serveOpts core.ServeOptions, gwArgs core.GatewayHandlerArg
 	}
 	ps.clustersConfig = clustersConfig
 
+	if serveOpts.WaitForAPIServer > 0 {
+		restConfig, err := getRestConfig(serveOpts)
+		if err != nil {
+			return nil, err
+		}
+		if err := waitForAPIServer(restConfig, serveOpts.WaitForAPIServer); err != nil {
+			return nil, err
+		}
+	}
+
+	if serveOpts.ValidateClusters {
+		restConfig, err := getRestConfig(serveOpts)
+		if err != nil {
+			return nil, err
+		}
+		if err := kube.ValidateClusters(restConfig, clustersConfig, clusterValidationTimeout); err != nil {
+			return nil, fmt.Errorf("failed to validate the clusters config %q: %w", serveOpts.ClustersConfigPath, err)
+		}
+		log.InfoS("Validated the configured clusters", "clusters", clustersConfig.Names())
+	}
+
+	pluginNamespaces, err := ParsePluginNamespaces(serveOpts.PluginNamespaces)
+	if err != nil {
+		return nil, err
+	}
+	ps.pluginNamespaces = pluginNamespaces
+
+	pluginGatewayAddrs, err := ParsePluginGatewayAddrs(serveOpts.PluginGatewayAddrs)
+	if err != nil {
+		return nil, err
+	}
+	ps.pluginGatewayAddrs = pluginGatewayAddrs
+
 	err = ps.registerPlugins(pluginPaths, gwArgs, serveOpts, mux)
 	if err != nil {
 		return nil, fmt.Errorf("failed to register plugins: %w", err)
@@ -96,6 +184,56 @@ func NewPluginsServer(serveOpts core.ServeOptions, gwArgs core.GatewayHandlerArg
 	return ps, nil
 }
 
+// NumPlugins returns the number of registered plugins.
+func (s *PluginsServer) NumPlugins() int {
+	return len(s.pluginsWithServers)
+}
+
+// DefaultCluster returns the name of the cluster on which Kubeapps is
+// installed, targeted by the requests not giving a cluster.
+func (s *PluginsServer) DefaultCluster() string {
+	return s.clustersConfig.KubeappsClusterName
+}
+
+// PluginNamespaces returns the plugins restricted to some namespaces.
+func (s *PluginsServer) PluginNamespaces() PluginNamespaces {
+	return s.pluginNamespaces
+}
+
+// AccessibleNamespaces returns the names of the active namespaces of the cluster
+// which the user of the request can access. When the user cannot list the
+// namespaces, they are listed with the service account of the server and then
+// filtered with a SelfSubjectAccessReview for each.
+func (s *PluginsServer) AccessibleNamespaces(ctx context.Context, headers http.Header, cluster string) ([]string, error) {
+	if s.configGetter == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unable to list the namespaces without a configured cluster"))
+	}
+	userTypedClientFunc := func() (kubernetes.Interface, error) {
+		config, err := s.configGetter(headers, cluster)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(config)
+	}
+	serviceAccountTypedClientFunc := func() (kubernetes.Interface, error) {
+		config, err := core.InClusterConfig(s.kubernetesClientTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(config)
+	}
+
+	namespaceList, err := resources.FindAccessibleNamespaces(userTypedClientFunc, serviceAccountTypedClientFunc, accessibleNamespacesWorkers)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{}
+	for _, ns := range resources.FilterActiveNamespaces(namespaceList) {
+		namespaces = append(namespaces, ns.Name)
+	}
+	return namespaces, nil
+}
+
 // sortPlugins returns a consistently ordered slice.
 func sortPlugins(p []PluginWithServer) {
 	sort.Slice(p, func(i, j int) bool { return ComparePlugin(p[i].Plugin, p[j].Plugin) })
@@ -110,9 +248,18 @@ func (s *PluginsServer) GetConfiguredPlugins(ctx context.Context, in *connect.Re
 	// this gets logged twice (liveness and readiness checks) every 10 seconds and
 	// really adds a lot of noise to the logs, so lowering verbosity
 	log.V(4).Infof("+core GetConfiguredPlugins")
-	pluginDetails := make([]*plugins.Plugin, len(s.pluginsWithServers))
-	for i, p := range s.pluginsWithServers {
-		pluginDetails[i] = p.Plugin
+	pluginDetails := []*plugins.Plugin{}
+	for _, p := range s.pluginsWithServers {
+		if namespace := in.Msg.GetNamespace(); namespace != "" && !s.pluginNamespaces.IsEnabled(p.Plugin, namespace) {
+			continue
+		}
+		pluginDetail := p.Plugin
+		if p.Capabilities != nil {
+			// The plugin detail is shared with the plugin, so it is not modified.
+			pluginDetail = proto.Clone(p.Plugin).(*plugins.Plugin)
+			pluginDetail.Capabilities = p.Capabilities
+		}
+		pluginDetails = append(pluginDetails, pluginDetail)
 	}
 	return connect.NewResponse(&plugins.GetConfiguredPluginsResponse{
 		Plugins: pluginDetails,
@@ -127,41 +274,242 @@ func (s *PluginsServer) registerPlugins(pluginPaths []string, gwArgs core.Gatewa
 	if err != nil {
 		return fmt.Errorf("unable to create a ClientGetter: %w", err)
 	}
+	s.configGetter = configGetter
+
+	// The plugins are opened and their servers created after those of the
+	// plugins they depend on, concurrently for the plugins of a same dependency
+	// level when the registration concurrency is above 1, which is opt-in since
+	// the plugins may not be safe to register concurrently. Their gateway
+	// handlers are registered afterwards,
+	// in the dependency order, since the gateway mux is not safe for
+	// concurrent use.
+	opened, err := openPlugins(pluginPaths, serveOpts.PluginRegistrationConcurrency, openPlugin)
+	if err != nil {
+		return err
+	}
 
-	for _, pluginPath := range pluginPaths {
-		p, err := plugin.Open(pluginPath)
-		if err != nil {
-			return fmt.Errorf("unable to open plugin %q: %w", pluginPath, err)
-		}
+	levels, err := dependencyLevels(opened)
+	if err != nil {
+		return err
+	}
 
-		var pluginDetail *plugins.Plugin
-		if pluginDetail, err = getPluginDetail(p, pluginPath); err != nil {
+	ordered := []openedPlugin{}
+	for _, level := range levels {
+		if err := createPluginServers(opened, level, serveOpts.PluginRegistrationConcurrency, func(o *openedPlugin) error {
+			return s.createPluginServer(o, configGetter, serveOpts, mux)
+		}); err != nil {
 			return err
 		}
+		for _, i := range level {
+			ordered = append(ordered, opened[i])
+		}
+	}
 
-		if grpcServer, err := s.registerGRPC(p, pluginDetail, configGetter, serveOpts, mux); err != nil {
+	for _, o := range ordered {
+		pluginGWArgs, err := s.pluginGatewayAddrs.gatewayArgsFor(o.Plugin, gwArgs).ForPlugin(o.Plugin.GetName())
+		if err != nil {
+			return fmt.Errorf("unable to create the gateway mux of plugin %q: %w", o.Plugin.GetName(), err)
+		}
+		if err = registerHTTP(o.p, o.Plugin, pluginGWArgs); err != nil {
 			return err
-		} else {
-			pluginsWithServers = append(pluginsWithServers, PluginWithServer{
-				Plugin: pluginDetail,
-				Server: grpcServer,
-			})
 		}
+		pluginsWithServers = append(pluginsWithServers, o.PluginWithServer)
+	}
 
-		if err = registerHTTP(p, pluginDetail, gwArgs); err != nil {
+	// The fixtures plugin is registered like the others, for the end-to-end
+	// tests, but is only allowed for the local development since it serves
+	// canned packages to all the users.
+	if serveOpts.PackagesFixturePath != "" {
+		if !serveOpts.UnsafeLocalDevKubeconfig {
+			return fmt.Errorf("the packages fixture %q is only served with the unsafe local dev kubeconfig", serveOpts.PackagesFixturePath)
+		}
+		fixturesPlugin, err := newFixturesPlugin(serveOpts.PackagesFixturePath)
+		if err != nil {
 			return err
 		}
-
-		log.InfoS("Successfully registered plugin", "pluginPath", pluginPath)
+		log.InfoS("Registered the packages fixture plugin", "path", serveOpts.PackagesFixturePath)
+		pluginsWithServers = append(pluginsWithServers, fixturesPlugin)
 	}
 
 	sortPlugins(pluginsWithServers)
 
 	s.pluginsWithServers = pluginsWithServers
+	log.InfoS("Registered plugins", "count", len(pluginsWithServers))
+
+	return nil
+}
+
+// openedPlugin is an opened plugin, whose server is created once those of
+// its dependencies are, before the registration of its gateway handlers.
+type openedPlugin struct {
+	PluginWithServer
+	p *plugin.Plugin
+	// dependencies are the names of the plugins whose servers are created
+	// before the one of the plugin.
+	dependencies []string
+}
+
+// openPlugins opens the plugins of the given paths with up to concurrency
+// plugins at a time, so that a slow plugin does not delay the others. The
+// plugins are returned in the order of their paths, or the errors of all the
+// plugins failing.
+func openPlugins(pluginPaths []string, concurrency int, open func(pluginPath string) (openedPlugin, error)) ([]openedPlugin, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	opened := make([]openedPlugin, len(pluginPaths))
+	errs := make([]error, len(pluginPaths))
+	g := errgroup.Group{}
+	g.SetLimit(concurrency)
+	for i, pluginPath := range pluginPaths {
+		i, pluginPath := i, pluginPath
+		g.Go(func() error {
+			opened[i], errs[i] = open(pluginPath)
+			return nil
+		})
+	}
+	// The errors are collected rather than returned by the workers, so that
+	// the errors of all the plugins are reported.
+	_ = g.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// openPlugin opens the plugin at the given path, without creating its server.
+func openPlugin(pluginPath string) (openedPlugin, error) {
+	p, err := plugin.Open(pluginPath)
+	if err != nil {
+		return openedPlugin{}, fmt.Errorf("unable to open plugin %q: %w", pluginPath, err)
+	}
+
+	pluginDetail, err := getPluginDetail(p, pluginPath)
+	if err != nil {
+		return openedPlugin{}, err
+	}
 
+	capabilities, err := getPluginCapabilities(p, pluginPath)
+	if err != nil {
+		return openedPlugin{}, err
+	}
+
+	dependencies, err := getPluginDependencies(p, pluginPath)
+	if err != nil {
+		return openedPlugin{}, err
+	}
+
+	return openedPlugin{
+		PluginWithServer: PluginWithServer{
+			Plugin:       pluginDetail,
+			Capabilities: capabilities,
+		},
+		p:            p,
+		dependencies: dependencies,
+	}, nil
+}
+
+// createPluginServer creates the server of the opened plugin.
+func (s *PluginsServer) createPluginServer(o *openedPlugin, configGetter core.KubernetesConfigGetter, serveOpts core.ServeOptions, mux *http.ServeMux) error {
+	// The start of the registration is logged so that a plugin hanging while
+	// registering can be identified.
+	start := time.Now()
+	log.InfoS("Registering plugin", "plugin", o.Plugin.GetName())
+	grpcServer, err := s.registerGRPC(o.p, o.Plugin, configGetter, serveOpts, mux)
+	if err != nil {
+		return err
+	}
+	o.Server = grpcServer
+	log.InfoS("Successfully registered plugin", "plugin", o.Plugin.GetName(), "duration", time.Since(start))
 	return nil
 }
 
+// createPluginServers creates the servers of the opened plugins of the given
+// indexes, which do not depend on each other, with up to concurrency plugins at
+// a time. The errors of all the plugins failing are returned.
+func createPluginServers(opened []openedPlugin, indexes []int, concurrency int, create func(o *openedPlugin) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	errs := make([]error, len(indexes))
+	g := errgroup.Group{}
+	g.SetLimit(concurrency)
+	for j, i := range indexes {
+		j, i := j, i
+		g.Go(func() error {
+			errs[j] = create(&opened[i])
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return errors.Join(errs...)
+}
+
+// dependencyLevels sorts the opened plugins by their dependencies, returning
+// the indexes of the plugins by level: the plugins of a level only depend on
+// those of the previous levels. The plugins of a level are in the order of
+// their paths. An error is returned if a plugin depends on a plugin which is
+// not registered, or if the dependencies of plugins form a cycle.
+func dependencyLevels(opened []openedPlugin) ([][]int, error) {
+	indexes := map[string]int{}
+	for i, o := range opened {
+		indexes[o.Plugin.GetName()] = i
+	}
+
+	pending := map[int]int{}
+	dependents := map[int][]int{}
+	errs := []error{}
+	for i, o := range opened {
+		for _, dependency := range o.dependencies {
+			d, ok := indexes[dependency]
+			if !ok {
+				errs = append(errs, fmt.Errorf("plugin %q depends on the plugin %q, which is not registered", o.Plugin.GetName(), dependency))
+				continue
+			}
+			pending[i]++
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	levels := [][]int{}
+	level := []int{}
+	for i := range opened {
+		if pending[i] == 0 {
+			level = append(level, i)
+		}
+	}
+	sorted := 0
+	for len(level) > 0 {
+		levels = append(levels, level)
+		sorted += len(level)
+		next := []int{}
+		for _, d := range level {
+			for _, i := range dependents[d] {
+				pending[i]--
+				if pending[i] == 0 {
+					next = append(next, i)
+				}
+			}
+		}
+		sort.Ints(next)
+		level = next
+	}
+
+	if sorted < len(opened) {
+		cycle := []string{}
+		for i, o := range opened {
+			if pending[i] > 0 {
+				cycle = append(cycle, o.Plugin.GetName())
+			}
+		}
+		return nil, fmt.Errorf("the dependencies of the plugins %s form a cycle", strings.Join(cycle, ", "))
+	}
+	return levels, nil
+}
+
 // registerGRPC finds and calls the required function for registering the plugin for the GRPC server.
 func (s *PluginsServer) registerGRPC(p *plugin.Plugin, pluginDetail *plugins.Plugin, configGetter core.KubernetesConfigGetter, serveOpts core.ServeOptions, mux *http.ServeMux) (interface{}, error) {
 	grpcRegFn, err := p.Lookup(grpcRegisterFunction)
@@ -186,6 +534,8 @@ func (s *PluginsServer) registerGRPC(p *plugin.Plugin, pluginDetail *plugins.Plu
 		ClientBurst:      serveOpts.Burst,
 		Mux:              mux,
 		LocalPort:        serveOpts.Port,
+		LeaderElected:    s.leaderElected,
+		HandlerOptions:   s.handlerOptions,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("plug-in %q failed to register due to: %v", pluginDetail, err)
@@ -232,6 +582,44 @@ func getPluginDetail(p *plugin.Plugin, pluginPath string) (*plugins.Plugin, erro
 	return fn(), nil
 }
 
+// getPluginCapabilities returns the core.plugins.PluginCapabilities declared by
+// the plugin itself, or nil if the plugin does not declare any.
+func getPluginCapabilities(p *plugin.Plugin, pluginPath string) (*plugins.PluginCapabilities, error) {
+	pluginCapabilitiesFn, err := p.Lookup(pluginCapabilitiesFunction)
+	if err != nil {
+		return nil, nil
+	}
+
+	type pluginCapabilitiesFunctionType = func() *plugins.PluginCapabilities
+
+	fn, ok := pluginCapabilitiesFn.(pluginCapabilitiesFunctionType)
+	if !ok {
+		var stubFn pluginCapabilitiesFunctionType = func() *plugins.PluginCapabilities { return &plugins.PluginCapabilities{} }
+		return nil, fmt.Errorf("unable to use %q in plugin %q due to a mismatched signature. \nwant: %T\ngot: %T", pluginCapabilitiesFunction, pluginPath, stubFn, pluginCapabilitiesFn)
+	}
+
+	return fn(), nil
+}
+
+// getPluginDependencies returns the names of the plugins on which the plugin
+// depends, or nil if the plugin does not declare any.
+func getPluginDependencies(p *plugin.Plugin, pluginPath string) ([]string, error) {
+	pluginDependenciesFn, err := p.Lookup(pluginDependenciesFunction)
+	if err != nil {
+		return nil, nil
+	}
+
+	type pluginDependenciesFunctionType = func() []string
+
+	fn, ok := pluginDependenciesFn.(pluginDependenciesFunctionType)
+	if !ok {
+		var stubFn pluginDependenciesFunctionType = func() []string { return nil }
+		return nil, fmt.Errorf("unable to use %q in plugin %q due to a mismatched signature. \nwant: %T\ngot: %T", pluginDependenciesFunction, pluginPath, stubFn, pluginDependenciesFn)
+	}
+
+	return fn(), nil
+}
+
 // registerHTTP finds and calls the required function for registering the plugin for the HTTP gateway server.
 func registerHTTP(p *plugin.Plugin, pluginDetail *plugins.Plugin, gwArgs core.GatewayHandlerArgs) error {
 	gwRegFn, err := p.Lookup(gatewayRegisterFunction)
@@ -246,7 +634,7 @@ func registerHTTP(p *plugin.Plugin, pluginDetail *plugins.Plugin, gwArgs core.Ga
 		var stubFn gatewayRegisterFunctionType = func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error { return nil }
 		return fmt.Errorf("unable to use %q in plugin %v due to mismatched signature.\nwant: %T\ngot: %T", gatewayRegisterFunction, pluginDetail, stubFn, gwRegFn)
 	}
-	return gwfn(gwArgs.Ctx, gwArgs.Mux, gwArgs.Addr, gwArgs.DialOptions)
+	return gwArgs.Register(gwfn)
 }
 
 // listSOFiles returns the absolute paths of all .so files found in any of the provided plugin directories.
@@ -284,6 +672,54 @@ func listSOFiles(fsys fs.FS, pluginDirs []string) ([]string, error) {
 // The returned function utilizes the user credential present in the request context.
 // The plugins just have to call this function passing the context in order to retrieve the configured k8s client
 func createConfigGetter(serveOpts core.ServeOptions, clustersConfig kube.ClustersConfig) (core.KubernetesConfigGetter, error) {
+	restConfig, err := getRestConfig(serveOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// return the closure function that takes the context, but preserving the required scope,
+	// 'inClusterConfig' and 'config'
+	return createConfigGetterWithParams(restConfig, serveOpts, clustersConfig)
+}
+
+// waitForAPIServer waits, until the timeout, for the API server to respond to a
+// discovery call, so that the plugins are not registered while it is still
+// unreachable, such as when the whole cluster is starting.
+func waitForAPIServer(restConfig *rest.Config, timeout time.Duration) error {
+	config := rest.CopyConfig(restConfig)
+	config.Timeout = apiServerPollInterval
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to create a discovery client: %w", err)
+	}
+	return pollAPIServer(func() error {
+		_, err := client.ServerVersion()
+		return err
+	}, apiServerPollInterval, timeout)
+}
+
+// pollAPIServer calls serverVersion at each interval until it succeeds,
+// failing with its last error after the timeout.
+func pollAPIServer(serverVersion func() error, interval, timeout time.Duration) error {
+	start := time.Now()
+	var lastErr error
+	err := wait.PollImmediate(interval, timeout, func() (bool, error) {
+		if lastErr = serverVersion(); lastErr != nil {
+			log.InfoS("Waiting for the API server to be reachable", "error", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the API server is still unreachable after %v: %w", timeout, lastErr)
+	}
+	log.InfoS("The API server is reachable", "duration", time.Since(start))
+	return nil
+}
+
+// getRestConfig returns the config of the cluster on which the server runs, read
+// from the local kubeconfig when running locally for development.
+func getRestConfig(serveOpts core.ServeOptions) (*rest.Config, error) {
 	var restConfig *rest.Config
 	var err error
 
@@ -306,10 +742,7 @@ func createConfigGetter(serveOpts core.ServeOptions, clustersConfig kube.Cluster
 			return nil, fmt.Errorf("unable to get inClusterConfig: %w", err)
 		}
 	}
-
-	// return the closure function that takes the context, but preserving the required scope,
-	// 'inClusterConfig' and 'config'
-	return createConfigGetterWithParams(restConfig, serveOpts, clustersConfig)
+	return restConfig, nil
 }
 
 // createClientGetter takes the required params and returns the closure function.
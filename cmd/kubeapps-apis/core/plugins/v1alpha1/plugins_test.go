@@ -8,15 +8,21 @@ import (
 	"fmt"
 	"io/fs"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/bufbuild/connect-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packages "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	packagesConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
 	plugins "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
 	"github.com/vmware-tanzu/kubeapps/pkg/kube"
 	"k8s.io/client-go/rest"
@@ -25,12 +31,15 @@ import (
 var ignoreUnexported = cmpopts.IgnoreUnexported(
 	PluginWithServer{},
 	plugins.Plugin{},
+	plugins.PluginCapabilities{},
 )
 
 func TestPluginsAvailable(t *testing.T) {
 	testCases := []struct {
 		name              string
 		configuredPlugins []PluginWithServer
+		pluginNamespaces  PluginNamespaces
+		namespace         string
 		expectedPlugins   []*plugins.Plugin
 	}{
 		{
@@ -60,6 +69,84 @@ func TestPluginsAvailable(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "it returns only the plugins enabled in the requested namespace",
+			configuredPlugins: []PluginWithServer{
+				{
+					Plugin: &plugins.Plugin{
+						Name:    "fluxv2.packages",
+						Version: "v1alpha1",
+					},
+				},
+				{
+					Plugin: &plugins.Plugin{
+						Name:    "kapp_controller.packages",
+						Version: "v1alpha1",
+					},
+				},
+			},
+			pluginNamespaces: PluginNamespaces{"fluxv2.packages": {"team-a"}},
+			namespace:        "team-b",
+			expectedPlugins: []*plugins.Plugin{
+				{
+					Name:    "kapp_controller.packages",
+					Version: "v1alpha1",
+				},
+			},
+		},
+		{
+			name: "it returns the plugins enabled in some namespaces when no namespace is requested",
+			configuredPlugins: []PluginWithServer{
+				{
+					Plugin: &plugins.Plugin{
+						Name:    "fluxv2.packages",
+						Version: "v1alpha1",
+					},
+				},
+			},
+			pluginNamespaces: PluginNamespaces{"fluxv2.packages": {"team-a"}},
+			expectedPlugins: []*plugins.Plugin{
+				{
+					Name:    "fluxv2.packages",
+					Version: "v1alpha1",
+				},
+			},
+		},
+		{
+			name: "it returns the capabilities declared by the plugins",
+			configuredPlugins: []PluginWithServer{
+				{
+					Plugin: &plugins.Plugin{
+						Name:    "fluxv2.packages",
+						Version: "v1alpha1",
+					},
+					Capabilities: &plugins.PluginCapabilities{
+						SupportsOci: true,
+						AuthTypes:   []string{"PACKAGE_REPOSITORY_AUTH_TYPE_BASIC_AUTH"},
+					},
+				},
+				{
+					Plugin: &plugins.Plugin{
+						Name:    "kapp_controller.packages",
+						Version: "v1alpha1",
+					},
+				},
+			},
+			expectedPlugins: []*plugins.Plugin{
+				{
+					Name:    "fluxv2.packages",
+					Version: "v1alpha1",
+					Capabilities: &plugins.PluginCapabilities{
+						SupportsOci: true,
+						AuthTypes:   []string{"PACKAGE_REPOSITORY_AUTH_TYPE_BASIC_AUTH"},
+					},
+				},
+				{
+					Name:    "kapp_controller.packages",
+					Version: "v1alpha1",
+				},
+			},
+		},
 		// We may later allow requesting just plugins for a specific service.
 	}
 
@@ -67,9 +154,12 @@ func TestPluginsAvailable(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ps := PluginsServer{
 				pluginsWithServers: tc.configuredPlugins,
+				pluginNamespaces:   tc.pluginNamespaces,
 			}
 
-			resp, err := ps.GetConfiguredPlugins(context.TODO(), connect.NewRequest(&plugins.GetConfiguredPluginsRequest{}))
+			resp, err := ps.GetConfiguredPlugins(context.TODO(), connect.NewRequest(&plugins.GetConfiguredPluginsRequest{
+				Namespace: tc.namespace,
+			}))
 			if err != nil {
 				t.Fatalf("%+v", err)
 			}
@@ -77,6 +167,270 @@ func TestPluginsAvailable(t *testing.T) {
 			if got, want := resp.Msg.Plugins, tc.expectedPlugins; !cmp.Equal(want, got, ignoreUnexported) {
 				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, ignoreUnexported))
 			}
+			// The plugin details shared with the plugins are not modified.
+			for _, p := range tc.configuredPlugins {
+				if p.Plugin.Capabilities != nil {
+					t.Errorf("got: %v, want: nil", p.Plugin.Capabilities)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePluginNamespaces(t *testing.T) {
+	testCases := []struct {
+		name          string
+		values        []string
+		expected      PluginNamespaces
+		expectedError bool
+	}{
+		{
+			name:     "no plugin namespaces",
+			expected: PluginNamespaces{},
+		},
+		{
+			name:   "plugins restricted to some namespaces",
+			values: []string{"fluxv2.packages=team-a, team-b", "helm.packages=team-c"},
+			expected: PluginNamespaces{
+				"fluxv2.packages": {"team-a", "team-b"},
+				"helm.packages":   {"team-c"},
+			},
+		},
+		{
+			name:          "missing namespaces",
+			values:        []string{"fluxv2.packages"},
+			expectedError: true,
+		},
+		{
+			name:          "missing plugin name",
+			values:        []string{"=team-a"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePluginNamespaces(tc.values)
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got error: %t, want error: %t, err: %+v", got, want, err)
+			}
+			if !cmp.Equal(tc.expected, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(tc.expected, got))
+			}
+		})
+	}
+}
+
+func TestParsePluginGatewayAddrs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		values        []string
+		expected      PluginGatewayAddrs
+		expectedError bool
+	}{
+		{
+			name:     "no plugin gateway addresses",
+			expected: PluginGatewayAddrs{},
+		},
+		{
+			name:   "plugins dialed at other addresses",
+			values: []string{"fluxv2.packages=kubeapps-flux-plugin:50051", " helm.packages = 10.0.0.1:8080 "},
+			expected: PluginGatewayAddrs{
+				"fluxv2.packages": "kubeapps-flux-plugin:50051",
+				"helm.packages":   "10.0.0.1:8080",
+			},
+		},
+		{
+			name:          "missing port",
+			values:        []string{"fluxv2.packages=kubeapps-flux-plugin"},
+			expectedError: true,
+		},
+		{
+			name:          "missing plugin name",
+			values:        []string{"=kubeapps-flux-plugin:50051"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePluginGatewayAddrs(tc.values)
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got error: %t, want error: %t, err: %+v", got, want, err)
+			}
+			if !cmp.Equal(tc.expected, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(tc.expected, got))
+			}
+		})
+	}
+
+	t.Run("overrides the address of the configured plugins only", func(t *testing.T) {
+		addrs := PluginGatewayAddrs{"fluxv2.packages": "kubeapps-flux-plugin:50051"}
+		gwArgs := core.GatewayHandlerArgs{Addr: ":50051"}
+		if got, want := addrs.gatewayArgsFor(&plugins.Plugin{Name: "fluxv2.packages"}, gwArgs).Addr, "kubeapps-flux-plugin:50051"; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+		if got, want := addrs.gatewayArgsFor(&plugins.Plugin{Name: "helm.packages"}, gwArgs).Addr, ":50051"; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+}
+
+func TestPollAPIServer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		failures    int
+		expectedErr bool
+	}{
+		{
+			name: "reachable API server",
+		},
+		{
+			name:     "API server reachable after some attempts",
+			failures: 2,
+		},
+		{
+			name:        "unreachable API server",
+			failures:    1000,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := pollAPIServer(func() error {
+				calls++
+				if calls <= tc.failures {
+					return fmt.Errorf("connection refused")
+				}
+				return nil
+			}, time.Millisecond, 100*time.Millisecond)
+			if got, want := err != nil, tc.expectedErr; got != want {
+				t.Fatalf("got error: %t, want error: %t, err: %+v", got, want, err)
+			}
+			if !tc.expectedErr && calls != tc.failures+1 {
+				t.Errorf("got: %d calls, want: %d", calls, tc.failures+1)
+			}
+		})
+	}
+}
+
+type rediscoveringServer struct {
+	err   error
+	calls int
+}
+
+func (s *rediscoveringServer) RediscoverAPIs(ctx context.Context) error {
+	s.calls++
+	return s.err
+}
+
+func TestRediscoverAPIs(t *testing.T) {
+	resources := &rediscoveringServer{}
+	failing := &rediscoveringServer{err: fmt.Errorf("boom")}
+	s := &PluginsServer{
+		pluginsWithServers: []PluginWithServer{
+			{Plugin: &plugins.Plugin{Name: "resources"}, Server: resources},
+			{Plugin: &plugins.Plugin{Name: "helm.packages"}, Server: struct{}{}},
+			{Plugin: &plugins.Plugin{Name: "failing"}, Server: failing},
+		},
+	}
+
+	results := s.RediscoverAPIs(context.Background())
+
+	if got, want := len(results), 2; got != want {
+		t.Fatalf("got: %d results, want: %d, results: %v", got, want, results)
+	}
+	if err := results["resources"]; err != nil {
+		t.Errorf("got: %v, want: nil", err)
+	}
+	if err := results["failing"]; err == nil {
+		t.Errorf("got: nil, want an error")
+	}
+	if got, want := []int{resources.calls, failing.calls}, []int{1, 1}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestOpenPlugins(t *testing.T) {
+	testCases := []struct {
+		name           string
+		concurrency    int
+		failing        map[string]bool
+		expectedErrors []string
+	}{
+		{
+			name:        "one plugin at a time",
+			concurrency: 1,
+		},
+		{
+			name:        "several plugins at a time",
+			concurrency: 2,
+		},
+		{
+			name:        "concurrency defaults to one",
+			concurrency: 0,
+		},
+		{
+			name:           "reports the errors of all the plugins",
+			concurrency:    2,
+			failing:        map[string]bool{"/plugins/b.so": true, "/plugins/d.so": true},
+			expectedErrors: []string{"/plugins/b.so", "/plugins/d.so"},
+		},
+	}
+
+	pluginPaths := []string{"/plugins/a.so", "/plugins/b.so", "/plugins/c.so", "/plugins/d.so"}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			opened, err := openPlugins(pluginPaths, tc.concurrency, func(pluginPath string) (openedPlugin, error) {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				// Gives the other plugins the time to be opened concurrently.
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+				if tc.failing[pluginPath] {
+					return openedPlugin{}, fmt.Errorf("unable to open plugin %q", pluginPath)
+				}
+				return openedPlugin{PluginWithServer: PluginWithServer{Plugin: &plugins.Plugin{Name: pluginPath}}}, nil
+			})
+
+			limit := tc.concurrency
+			if limit < 1 {
+				limit = 1
+			}
+			if maxRunning > limit {
+				t.Errorf("got: %d plugins opened concurrently, want at most: %d", maxRunning, limit)
+			}
+			if len(tc.expectedErrors) > 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want an error")
+				}
+				for _, expected := range tc.expectedErrors {
+					if !strings.Contains(err.Error(), expected) {
+						t.Errorf("got: %v, want it to contain: %s", err, expected)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			names := []string{}
+			for _, o := range opened {
+				names = append(names, o.Plugin.GetName())
+			}
+			if got, want := names, pluginPaths; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
 		})
 	}
 }
@@ -85,6 +439,165 @@ func pluginEqual(a, b PluginWithServer) bool {
 	return a.Plugin.Name == b.Plugin.Name && a.Plugin.Version == b.Plugin.Version
 }
 
+func TestDependencyLevels(t *testing.T) {
+	testCases := []struct {
+		name           string
+		dependencies   map[string][]string
+		expectedLevels [][]string
+		expectedErrors []string
+	}{
+		{
+			name:           "plugins without dependencies are in a single level",
+			expectedLevels: [][]string{{"a", "b", "c", "d"}},
+		},
+		{
+			name: "plugins follow their dependencies",
+			dependencies: map[string][]string{
+				"a": {"c"},
+				"b": {"a", "d"},
+			},
+			expectedLevels: [][]string{{"c", "d"}, {"a"}, {"b"}},
+		},
+		{
+			name: "reports the missing dependencies",
+			dependencies: map[string][]string{
+				"a": {"e"},
+				"c": {"f"},
+			},
+			expectedErrors: []string{`plugin "a" depends on the plugin "e"`, `plugin "c" depends on the plugin "f"`},
+		},
+		{
+			name: "reports the plugins of a cycle",
+			dependencies: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"a"},
+			},
+			expectedErrors: []string{"the dependencies of the plugins a, b, c form a cycle"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opened := []openedPlugin{}
+			for _, name := range []string{"a", "b", "c", "d"} {
+				opened = append(opened, openedPlugin{
+					PluginWithServer: PluginWithServer{Plugin: &plugins.Plugin{Name: name}},
+					dependencies:     tc.dependencies[name],
+				})
+			}
+
+			levels, err := dependencyLevels(opened)
+			if len(tc.expectedErrors) > 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want an error")
+				}
+				for _, expected := range tc.expectedErrors {
+					if !strings.Contains(err.Error(), expected) {
+						t.Errorf("got: %v, want it to contain: %s", err, expected)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			names := [][]string{}
+			for _, level := range levels {
+				levelNames := []string{}
+				for _, i := range level {
+					levelNames = append(levelNames, opened[i].Plugin.GetName())
+				}
+				names = append(names, levelNames)
+			}
+			if got, want := names, tc.expectedLevels; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestFixturesPlugin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages.json")
+	fixture := `{
+		"availablePackageSummaries": [
+			{"availablePackageRef": {"identifier": "bitnami/apache"}, "name": "apache", "categories": ["Infrastructure"]},
+			{"availablePackageRef": {"identifier": "bitnami/wordpress"}, "name": "wordpress", "categories": ["CMS", "Infrastructure"]}
+		],
+		"availablePackageDetails": [
+			{"availablePackageRef": {"identifier": "bitnami/apache"}, "name": "apache", "version": {"pkgVersion": "1.0.0"}},
+			{"availablePackageRef": {"identifier": "bitnami/apache"}, "name": "apache", "version": {"pkgVersion": "2.0.0"}}
+		]
+	}`
+	if err := os.WriteFile(path, []byte(fixture), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	p, err := newFixturesPlugin(path)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server, ok := p.Server.(packagesConnect.PackagesServiceHandler)
+	if !ok {
+		t.Fatalf("got: %T, want: a packages plugin", p.Server)
+	}
+
+	summaries, err := server.GetAvailablePackageSummaries(context.Background(), connect.NewRequest(&packages.GetAvailablePackageSummariesRequest{}))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := summaries.Msg.GetCategories(), []string{"CMS", "Infrastructure"}; !cmp.Equal(got, want) {
+		t.Errorf(cmp.Diff(want, got))
+	}
+	if got, want := len(summaries.Msg.GetAvailablePackageSummaries()), 2; got != want {
+		t.Fatalf("got: %d, want: %d", got, want)
+	}
+	if got, want := summaries.Msg.GetAvailablePackageSummaries()[0].GetAvailablePackageRef().GetPlugin().GetName(), fixturesPluginName; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+
+	testCases := []struct {
+		name        string
+		identifier  string
+		pkgVersion  string
+		wantVersion string
+		wantCode    connect.Code
+	}{
+		{name: "it returns the first detail of the package", identifier: "bitnami/apache", wantVersion: "1.0.0"},
+		{name: "it returns the detail of the requested version", identifier: "bitnami/apache", pkgVersion: "2.0.0", wantVersion: "2.0.0"},
+		{name: "it returns not found for an unknown version", identifier: "bitnami/apache", pkgVersion: "3.0.0", wantCode: connect.CodeNotFound},
+		{name: "it returns not found for an unknown package", identifier: "bitnami/wordpress", wantCode: connect.CodeNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			detail, err := server.GetAvailablePackageDetail(context.Background(), connect.NewRequest(&packages.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &packages.AvailablePackageReference{Identifier: tc.identifier},
+				PkgVersion:          tc.pkgVersion,
+			}))
+			if tc.wantCode != 0 {
+				if got, want := connect.CodeOf(err), tc.wantCode; got != want {
+					t.Errorf("got: %v, want: %v", got, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := detail.Msg.GetAvailablePackageDetail().GetVersion().GetPkgVersion(), tc.wantVersion; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+
+	if err := os.WriteFile(path, []byte(`{"availablePackageSummaries": [{"unknown": true}]}`), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := newFixturesPlugin(path); err == nil {
+		t.Errorf("got: nil, want: an error for an invalid summary")
+	}
+}
+
 func TestSortPlugins(t *testing.T) {
 	testCases := []struct {
 		name              string
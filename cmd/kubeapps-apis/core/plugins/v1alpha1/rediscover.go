@@ -0,0 +1,36 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"reflect"
+
+	log "k8s.io/klog/v2"
+)
+
+// APIRediscoverer is implemented by the plugins keeping the APIs discovered on
+// the API server, such as the REST mappings of the resources plugin, so that
+// the APIs installed after the startup, such as the CRDs of Flux, are taken
+// into account without a restart.
+type APIRediscoverer interface {
+	RediscoverAPIs(ctx context.Context) error
+}
+
+// RediscoverAPIs re-runs the API discovery of the plugins implementing
+// APIRediscoverer, returning the error of each plugin by name, which is nil
+// when the APIs were rediscovered.
+func (s *PluginsServer) RediscoverAPIs(ctx context.Context) map[string]error {
+	results := map[string]error{}
+	for _, p := range s.GetPluginsSatisfyingInterface(reflect.TypeOf((*APIRediscoverer)(nil)).Elem()) {
+		err := p.Server.(APIRediscoverer).RediscoverAPIs(ctx)
+		if err != nil {
+			log.Errorf("Failed to rediscover the APIs of plugin %q: %v", p.Plugin.GetName(), err)
+		} else {
+			log.InfoS("Rediscovered the APIs of plugin", "plugin", p.Plugin.GetName())
+		}
+		results[p.Plugin.GetName()] = err
+	}
+	return results
+}
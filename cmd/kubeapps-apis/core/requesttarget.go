@@ -0,0 +1,32 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import "context"
+
+type requestTargetKey struct{}
+
+// RequestTarget is the cluster and namespace targeted by a request, as given by
+// the context of its message, such as the context of the available_package_ref.
+// The cluster is the default one when not given by the request, while the
+// namespace is empty.
+type RequestTarget struct {
+	Cluster   string
+	Namespace string
+}
+
+// ContextWithRequestTarget returns a copy of ctx tagged with the target of the
+// request, so that the interceptors and the plugins read the same values rather
+// than each parsing them out of the request.
+func ContextWithRequestTarget(ctx context.Context, target RequestTarget) context.Context {
+	return context.WithValue(ctx, requestTargetKey{}, target)
+}
+
+// RequestTargetFromContext returns the target of the request the context is
+// tagged with, if any. Only the unary requests are tagged, since the message of
+// the streaming ones is not received before they are handled.
+func RequestTargetFromContext(ctx context.Context) (RequestTarget, bool) {
+	target, ok := ctx.Value(requestTargetKey{}).(RequestTarget)
+	return target, ok
+}
@@ -0,0 +1,24 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestTargetFromContext(t *testing.T) {
+	if _, ok := RequestTargetFromContext(context.Background()); ok {
+		t.Errorf("got: a target, want: none for an untagged context")
+	}
+
+	want := RequestTarget{Cluster: "default", Namespace: "kubeapps"}
+	got, ok := RequestTargetFromContext(ContextWithRequestTarget(context.Background(), want))
+	if !ok {
+		t.Fatalf("got: no target, want: %+v", want)
+	}
+	if got != want {
+		t.Errorf("got: %+v, want: %+v", got, want)
+	}
+}
@@ -6,12 +6,22 @@ package core
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
 	"k8s.io/client-go/rest"
 )
 
+// DefaultPublicMethods are the methods which do not require a bearer token
+// when the authentication is required: the health checks and the listing of
+// the configured plugins, which the clients use to find the server version.
+var DefaultPublicMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+	"/kubeappsapis.core.plugins.v1alpha1.PluginsService/GetConfiguredPlugins",
+}
+
 // ServeOptions encapsulates the available command-line options.
 type ServeOptions struct {
 	Port                     int
@@ -24,15 +34,294 @@ type ServeOptions struct {
 	UnsafeLocalDevKubeconfig bool
 	QPS                      float32
 	Burst                    int
+	// Timeout of the requests of the in-cluster clients of the server, such as
+	// the operator logo proxy, so that they fail rather than hang when the API
+	// server is slow. 0 disables it.
+	KubernetesClientTimeout time.Duration
+	// Whether the JSON responses of the gateway use the original proto field
+	// names, such as "available_package_ref", rather than their lowerCamelCase
+	// JSON names, such as "availablePackageRef", the default.
+	JSONUseProtoNames bool
+	// JSON marshaling options of the gateway responses of the plugins, overriding
+	// the global ones, in the form <plugin name>=<option>[,<option>...], such as
+	// "fluxv2.packages=use-proto-names,emit-unpopulated".
+	PluginJSONOptions []string
+	// Maximum number of gateway responses marshaled concurrently, bounding the
+	// memory used by the serialization of large responses under load. The
+	// other responses wait for their turn. 0 disables the limit.
+	MaxConcurrentMarshals int
+	// Request logging options. The IP addresses of the callers are logged unless
+	// disabled for privacy.
+	LogRequestClientIPs bool
+	// Encoding of the request messages logged, with their sensitive fields
+	// redacted, at the verbosity 5: "json" or "prototext".
+	RequestLogMessageFormat string
+	// Regular expressions whose matches are redacted from all the logs and the
+	// audit records, such as the credentials embedded in the URLs. Only the
+	// submatches of the patterns with groups are redacted.
+	RedactPatterns []string
+	// Only 1 in LogSampleRate successful calls of each method is logged, 0 or 1
+	// logging all of them, while the failed calls and those taking at least
+	// the slow request threshold, if not 0, are always logged.
+	LogSampleRate           int
+	LogSlowRequestThreshold time.Duration
+	// Log verbosity of the plugins, overriding the global verbosity for the
+	// requests they handle, in the form <plugin name>=<level>, such as
+	// "helm.packages=4".
+	PluginLogLevels []string
+	// CIDRs of the proxies trusted to set X-Forwarded-For when resolving the
+	// client IP address, and X-Forwarded-Host and X-Forwarded-Prefix when
+	// rewriting the redirects. By default only the direct peer is used.
+	TrustedProxies []string
+	// Duration after which idle connections, including new connections on which
+	// nothing is sent, are closed. 0 disables the timeout.
+	ConnectionIdleTimeout time.Duration
+	// Log verbosity at which the opening and closing of the connections are logged.
+	ConnectionLogVerbosity int
+	// Maximum size, in bytes, of the request headers. 0 uses the default of 1MB.
+	MaxHeaderBytes int
+	// Initial HTTP/2 flow-control windows, in bytes, of each stream and of each
+	// connection, which can be raised for the clients behind high-latency links.
+	// 0 uses the defaults.
+	HTTP2InitialWindowSize     int32
+	HTTP2InitialConnWindowSize int32
+	// TLS options. When TLSCertFile and TLSKeyFile are set, the server is served over
+	// TLS. When TLSClientCAFile is also set, client certificates are verified against it.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	// Minimum TLS version, such as "1.2", and allowlist of cipher suite names,
+	// applied both when serving over TLS and to the dials of the gateway. The
+	// cipher suites only apply up to TLS 1.2, since those of TLS 1.3 are not
+	// configurable. When empty, the secure cipher suites of Go are used.
+	TLSMinVersion   string
+	TLSCipherSuites []string
+	// Path of the file of the bearer token attached by the gateway, as the
+	// x-gateway-authorization metadata, to the calls it proxies, for plugins
+	// requiring authenticated intra-service calls. The file is read for each call.
+	GatewayTokenFile string
+	// Full names of the methods reachable through the REST gateway, such as
+	// "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries",
+	// or "/<service full name>/*" for all the methods of a service. The other
+	// methods are only served with the gRPC, gRPC-web and connect protocols.
+	// When empty, all the methods are exposed.
+	GatewayExposedMethods []string
+	// Leader election options. When enabled, only the replica holding the lease runs
+	// the watch-heavy background work of the plugins, while all replicas serve requests.
+	EnableLeaderElection    bool
+	LeaderElectionNamespace string
+	LeaderElectionLeaseName string
+	// Plugins restricted to some namespaces, such as "fluxv2.packages=team-a,team-b".
+	PluginNamespaces []string
+	// Addresses dialed by the gateway for the requests of some plugins, rather
+	// than the listen address, such as for plugins served out of process, in the
+	// form <plugin name>=<host>:<port>. The gateway dials them with the same
+	// options, including the transport credentials, as this server.
+	PluginGatewayAddrs []string
+	// Token required to use the admin endpoints, which are disabled when empty.
+	AdminToken string
+	// Bearer token required to scrape the metrics, unless the scraper presents a
+	// client certificate verified against the client CA and MetricsRequireClientCert
+	// is set. The metrics are open to all when neither is set.
+	MetricsAuthToken         string
+	MetricsRequireClientCert bool
+	// Whether to expose the build info and up gauges along with the request
+	// metrics, so that the versions running across the replicas can be shown.
+	MetricsBuildInfo bool
+	// Version of the build, set by the command rather than by a flag.
+	Version string
+	// Address of the gRPC server exposing the channelz service, such as
+	// "localhost:50052", to inspect the state of the gRPC channels of the
+	// gateway. The channelz server is not started when empty.
+	ChannelzAddr string
+	// Verbosity of the logs, reloaded from the config file on SIGHUP.
+	LogVerbosity int
+	// Duration after which a log verbosity changed with the admin endpoint is reset.
+	LogLevelResetAfter time.Duration
+	// Maintenance mode options. While enabled, the methods whose name starts with
+	// any of the write methods are rejected. It can be toggled with an admin endpoint.
+	MaintenanceMode         bool
+	MaintenanceWriteMethods []string
+	// Retry policy for read-only requests to the packaging plugins.
+	PluginReadRetryMaxAttempts      int
+	PluginReadRetryInitialBackoff   time.Duration
+	PluginReadRetryMaxBackoff       time.Duration
+	PluginReadRetryCodes            []string
+	PluginReadRetryBudgetMaxTokens  float64
+	PluginReadRetryBudgetTokenRatio float64
+	// Stale-while-revalidate cache of the available package summaries: cached
+	// summaries are served as is for CacheFreshTTL, then served while being
+	// refreshed for CacheMaxStale. A CacheFreshTTL of 0 disables the cache.
+	CacheFreshTTL time.Duration
+	CacheMaxStale time.Duration
+	// Persist the cached summaries across restarts: they are saved to the
+	// snapshot file, such as on a mounted volume, at each interval and on
+	// shutdown, then restored on startup when the snapshot is valid.
+	PersistCache          bool
+	CacheSnapshotPath     string
+	CacheSnapshotInterval time.Duration
+	// Return the results of the other plugins, with warnings, when some plugins
+	// fail during aggregated reads, even if the request does not ask for it.
+	PartialResults bool
+	// Names of the plugins, from the highest priority, whose available packages
+	// take precedence over the packages with the same name of the other plugins
+	// in the aggregated results.
+	PluginPriority []string
+	// Log the discrepancies between the registered services and the OpenAPI
+	// document on startup. Intended for development.
+	ValidateOpenAPI bool
+	// Validate the bearer tokens of the requests with a TokenReview so that
+	// plugins can impersonate the caller with core.ImpersonatingConfig.
+	ImpersonateUsers bool
+	// Audit log of the calls to the methods whose name starts with any of the
+	// audit log methods. The sink is either "stdout" or the path of a file, and
+	// the audit log is disabled when empty.
+	AuditLogSink    string
+	AuditLogMethods []string
+	// GOMAXPROCS of the server. When 0, it is set from the CPU quota of the
+	// cgroup of the container, unless the GOMAXPROCS environment variable is set.
+	MaxProcs int
+	// Fail to start when no plugin is registered, such as when the plugin dirs
+	// are not mounted, rather than serving an empty API.
+	RequireAtLeastOnePlugin bool
+	// Fail to start when a gateway route is registered more than once, such as
+	// by two plugins, rather than only logging an error.
+	StrictGatewayRoutes bool
+	// Maximum size, in bytes, of the messages received by the server with any
+	// protocol, including gRPC-web. 0 disables the limit.
+	MaxReceiveMessageSize int
+	// Level of the gzip compression of the messages, from -2 (Huffman only) to 9
+	// (best compression), trading the size of the responses for CPU time. -1 is
+	// the default level of compress/gzip.
+	GRPCCompressionLevel int
+	// Fail to start when the API server of a cluster of the clusters config
+	// is unreachable, rather than failing the requests targeting it.
+	ValidateClusters bool
+	// Number of plugins registered concurrently on startup, so that a slow
+	// plugin does not delay the others. 1, the default, registers them one after
+	// the other, since the plugins, including the in-tree ones, are not known to
+	// be safe to register concurrently, such as with their package-level state.
+	PluginRegistrationConcurrency int
+	// Policy resolving the namespace of the requests omitting it, consistently
+	// for all the plugins: "error" rejects them, except the lists, for which the
+	// omitted namespace means all the namespaces, "default" sets the namespace to
+	// DefaultNamespace and "all" to all the namespaces. The writes, such as
+	// CreateInstalledPackage, are never resolved to all the namespaces: they are
+	// rejected with "all" unless DefaultNamespace is set. The plugins handle the
+	// omitted namespaces themselves when empty.
+	DefaultNamespacePolicy string
+	DefaultNamespace       string
+	// JSON fixture file of the canned available packages served by the fixtures
+	// plugin, which is only registered for the local development, with
+	// UnsafeLocalDevKubeconfig, so that the dashboard can be tested without a
+	// cluster.
+	PackagesFixturePath string
+	// Maximum duration to wait on startup for the API server to be reachable,
+	// with a discovery call, before registering the plugins. The server fails
+	// to start when it is still unreachable. 0 disables the wait.
+	WaitForAPIServer time.Duration
+	// Cache-Control headers of the gateway responses by method name prefix, in
+	// the form <method name prefix>=<directives>, such as
+	// "GetAvailablePackageVersions=public, max-age=60". The responses of the
+	// other methods, and the errors, are not cached.
+	CacheControl []string
+	// Operator icons proxied to the API server: they are cached for the TTL, 0
+	// disabling the cache, and the API server calls are limited to the QPS,
+	// with bursts of the given size, 0 disabling the limit.
+	OperatorLogoCacheTTL time.Duration
+	OperatorLogoQPS      float64
+	OperatorLogoBurst    int
+	// Duration of the startup, until the plugins and core services are
+	// registered, after which a warning naming the current step is logged.
+	// 0 disables the warning.
+	StartupWarningThreshold time.Duration
+	// Serve the REST API with the gateway. When disabled, the API is only served
+	// with the gRPC, gRPC-web and connect protocols.
+	EnableRESTGateway bool
+	// Default timeout of the unary requests, 0 disabling it, and the timeouts
+	// overriding it for the methods or plugins, in the form
+	// <method name or plugin name>=<duration>, such as "fluxv2.packages=2m".
+	// The streaming requests are only bounded by the latter.
+	RequestTimeout time.Duration
+	PluginTimeouts []string
+	// Full names of the methods disabled on this server, such as
+	// "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage",
+	// whose requests fail with an Unimplemented error.
+	DisabledMethods []string
+	// Fail the requests without a bearer token with an Unauthenticated error,
+	// before they reach the plugins, except for those of the public methods,
+	// given by their full names.
+	RequireAuth   bool
+	PublicMethods []string
+	// Range of the API versions, such as "2.9.0", of the clients supported by
+	// this server, both included. The requests of the clients sending another
+	// version in the X-Kubeapps-Api-Version header fail with a
+	// FailedPrecondition error. Either bound is disabled when empty.
+	MinAPIVersion string
+	MaxAPIVersion string
+	// Maximum number of requests of the methods whose name starts with any of
+	// the quota methods, such as the installations, per user and per day. 0
+	// disables the quota. The requests are counted in memory, for each replica,
+	// or in the Redis server of the given address, for all of them. The quota
+	// requires the identity of the callers, with ImpersonateUsers or the client
+	// certificates, and the callers whose identity is not known share one quota.
+	// The requests which cannot be counted, such as while Redis is down, fail
+	// with an Unavailable error, unless DailyQuotaFailOpen is set, in which case
+	// they are let through. Either way, they are counted by the
+	// kubeapps_apis_daily_quota_store_errors_total metric.
+	DailyQuota          int
+	DailyQuotaMethods   []string
+	DailyQuotaRedisAddr string
+	DailyQuotaFailOpen  bool
+	// Shutdown in two phases: the in-flight requests are drained during the
+	// grace period, then given until the hard timeout, counted from the start
+	// of the shutdown, after which the connections are force-closed.
+	ShutdownGracePeriod time.Duration
+	ShutdownHardTimeout time.Duration
 }
 
 // GatewayHandlerArgs is a helper struct just encapsulating all the args
-// required when registering an HTTP handler for the gateway.
+// required when registering an HTTP handler for the gateway. The Mux is nil
+// when the REST gateway is disabled.
 type GatewayHandlerArgs struct {
 	Ctx         context.Context
 	Mux         *runtime.ServeMux
 	Addr        string
 	DialOptions []grpc.DialOption
+	// PluginMux returns the mux with which the HTTP handlers of a plugin are
+	// registered. When nil, they are registered with the Mux.
+	PluginMux func(pluginName string) (*runtime.ServeMux, error)
+}
+
+// ForPlugin returns the args registering the HTTP handlers of the given plugin,
+// whose mux differs from the shared one when the plugin has its own marshaler.
+func (a GatewayHandlerArgs) ForPlugin(pluginName string) (GatewayHandlerArgs, error) {
+	if a.Mux == nil || a.PluginMux == nil {
+		return a, nil
+	}
+	mux, err := a.PluginMux(pluginName)
+	if err != nil {
+		return a, err
+	}
+	a.Mux = mux
+	return a, nil
+}
+
+// Register registers an HTTP handler for the gateway with the given function,
+// such as a generated Register...HandlerFromEndpoint, unless the REST gateway
+// is disabled.
+func (a GatewayHandlerArgs) Register(register func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error) error {
+	if a.Mux == nil {
+		return nil
+	}
+	return register(a.Ctx, a.Mux, a.Addr, a.DialOptions)
+}
+
+// HTTPRouteMux is the mux with which the plugins register their own REST
+// routes on the gateway, such as a *runtime.ServeMux. The pattern of a route
+// may have path parameters, such as "/foo/{name}".
+type HTTPRouteMux interface {
+	HandlePath(method, pattern string, handler runtime.HandlerFunc) error
 }
 
 // KubernetesConfigGetter is a function type used throughout the apis server so
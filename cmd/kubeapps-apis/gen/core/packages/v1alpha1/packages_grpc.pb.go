@@ -22,16 +22,19 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	PackagesService_GetAvailablePackageSummaries_FullMethodName    = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
-	PackagesService_GetAvailablePackageDetail_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail"
-	PackagesService_GetAvailablePackageVersions_FullMethodName     = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageVersions"
-	PackagesService_GetAvailablePackageMetadatas_FullMethodName    = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageMetadatas"
-	PackagesService_GetInstalledPackageSummaries_FullMethodName    = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageSummaries"
-	PackagesService_GetInstalledPackageDetail_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
-	PackagesService_CreateInstalledPackage_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage"
-	PackagesService_UpdateInstalledPackage_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.PackagesService/UpdateInstalledPackage"
-	PackagesService_DeleteInstalledPackage_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage"
-	PackagesService_GetInstalledPackageResourceRefs_FullMethodName = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageResourceRefs"
+	PackagesService_GetAvailablePackageSummaries_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+	PackagesService_GetAvailablePackageDetail_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail"
+	PackagesService_GetAvailablePackageDetailsBatch_FullMethodName    = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetailsBatch"
+	PackagesService_StreamAvailablePackageDetail_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.PackagesService/StreamAvailablePackageDetail"
+	PackagesService_GetAvailablePackageVersions_FullMethodName        = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageVersions"
+	PackagesService_GetAvailablePackageMetadatas_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageMetadatas"
+	PackagesService_GetInstalledPackageSummaries_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageSummaries"
+	PackagesService_GetInstalledPackageDetail_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	PackagesService_CreateInstalledPackage_FullMethodName             = "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage"
+	PackagesService_UpdateInstalledPackage_FullMethodName             = "/kubeappsapis.core.packages.v1alpha1.PackagesService/UpdateInstalledPackage"
+	PackagesService_DeleteInstalledPackage_FullMethodName             = "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage"
+	PackagesService_GetInstalledPackageResourceRefs_FullMethodName    = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageResourceRefs"
+	PackagesService_StreamInstalledPackageResourceRefs_FullMethodName = "/kubeappsapis.core.packages.v1alpha1.PackagesService/StreamInstalledPackageResourceRefs"
 )
 
 // PackagesServiceClient is the client API for PackagesService service.
@@ -40,6 +43,8 @@ const (
 type PackagesServiceClient interface {
 	GetAvailablePackageSummaries(ctx context.Context, in *GetAvailablePackageSummariesRequest, opts ...grpc.CallOption) (*GetAvailablePackageSummariesResponse, error)
 	GetAvailablePackageDetail(ctx context.Context, in *GetAvailablePackageDetailRequest, opts ...grpc.CallOption) (*GetAvailablePackageDetailResponse, error)
+	GetAvailablePackageDetailsBatch(ctx context.Context, in *GetAvailablePackageDetailsBatchRequest, opts ...grpc.CallOption) (*GetAvailablePackageDetailsBatchResponse, error)
+	StreamAvailablePackageDetail(ctx context.Context, in *GetAvailablePackageDetailRequest, opts ...grpc.CallOption) (PackagesService_StreamAvailablePackageDetailClient, error)
 	GetAvailablePackageVersions(ctx context.Context, in *GetAvailablePackageVersionsRequest, opts ...grpc.CallOption) (*GetAvailablePackageVersionsResponse, error)
 	GetAvailablePackageMetadatas(ctx context.Context, in *GetAvailablePackageMetadatasRequest, opts ...grpc.CallOption) (*GetAvailablePackageMetadatasResponse, error)
 	GetInstalledPackageSummaries(ctx context.Context, in *GetInstalledPackageSummariesRequest, opts ...grpc.CallOption) (*GetInstalledPackageSummariesResponse, error)
@@ -48,6 +53,7 @@ type PackagesServiceClient interface {
 	UpdateInstalledPackage(ctx context.Context, in *UpdateInstalledPackageRequest, opts ...grpc.CallOption) (*UpdateInstalledPackageResponse, error)
 	DeleteInstalledPackage(ctx context.Context, in *DeleteInstalledPackageRequest, opts ...grpc.CallOption) (*DeleteInstalledPackageResponse, error)
 	GetInstalledPackageResourceRefs(ctx context.Context, in *GetInstalledPackageResourceRefsRequest, opts ...grpc.CallOption) (*GetInstalledPackageResourceRefsResponse, error)
+	StreamInstalledPackageResourceRefs(ctx context.Context, in *GetInstalledPackageResourceRefsRequest, opts ...grpc.CallOption) (PackagesService_StreamInstalledPackageResourceRefsClient, error)
 }
 
 type packagesServiceClient struct {
@@ -76,6 +82,47 @@ func (c *packagesServiceClient) GetAvailablePackageDetail(ctx context.Context, i
 	return out, nil
 }
 
+func (c *packagesServiceClient) GetAvailablePackageDetailsBatch(ctx context.Context, in *GetAvailablePackageDetailsBatchRequest, opts ...grpc.CallOption) (*GetAvailablePackageDetailsBatchResponse, error) {
+	out := new(GetAvailablePackageDetailsBatchResponse)
+	err := c.cc.Invoke(ctx, PackagesService_GetAvailablePackageDetailsBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packagesServiceClient) StreamAvailablePackageDetail(ctx context.Context, in *GetAvailablePackageDetailRequest, opts ...grpc.CallOption) (PackagesService_StreamAvailablePackageDetailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PackagesService_ServiceDesc.Streams[0], PackagesService_StreamAvailablePackageDetail_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesServiceStreamAvailablePackageDetailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PackagesService_StreamAvailablePackageDetailClient interface {
+	Recv() (*StreamAvailablePackageDetailResponse, error)
+	grpc.ClientStream
+}
+
+type packagesServiceStreamAvailablePackageDetailClient struct {
+	grpc.ClientStream
+}
+
+func (x *packagesServiceStreamAvailablePackageDetailClient) Recv() (*StreamAvailablePackageDetailResponse, error) {
+	m := new(StreamAvailablePackageDetailResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *packagesServiceClient) GetAvailablePackageVersions(ctx context.Context, in *GetAvailablePackageVersionsRequest, opts ...grpc.CallOption) (*GetAvailablePackageVersionsResponse, error) {
 	out := new(GetAvailablePackageVersionsResponse)
 	err := c.cc.Invoke(ctx, PackagesService_GetAvailablePackageVersions_FullMethodName, in, out, opts...)
@@ -148,12 +195,46 @@ func (c *packagesServiceClient) GetInstalledPackageResourceRefs(ctx context.Cont
 	return out, nil
 }
 
+func (c *packagesServiceClient) StreamInstalledPackageResourceRefs(ctx context.Context, in *GetInstalledPackageResourceRefsRequest, opts ...grpc.CallOption) (PackagesService_StreamInstalledPackageResourceRefsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PackagesService_ServiceDesc.Streams[1], PackagesService_StreamInstalledPackageResourceRefs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesServiceStreamInstalledPackageResourceRefsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PackagesService_StreamInstalledPackageResourceRefsClient interface {
+	Recv() (*StreamInstalledPackageResourceRefsResponse, error)
+	grpc.ClientStream
+}
+
+type packagesServiceStreamInstalledPackageResourceRefsClient struct {
+	grpc.ClientStream
+}
+
+func (x *packagesServiceStreamInstalledPackageResourceRefsClient) Recv() (*StreamInstalledPackageResourceRefsResponse, error) {
+	m := new(StreamInstalledPackageResourceRefsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PackagesServiceServer is the server API for PackagesService service.
 // All implementations should embed UnimplementedPackagesServiceServer
 // for forward compatibility
 type PackagesServiceServer interface {
 	GetAvailablePackageSummaries(context.Context, *GetAvailablePackageSummariesRequest) (*GetAvailablePackageSummariesResponse, error)
 	GetAvailablePackageDetail(context.Context, *GetAvailablePackageDetailRequest) (*GetAvailablePackageDetailResponse, error)
+	GetAvailablePackageDetailsBatch(context.Context, *GetAvailablePackageDetailsBatchRequest) (*GetAvailablePackageDetailsBatchResponse, error)
+	StreamAvailablePackageDetail(*GetAvailablePackageDetailRequest, PackagesService_StreamAvailablePackageDetailServer) error
 	GetAvailablePackageVersions(context.Context, *GetAvailablePackageVersionsRequest) (*GetAvailablePackageVersionsResponse, error)
 	GetAvailablePackageMetadatas(context.Context, *GetAvailablePackageMetadatasRequest) (*GetAvailablePackageMetadatasResponse, error)
 	GetInstalledPackageSummaries(context.Context, *GetInstalledPackageSummariesRequest) (*GetInstalledPackageSummariesResponse, error)
@@ -162,6 +243,7 @@ type PackagesServiceServer interface {
 	UpdateInstalledPackage(context.Context, *UpdateInstalledPackageRequest) (*UpdateInstalledPackageResponse, error)
 	DeleteInstalledPackage(context.Context, *DeleteInstalledPackageRequest) (*DeleteInstalledPackageResponse, error)
 	GetInstalledPackageResourceRefs(context.Context, *GetInstalledPackageResourceRefsRequest) (*GetInstalledPackageResourceRefsResponse, error)
+	StreamInstalledPackageResourceRefs(*GetInstalledPackageResourceRefsRequest, PackagesService_StreamInstalledPackageResourceRefsServer) error
 }
 
 // UnimplementedPackagesServiceServer should be embedded to have forward compatible implementations.
@@ -174,6 +256,12 @@ func (UnimplementedPackagesServiceServer) GetAvailablePackageSummaries(context.C
 func (UnimplementedPackagesServiceServer) GetAvailablePackageDetail(context.Context, *GetAvailablePackageDetailRequest) (*GetAvailablePackageDetailResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAvailablePackageDetail not implemented")
 }
+func (UnimplementedPackagesServiceServer) GetAvailablePackageDetailsBatch(context.Context, *GetAvailablePackageDetailsBatchRequest) (*GetAvailablePackageDetailsBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvailablePackageDetailsBatch not implemented")
+}
+func (UnimplementedPackagesServiceServer) StreamAvailablePackageDetail(*GetAvailablePackageDetailRequest, PackagesService_StreamAvailablePackageDetailServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAvailablePackageDetail not implemented")
+}
 func (UnimplementedPackagesServiceServer) GetAvailablePackageVersions(context.Context, *GetAvailablePackageVersionsRequest) (*GetAvailablePackageVersionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAvailablePackageVersions not implemented")
 }
@@ -198,6 +286,9 @@ func (UnimplementedPackagesServiceServer) DeleteInstalledPackage(context.Context
 func (UnimplementedPackagesServiceServer) GetInstalledPackageResourceRefs(context.Context, *GetInstalledPackageResourceRefsRequest) (*GetInstalledPackageResourceRefsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInstalledPackageResourceRefs not implemented")
 }
+func (UnimplementedPackagesServiceServer) StreamInstalledPackageResourceRefs(*GetInstalledPackageResourceRefsRequest, PackagesService_StreamInstalledPackageResourceRefsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamInstalledPackageResourceRefs not implemented")
+}
 
 // UnsafePackagesServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to PackagesServiceServer will
@@ -246,6 +337,45 @@ func _PackagesService_GetAvailablePackageDetail_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PackagesService_GetAvailablePackageDetailsBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvailablePackageDetailsBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackagesServiceServer).GetAvailablePackageDetailsBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackagesService_GetAvailablePackageDetailsBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackagesServiceServer).GetAvailablePackageDetailsBatch(ctx, req.(*GetAvailablePackageDetailsBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackagesService_StreamAvailablePackageDetail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAvailablePackageDetailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackagesServiceServer).StreamAvailablePackageDetail(m, &packagesServiceStreamAvailablePackageDetailServer{stream})
+}
+
+type PackagesService_StreamAvailablePackageDetailServer interface {
+	Send(*StreamAvailablePackageDetailResponse) error
+	grpc.ServerStream
+}
+
+type packagesServiceStreamAvailablePackageDetailServer struct {
+	grpc.ServerStream
+}
+
+func (x *packagesServiceStreamAvailablePackageDetailServer) Send(m *StreamAvailablePackageDetailResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _PackagesService_GetAvailablePackageVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAvailablePackageVersionsRequest)
 	if err := dec(in); err != nil {
@@ -390,6 +520,27 @@ func _PackagesService_GetInstalledPackageResourceRefs_Handler(srv interface{}, c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PackagesService_StreamInstalledPackageResourceRefs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetInstalledPackageResourceRefsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackagesServiceServer).StreamInstalledPackageResourceRefs(m, &packagesServiceStreamInstalledPackageResourceRefsServer{stream})
+}
+
+type PackagesService_StreamInstalledPackageResourceRefsServer interface {
+	Send(*StreamInstalledPackageResourceRefsResponse) error
+	grpc.ServerStream
+}
+
+type packagesServiceStreamInstalledPackageResourceRefsServer struct {
+	grpc.ServerStream
+}
+
+func (x *packagesServiceStreamInstalledPackageResourceRefsServer) Send(m *StreamInstalledPackageResourceRefsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // PackagesService_ServiceDesc is the grpc.ServiceDesc for PackagesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -405,6 +556,10 @@ var PackagesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAvailablePackageDetail",
 			Handler:    _PackagesService_GetAvailablePackageDetail_Handler,
 		},
+		{
+			MethodName: "GetAvailablePackageDetailsBatch",
+			Handler:    _PackagesService_GetAvailablePackageDetailsBatch_Handler,
+		},
 		{
 			MethodName: "GetAvailablePackageVersions",
 			Handler:    _PackagesService_GetAvailablePackageVersions_Handler,
@@ -438,6 +593,17 @@ var PackagesService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _PackagesService_GetInstalledPackageResourceRefs_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAvailablePackageDetail",
+			Handler:       _PackagesService_StreamAvailablePackageDetail_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamInstalledPackageResourceRefs",
+			Handler:       _PackagesService_StreamInstalledPackageResourceRefs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "kubeappsapis/core/packages/v1alpha1/packages.proto",
 }
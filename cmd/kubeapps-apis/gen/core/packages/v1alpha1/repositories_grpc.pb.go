@@ -22,12 +22,15 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	RepositoriesService_AddPackageRepository_FullMethodName            = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/AddPackageRepository"
-	RepositoriesService_GetPackageRepositoryDetail_FullMethodName      = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositoryDetail"
-	RepositoriesService_GetPackageRepositorySummaries_FullMethodName   = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositorySummaries"
-	RepositoriesService_UpdatePackageRepository_FullMethodName         = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/UpdatePackageRepository"
-	RepositoriesService_DeletePackageRepository_FullMethodName         = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/DeletePackageRepository"
-	RepositoriesService_GetPackageRepositoryPermissions_FullMethodName = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositoryPermissions"
+	RepositoriesService_AddPackageRepository_FullMethodName             = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/AddPackageRepository"
+	RepositoriesService_GetPackageRepositoryDetail_FullMethodName       = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositoryDetail"
+	RepositoriesService_GetPackageRepositorySummaries_FullMethodName    = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositorySummaries"
+	RepositoriesService_UpdatePackageRepository_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/UpdatePackageRepository"
+	RepositoriesService_DeletePackageRepository_FullMethodName          = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/DeletePackageRepository"
+	RepositoriesService_GetPackageRepositoryPermissions_FullMethodName  = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositoryPermissions"
+	RepositoriesService_InvalidatePackageRepositoryCache_FullMethodName = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/InvalidatePackageRepositoryCache"
+	RepositoriesService_GetRepositoryTypes_FullMethodName               = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetRepositoryTypes"
+	RepositoriesService_ValidatePackageRepository_FullMethodName        = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/ValidatePackageRepository"
 )
 
 // RepositoriesServiceClient is the client API for RepositoriesService service.
@@ -40,6 +43,9 @@ type RepositoriesServiceClient interface {
 	UpdatePackageRepository(ctx context.Context, in *UpdatePackageRepositoryRequest, opts ...grpc.CallOption) (*UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(ctx context.Context, in *DeletePackageRepositoryRequest, opts ...grpc.CallOption) (*DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(ctx context.Context, in *GetPackageRepositoryPermissionsRequest, opts ...grpc.CallOption) (*GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(ctx context.Context, in *InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(ctx context.Context, in *GetRepositoryTypesRequest, opts ...grpc.CallOption) (*GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(ctx context.Context, in *AddPackageRepositoryRequest, opts ...grpc.CallOption) (*ValidatePackageRepositoryResponse, error)
 }
 
 type repositoriesServiceClient struct {
@@ -104,6 +110,33 @@ func (c *repositoriesServiceClient) GetPackageRepositoryPermissions(ctx context.
 	return out, nil
 }
 
+func (c *repositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, in *InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*InvalidatePackageRepositoryCacheResponse, error) {
+	out := new(InvalidatePackageRepositoryCacheResponse)
+	err := c.cc.Invoke(ctx, RepositoriesService_InvalidatePackageRepositoryCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoriesServiceClient) GetRepositoryTypes(ctx context.Context, in *GetRepositoryTypesRequest, opts ...grpc.CallOption) (*GetRepositoryTypesResponse, error) {
+	out := new(GetRepositoryTypesResponse)
+	err := c.cc.Invoke(ctx, RepositoriesService_GetRepositoryTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoriesServiceClient) ValidatePackageRepository(ctx context.Context, in *AddPackageRepositoryRequest, opts ...grpc.CallOption) (*ValidatePackageRepositoryResponse, error) {
+	out := new(ValidatePackageRepositoryResponse)
+	err := c.cc.Invoke(ctx, RepositoriesService_ValidatePackageRepository_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RepositoriesServiceServer is the server API for RepositoriesService service.
 // All implementations should embed UnimplementedRepositoriesServiceServer
 // for forward compatibility
@@ -114,6 +147,9 @@ type RepositoriesServiceServer interface {
 	UpdatePackageRepository(context.Context, *UpdatePackageRepositoryRequest) (*UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(context.Context, *DeletePackageRepositoryRequest) (*DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(context.Context, *GetPackageRepositoryPermissionsRequest) (*GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(context.Context, *InvalidatePackageRepositoryCacheRequest) (*InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(context.Context, *GetRepositoryTypesRequest) (*GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(context.Context, *AddPackageRepositoryRequest) (*ValidatePackageRepositoryResponse, error)
 }
 
 // UnimplementedRepositoriesServiceServer should be embedded to have forward compatible implementations.
@@ -138,6 +174,15 @@ func (UnimplementedRepositoriesServiceServer) DeletePackageRepository(context.Co
 func (UnimplementedRepositoriesServiceServer) GetPackageRepositoryPermissions(context.Context, *GetPackageRepositoryPermissionsRequest) (*GetPackageRepositoryPermissionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPackageRepositoryPermissions not implemented")
 }
+func (UnimplementedRepositoriesServiceServer) InvalidatePackageRepositoryCache(context.Context, *InvalidatePackageRepositoryCacheRequest) (*InvalidatePackageRepositoryCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidatePackageRepositoryCache not implemented")
+}
+func (UnimplementedRepositoriesServiceServer) GetRepositoryTypes(context.Context, *GetRepositoryTypesRequest) (*GetRepositoryTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRepositoryTypes not implemented")
+}
+func (UnimplementedRepositoriesServiceServer) ValidatePackageRepository(context.Context, *AddPackageRepositoryRequest) (*ValidatePackageRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePackageRepository not implemented")
+}
 
 // UnsafeRepositoriesServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to RepositoriesServiceServer will
@@ -258,6 +303,60 @@ func _RepositoriesService_GetPackageRepositoryPermissions_Handler(srv interface{
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepositoriesService_InvalidatePackageRepositoryCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidatePackageRepositoryCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoriesService_InvalidatePackageRepositoryCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, req.(*InvalidatePackageRepositoryCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoriesService_GetRepositoryTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRepositoryTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoriesServiceServer).GetRepositoryTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoriesService_GetRepositoryTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoriesServiceServer).GetRepositoryTypes(ctx, req.(*GetRepositoryTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoriesService_ValidatePackageRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPackageRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoriesServiceServer).ValidatePackageRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoriesService_ValidatePackageRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoriesServiceServer).ValidatePackageRepository(ctx, req.(*AddPackageRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RepositoriesService_ServiceDesc is the grpc.ServiceDesc for RepositoriesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -289,6 +388,18 @@ var RepositoriesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPackageRepositoryPermissions",
 			Handler:    _RepositoriesService_GetPackageRepositoryPermissions_Handler,
 		},
+		{
+			MethodName: "InvalidatePackageRepositoryCache",
+			Handler:    _RepositoriesService_InvalidatePackageRepositoryCache_Handler,
+		},
+		{
+			MethodName: "GetRepositoryTypes",
+			Handler:    _RepositoriesService_GetRepositoryTypes_Handler,
+		},
+		{
+			MethodName: "ValidatePackageRepository",
+			Handler:    _RepositoriesService_ValidatePackageRepository_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "kubeappsapis/core/packages/v1alpha1/repositories.proto",
@@ -42,6 +42,12 @@ const (
 	// PackagesServiceGetAvailablePackageDetailProcedure is the fully-qualified name of the
 	// PackagesService's GetAvailablePackageDetail RPC.
 	PackagesServiceGetAvailablePackageDetailProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail"
+	// PackagesServiceGetAvailablePackageDetailsBatchProcedure is the fully-qualified name of the
+	// PackagesService's GetAvailablePackageDetailsBatch RPC.
+	PackagesServiceGetAvailablePackageDetailsBatchProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetailsBatch"
+	// PackagesServiceStreamAvailablePackageDetailProcedure is the fully-qualified name of the
+	// PackagesService's StreamAvailablePackageDetail RPC.
+	PackagesServiceStreamAvailablePackageDetailProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/StreamAvailablePackageDetail"
 	// PackagesServiceGetAvailablePackageVersionsProcedure is the fully-qualified name of the
 	// PackagesService's GetAvailablePackageVersions RPC.
 	PackagesServiceGetAvailablePackageVersionsProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageVersions"
@@ -66,6 +72,9 @@ const (
 	// PackagesServiceGetInstalledPackageResourceRefsProcedure is the fully-qualified name of the
 	// PackagesService's GetInstalledPackageResourceRefs RPC.
 	PackagesServiceGetInstalledPackageResourceRefsProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageResourceRefs"
+	// PackagesServiceStreamInstalledPackageResourceRefsProcedure is the fully-qualified name of the
+	// PackagesService's StreamInstalledPackageResourceRefs RPC.
+	PackagesServiceStreamInstalledPackageResourceRefsProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/StreamInstalledPackageResourceRefs"
 )
 
 // PackagesServiceClient is a client for the kubeappsapis.core.packages.v1alpha1.PackagesService
@@ -73,6 +82,8 @@ const (
 type PackagesServiceClient interface {
 	GetAvailablePackageSummaries(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageSummariesRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageSummariesResponse], error)
 	GetAvailablePackageDetail(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageDetailResponse], error)
+	GetAvailablePackageDetailsBatch(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailsBatchRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageDetailsBatchResponse], error)
+	StreamAvailablePackageDetail(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailRequest]) (*connect_go.ServerStreamForClient[v1alpha1.StreamAvailablePackageDetailResponse], error)
 	GetAvailablePackageVersions(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageVersionsRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageVersionsResponse], error)
 	GetAvailablePackageMetadatas(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageMetadatasRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageMetadatasResponse], error)
 	GetInstalledPackageSummaries(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageSummariesRequest]) (*connect_go.Response[v1alpha1.GetInstalledPackageSummariesResponse], error)
@@ -81,6 +92,7 @@ type PackagesServiceClient interface {
 	UpdateInstalledPackage(context.Context, *connect_go.Request[v1alpha1.UpdateInstalledPackageRequest]) (*connect_go.Response[v1alpha1.UpdateInstalledPackageResponse], error)
 	DeleteInstalledPackage(context.Context, *connect_go.Request[v1alpha1.DeleteInstalledPackageRequest]) (*connect_go.Response[v1alpha1.DeleteInstalledPackageResponse], error)
 	GetInstalledPackageResourceRefs(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest]) (*connect_go.Response[v1alpha1.GetInstalledPackageResourceRefsResponse], error)
+	StreamInstalledPackageResourceRefs(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest]) (*connect_go.ServerStreamForClient[v1alpha1.StreamInstalledPackageResourceRefsResponse], error)
 }
 
 // NewPackagesServiceClient constructs a client for the
@@ -104,6 +116,16 @@ func NewPackagesServiceClient(httpClient connect_go.HTTPClient, baseURL string,
 			baseURL+PackagesServiceGetAvailablePackageDetailProcedure,
 			opts...,
 		),
+		getAvailablePackageDetailsBatch: connect_go.NewClient[v1alpha1.GetAvailablePackageDetailsBatchRequest, v1alpha1.GetAvailablePackageDetailsBatchResponse](
+			httpClient,
+			baseURL+PackagesServiceGetAvailablePackageDetailsBatchProcedure,
+			opts...,
+		),
+		streamAvailablePackageDetail: connect_go.NewClient[v1alpha1.GetAvailablePackageDetailRequest, v1alpha1.StreamAvailablePackageDetailResponse](
+			httpClient,
+			baseURL+PackagesServiceStreamAvailablePackageDetailProcedure,
+			opts...,
+		),
 		getAvailablePackageVersions: connect_go.NewClient[v1alpha1.GetAvailablePackageVersionsRequest, v1alpha1.GetAvailablePackageVersionsResponse](
 			httpClient,
 			baseURL+PackagesServiceGetAvailablePackageVersionsProcedure,
@@ -144,21 +166,29 @@ func NewPackagesServiceClient(httpClient connect_go.HTTPClient, baseURL string,
 			baseURL+PackagesServiceGetInstalledPackageResourceRefsProcedure,
 			opts...,
 		),
+		streamInstalledPackageResourceRefs: connect_go.NewClient[v1alpha1.GetInstalledPackageResourceRefsRequest, v1alpha1.StreamInstalledPackageResourceRefsResponse](
+			httpClient,
+			baseURL+PackagesServiceStreamInstalledPackageResourceRefsProcedure,
+			opts...,
+		),
 	}
 }
 
 // packagesServiceClient implements PackagesServiceClient.
 type packagesServiceClient struct {
-	getAvailablePackageSummaries    *connect_go.Client[v1alpha1.GetAvailablePackageSummariesRequest, v1alpha1.GetAvailablePackageSummariesResponse]
-	getAvailablePackageDetail       *connect_go.Client[v1alpha1.GetAvailablePackageDetailRequest, v1alpha1.GetAvailablePackageDetailResponse]
-	getAvailablePackageVersions     *connect_go.Client[v1alpha1.GetAvailablePackageVersionsRequest, v1alpha1.GetAvailablePackageVersionsResponse]
-	getAvailablePackageMetadatas    *connect_go.Client[v1alpha1.GetAvailablePackageMetadatasRequest, v1alpha1.GetAvailablePackageMetadatasResponse]
-	getInstalledPackageSummaries    *connect_go.Client[v1alpha1.GetInstalledPackageSummariesRequest, v1alpha1.GetInstalledPackageSummariesResponse]
-	getInstalledPackageDetail       *connect_go.Client[v1alpha1.GetInstalledPackageDetailRequest, v1alpha1.GetInstalledPackageDetailResponse]
-	createInstalledPackage          *connect_go.Client[v1alpha1.CreateInstalledPackageRequest, v1alpha1.CreateInstalledPackageResponse]
-	updateInstalledPackage          *connect_go.Client[v1alpha1.UpdateInstalledPackageRequest, v1alpha1.UpdateInstalledPackageResponse]
-	deleteInstalledPackage          *connect_go.Client[v1alpha1.DeleteInstalledPackageRequest, v1alpha1.DeleteInstalledPackageResponse]
-	getInstalledPackageResourceRefs *connect_go.Client[v1alpha1.GetInstalledPackageResourceRefsRequest, v1alpha1.GetInstalledPackageResourceRefsResponse]
+	getAvailablePackageSummaries       *connect_go.Client[v1alpha1.GetAvailablePackageSummariesRequest, v1alpha1.GetAvailablePackageSummariesResponse]
+	getAvailablePackageDetail          *connect_go.Client[v1alpha1.GetAvailablePackageDetailRequest, v1alpha1.GetAvailablePackageDetailResponse]
+	getAvailablePackageDetailsBatch    *connect_go.Client[v1alpha1.GetAvailablePackageDetailsBatchRequest, v1alpha1.GetAvailablePackageDetailsBatchResponse]
+	streamAvailablePackageDetail       *connect_go.Client[v1alpha1.GetAvailablePackageDetailRequest, v1alpha1.StreamAvailablePackageDetailResponse]
+	getAvailablePackageVersions        *connect_go.Client[v1alpha1.GetAvailablePackageVersionsRequest, v1alpha1.GetAvailablePackageVersionsResponse]
+	getAvailablePackageMetadatas       *connect_go.Client[v1alpha1.GetAvailablePackageMetadatasRequest, v1alpha1.GetAvailablePackageMetadatasResponse]
+	getInstalledPackageSummaries       *connect_go.Client[v1alpha1.GetInstalledPackageSummariesRequest, v1alpha1.GetInstalledPackageSummariesResponse]
+	getInstalledPackageDetail          *connect_go.Client[v1alpha1.GetInstalledPackageDetailRequest, v1alpha1.GetInstalledPackageDetailResponse]
+	createInstalledPackage             *connect_go.Client[v1alpha1.CreateInstalledPackageRequest, v1alpha1.CreateInstalledPackageResponse]
+	updateInstalledPackage             *connect_go.Client[v1alpha1.UpdateInstalledPackageRequest, v1alpha1.UpdateInstalledPackageResponse]
+	deleteInstalledPackage             *connect_go.Client[v1alpha1.DeleteInstalledPackageRequest, v1alpha1.DeleteInstalledPackageResponse]
+	getInstalledPackageResourceRefs    *connect_go.Client[v1alpha1.GetInstalledPackageResourceRefsRequest, v1alpha1.GetInstalledPackageResourceRefsResponse]
+	streamInstalledPackageResourceRefs *connect_go.Client[v1alpha1.GetInstalledPackageResourceRefsRequest, v1alpha1.StreamInstalledPackageResourceRefsResponse]
 }
 
 // GetAvailablePackageSummaries calls
@@ -173,6 +203,18 @@ func (c *packagesServiceClient) GetAvailablePackageDetail(ctx context.Context, r
 	return c.getAvailablePackageDetail.CallUnary(ctx, req)
 }
 
+// GetAvailablePackageDetailsBatch calls
+// kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageDetailsBatch.
+func (c *packagesServiceClient) GetAvailablePackageDetailsBatch(ctx context.Context, req *connect_go.Request[v1alpha1.GetAvailablePackageDetailsBatchRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageDetailsBatchResponse], error) {
+	return c.getAvailablePackageDetailsBatch.CallUnary(ctx, req)
+}
+
+// StreamAvailablePackageDetail calls
+// kubeappsapis.core.packages.v1alpha1.PackagesService.StreamAvailablePackageDetail.
+func (c *packagesServiceClient) StreamAvailablePackageDetail(ctx context.Context, req *connect_go.Request[v1alpha1.GetAvailablePackageDetailRequest]) (*connect_go.ServerStreamForClient[v1alpha1.StreamAvailablePackageDetailResponse], error) {
+	return c.streamAvailablePackageDetail.CallServerStream(ctx, req)
+}
+
 // GetAvailablePackageVersions calls
 // kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageVersions.
 func (c *packagesServiceClient) GetAvailablePackageVersions(ctx context.Context, req *connect_go.Request[v1alpha1.GetAvailablePackageVersionsRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageVersionsResponse], error) {
@@ -221,11 +263,19 @@ func (c *packagesServiceClient) GetInstalledPackageResourceRefs(ctx context.Cont
 	return c.getInstalledPackageResourceRefs.CallUnary(ctx, req)
 }
 
+// StreamInstalledPackageResourceRefs calls
+// kubeappsapis.core.packages.v1alpha1.PackagesService.StreamInstalledPackageResourceRefs.
+func (c *packagesServiceClient) StreamInstalledPackageResourceRefs(ctx context.Context, req *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest]) (*connect_go.ServerStreamForClient[v1alpha1.StreamInstalledPackageResourceRefsResponse], error) {
+	return c.streamInstalledPackageResourceRefs.CallServerStream(ctx, req)
+}
+
 // PackagesServiceHandler is an implementation of the
 // kubeappsapis.core.packages.v1alpha1.PackagesService service.
 type PackagesServiceHandler interface {
 	GetAvailablePackageSummaries(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageSummariesRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageSummariesResponse], error)
 	GetAvailablePackageDetail(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageDetailResponse], error)
+	GetAvailablePackageDetailsBatch(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailsBatchRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageDetailsBatchResponse], error)
+	StreamAvailablePackageDetail(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailRequest], *connect_go.ServerStream[v1alpha1.StreamAvailablePackageDetailResponse]) error
 	GetAvailablePackageVersions(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageVersionsRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageVersionsResponse], error)
 	GetAvailablePackageMetadatas(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageMetadatasRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageMetadatasResponse], error)
 	GetInstalledPackageSummaries(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageSummariesRequest]) (*connect_go.Response[v1alpha1.GetInstalledPackageSummariesResponse], error)
@@ -234,6 +284,7 @@ type PackagesServiceHandler interface {
 	UpdateInstalledPackage(context.Context, *connect_go.Request[v1alpha1.UpdateInstalledPackageRequest]) (*connect_go.Response[v1alpha1.UpdateInstalledPackageResponse], error)
 	DeleteInstalledPackage(context.Context, *connect_go.Request[v1alpha1.DeleteInstalledPackageRequest]) (*connect_go.Response[v1alpha1.DeleteInstalledPackageResponse], error)
 	GetInstalledPackageResourceRefs(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest]) (*connect_go.Response[v1alpha1.GetInstalledPackageResourceRefsResponse], error)
+	StreamInstalledPackageResourceRefs(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest], *connect_go.ServerStream[v1alpha1.StreamInstalledPackageResourceRefsResponse]) error
 }
 
 // NewPackagesServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -252,6 +303,16 @@ func NewPackagesServiceHandler(svc PackagesServiceHandler, opts ...connect_go.Ha
 		svc.GetAvailablePackageDetail,
 		opts...,
 	)
+	packagesServiceGetAvailablePackageDetailsBatchHandler := connect_go.NewUnaryHandler(
+		PackagesServiceGetAvailablePackageDetailsBatchProcedure,
+		svc.GetAvailablePackageDetailsBatch,
+		opts...,
+	)
+	packagesServiceStreamAvailablePackageDetailHandler := connect_go.NewServerStreamHandler(
+		PackagesServiceStreamAvailablePackageDetailProcedure,
+		svc.StreamAvailablePackageDetail,
+		opts...,
+	)
 	packagesServiceGetAvailablePackageVersionsHandler := connect_go.NewUnaryHandler(
 		PackagesServiceGetAvailablePackageVersionsProcedure,
 		svc.GetAvailablePackageVersions,
@@ -292,12 +353,21 @@ func NewPackagesServiceHandler(svc PackagesServiceHandler, opts ...connect_go.Ha
 		svc.GetInstalledPackageResourceRefs,
 		opts...,
 	)
+	packagesServiceStreamInstalledPackageResourceRefsHandler := connect_go.NewServerStreamHandler(
+		PackagesServiceStreamInstalledPackageResourceRefsProcedure,
+		svc.StreamInstalledPackageResourceRefs,
+		opts...,
+	)
 	return "/kubeappsapis.core.packages.v1alpha1.PackagesService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case PackagesServiceGetAvailablePackageSummariesProcedure:
 			packagesServiceGetAvailablePackageSummariesHandler.ServeHTTP(w, r)
 		case PackagesServiceGetAvailablePackageDetailProcedure:
 			packagesServiceGetAvailablePackageDetailHandler.ServeHTTP(w, r)
+		case PackagesServiceGetAvailablePackageDetailsBatchProcedure:
+			packagesServiceGetAvailablePackageDetailsBatchHandler.ServeHTTP(w, r)
+		case PackagesServiceStreamAvailablePackageDetailProcedure:
+			packagesServiceStreamAvailablePackageDetailHandler.ServeHTTP(w, r)
 		case PackagesServiceGetAvailablePackageVersionsProcedure:
 			packagesServiceGetAvailablePackageVersionsHandler.ServeHTTP(w, r)
 		case PackagesServiceGetAvailablePackageMetadatasProcedure:
@@ -314,6 +384,8 @@ func NewPackagesServiceHandler(svc PackagesServiceHandler, opts ...connect_go.Ha
 			packagesServiceDeleteInstalledPackageHandler.ServeHTTP(w, r)
 		case PackagesServiceGetInstalledPackageResourceRefsProcedure:
 			packagesServiceGetInstalledPackageResourceRefsHandler.ServeHTTP(w, r)
+		case PackagesServiceStreamInstalledPackageResourceRefsProcedure:
+			packagesServiceStreamInstalledPackageResourceRefsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -331,6 +403,14 @@ func (UnimplementedPackagesServiceHandler) GetAvailablePackageDetail(context.Con
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageDetail is not implemented"))
 }
 
+func (UnimplementedPackagesServiceHandler) GetAvailablePackageDetailsBatch(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailsBatchRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageDetailsBatchResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageDetailsBatch is not implemented"))
+}
+
+func (UnimplementedPackagesServiceHandler) StreamAvailablePackageDetail(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageDetailRequest], *connect_go.ServerStream[v1alpha1.StreamAvailablePackageDetailResponse]) error {
+	return connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.PackagesService.StreamAvailablePackageDetail is not implemented"))
+}
+
 func (UnimplementedPackagesServiceHandler) GetAvailablePackageVersions(context.Context, *connect_go.Request[v1alpha1.GetAvailablePackageVersionsRequest]) (*connect_go.Response[v1alpha1.GetAvailablePackageVersionsResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageVersions is not implemented"))
 }
@@ -362,3 +442,7 @@ func (UnimplementedPackagesServiceHandler) DeleteInstalledPackage(context.Contex
 func (UnimplementedPackagesServiceHandler) GetInstalledPackageResourceRefs(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest]) (*connect_go.Response[v1alpha1.GetInstalledPackageResourceRefsResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.PackagesService.GetInstalledPackageResourceRefs is not implemented"))
 }
+
+func (UnimplementedPackagesServiceHandler) StreamInstalledPackageResourceRefs(context.Context, *connect_go.Request[v1alpha1.GetInstalledPackageResourceRefsRequest], *connect_go.ServerStream[v1alpha1.StreamInstalledPackageResourceRefsResponse]) error {
+	return connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.PackagesService.StreamInstalledPackageResourceRefs is not implemented"))
+}
@@ -54,6 +54,15 @@ const (
 	// RepositoriesServiceGetPackageRepositoryPermissionsProcedure is the fully-qualified name of the
 	// RepositoriesService's GetPackageRepositoryPermissions RPC.
 	RepositoriesServiceGetPackageRepositoryPermissionsProcedure = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetPackageRepositoryPermissions"
+	// RepositoriesServiceInvalidatePackageRepositoryCacheProcedure is the fully-qualified name of the
+	// RepositoriesService's InvalidatePackageRepositoryCache RPC.
+	RepositoriesServiceInvalidatePackageRepositoryCacheProcedure = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/InvalidatePackageRepositoryCache"
+	// RepositoriesServiceGetRepositoryTypesProcedure is the fully-qualified name of the
+	// RepositoriesService's GetRepositoryTypes RPC.
+	RepositoriesServiceGetRepositoryTypesProcedure = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/GetRepositoryTypes"
+	// RepositoriesServiceValidatePackageRepositoryProcedure is the fully-qualified name of the
+	// RepositoriesService's ValidatePackageRepository RPC.
+	RepositoriesServiceValidatePackageRepositoryProcedure = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/ValidatePackageRepository"
 )
 
 // RepositoriesServiceClient is a client for the
@@ -65,6 +74,9 @@ type RepositoriesServiceClient interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewRepositoriesServiceClient constructs a client for the
@@ -108,17 +120,35 @@ func NewRepositoriesServiceClient(httpClient connect_go.HTTPClient, baseURL stri
 			baseURL+RepositoriesServiceGetPackageRepositoryPermissionsProcedure,
 			opts...,
 		),
+		invalidatePackageRepositoryCache: connect_go.NewClient[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse](
+			httpClient,
+			baseURL+RepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+			opts...,
+		),
+		getRepositoryTypes: connect_go.NewClient[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse](
+			httpClient,
+			baseURL+RepositoriesServiceGetRepositoryTypesProcedure,
+			opts...,
+		),
+		validatePackageRepository: connect_go.NewClient[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse](
+			httpClient,
+			baseURL+RepositoriesServiceValidatePackageRepositoryProcedure,
+			opts...,
+		),
 	}
 }
 
 // repositoriesServiceClient implements RepositoriesServiceClient.
 type repositoriesServiceClient struct {
-	addPackageRepository            *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
-	getPackageRepositoryDetail      *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
-	getPackageRepositorySummaries   *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
-	updatePackageRepository         *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
-	deletePackageRepository         *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
-	getPackageRepositoryPermissions *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	addPackageRepository             *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
+	getPackageRepositoryDetail       *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
+	getPackageRepositorySummaries    *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
+	updatePackageRepository          *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
+	deletePackageRepository          *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
+	getPackageRepositoryPermissions  *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	invalidatePackageRepositoryCache *connect_go.Client[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse]
+	getRepositoryTypes               *connect_go.Client[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse]
+	validatePackageRepository        *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse]
 }
 
 // AddPackageRepository calls
@@ -157,6 +187,24 @@ func (c *repositoriesServiceClient) GetPackageRepositoryPermissions(ctx context.
 	return c.getPackageRepositoryPermissions.CallUnary(ctx, req)
 }
 
+// InvalidatePackageRepositoryCache calls
+// kubeappsapis.core.packages.v1alpha1.RepositoriesService.InvalidatePackageRepositoryCache.
+func (c *repositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, req *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return c.invalidatePackageRepositoryCache.CallUnary(ctx, req)
+}
+
+// GetRepositoryTypes calls
+// kubeappsapis.core.packages.v1alpha1.RepositoriesService.GetRepositoryTypes.
+func (c *repositoriesServiceClient) GetRepositoryTypes(ctx context.Context, req *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return c.getRepositoryTypes.CallUnary(ctx, req)
+}
+
+// ValidatePackageRepository calls
+// kubeappsapis.core.packages.v1alpha1.RepositoriesService.ValidatePackageRepository.
+func (c *repositoriesServiceClient) ValidatePackageRepository(ctx context.Context, req *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return c.validatePackageRepository.CallUnary(ctx, req)
+}
+
 // RepositoriesServiceHandler is an implementation of the
 // kubeappsapis.core.packages.v1alpha1.RepositoriesService service.
 type RepositoriesServiceHandler interface {
@@ -166,6 +214,9 @@ type RepositoriesServiceHandler interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewRepositoriesServiceHandler builds an HTTP handler from the service implementation. It returns
@@ -204,6 +255,21 @@ func NewRepositoriesServiceHandler(svc RepositoriesServiceHandler, opts ...conne
 		svc.GetPackageRepositoryPermissions,
 		opts...,
 	)
+	repositoriesServiceInvalidatePackageRepositoryCacheHandler := connect_go.NewUnaryHandler(
+		RepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+		svc.InvalidatePackageRepositoryCache,
+		opts...,
+	)
+	repositoriesServiceGetRepositoryTypesHandler := connect_go.NewUnaryHandler(
+		RepositoriesServiceGetRepositoryTypesProcedure,
+		svc.GetRepositoryTypes,
+		opts...,
+	)
+	repositoriesServiceValidatePackageRepositoryHandler := connect_go.NewUnaryHandler(
+		RepositoriesServiceValidatePackageRepositoryProcedure,
+		svc.ValidatePackageRepository,
+		opts...,
+	)
 	return "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case RepositoriesServiceAddPackageRepositoryProcedure:
@@ -218,6 +284,12 @@ func NewRepositoriesServiceHandler(svc RepositoriesServiceHandler, opts ...conne
 			repositoriesServiceDeletePackageRepositoryHandler.ServeHTTP(w, r)
 		case RepositoriesServiceGetPackageRepositoryPermissionsProcedure:
 			repositoriesServiceGetPackageRepositoryPermissionsHandler.ServeHTTP(w, r)
+		case RepositoriesServiceInvalidatePackageRepositoryCacheProcedure:
+			repositoriesServiceInvalidatePackageRepositoryCacheHandler.ServeHTTP(w, r)
+		case RepositoriesServiceGetRepositoryTypesProcedure:
+			repositoriesServiceGetRepositoryTypesHandler.ServeHTTP(w, r)
+		case RepositoriesServiceValidatePackageRepositoryProcedure:
+			repositoriesServiceValidatePackageRepositoryHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -250,3 +322,15 @@ func (UnimplementedRepositoriesServiceHandler) DeletePackageRepository(context.C
 func (UnimplementedRepositoriesServiceHandler) GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.RepositoriesService.GetPackageRepositoryPermissions is not implemented"))
 }
+
+func (UnimplementedRepositoriesServiceHandler) InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.RepositoriesService.InvalidatePackageRepositoryCache is not implemented"))
+}
+
+func (UnimplementedRepositoriesServiceHandler) GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.RepositoriesService.GetRepositoryTypes is not implemented"))
+}
+
+func (UnimplementedRepositoriesServiceHandler) ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.core.packages.v1alpha1.RepositoriesService.ValidatePackageRepository is not implemented"))
+}
@@ -427,12 +427,15 @@ var FluxV2PackagesService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	FluxV2RepositoriesService_AddPackageRepository_FullMethodName            = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/AddPackageRepository"
-	FluxV2RepositoriesService_GetPackageRepositoryDetail_FullMethodName      = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositoryDetail"
-	FluxV2RepositoriesService_GetPackageRepositorySummaries_FullMethodName   = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositorySummaries"
-	FluxV2RepositoriesService_UpdatePackageRepository_FullMethodName         = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/UpdatePackageRepository"
-	FluxV2RepositoriesService_DeletePackageRepository_FullMethodName         = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/DeletePackageRepository"
-	FluxV2RepositoriesService_GetPackageRepositoryPermissions_FullMethodName = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositoryPermissions"
+	FluxV2RepositoriesService_AddPackageRepository_FullMethodName             = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/AddPackageRepository"
+	FluxV2RepositoriesService_GetPackageRepositoryDetail_FullMethodName       = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositoryDetail"
+	FluxV2RepositoriesService_GetPackageRepositorySummaries_FullMethodName    = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositorySummaries"
+	FluxV2RepositoriesService_UpdatePackageRepository_FullMethodName          = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/UpdatePackageRepository"
+	FluxV2RepositoriesService_DeletePackageRepository_FullMethodName          = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/DeletePackageRepository"
+	FluxV2RepositoriesService_GetPackageRepositoryPermissions_FullMethodName  = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositoryPermissions"
+	FluxV2RepositoriesService_InvalidatePackageRepositoryCache_FullMethodName = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/InvalidatePackageRepositoryCache"
+	FluxV2RepositoriesService_GetRepositoryTypes_FullMethodName               = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetRepositoryTypes"
+	FluxV2RepositoriesService_ValidatePackageRepository_FullMethodName        = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/ValidatePackageRepository"
 )
 
 // FluxV2RepositoriesServiceClient is the client API for FluxV2RepositoriesService service.
@@ -447,6 +450,9 @@ type FluxV2RepositoriesServiceClient interface {
 	UpdatePackageRepository(ctx context.Context, in *v1alpha1.UpdatePackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(ctx context.Context, in *v1alpha1.DeletePackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(ctx context.Context, in *v1alpha1.GetPackageRepositoryPermissionsRequest, opts ...grpc.CallOption) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(ctx context.Context, in *v1alpha1.InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(ctx context.Context, in *v1alpha1.GetRepositoryTypesRequest, opts ...grpc.CallOption) (*v1alpha1.GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(ctx context.Context, in *v1alpha1.AddPackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.ValidatePackageRepositoryResponse, error)
 }
 
 type fluxV2RepositoriesServiceClient struct {
@@ -511,6 +517,33 @@ func (c *fluxV2RepositoriesServiceClient) GetPackageRepositoryPermissions(ctx co
 	return out, nil
 }
 
+func (c *fluxV2RepositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, in *v1alpha1.InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error) {
+	out := new(v1alpha1.InvalidatePackageRepositoryCacheResponse)
+	err := c.cc.Invoke(ctx, FluxV2RepositoriesService_InvalidatePackageRepositoryCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fluxV2RepositoriesServiceClient) GetRepositoryTypes(ctx context.Context, in *v1alpha1.GetRepositoryTypesRequest, opts ...grpc.CallOption) (*v1alpha1.GetRepositoryTypesResponse, error) {
+	out := new(v1alpha1.GetRepositoryTypesResponse)
+	err := c.cc.Invoke(ctx, FluxV2RepositoriesService_GetRepositoryTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fluxV2RepositoriesServiceClient) ValidatePackageRepository(ctx context.Context, in *v1alpha1.AddPackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.ValidatePackageRepositoryResponse, error) {
+	out := new(v1alpha1.ValidatePackageRepositoryResponse)
+	err := c.cc.Invoke(ctx, FluxV2RepositoriesService_ValidatePackageRepository_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FluxV2RepositoriesServiceServer is the server API for FluxV2RepositoriesService service.
 // All implementations should embed UnimplementedFluxV2RepositoriesServiceServer
 // for forward compatibility
@@ -523,6 +556,9 @@ type FluxV2RepositoriesServiceServer interface {
 	UpdatePackageRepository(context.Context, *v1alpha1.UpdatePackageRepositoryRequest) (*v1alpha1.UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(context.Context, *v1alpha1.DeletePackageRepositoryRequest) (*v1alpha1.DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(context.Context, *v1alpha1.GetPackageRepositoryPermissionsRequest) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(context.Context, *v1alpha1.InvalidatePackageRepositoryCacheRequest) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(context.Context, *v1alpha1.GetRepositoryTypesRequest) (*v1alpha1.GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(context.Context, *v1alpha1.AddPackageRepositoryRequest) (*v1alpha1.ValidatePackageRepositoryResponse, error)
 }
 
 // UnimplementedFluxV2RepositoriesServiceServer should be embedded to have forward compatible implementations.
@@ -547,6 +583,15 @@ func (UnimplementedFluxV2RepositoriesServiceServer) DeletePackageRepository(cont
 func (UnimplementedFluxV2RepositoriesServiceServer) GetPackageRepositoryPermissions(context.Context, *v1alpha1.GetPackageRepositoryPermissionsRequest) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPackageRepositoryPermissions not implemented")
 }
+func (UnimplementedFluxV2RepositoriesServiceServer) InvalidatePackageRepositoryCache(context.Context, *v1alpha1.InvalidatePackageRepositoryCacheRequest) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidatePackageRepositoryCache not implemented")
+}
+func (UnimplementedFluxV2RepositoriesServiceServer) GetRepositoryTypes(context.Context, *v1alpha1.GetRepositoryTypesRequest) (*v1alpha1.GetRepositoryTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRepositoryTypes not implemented")
+}
+func (UnimplementedFluxV2RepositoriesServiceServer) ValidatePackageRepository(context.Context, *v1alpha1.AddPackageRepositoryRequest) (*v1alpha1.ValidatePackageRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePackageRepository not implemented")
+}
 
 // UnsafeFluxV2RepositoriesServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to FluxV2RepositoriesServiceServer will
@@ -667,6 +712,60 @@ func _FluxV2RepositoriesService_GetPackageRepositoryPermissions_Handler(srv inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FluxV2RepositoriesService_InvalidatePackageRepositoryCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.InvalidatePackageRepositoryCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FluxV2RepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FluxV2RepositoriesService_InvalidatePackageRepositoryCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FluxV2RepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, req.(*v1alpha1.InvalidatePackageRepositoryCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FluxV2RepositoriesService_GetRepositoryTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.GetRepositoryTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FluxV2RepositoriesServiceServer).GetRepositoryTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FluxV2RepositoriesService_GetRepositoryTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FluxV2RepositoriesServiceServer).GetRepositoryTypes(ctx, req.(*v1alpha1.GetRepositoryTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FluxV2RepositoriesService_ValidatePackageRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.AddPackageRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FluxV2RepositoriesServiceServer).ValidatePackageRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FluxV2RepositoriesService_ValidatePackageRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FluxV2RepositoriesServiceServer).ValidatePackageRepository(ctx, req.(*v1alpha1.AddPackageRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FluxV2RepositoriesService_ServiceDesc is the grpc.ServiceDesc for FluxV2RepositoriesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -698,6 +797,18 @@ var FluxV2RepositoriesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPackageRepositoryPermissions",
 			Handler:    _FluxV2RepositoriesService_GetPackageRepositoryPermissions_Handler,
 		},
+		{
+			MethodName: "InvalidatePackageRepositoryCache",
+			Handler:    _FluxV2RepositoriesService_InvalidatePackageRepositoryCache_Handler,
+		},
+		{
+			MethodName: "GetRepositoryTypes",
+			Handler:    _FluxV2RepositoriesService_GetRepositoryTypes_Handler,
+		},
+		{
+			MethodName: "ValidatePackageRepository",
+			Handler:    _FluxV2RepositoriesService_ValidatePackageRepository_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "kubeappsapis/plugins/fluxv2/packages/v1alpha1/fluxv2.proto",
@@ -85,6 +85,15 @@ const (
 	// FluxV2RepositoriesServiceGetPackageRepositoryPermissionsProcedure is the fully-qualified name of
 	// the FluxV2RepositoriesService's GetPackageRepositoryPermissions RPC.
 	FluxV2RepositoriesServiceGetPackageRepositoryPermissionsProcedure = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetPackageRepositoryPermissions"
+	// FluxV2RepositoriesServiceInvalidatePackageRepositoryCacheProcedure is the fully-qualified name of
+	// the FluxV2RepositoriesService's InvalidatePackageRepositoryCache RPC.
+	FluxV2RepositoriesServiceInvalidatePackageRepositoryCacheProcedure = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/InvalidatePackageRepositoryCache"
+	// FluxV2RepositoriesServiceGetRepositoryTypesProcedure is the fully-qualified name of the
+	// FluxV2RepositoriesService's GetRepositoryTypes RPC.
+	FluxV2RepositoriesServiceGetRepositoryTypesProcedure = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/GetRepositoryTypes"
+	// FluxV2RepositoriesServiceValidatePackageRepositoryProcedure is the fully-qualified name of the
+	// FluxV2RepositoriesService's ValidatePackageRepository RPC.
+	FluxV2RepositoriesServiceValidatePackageRepositoryProcedure = "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/ValidatePackageRepository"
 )
 
 // FluxV2PackagesServiceClient is a client for the
@@ -388,6 +397,9 @@ type FluxV2RepositoriesServiceClient interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewFluxV2RepositoriesServiceClient constructs a client for the
@@ -431,17 +443,35 @@ func NewFluxV2RepositoriesServiceClient(httpClient connect_go.HTTPClient, baseUR
 			baseURL+FluxV2RepositoriesServiceGetPackageRepositoryPermissionsProcedure,
 			opts...,
 		),
+		invalidatePackageRepositoryCache: connect_go.NewClient[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse](
+			httpClient,
+			baseURL+FluxV2RepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+			opts...,
+		),
+		getRepositoryTypes: connect_go.NewClient[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse](
+			httpClient,
+			baseURL+FluxV2RepositoriesServiceGetRepositoryTypesProcedure,
+			opts...,
+		),
+		validatePackageRepository: connect_go.NewClient[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse](
+			httpClient,
+			baseURL+FluxV2RepositoriesServiceValidatePackageRepositoryProcedure,
+			opts...,
+		),
 	}
 }
 
 // fluxV2RepositoriesServiceClient implements FluxV2RepositoriesServiceClient.
 type fluxV2RepositoriesServiceClient struct {
-	addPackageRepository            *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
-	getPackageRepositoryDetail      *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
-	getPackageRepositorySummaries   *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
-	updatePackageRepository         *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
-	deletePackageRepository         *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
-	getPackageRepositoryPermissions *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	addPackageRepository             *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
+	getPackageRepositoryDetail       *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
+	getPackageRepositorySummaries    *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
+	updatePackageRepository          *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
+	deletePackageRepository          *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
+	getPackageRepositoryPermissions  *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	invalidatePackageRepositoryCache *connect_go.Client[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse]
+	getRepositoryTypes               *connect_go.Client[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse]
+	validatePackageRepository        *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse]
 }
 
 // AddPackageRepository calls
@@ -480,6 +510,24 @@ func (c *fluxV2RepositoriesServiceClient) GetPackageRepositoryPermissions(ctx co
 	return c.getPackageRepositoryPermissions.CallUnary(ctx, req)
 }
 
+// InvalidatePackageRepositoryCache calls
+// kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.InvalidatePackageRepositoryCache.
+func (c *fluxV2RepositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, req *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return c.invalidatePackageRepositoryCache.CallUnary(ctx, req)
+}
+
+// GetRepositoryTypes calls
+// kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.GetRepositoryTypes.
+func (c *fluxV2RepositoriesServiceClient) GetRepositoryTypes(ctx context.Context, req *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return c.getRepositoryTypes.CallUnary(ctx, req)
+}
+
+// ValidatePackageRepository calls
+// kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.ValidatePackageRepository.
+func (c *fluxV2RepositoriesServiceClient) ValidatePackageRepository(ctx context.Context, req *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return c.validatePackageRepository.CallUnary(ctx, req)
+}
+
 // FluxV2RepositoriesServiceHandler is an implementation of the
 // kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService service.
 type FluxV2RepositoriesServiceHandler interface {
@@ -491,6 +539,9 @@ type FluxV2RepositoriesServiceHandler interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewFluxV2RepositoriesServiceHandler builds an HTTP handler from the service implementation. It
@@ -529,6 +580,21 @@ func NewFluxV2RepositoriesServiceHandler(svc FluxV2RepositoriesServiceHandler, o
 		svc.GetPackageRepositoryPermissions,
 		opts...,
 	)
+	fluxV2RepositoriesServiceInvalidatePackageRepositoryCacheHandler := connect_go.NewUnaryHandler(
+		FluxV2RepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+		svc.InvalidatePackageRepositoryCache,
+		opts...,
+	)
+	fluxV2RepositoriesServiceGetRepositoryTypesHandler := connect_go.NewUnaryHandler(
+		FluxV2RepositoriesServiceGetRepositoryTypesProcedure,
+		svc.GetRepositoryTypes,
+		opts...,
+	)
+	fluxV2RepositoriesServiceValidatePackageRepositoryHandler := connect_go.NewUnaryHandler(
+		FluxV2RepositoriesServiceValidatePackageRepositoryProcedure,
+		svc.ValidatePackageRepository,
+		opts...,
+	)
 	return "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case FluxV2RepositoriesServiceAddPackageRepositoryProcedure:
@@ -543,6 +609,12 @@ func NewFluxV2RepositoriesServiceHandler(svc FluxV2RepositoriesServiceHandler, o
 			fluxV2RepositoriesServiceDeletePackageRepositoryHandler.ServeHTTP(w, r)
 		case FluxV2RepositoriesServiceGetPackageRepositoryPermissionsProcedure:
 			fluxV2RepositoriesServiceGetPackageRepositoryPermissionsHandler.ServeHTTP(w, r)
+		case FluxV2RepositoriesServiceInvalidatePackageRepositoryCacheProcedure:
+			fluxV2RepositoriesServiceInvalidatePackageRepositoryCacheHandler.ServeHTTP(w, r)
+		case FluxV2RepositoriesServiceGetRepositoryTypesProcedure:
+			fluxV2RepositoriesServiceGetRepositoryTypesHandler.ServeHTTP(w, r)
+		case FluxV2RepositoriesServiceValidatePackageRepositoryProcedure:
+			fluxV2RepositoriesServiceValidatePackageRepositoryHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -575,3 +647,15 @@ func (UnimplementedFluxV2RepositoriesServiceHandler) DeletePackageRepository(con
 func (UnimplementedFluxV2RepositoriesServiceHandler) GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.GetPackageRepositoryPermissions is not implemented"))
 }
+
+func (UnimplementedFluxV2RepositoriesServiceHandler) InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.InvalidatePackageRepositoryCache is not implemented"))
+}
+
+func (UnimplementedFluxV2RepositoriesServiceHandler) GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.GetRepositoryTypes is not implemented"))
+}
+
+func (UnimplementedFluxV2RepositoriesServiceHandler) ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.ValidatePackageRepository is not implemented"))
+}
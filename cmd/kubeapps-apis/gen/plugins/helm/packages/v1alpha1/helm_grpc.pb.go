@@ -466,12 +466,15 @@ var HelmPackagesService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	HelmRepositoriesService_AddPackageRepository_FullMethodName            = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/AddPackageRepository"
-	HelmRepositoriesService_GetPackageRepositoryDetail_FullMethodName      = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositoryDetail"
-	HelmRepositoriesService_GetPackageRepositorySummaries_FullMethodName   = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositorySummaries"
-	HelmRepositoriesService_UpdatePackageRepository_FullMethodName         = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/UpdatePackageRepository"
-	HelmRepositoriesService_DeletePackageRepository_FullMethodName         = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/DeletePackageRepository"
-	HelmRepositoriesService_GetPackageRepositoryPermissions_FullMethodName = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositoryPermissions"
+	HelmRepositoriesService_AddPackageRepository_FullMethodName             = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/AddPackageRepository"
+	HelmRepositoriesService_GetPackageRepositoryDetail_FullMethodName       = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositoryDetail"
+	HelmRepositoriesService_GetPackageRepositorySummaries_FullMethodName    = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositorySummaries"
+	HelmRepositoriesService_UpdatePackageRepository_FullMethodName          = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/UpdatePackageRepository"
+	HelmRepositoriesService_DeletePackageRepository_FullMethodName          = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/DeletePackageRepository"
+	HelmRepositoriesService_GetPackageRepositoryPermissions_FullMethodName  = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositoryPermissions"
+	HelmRepositoriesService_InvalidatePackageRepositoryCache_FullMethodName = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/InvalidatePackageRepositoryCache"
+	HelmRepositoriesService_GetRepositoryTypes_FullMethodName               = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetRepositoryTypes"
+	HelmRepositoriesService_ValidatePackageRepository_FullMethodName        = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/ValidatePackageRepository"
 )
 
 // HelmRepositoriesServiceClient is the client API for HelmRepositoriesService service.
@@ -485,6 +488,9 @@ type HelmRepositoriesServiceClient interface {
 	UpdatePackageRepository(ctx context.Context, in *v1alpha1.UpdatePackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(ctx context.Context, in *v1alpha1.DeletePackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(ctx context.Context, in *v1alpha1.GetPackageRepositoryPermissionsRequest, opts ...grpc.CallOption) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(ctx context.Context, in *v1alpha1.InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(ctx context.Context, in *v1alpha1.GetRepositoryTypesRequest, opts ...grpc.CallOption) (*v1alpha1.GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(ctx context.Context, in *v1alpha1.AddPackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.ValidatePackageRepositoryResponse, error)
 }
 
 type helmRepositoriesServiceClient struct {
@@ -549,6 +555,33 @@ func (c *helmRepositoriesServiceClient) GetPackageRepositoryPermissions(ctx cont
 	return out, nil
 }
 
+func (c *helmRepositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, in *v1alpha1.InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error) {
+	out := new(v1alpha1.InvalidatePackageRepositoryCacheResponse)
+	err := c.cc.Invoke(ctx, HelmRepositoriesService_InvalidatePackageRepositoryCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *helmRepositoriesServiceClient) GetRepositoryTypes(ctx context.Context, in *v1alpha1.GetRepositoryTypesRequest, opts ...grpc.CallOption) (*v1alpha1.GetRepositoryTypesResponse, error) {
+	out := new(v1alpha1.GetRepositoryTypesResponse)
+	err := c.cc.Invoke(ctx, HelmRepositoriesService_GetRepositoryTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *helmRepositoriesServiceClient) ValidatePackageRepository(ctx context.Context, in *v1alpha1.AddPackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.ValidatePackageRepositoryResponse, error) {
+	out := new(v1alpha1.ValidatePackageRepositoryResponse)
+	err := c.cc.Invoke(ctx, HelmRepositoriesService_ValidatePackageRepository_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HelmRepositoriesServiceServer is the server API for HelmRepositoriesService service.
 // All implementations should embed UnimplementedHelmRepositoriesServiceServer
 // for forward compatibility
@@ -560,6 +593,9 @@ type HelmRepositoriesServiceServer interface {
 	UpdatePackageRepository(context.Context, *v1alpha1.UpdatePackageRepositoryRequest) (*v1alpha1.UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(context.Context, *v1alpha1.DeletePackageRepositoryRequest) (*v1alpha1.DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(context.Context, *v1alpha1.GetPackageRepositoryPermissionsRequest) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(context.Context, *v1alpha1.InvalidatePackageRepositoryCacheRequest) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(context.Context, *v1alpha1.GetRepositoryTypesRequest) (*v1alpha1.GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(context.Context, *v1alpha1.AddPackageRepositoryRequest) (*v1alpha1.ValidatePackageRepositoryResponse, error)
 }
 
 // UnimplementedHelmRepositoriesServiceServer should be embedded to have forward compatible implementations.
@@ -584,6 +620,15 @@ func (UnimplementedHelmRepositoriesServiceServer) DeletePackageRepository(contex
 func (UnimplementedHelmRepositoriesServiceServer) GetPackageRepositoryPermissions(context.Context, *v1alpha1.GetPackageRepositoryPermissionsRequest) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPackageRepositoryPermissions not implemented")
 }
+func (UnimplementedHelmRepositoriesServiceServer) InvalidatePackageRepositoryCache(context.Context, *v1alpha1.InvalidatePackageRepositoryCacheRequest) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidatePackageRepositoryCache not implemented")
+}
+func (UnimplementedHelmRepositoriesServiceServer) GetRepositoryTypes(context.Context, *v1alpha1.GetRepositoryTypesRequest) (*v1alpha1.GetRepositoryTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRepositoryTypes not implemented")
+}
+func (UnimplementedHelmRepositoriesServiceServer) ValidatePackageRepository(context.Context, *v1alpha1.AddPackageRepositoryRequest) (*v1alpha1.ValidatePackageRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePackageRepository not implemented")
+}
 
 // UnsafeHelmRepositoriesServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to HelmRepositoriesServiceServer will
@@ -704,6 +749,60 @@ func _HelmRepositoriesService_GetPackageRepositoryPermissions_Handler(srv interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HelmRepositoriesService_InvalidatePackageRepositoryCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.InvalidatePackageRepositoryCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HelmRepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HelmRepositoriesService_InvalidatePackageRepositoryCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HelmRepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, req.(*v1alpha1.InvalidatePackageRepositoryCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HelmRepositoriesService_GetRepositoryTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.GetRepositoryTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HelmRepositoriesServiceServer).GetRepositoryTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HelmRepositoriesService_GetRepositoryTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HelmRepositoriesServiceServer).GetRepositoryTypes(ctx, req.(*v1alpha1.GetRepositoryTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HelmRepositoriesService_ValidatePackageRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.AddPackageRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HelmRepositoriesServiceServer).ValidatePackageRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HelmRepositoriesService_ValidatePackageRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HelmRepositoriesServiceServer).ValidatePackageRepository(ctx, req.(*v1alpha1.AddPackageRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HelmRepositoriesService_ServiceDesc is the grpc.ServiceDesc for HelmRepositoriesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -735,6 +834,18 @@ var HelmRepositoriesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPackageRepositoryPermissions",
 			Handler:    _HelmRepositoriesService_GetPackageRepositoryPermissions_Handler,
 		},
+		{
+			MethodName: "InvalidatePackageRepositoryCache",
+			Handler:    _HelmRepositoriesService_InvalidatePackageRepositoryCache_Handler,
+		},
+		{
+			MethodName: "GetRepositoryTypes",
+			Handler:    _HelmRepositoriesService_GetRepositoryTypes_Handler,
+		},
+		{
+			MethodName: "ValidatePackageRepository",
+			Handler:    _HelmRepositoriesService_ValidatePackageRepository_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "kubeappsapis/plugins/helm/packages/v1alpha1/helm.proto",
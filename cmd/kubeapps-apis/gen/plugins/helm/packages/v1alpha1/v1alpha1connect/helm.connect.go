@@ -87,6 +87,15 @@ const (
 	// HelmRepositoriesServiceGetPackageRepositoryPermissionsProcedure is the fully-qualified name of
 	// the HelmRepositoriesService's GetPackageRepositoryPermissions RPC.
 	HelmRepositoriesServiceGetPackageRepositoryPermissionsProcedure = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetPackageRepositoryPermissions"
+	// HelmRepositoriesServiceInvalidatePackageRepositoryCacheProcedure is the fully-qualified name of
+	// the HelmRepositoriesService's InvalidatePackageRepositoryCache RPC.
+	HelmRepositoriesServiceInvalidatePackageRepositoryCacheProcedure = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/InvalidatePackageRepositoryCache"
+	// HelmRepositoriesServiceGetRepositoryTypesProcedure is the fully-qualified name of the
+	// HelmRepositoriesService's GetRepositoryTypes RPC.
+	HelmRepositoriesServiceGetRepositoryTypesProcedure = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/GetRepositoryTypes"
+	// HelmRepositoriesServiceValidatePackageRepositoryProcedure is the fully-qualified name of the
+	// HelmRepositoriesService's ValidatePackageRepository RPC.
+	HelmRepositoriesServiceValidatePackageRepositoryProcedure = "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/ValidatePackageRepository"
 )
 
 // HelmPackagesServiceClient is a client for the
@@ -416,6 +425,9 @@ type HelmRepositoriesServiceClient interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewHelmRepositoriesServiceClient constructs a client for the
@@ -459,17 +471,35 @@ func NewHelmRepositoriesServiceClient(httpClient connect_go.HTTPClient, baseURL
 			baseURL+HelmRepositoriesServiceGetPackageRepositoryPermissionsProcedure,
 			opts...,
 		),
+		invalidatePackageRepositoryCache: connect_go.NewClient[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse](
+			httpClient,
+			baseURL+HelmRepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+			opts...,
+		),
+		getRepositoryTypes: connect_go.NewClient[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse](
+			httpClient,
+			baseURL+HelmRepositoriesServiceGetRepositoryTypesProcedure,
+			opts...,
+		),
+		validatePackageRepository: connect_go.NewClient[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse](
+			httpClient,
+			baseURL+HelmRepositoriesServiceValidatePackageRepositoryProcedure,
+			opts...,
+		),
 	}
 }
 
 // helmRepositoriesServiceClient implements HelmRepositoriesServiceClient.
 type helmRepositoriesServiceClient struct {
-	addPackageRepository            *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
-	getPackageRepositoryDetail      *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
-	getPackageRepositorySummaries   *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
-	updatePackageRepository         *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
-	deletePackageRepository         *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
-	getPackageRepositoryPermissions *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	addPackageRepository             *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
+	getPackageRepositoryDetail       *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
+	getPackageRepositorySummaries    *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
+	updatePackageRepository          *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
+	deletePackageRepository          *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
+	getPackageRepositoryPermissions  *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	invalidatePackageRepositoryCache *connect_go.Client[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse]
+	getRepositoryTypes               *connect_go.Client[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse]
+	validatePackageRepository        *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse]
 }
 
 // AddPackageRepository calls
@@ -508,6 +538,24 @@ func (c *helmRepositoriesServiceClient) GetPackageRepositoryPermissions(ctx cont
 	return c.getPackageRepositoryPermissions.CallUnary(ctx, req)
 }
 
+// InvalidatePackageRepositoryCache calls
+// kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.InvalidatePackageRepositoryCache.
+func (c *helmRepositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, req *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return c.invalidatePackageRepositoryCache.CallUnary(ctx, req)
+}
+
+// GetRepositoryTypes calls
+// kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.GetRepositoryTypes.
+func (c *helmRepositoriesServiceClient) GetRepositoryTypes(ctx context.Context, req *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return c.getRepositoryTypes.CallUnary(ctx, req)
+}
+
+// ValidatePackageRepository calls
+// kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.ValidatePackageRepository.
+func (c *helmRepositoriesServiceClient) ValidatePackageRepository(ctx context.Context, req *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return c.validatePackageRepository.CallUnary(ctx, req)
+}
+
 // HelmRepositoriesServiceHandler is an implementation of the
 // kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService service.
 type HelmRepositoriesServiceHandler interface {
@@ -518,6 +566,9 @@ type HelmRepositoriesServiceHandler interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewHelmRepositoriesServiceHandler builds an HTTP handler from the service implementation. It
@@ -556,6 +607,21 @@ func NewHelmRepositoriesServiceHandler(svc HelmRepositoriesServiceHandler, opts
 		svc.GetPackageRepositoryPermissions,
 		opts...,
 	)
+	helmRepositoriesServiceInvalidatePackageRepositoryCacheHandler := connect_go.NewUnaryHandler(
+		HelmRepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+		svc.InvalidatePackageRepositoryCache,
+		opts...,
+	)
+	helmRepositoriesServiceGetRepositoryTypesHandler := connect_go.NewUnaryHandler(
+		HelmRepositoriesServiceGetRepositoryTypesProcedure,
+		svc.GetRepositoryTypes,
+		opts...,
+	)
+	helmRepositoriesServiceValidatePackageRepositoryHandler := connect_go.NewUnaryHandler(
+		HelmRepositoriesServiceValidatePackageRepositoryProcedure,
+		svc.ValidatePackageRepository,
+		opts...,
+	)
 	return "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case HelmRepositoriesServiceAddPackageRepositoryProcedure:
@@ -570,6 +636,12 @@ func NewHelmRepositoriesServiceHandler(svc HelmRepositoriesServiceHandler, opts
 			helmRepositoriesServiceDeletePackageRepositoryHandler.ServeHTTP(w, r)
 		case HelmRepositoriesServiceGetPackageRepositoryPermissionsProcedure:
 			helmRepositoriesServiceGetPackageRepositoryPermissionsHandler.ServeHTTP(w, r)
+		case HelmRepositoriesServiceInvalidatePackageRepositoryCacheProcedure:
+			helmRepositoriesServiceInvalidatePackageRepositoryCacheHandler.ServeHTTP(w, r)
+		case HelmRepositoriesServiceGetRepositoryTypesProcedure:
+			helmRepositoriesServiceGetRepositoryTypesHandler.ServeHTTP(w, r)
+		case HelmRepositoriesServiceValidatePackageRepositoryProcedure:
+			helmRepositoriesServiceValidatePackageRepositoryHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -602,3 +674,15 @@ func (UnimplementedHelmRepositoriesServiceHandler) DeletePackageRepository(conte
 func (UnimplementedHelmRepositoriesServiceHandler) GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.GetPackageRepositoryPermissions is not implemented"))
 }
+
+func (UnimplementedHelmRepositoriesServiceHandler) InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.InvalidatePackageRepositoryCache is not implemented"))
+}
+
+func (UnimplementedHelmRepositoriesServiceHandler) GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.GetRepositoryTypes is not implemented"))
+}
+
+func (UnimplementedHelmRepositoriesServiceHandler) ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.ValidatePackageRepository is not implemented"))
+}
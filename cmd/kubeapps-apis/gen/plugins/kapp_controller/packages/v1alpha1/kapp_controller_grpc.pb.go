@@ -427,12 +427,15 @@ var KappControllerPackagesService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	KappControllerRepositoriesService_AddPackageRepository_FullMethodName            = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/AddPackageRepository"
-	KappControllerRepositoriesService_GetPackageRepositoryDetail_FullMethodName      = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositoryDetail"
-	KappControllerRepositoriesService_GetPackageRepositorySummaries_FullMethodName   = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositorySummaries"
-	KappControllerRepositoriesService_UpdatePackageRepository_FullMethodName         = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/UpdatePackageRepository"
-	KappControllerRepositoriesService_DeletePackageRepository_FullMethodName         = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/DeletePackageRepository"
-	KappControllerRepositoriesService_GetPackageRepositoryPermissions_FullMethodName = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositoryPermissions"
+	KappControllerRepositoriesService_AddPackageRepository_FullMethodName             = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/AddPackageRepository"
+	KappControllerRepositoriesService_GetPackageRepositoryDetail_FullMethodName       = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositoryDetail"
+	KappControllerRepositoriesService_GetPackageRepositorySummaries_FullMethodName    = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositorySummaries"
+	KappControllerRepositoriesService_UpdatePackageRepository_FullMethodName          = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/UpdatePackageRepository"
+	KappControllerRepositoriesService_DeletePackageRepository_FullMethodName          = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/DeletePackageRepository"
+	KappControllerRepositoriesService_GetPackageRepositoryPermissions_FullMethodName  = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositoryPermissions"
+	KappControllerRepositoriesService_InvalidatePackageRepositoryCache_FullMethodName = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/InvalidatePackageRepositoryCache"
+	KappControllerRepositoriesService_GetRepositoryTypes_FullMethodName               = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetRepositoryTypes"
+	KappControllerRepositoriesService_ValidatePackageRepository_FullMethodName        = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/ValidatePackageRepository"
 )
 
 // KappControllerRepositoriesServiceClient is the client API for KappControllerRepositoriesService service.
@@ -446,6 +449,9 @@ type KappControllerRepositoriesServiceClient interface {
 	UpdatePackageRepository(ctx context.Context, in *v1alpha1.UpdatePackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(ctx context.Context, in *v1alpha1.DeletePackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(ctx context.Context, in *v1alpha1.GetPackageRepositoryPermissionsRequest, opts ...grpc.CallOption) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(ctx context.Context, in *v1alpha1.InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(ctx context.Context, in *v1alpha1.GetRepositoryTypesRequest, opts ...grpc.CallOption) (*v1alpha1.GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(ctx context.Context, in *v1alpha1.AddPackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.ValidatePackageRepositoryResponse, error)
 }
 
 type kappControllerRepositoriesServiceClient struct {
@@ -510,6 +516,33 @@ func (c *kappControllerRepositoriesServiceClient) GetPackageRepositoryPermission
 	return out, nil
 }
 
+func (c *kappControllerRepositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, in *v1alpha1.InvalidatePackageRepositoryCacheRequest, opts ...grpc.CallOption) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error) {
+	out := new(v1alpha1.InvalidatePackageRepositoryCacheResponse)
+	err := c.cc.Invoke(ctx, KappControllerRepositoriesService_InvalidatePackageRepositoryCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kappControllerRepositoriesServiceClient) GetRepositoryTypes(ctx context.Context, in *v1alpha1.GetRepositoryTypesRequest, opts ...grpc.CallOption) (*v1alpha1.GetRepositoryTypesResponse, error) {
+	out := new(v1alpha1.GetRepositoryTypesResponse)
+	err := c.cc.Invoke(ctx, KappControllerRepositoriesService_GetRepositoryTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kappControllerRepositoriesServiceClient) ValidatePackageRepository(ctx context.Context, in *v1alpha1.AddPackageRepositoryRequest, opts ...grpc.CallOption) (*v1alpha1.ValidatePackageRepositoryResponse, error) {
+	out := new(v1alpha1.ValidatePackageRepositoryResponse)
+	err := c.cc.Invoke(ctx, KappControllerRepositoriesService_ValidatePackageRepository_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KappControllerRepositoriesServiceServer is the server API for KappControllerRepositoriesService service.
 // All implementations should embed UnimplementedKappControllerRepositoriesServiceServer
 // for forward compatibility
@@ -521,6 +554,9 @@ type KappControllerRepositoriesServiceServer interface {
 	UpdatePackageRepository(context.Context, *v1alpha1.UpdatePackageRepositoryRequest) (*v1alpha1.UpdatePackageRepositoryResponse, error)
 	DeletePackageRepository(context.Context, *v1alpha1.DeletePackageRepositoryRequest) (*v1alpha1.DeletePackageRepositoryResponse, error)
 	GetPackageRepositoryPermissions(context.Context, *v1alpha1.GetPackageRepositoryPermissionsRequest) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error)
+	InvalidatePackageRepositoryCache(context.Context, *v1alpha1.InvalidatePackageRepositoryCacheRequest) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error)
+	GetRepositoryTypes(context.Context, *v1alpha1.GetRepositoryTypesRequest) (*v1alpha1.GetRepositoryTypesResponse, error)
+	ValidatePackageRepository(context.Context, *v1alpha1.AddPackageRepositoryRequest) (*v1alpha1.ValidatePackageRepositoryResponse, error)
 }
 
 // UnimplementedKappControllerRepositoriesServiceServer should be embedded to have forward compatible implementations.
@@ -545,6 +581,15 @@ func (UnimplementedKappControllerRepositoriesServiceServer) DeletePackageReposit
 func (UnimplementedKappControllerRepositoriesServiceServer) GetPackageRepositoryPermissions(context.Context, *v1alpha1.GetPackageRepositoryPermissionsRequest) (*v1alpha1.GetPackageRepositoryPermissionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPackageRepositoryPermissions not implemented")
 }
+func (UnimplementedKappControllerRepositoriesServiceServer) InvalidatePackageRepositoryCache(context.Context, *v1alpha1.InvalidatePackageRepositoryCacheRequest) (*v1alpha1.InvalidatePackageRepositoryCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidatePackageRepositoryCache not implemented")
+}
+func (UnimplementedKappControllerRepositoriesServiceServer) GetRepositoryTypes(context.Context, *v1alpha1.GetRepositoryTypesRequest) (*v1alpha1.GetRepositoryTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRepositoryTypes not implemented")
+}
+func (UnimplementedKappControllerRepositoriesServiceServer) ValidatePackageRepository(context.Context, *v1alpha1.AddPackageRepositoryRequest) (*v1alpha1.ValidatePackageRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePackageRepository not implemented")
+}
 
 // UnsafeKappControllerRepositoriesServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to KappControllerRepositoriesServiceServer will
@@ -665,6 +710,60 @@ func _KappControllerRepositoriesService_GetPackageRepositoryPermissions_Handler(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KappControllerRepositoriesService_InvalidatePackageRepositoryCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.InvalidatePackageRepositoryCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KappControllerRepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KappControllerRepositoriesService_InvalidatePackageRepositoryCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KappControllerRepositoriesServiceServer).InvalidatePackageRepositoryCache(ctx, req.(*v1alpha1.InvalidatePackageRepositoryCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KappControllerRepositoriesService_GetRepositoryTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.GetRepositoryTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KappControllerRepositoriesServiceServer).GetRepositoryTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KappControllerRepositoriesService_GetRepositoryTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KappControllerRepositoriesServiceServer).GetRepositoryTypes(ctx, req.(*v1alpha1.GetRepositoryTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KappControllerRepositoriesService_ValidatePackageRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1alpha1.AddPackageRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KappControllerRepositoriesServiceServer).ValidatePackageRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KappControllerRepositoriesService_ValidatePackageRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KappControllerRepositoriesServiceServer).ValidatePackageRepository(ctx, req.(*v1alpha1.AddPackageRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // KappControllerRepositoriesService_ServiceDesc is the grpc.ServiceDesc for KappControllerRepositoriesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -696,6 +795,18 @@ var KappControllerRepositoriesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPackageRepositoryPermissions",
 			Handler:    _KappControllerRepositoriesService_GetPackageRepositoryPermissions_Handler,
 		},
+		{
+			MethodName: "InvalidatePackageRepositoryCache",
+			Handler:    _KappControllerRepositoriesService_InvalidatePackageRepositoryCache_Handler,
+		},
+		{
+			MethodName: "GetRepositoryTypes",
+			Handler:    _KappControllerRepositoriesService_GetRepositoryTypes_Handler,
+		},
+		{
+			MethodName: "ValidatePackageRepository",
+			Handler:    _KappControllerRepositoriesService_ValidatePackageRepository_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "kubeappsapis/plugins/kapp_controller/packages/v1alpha1/kapp_controller.proto",
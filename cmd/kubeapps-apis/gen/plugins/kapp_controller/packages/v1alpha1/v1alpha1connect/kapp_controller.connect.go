@@ -86,6 +86,15 @@ const (
 	// KappControllerRepositoriesServiceGetPackageRepositoryPermissionsProcedure is the fully-qualified
 	// name of the KappControllerRepositoriesService's GetPackageRepositoryPermissions RPC.
 	KappControllerRepositoriesServiceGetPackageRepositoryPermissionsProcedure = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetPackageRepositoryPermissions"
+	// KappControllerRepositoriesServiceInvalidatePackageRepositoryCacheProcedure is the fully-qualified
+	// name of the KappControllerRepositoriesService's InvalidatePackageRepositoryCache RPC.
+	KappControllerRepositoriesServiceInvalidatePackageRepositoryCacheProcedure = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/InvalidatePackageRepositoryCache"
+	// KappControllerRepositoriesServiceGetRepositoryTypesProcedure is the fully-qualified name of the
+	// KappControllerRepositoriesService's GetRepositoryTypes RPC.
+	KappControllerRepositoriesServiceGetRepositoryTypesProcedure = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/GetRepositoryTypes"
+	// KappControllerRepositoriesServiceValidatePackageRepositoryProcedure is the fully-qualified name
+	// of the KappControllerRepositoriesService's ValidatePackageRepository RPC.
+	KappControllerRepositoriesServiceValidatePackageRepositoryProcedure = "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/ValidatePackageRepository"
 )
 
 // KappControllerPackagesServiceClient is a client for the
@@ -388,6 +397,9 @@ type KappControllerRepositoriesServiceClient interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewKappControllerRepositoriesServiceClient constructs a client for the
@@ -431,17 +443,35 @@ func NewKappControllerRepositoriesServiceClient(httpClient connect_go.HTTPClient
 			baseURL+KappControllerRepositoriesServiceGetPackageRepositoryPermissionsProcedure,
 			opts...,
 		),
+		invalidatePackageRepositoryCache: connect_go.NewClient[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse](
+			httpClient,
+			baseURL+KappControllerRepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+			opts...,
+		),
+		getRepositoryTypes: connect_go.NewClient[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse](
+			httpClient,
+			baseURL+KappControllerRepositoriesServiceGetRepositoryTypesProcedure,
+			opts...,
+		),
+		validatePackageRepository: connect_go.NewClient[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse](
+			httpClient,
+			baseURL+KappControllerRepositoriesServiceValidatePackageRepositoryProcedure,
+			opts...,
+		),
 	}
 }
 
 // kappControllerRepositoriesServiceClient implements KappControllerRepositoriesServiceClient.
 type kappControllerRepositoriesServiceClient struct {
-	addPackageRepository            *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
-	getPackageRepositoryDetail      *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
-	getPackageRepositorySummaries   *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
-	updatePackageRepository         *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
-	deletePackageRepository         *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
-	getPackageRepositoryPermissions *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	addPackageRepository             *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.AddPackageRepositoryResponse]
+	getPackageRepositoryDetail       *connect_go.Client[v1alpha1.GetPackageRepositoryDetailRequest, v1alpha1.GetPackageRepositoryDetailResponse]
+	getPackageRepositorySummaries    *connect_go.Client[v1alpha1.GetPackageRepositorySummariesRequest, v1alpha1.GetPackageRepositorySummariesResponse]
+	updatePackageRepository          *connect_go.Client[v1alpha1.UpdatePackageRepositoryRequest, v1alpha1.UpdatePackageRepositoryResponse]
+	deletePackageRepository          *connect_go.Client[v1alpha1.DeletePackageRepositoryRequest, v1alpha1.DeletePackageRepositoryResponse]
+	getPackageRepositoryPermissions  *connect_go.Client[v1alpha1.GetPackageRepositoryPermissionsRequest, v1alpha1.GetPackageRepositoryPermissionsResponse]
+	invalidatePackageRepositoryCache *connect_go.Client[v1alpha1.InvalidatePackageRepositoryCacheRequest, v1alpha1.InvalidatePackageRepositoryCacheResponse]
+	getRepositoryTypes               *connect_go.Client[v1alpha1.GetRepositoryTypesRequest, v1alpha1.GetRepositoryTypesResponse]
+	validatePackageRepository        *connect_go.Client[v1alpha1.AddPackageRepositoryRequest, v1alpha1.ValidatePackageRepositoryResponse]
 }
 
 // AddPackageRepository calls
@@ -480,6 +510,24 @@ func (c *kappControllerRepositoriesServiceClient) GetPackageRepositoryPermission
 	return c.getPackageRepositoryPermissions.CallUnary(ctx, req)
 }
 
+// InvalidatePackageRepositoryCache calls
+// kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.InvalidatePackageRepositoryCache.
+func (c *kappControllerRepositoriesServiceClient) InvalidatePackageRepositoryCache(ctx context.Context, req *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return c.invalidatePackageRepositoryCache.CallUnary(ctx, req)
+}
+
+// GetRepositoryTypes calls
+// kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.GetRepositoryTypes.
+func (c *kappControllerRepositoriesServiceClient) GetRepositoryTypes(ctx context.Context, req *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return c.getRepositoryTypes.CallUnary(ctx, req)
+}
+
+// ValidatePackageRepository calls
+// kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.ValidatePackageRepository.
+func (c *kappControllerRepositoriesServiceClient) ValidatePackageRepository(ctx context.Context, req *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return c.validatePackageRepository.CallUnary(ctx, req)
+}
+
 // KappControllerRepositoriesServiceHandler is an implementation of the
 // kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService service.
 type KappControllerRepositoriesServiceHandler interface {
@@ -490,6 +538,9 @@ type KappControllerRepositoriesServiceHandler interface {
 	UpdatePackageRepository(context.Context, *connect_go.Request[v1alpha1.UpdatePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.UpdatePackageRepositoryResponse], error)
 	DeletePackageRepository(context.Context, *connect_go.Request[v1alpha1.DeletePackageRepositoryRequest]) (*connect_go.Response[v1alpha1.DeletePackageRepositoryResponse], error)
 	GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error)
+	InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error)
+	GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error)
+	ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error)
 }
 
 // NewKappControllerRepositoriesServiceHandler builds an HTTP handler from the service
@@ -528,6 +579,21 @@ func NewKappControllerRepositoriesServiceHandler(svc KappControllerRepositoriesS
 		svc.GetPackageRepositoryPermissions,
 		opts...,
 	)
+	kappControllerRepositoriesServiceInvalidatePackageRepositoryCacheHandler := connect_go.NewUnaryHandler(
+		KappControllerRepositoriesServiceInvalidatePackageRepositoryCacheProcedure,
+		svc.InvalidatePackageRepositoryCache,
+		opts...,
+	)
+	kappControllerRepositoriesServiceGetRepositoryTypesHandler := connect_go.NewUnaryHandler(
+		KappControllerRepositoriesServiceGetRepositoryTypesProcedure,
+		svc.GetRepositoryTypes,
+		opts...,
+	)
+	kappControllerRepositoriesServiceValidatePackageRepositoryHandler := connect_go.NewUnaryHandler(
+		KappControllerRepositoriesServiceValidatePackageRepositoryProcedure,
+		svc.ValidatePackageRepository,
+		opts...,
+	)
 	return "/kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case KappControllerRepositoriesServiceAddPackageRepositoryProcedure:
@@ -542,6 +608,12 @@ func NewKappControllerRepositoriesServiceHandler(svc KappControllerRepositoriesS
 			kappControllerRepositoriesServiceDeletePackageRepositoryHandler.ServeHTTP(w, r)
 		case KappControllerRepositoriesServiceGetPackageRepositoryPermissionsProcedure:
 			kappControllerRepositoriesServiceGetPackageRepositoryPermissionsHandler.ServeHTTP(w, r)
+		case KappControllerRepositoriesServiceInvalidatePackageRepositoryCacheProcedure:
+			kappControllerRepositoriesServiceInvalidatePackageRepositoryCacheHandler.ServeHTTP(w, r)
+		case KappControllerRepositoriesServiceGetRepositoryTypesProcedure:
+			kappControllerRepositoriesServiceGetRepositoryTypesHandler.ServeHTTP(w, r)
+		case KappControllerRepositoriesServiceValidatePackageRepositoryProcedure:
+			kappControllerRepositoriesServiceValidatePackageRepositoryHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -574,3 +646,15 @@ func (UnimplementedKappControllerRepositoriesServiceHandler) DeletePackageReposi
 func (UnimplementedKappControllerRepositoriesServiceHandler) GetPackageRepositoryPermissions(context.Context, *connect_go.Request[v1alpha1.GetPackageRepositoryPermissionsRequest]) (*connect_go.Response[v1alpha1.GetPackageRepositoryPermissionsResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.GetPackageRepositoryPermissions is not implemented"))
 }
+
+func (UnimplementedKappControllerRepositoriesServiceHandler) InvalidatePackageRepositoryCache(context.Context, *connect_go.Request[v1alpha1.InvalidatePackageRepositoryCacheRequest]) (*connect_go.Response[v1alpha1.InvalidatePackageRepositoryCacheResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.InvalidatePackageRepositoryCache is not implemented"))
+}
+
+func (UnimplementedKappControllerRepositoriesServiceHandler) GetRepositoryTypes(context.Context, *connect_go.Request[v1alpha1.GetRepositoryTypesRequest]) (*connect_go.Response[v1alpha1.GetRepositoryTypesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.GetRepositoryTypes is not implemented"))
+}
+
+func (UnimplementedKappControllerRepositoriesServiceHandler) ValidatePackageRepository(context.Context, *connect_go.Request[v1alpha1.AddPackageRepositoryRequest]) (*connect_go.Response[v1alpha1.ValidatePackageRepositoryResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("kubeappsapis.plugins.kapp_controller.packages.v1alpha1.KappControllerRepositoriesService.ValidatePackageRepository is not implemented"))
+}
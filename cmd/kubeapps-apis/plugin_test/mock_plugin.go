@@ -67,6 +67,32 @@ func (s TestPackagingPluginServer) GetAvailablePackageDetail(ctx context.Context
 	}), nil
 }
 
+// GetAvailablePackageDetailsBatch returns the package detail for each of the requests.
+func (s TestPackagingPluginServer) GetAvailablePackageDetailsBatch(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailsBatchRequest]) (*connect.Response[corev1.GetAvailablePackageDetailsBatchResponse], error) {
+	if s.ErrorCode != 0 {
+		return nil, connect.NewError(s.ErrorCode, fmt.Errorf("Non-OK response"))
+	}
+	details := make([]*corev1.AvailablePackageDetail, len(request.Msg.GetRequests()))
+	for i := range details {
+		details[i] = s.AvailablePackageDetail
+	}
+	return connect.NewResponse(&corev1.GetAvailablePackageDetailsBatchResponse{
+		AvailablePackageDetails: details,
+	}), nil
+}
+
+// StreamAvailablePackageDetail is not implemented, the details being streamed
+// by the core from the GetAvailablePackageDetail responses.
+func (s TestPackagingPluginServer) StreamAvailablePackageDetail(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailRequest], stream *connect.ServerStream[corev1.StreamAvailablePackageDetailResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
+// StreamInstalledPackageResourceRefs is not implemented, the resource refs
+// being streamed by the core from the GetInstalledPackageResourceRefs responses.
+func (s TestPackagingPluginServer) StreamInstalledPackageResourceRefs(ctx context.Context, request *connect.Request[corev1.GetInstalledPackageResourceRefsRequest], stream *connect.ServerStream[corev1.StreamInstalledPackageResourceRefsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
 // GetAvailablePackageMetadatas returns the package metadatas based on the request.
 func (s TestPackagingPluginServer) GetAvailablePackageMetadatas(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageMetadatasRequest]) (*connect.Response[corev1.GetAvailablePackageMetadatasResponse], error) {
 	if s.ErrorCode != 0 {
@@ -218,6 +244,20 @@ func (s TestRepositoriesPluginServer) DeletePackageRepository(ctx context.Contex
 	return connect.NewResponse(&corev1.DeletePackageRepositoryResponse{}), nil
 }
 
+func (s TestRepositoriesPluginServer) InvalidatePackageRepositoryCache(ctx context.Context, request *connect.Request[corev1.InvalidatePackageRepositoryCacheRequest]) (*connect.Response[corev1.InvalidatePackageRepositoryCacheResponse], error) {
+	if s.ErrorCode != 0 {
+		return nil, connect.NewError(s.ErrorCode, fmt.Errorf("Non-OK response"))
+	}
+	return connect.NewResponse(&corev1.InvalidatePackageRepositoryCacheResponse{}), nil
+}
+
+func (s TestRepositoriesPluginServer) ValidatePackageRepository(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.ValidatePackageRepositoryResponse], error) {
+	if s.ErrorCode != 0 {
+		return nil, connect.NewError(s.ErrorCode, fmt.Errorf("Non-OK response"))
+	}
+	return connect.NewResponse(&corev1.ValidatePackageRepositoryResponse{Valid: true}), nil
+}
+
 func (s TestRepositoriesPluginServer) GetPackageRepositoryPermissions(ctx context.Context, request *connect.Request[corev1.GetPackageRepositoryPermissionsRequest]) (*connect.Response[corev1.GetPackageRepositoryPermissionsResponse], error) {
 	if s.ErrorCode != 0 {
 		return nil, connect.NewError(s.ErrorCode, fmt.Errorf("Non-OK response"))
@@ -236,3 +276,15 @@ func (s TestRepositoriesPluginServer) GetPackageRepositoryPermissions(ctx contex
 		},
 	}), nil
 }
+
+func (s TestRepositoriesPluginServer) GetRepositoryTypes(ctx context.Context, request *connect.Request[corev1.GetRepositoryTypesRequest]) (*connect.Response[corev1.GetRepositoryTypesResponse], error) {
+	if s.ErrorCode != 0 {
+		return nil, connect.NewError(s.ErrorCode, fmt.Errorf("Non-OK response"))
+	}
+	return connect.NewResponse(&corev1.GetRepositoryTypesResponse{
+		RepositoryTypes: []*corev1.PackageRepositoryTypes{{
+			Plugin: s.Plugin,
+			Types:  []string{s.Plugin.Name},
+		}},
+	}), nil
+}
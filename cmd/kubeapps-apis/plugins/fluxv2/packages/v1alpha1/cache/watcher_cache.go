@@ -135,6 +135,10 @@ type NamespacedResourceWatcherCacheConfig struct {
 	NewObjFunc    NewObjectFunc
 	NewListFunc   NewObjectListFunc
 	ListItemsFunc GetListItemsFunc
+
+	// If set, the initial sync and the watch loop only start once 'StartCh' is closed,
+	// e.g. when this replica is elected as leader. A nil channel starts them right away
+	StartCh <-chan struct{}
 }
 
 // invokeExpectResync arg is only set to true for by unit tests only
@@ -182,7 +186,16 @@ func NewNamespacedResourceWatcherCache(name string, config NamespacedResourceWat
 	// per https://github.com/vmware-tanzu/kubeapps/issues/4329
 	// we want to do this asynchronously, so that having to parse existing large repos in the cluster
 	// doesn't block the kubeapps apis pod start-up
-	go c.syncAndStartWatchLoop(stopCh)
+	go func() {
+		if c.config.StartCh != nil {
+			select {
+			case <-c.config.StartCh:
+			case <-stopCh:
+				return
+			}
+		}
+		c.syncAndStartWatchLoop(stopCh)
+	}()
 	return &c, nil
 }
 
@@ -4058,6 +4058,14 @@ var (
 		}
 	}
 
+	invalidate_repo_req_1 = &corev1.InvalidatePackageRepositoryCacheRequest{
+		PackageRepoRef: repoRefInReq("repo-1", "namespace-1"),
+	}
+
+	invalidate_repo_req_2 = &corev1.InvalidatePackageRepositoryCacheRequest{
+		PackageRepoRef: repoRefInReq("repo-kaka", "namespace-kaka"),
+	}
+
 	delete_repo_req_1 = &corev1.DeletePackageRepositoryRequest{
 		PackageRepoRef: repoRefInReq("repo-1", "namespace-1"),
 	}
@@ -27,12 +27,12 @@ func RegisterWithGRPCServer(opts pluginsv1alpha1.GRPCPluginRegistrationOptions)
 	// 'Shutdown' hook
 	stopCh := make(chan struct{})
 
-	svr, err := NewServer(opts.ConfigGetter, opts.ClustersConfig.KubeappsClusterName, stopCh, opts.PluginConfigPath, opts.ClientQPS, opts.ClientBurst)
+	svr, err := NewServer(opts.ConfigGetter, opts.ClustersConfig.KubeappsClusterName, stopCh, opts.LeaderElected, opts.PluginConfigPath, opts.ClientQPS, opts.ClientBurst)
 	if err != nil {
 		return nil, err
 	}
-	opts.Mux.Handle(packagesConnect.NewFluxV2PackagesServiceHandler(svr))
-	opts.Mux.Handle(packagesConnect.NewFluxV2RepositoriesServiceHandler(svr))
+	opts.Mux.Handle(packagesConnect.NewFluxV2PackagesServiceHandler(svr, opts.HandlerOptions...))
+	opts.Mux.Handle(packagesConnect.NewFluxV2RepositoriesServiceHandler(svr, opts.HandlerOptions...))
 	return svr, nil
 }
 
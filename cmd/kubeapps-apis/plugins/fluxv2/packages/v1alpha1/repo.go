@@ -220,32 +220,42 @@ func (s *Server) httpClientOptionsForRepo(ctx context.Context, headers http.Head
 	return sink.clientOptionsForHttpRepo(ctx, *repo)
 }
 
-func (s *Server) newRepo(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.PackageRepositoryReference], error) {
-	if request.Msg.Name == "" {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request Name provided"))
+// checkNewRepoRequest checks the arguments of a request to add a repository.
+func checkNewRepoRequest(request *corev1.AddPackageRepositoryRequest) error {
+	if request.Name == "" {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request Name provided"))
 	}
 
 	// flux repositories are now considered to be namespaced, to support the most common cases.
 	// see discussion at https://github.com/vmware-tanzu/kubeapps/issues/5542
-	if !request.Msg.GetNamespaceScoped() {
-		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Global-scoped repositories are not supported"))
+	if !request.GetNamespaceScoped() {
+		return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Global-scoped repositories are not supported"))
 	}
 
-	typ := request.Msg.GetType()
+	typ := request.GetType()
 	if typ != "helm" && typ != sourcev1.HelmRepositoryTypeOCI {
-		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Repository type [%s] not supported", typ))
+		return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Repository type [%s] not supported", typ))
 	}
 
-	description := request.Msg.GetDescription()
-	url := request.Msg.GetUrl()
-	tlsConfig := request.Msg.GetTlsConfig()
-	if url == "" {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Repository url may not be empty"))
+	tlsConfig := request.GetTlsConfig()
+	if request.GetUrl() == "" {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Repository url may not be empty"))
 	} else if tlsConfig != nil && tlsConfig.InsecureSkipVerify {
 		// ref https://github.com/fluxcd/source-controller/issues/807
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("TLS flag insecureSkipVerify is not supported"))
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("TLS flag insecureSkipVerify is not supported"))
 	}
+	return nil
+}
 
+func (s *Server) newRepo(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.PackageRepositoryReference], error) {
+	if err := checkNewRepoRequest(request.Msg); err != nil {
+		return nil, err
+	}
+
+	typ := request.Msg.GetType()
+	description := request.Msg.GetDescription()
+	url := request.Msg.GetUrl()
+	tlsConfig := request.Msg.GetTlsConfig()
 	name := types.NamespacedName{Name: request.Msg.Name, Namespace: request.Msg.Context.Namespace}
 	auth := request.Msg.GetAuth()
 
@@ -298,6 +308,57 @@ func (s *Server) newRepo(ctx context.Context, request *connect.Request[corev1.Ad
 	}
 }
 
+// validateNewRepo checks that the index of a repository which is not created yet
+// can be fetched with its credentials, as source-controller would do once the
+// repository is created. Neither the repository nor its secret are stored.
+func (s *Server) validateNewRepo(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*corev1.ValidatePackageRepositoryResponse, error) {
+	if err := checkNewRepoRequest(request.Msg); err != nil {
+		return nil, err
+	}
+	typ := request.Msg.GetType()
+	if typ == sourcev1.HelmRepositoryTypeOCI {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Validation of repositories of type [%s] is not supported yet", typ))
+	}
+	if request.Msg.CustomDetail != nil {
+		customDetail := &v1alpha1.FluxPackageRepositoryCustomDetail{}
+		if err := request.Msg.CustomDetail.UnmarshalTo(customDetail); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("The customDetail could not be parsed due to: %w", err))
+		}
+		if provider := customDetail.Provider; provider != "" && provider != "generic" {
+			return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Validation of repositories with the auth provider [%s] is not supported yet", provider))
+		}
+	}
+
+	name := types.NamespacedName{Name: request.Msg.Name, Namespace: request.Msg.Context.Namespace}
+	secret, _, err := s.repoSecretForCreate(ctx, request.Header(), name, typ, request.Msg.GetTlsConfig(), request.Msg.GetAuth())
+	if err != nil {
+		return nil, err
+	}
+	if err := fetchRepoIndex(request.Msg.GetUrl(), secret); err != nil {
+		log.Infof("Failed validation of the repository [%s]: %v", name, err)
+		return &corev1.ValidatePackageRepositoryResponse{Reason: err.Error()}, nil
+	}
+	return &corev1.ValidatePackageRepositoryResponse{Valid: true}, nil
+}
+
+// fetchRepoIndex fetches the index of an HTTP repository, with the credentials of
+// its secret if any.
+func fetchRepoIndex(repoUrl string, secret *apiv1.Secret) error {
+	var clientOptions *common.HttpClientOptions
+	if secret != nil {
+		var err error
+		if clientOptions, err = common.HttpClientOptionsFromSecret(*secret); err != nil {
+			return err
+		}
+	}
+	client, headers, err := common.NewHttpClientAndHeaders(clientOptions)
+	if err != nil {
+		return err
+	}
+	_, err = httpclient.Get(strings.TrimSuffix(repoUrl, "/")+"/index.yaml", client, headers)
+	return err
+}
+
 func (s *Server) repoDetail(ctx context.Context, headers http.Header, repoRef *corev1.PackageRepositoryReference) (*corev1.PackageRepositoryDetail, error) {
 	key := types.NamespacedName{Namespace: repoRef.Context.Namespace, Name: repoRef.Identifier}
 
@@ -540,6 +601,31 @@ func (s *Server) deleteRepo(ctx context.Context, headers http.Header, repoRef *c
 	}
 }
 
+// invalidateRepoCache asks flux source-controller to reconcile the HelmRepository right
+// away by setting the reconcile request annotation. Once the new index has been fetched,
+// the resulting status change is picked up by the repo cache watcher as usual.
+func (s *Server) invalidateRepoCache(ctx context.Context, headers http.Header, repoRef *corev1.PackageRepositoryReference) error {
+	key := types.NamespacedName{Namespace: repoRef.GetContext().GetNamespace(), Name: repoRef.GetIdentifier()}
+	repo, err := s.getRepoInCluster(ctx, headers, key)
+	if err != nil {
+		return err
+	}
+
+	log.V(4).Infof("Requesting reconciliation of repo: [%s]", key.String())
+
+	if repo.Annotations == nil {
+		repo.Annotations = map[string]string{}
+	}
+	repo.Annotations[fluxmeta.ReconcileRequestAnnotation] = time.Now().Format(time.RFC3339Nano)
+
+	if client, err := s.getClient(headers, key.Namespace); err != nil {
+		return err
+	} else if err = client.Update(ctx, repo); err != nil {
+		return connecterror.FromK8sError("update", "HelmRepository", key.String(), err)
+	}
+	return nil
+}
+
 // implements plug-in specific cache-related functionality
 type repoEventSink struct {
 	clientGetter clientgetter.FixedClusterClientProviderInterface
@@ -2337,6 +2337,131 @@ func TestDeletePackageRepository(t *testing.T) {
 	}
 }
 
+func TestInvalidatePackageRepositoryCache(t *testing.T) {
+	testCases := []struct {
+		name              string
+		request           *corev1.InvalidatePackageRepositoryCacheRequest
+		repoIndex         string
+		repoName          string
+		repoNamespace     string
+		expectedErrorCode connect.Code
+	}{
+		{
+			name:          "requests reconciliation of the repository",
+			request:       invalidate_repo_req_1,
+			repoIndex:     testYaml("valid-index.yaml"),
+			repoName:      "repo-1",
+			repoNamespace: "namespace-1",
+		},
+		{
+			name:              "returns not found if package repo doesn't exist",
+			request:           invalidate_repo_req_2,
+			repoIndex:         testYaml("valid-index.yaml"),
+			repoName:          "repo-1",
+			repoNamespace:     "namespace-1",
+			expectedErrorCode: connect.CodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, repo, err := newHttpRepoAndServeIndex(tc.repoIndex, tc.repoName, tc.repoNamespace, nil, "")
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			defer ts.Close()
+
+			s, _, err := newServerWithRepos(t, []sourcev1.HelmRepository{*repo}, nil, nil)
+			if err != nil {
+				t.Fatalf("error instantiating the server: %v", err)
+			}
+
+			ctx := context.Background()
+			_, err = s.InvalidatePackageRepositoryCache(ctx, connect.NewRequest(tc.request))
+			if got, want := connect.CodeOf(err), tc.expectedErrorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			} else if got != 0 {
+				return
+			}
+
+			ctrlClient, err := s.clientGetter.ControllerRuntime(http.Header{}, s.kubeappsCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nsname := types.NamespacedName{
+				Namespace: tc.request.PackageRepoRef.Context.Namespace,
+				Name:      tc.request.PackageRepoRef.Identifier,
+			}
+			var actualRepo sourcev1.HelmRepository
+			if err = ctrlClient.Get(ctx, nsname, &actualRepo); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := actualRepo.GetAnnotations()[fluxmeta.ReconcileRequestAnnotation]; !ok {
+				t.Errorf("expected annotation [%s] to be set on repository [%s]", fluxmeta.ReconcileRequestAnnotation, nsname)
+			}
+		})
+	}
+}
+
+func TestFetchRepoIndex(t *testing.T) {
+	// The repository only serves its index to the user "foo".
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "foo" || password != "bar" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/index.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "apiVersion: v1")
+	}))
+	defer ts.Close()
+
+	basicAuthSecret := func(username, password string) *apiv1.Secret {
+		return &apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-1", Namespace: "namespace-1"},
+			Data: map[string][]byte{
+				"username": []byte(username),
+				"password": []byte(password),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		repoUrl     string
+		secret      *apiv1.Secret
+		expectedErr bool
+	}{
+		{
+			name:    "fetches the index with the credentials of the secret",
+			repoUrl: ts.URL + "/",
+			secret:  basicAuthSecret("foo", "bar"),
+		},
+		{
+			name:        "fails with the wrong credentials",
+			repoUrl:     ts.URL,
+			secret:      basicAuthSecret("foo", "baz"),
+			expectedErr: true,
+		},
+		{
+			name:        "fails without credentials",
+			repoUrl:     ts.URL,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := fetchRepoIndex(tc.repoUrl, tc.secret)
+			if got, want := err != nil, tc.expectedErr; got != want {
+				t.Errorf("got error: %v, want error: %t", err, want)
+			}
+		})
+	}
+}
+
 func TestGetOciAvailablePackageSummariesWithoutPagination(t *testing.T) {
 	seed_data_1, err := newFakeRemoteOciRegistryData_1()
 	if err != nil {
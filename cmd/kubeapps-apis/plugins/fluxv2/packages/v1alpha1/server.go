@@ -66,8 +66,9 @@ type Server struct {
 }
 
 // NewServer returns a Server automatically configured with a function to obtain
-// the k8s client config.
-func NewServer(configGetter core.KubernetesConfigGetter, kubeappsCluster string, stopCh <-chan struct{}, pluginConfigPath string, clientQPS float32, clientBurst int) (*Server, error) {
+// the k8s client config. The repository watcher only starts once leaderElected
+// is closed, so that a single replica watches the repositories in the cluster.
+func NewServer(configGetter core.KubernetesConfigGetter, kubeappsCluster string, stopCh <-chan struct{}, leaderElected <-chan struct{}, pluginConfigPath string, clientQPS float32, clientBurst int) (*Server, error) {
 	log.Infof("+fluxv2 NewServer(kubeappsCluster: [%v], pluginConfigPath: [%s]",
 		kubeappsCluster, pluginConfigPath)
 
@@ -126,6 +127,7 @@ func NewServer(configGetter core.KubernetesConfigGetter, kubeappsCluster string,
 					return ret
 				}
 			},
+			StartCh: leaderElected,
 		}
 		if repoCache, err := cache.NewNamespacedResourceWatcherCache(
 			"repoCache", repoCacheConfig, redisCli, stopCh, false); err != nil {
@@ -494,6 +496,30 @@ func (s *Server) GetInstalledPackageResourceRefs(ctx context.Context, request *c
 	}
 }
 
+// StreamInstalledPackageResourceRefs streams the references for the Kubernetes
+// resources created by an installed package, as they are decoded from the
+// manifest of its Helm release.
+func (s *Server) StreamInstalledPackageResourceRefs(ctx context.Context, request *connect.Request[corev1.GetInstalledPackageResourceRefsRequest], stream *connect.ServerStream[corev1.StreamInstalledPackageResourceRefsResponse]) error {
+	pkgRef := request.Msg.GetInstalledPackageRef()
+	identifier := pkgRef.GetIdentifier()
+	log.InfoS("+fluxv2 StreamInstalledPackageResourceRefs", "cluster", pkgRef.GetContext().GetCluster(), "namespace", pkgRef.GetContext().GetNamespace(), "id", identifier)
+
+	key := types.NamespacedName{Namespace: pkgRef.Context.Namespace, Name: identifier}
+	rel, err := s.getReleaseInCluster(ctx, request.Header(), key)
+	if err != nil {
+		return err
+	}
+	hrName := helmReleaseName(key, rel)
+	// The context is the same as the one of GetInstalledPackageResourceRefs.
+	responseContext := &corev1.Context{Cluster: s.kubeappsCluster, Namespace: key.Namespace}
+	return resourcerefs.StreamInstalledPackageResourceRefs(request.Header(), hrName, s.actionConfigGetter, func(refs []*corev1.ResourceRef) error {
+		return stream.Send(&corev1.StreamInstalledPackageResourceRefsResponse{
+			Context:      responseContext,
+			ResourceRefs: refs,
+		})
+	})
+}
+
 func (s *Server) AddPackageRepository(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.AddPackageRepositoryResponse], error) {
 	if request == nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request provided"))
@@ -518,6 +544,30 @@ func (s *Server) AddPackageRepository(ctx context.Context, request *connect.Requ
 	}
 }
 
+// ValidatePackageRepository checks that the repository of the request can be
+// reached with its credentials, without adding it.
+func (s *Server) ValidatePackageRepository(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.ValidatePackageRepositoryResponse], error) {
+	if request == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request provided"))
+	}
+	if request.Msg.Context == nil || request.Msg.Context.Namespace == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request Context namespace provided"))
+	}
+
+	cluster := request.Msg.GetContext().GetCluster()
+	log.InfoS("+fluxv2 ValidatePackageRepository", "cluster", cluster, "namespace", request.Msg.GetContext().GetNamespace(), "name", request.Msg.GetName())
+
+	if cluster != "" && cluster != s.kubeappsCluster {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Not supported yet: request.Context.Cluster: [%v]", request.Msg.Context.Cluster))
+	}
+
+	if response, err := s.validateNewRepo(ctx, request); err != nil {
+		return nil, err
+	} else {
+		return connect.NewResponse(response), nil
+	}
+}
+
 func (s *Server) GetPackageRepositoryDetail(ctx context.Context, request *connect.Request[corev1.GetPackageRepositoryDetailRequest]) (*connect.Response[corev1.GetPackageRepositoryDetailResponse], error) {
 	log.Infof("+fluxv2 GetPackageRepositoryDetail [%v]", request)
 	defer log.Info("-fluxv2 GetPackageRepositoryDetail")
@@ -605,6 +655,25 @@ func (s *Server) DeletePackageRepository(ctx context.Context, request *connect.R
 	}
 }
 
+func (s *Server) InvalidatePackageRepositoryCache(ctx context.Context, request *connect.Request[corev1.InvalidatePackageRepositoryCacheRequest]) (*connect.Response[corev1.InvalidatePackageRepositoryCacheResponse], error) {
+	log.Infof("+fluxv2 InvalidatePackageRepositoryCache [%v]", request)
+	if request == nil || request.Msg.PackageRepoRef == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request PackageRepoRef provided"))
+	}
+
+	repoRef := request.Msg.PackageRepoRef
+	cluster := repoRef.GetContext().GetCluster()
+	if cluster != "" && cluster != s.kubeappsCluster {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Not supported yet: request.packageRepoRef.Context.Cluster: [%v]", cluster))
+	}
+
+	if err := s.invalidateRepoCache(ctx, request.Header(), repoRef); err != nil {
+		return nil, err
+	} else {
+		return connect.NewResponse(&corev1.InvalidatePackageRepositoryCacheResponse{}), nil
+	}
+}
+
 func (s *Server) GetPackageRepositoryPermissions(ctx context.Context, request *connect.Request[corev1.GetPackageRepositoryPermissionsRequest]) (*connect.Response[corev1.GetPackageRepositoryPermissionsResponse], error) {
 	log.Infof("+fluxv2 GetPackageRepositoryPermissions [%v]", request)
 
@@ -642,6 +711,17 @@ func (s *Server) GetPackageRepositoryPermissions(ctx context.Context, request *c
 	}), nil
 }
 
+// GetRepositoryTypes returns the types of the repositories which can be added with the 'fluxv2' plugin
+func (s *Server) GetRepositoryTypes(ctx context.Context, request *connect.Request[corev1.GetRepositoryTypesRequest]) (*connect.Response[corev1.GetRepositoryTypesResponse], error) {
+	log.Infof("+fluxv2 GetRepositoryTypes [%v]", request)
+	return connect.NewResponse(&corev1.GetRepositoryTypesResponse{
+		RepositoryTypes: []*corev1.PackageRepositoryTypes{{
+			Plugin: GetPluginDetail(),
+			Types:  []string{"helm", sourcev1.HelmRepositoryTypeOCI},
+		}},
+	}), nil
+}
+
 // makes the server look like a repo event sink. Facilitates code reuse between
 // use cases when something happens in background as a result of a watch event,
 // aka an "out-of-band" interaction and use cases when the user wants something
@@ -710,6 +790,30 @@ func GetPluginDetail() *plugins.Plugin {
 	return common.GetPluginDetail()
 }
 
+// GetPluginCapabilities returns the core.plugins.PluginCapabilities declared by the plugin.
+func GetPluginCapabilities() *plugins.PluginCapabilities {
+	return &plugins.PluginCapabilities{
+		SupportsOci: true,
+		AuthTypes: []string{
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_BASIC_AUTH.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_TLS.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_DOCKER_CONFIG_JSON.String(),
+		},
+	}
+}
+
 func (s *Server) GetAvailablePackageMetadatas(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageMetadatasRequest]) (*connect.Response[corev1.GetAvailablePackageMetadatasResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
 }
+
+// GetAvailablePackageDetailsBatch is not implemented by the plugin, since the
+// batches are fanned out as individual GetAvailablePackageDetail requests by the core.
+func (s *Server) GetAvailablePackageDetailsBatch(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailsBatchRequest]) (*connect.Response[corev1.GetAvailablePackageDetailsBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
+// StreamAvailablePackageDetail is not implemented by the plugin, since the
+// details are streamed by the core from the GetAvailablePackageDetail responses.
+func (s *Server) StreamAvailablePackageDetail(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailRequest], stream *connect.ServerStream[corev1.StreamAvailablePackageDetailResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugins/pkg/pkgutils"
+	kubeappshelm "github.com/vmware-tanzu/kubeapps/pkg/helm"
+	log "k8s.io/klog/v2"
+)
+
+// ChartArchivePath is the path of the endpoint downloading the archive of a
+// chart version, such as
+// /plugins/helm/packages/v1alpha1/chart-archive?namespace=kubeapps&identifier=bitnami/apache&version=10.1.0
+const ChartArchivePath = "/plugins/helm/packages/v1alpha1/chart-archive"
+
+// ServeChartArchive streams the archive of a chart version from its repository
+// to the response, without buffering it, so that large charts can be downloaded
+// through the API. The repository is read with the credentials of the caller.
+func (s *Server) ServeChartArchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	repoNamespace, chartID, chartVersion := query.Get("namespace"), query.Get("identifier"), query.Get("version")
+	log.InfoS("+helm ServeChartArchive", "namespace", repoNamespace, "id", chartID, "version", chartVersion)
+	if repoNamespace == "" || chartVersion == "" {
+		http.Error(w, "The query parameters 'namespace', 'identifier' and 'version' are required", http.StatusBadRequest)
+		return
+	}
+	repoName, chartName, err := pkgutils.SplitPackageIdentifier(chartID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	appRepo, caCertSecret, authSecret, _, err := s.getAppRepoAndRelatedSecrets(r.Context(), r.Header, s.globalPackagingCluster, repoName, repoNamespace)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to fetch app repo %q from namespace %q: %v", repoName, repoNamespace, err), http.StatusForbidden)
+		return
+	}
+	if appRepo.Spec.Type == "oci" {
+		http.Error(w, "Downloading the archive of a chart from an OCI registry is not supported", http.StatusNotImplemented)
+		return
+	}
+
+	cachedChart, err := s.manager.GetChartVersion(repoNamespace, chartID, chartVersion)
+	if err != nil || len(cachedChart.ChartVersions) != 1 || len(cachedChart.ChartVersions[0].URLs) == 0 {
+		http.Error(w, fmt.Sprintf("Unable to find the chart %s (version %s) in the namespace %q", chartID, chartVersion, repoNamespace), http.StatusNotFound)
+		return
+	}
+	tarballURL := chartTarballURL(cachedChart.Repo, cachedChart.ChartVersions[0])
+
+	userAgentString := fmt.Sprintf("%s/%s/%s/%s", UserAgentPrefix, pluginDetail.Name, pluginDetail.Version, version)
+	netClient, err := kubeappshelm.InitNetClient(appRepo, caCertSecret, authSecret, http.Header{"User-Agent": []string{userAgentString}})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to create the client of the repository %q: %v", repoName, err), http.StatusInternalServerError)
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, tarballURL, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid chart tarball URL %q: %v", tarballURL, err), http.StatusInternalServerError)
+		return
+	}
+	res, err := netClient.Do(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to download the chart %s (version %s): %v", chartID, chartVersion, err), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		http.Error(w, fmt.Sprintf("Unable to download the chart %s (version %s): the repository responded with %q", chartID, chartVersion, res.Status), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%s.tgz", chartName, chartVersion)))
+	if res.ContentLength >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(res.ContentLength, 10))
+	}
+	if _, err := io.Copy(w, res.Body); err != nil {
+		log.Errorf("Unable to stream the chart %s (version %s): %v", chartID, chartVersion, err)
+	}
+}
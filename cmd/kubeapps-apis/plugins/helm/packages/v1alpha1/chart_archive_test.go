@@ -0,0 +1,116 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/vmware-tanzu/kubeapps/cmd/apprepository-controller/pkg/apis/apprepository/v1alpha1"
+	"github.com/vmware-tanzu/kubeapps/pkg/chart/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServeChartArchive(t *testing.T) {
+	archive := []byte("not really a gzipped tarball")
+	repo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apache-10.1.0.tgz" {
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := w.Write(archive); err != nil {
+			t.Errorf("%+v", err)
+		}
+	}))
+	defer repo.Close()
+
+	testCases := []struct {
+		name            string
+		query           string
+		chartURL        string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:           "streams the archive of the chart version",
+			query:          "namespace=" + globalPackagingNamespace + "&identifier=bitnami/apache&version=10.1.0",
+			chartURL:       repo.URL + "/apache-10.1.0.tgz",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Content-Type":        "application/gzip",
+				"Content-Disposition": `attachment; filename="apache-10.1.0.tgz"`,
+				"Content-Length":      "28",
+			},
+		},
+		{
+			name:           "fails when the repository does not serve the archive",
+			query:          "namespace=" + globalPackagingNamespace + "&identifier=bitnami/apache&version=10.1.0",
+			chartURL:       repo.URL + "/missing.tgz",
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "fails without a version",
+			query:          "namespace=" + globalPackagingNamespace + "&identifier=bitnami/apache",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "fails for an invalid identifier",
+			query:          "namespace=" + globalPackagingNamespace + "&identifier=apache&version=10.1.0",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, mockDB, cleanup := makeServer(t, true, nil, &v1alpha1.AppRepository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bitnami",
+					Namespace: globalPackagingNamespace,
+				},
+				Spec: v1alpha1.AppRepositorySpec{
+					URL:  repo.URL,
+					Type: "helm",
+				},
+			})
+			defer cleanup()
+
+			if tc.chartURL != "" {
+				chartJSON, err := json.Marshal(&models.Chart{
+					ID:            "bitnami/apache",
+					Name:          "apache",
+					Repo:          &models.AppRepository{Name: "bitnami", Namespace: globalPackagingNamespace, URL: repo.URL},
+					ChartVersions: []models.ChartVersion{{Version: "10.1.0", URLs: []string{tc.chartURL}}},
+				})
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				mockDB.ExpectQuery(regexp.QuoteMeta("SELECT info FROM charts WHERE repo_namespace = $1 AND chart_id = $2")).
+					WithArgs(globalPackagingNamespace, "bitnami/apache").
+					WillReturnRows(sqlmock.NewRows([]string{"info"}).AddRow(string(chartJSON)))
+			}
+
+			req := httptest.NewRequest(http.MethodGet, ChartArchivePath+"?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			server.ServeChartArchive(rec, req)
+
+			if got, want := rec.Code, tc.expectedStatus; got != want {
+				t.Fatalf("got: %d, want: %d, body: %s", got, want, rec.Body.String())
+			}
+			for header, want := range tc.expectedHeaders {
+				if got := rec.Header().Get(header); got != want {
+					t.Errorf("%s: got: %q, want: %q", header, got, want)
+				}
+			}
+			if tc.expectedStatus == http.StatusOK {
+				if got, want := rec.Body.String(), string(archive); got != want {
+					t.Errorf("got: %q, want: %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	pluginsv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/plugins/v1alpha1"
+	corev1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
 	pluginsgrpcv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/plugins/helm/packages/v1alpha1"
 	packagesConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/plugins/helm/packages/v1alpha1/v1alpha1connect"
@@ -36,8 +37,9 @@ func init() {
 //nolint:deadcode
 func RegisterWithGRPCServer(opts pluginsv1alpha1.GRPCPluginRegistrationOptions) (interface{}, error) {
 	svr := NewServer(opts.ConfigGetter, opts.ClustersConfig.KubeappsClusterName, opts.ClustersConfig.GlobalPackagingNamespace, opts.ClientQPS, opts.ClientBurst, opts.PluginConfigPath)
-	opts.Mux.Handle(packagesConnect.NewHelmPackagesServiceHandler(svr))
-	opts.Mux.Handle(packagesConnect.NewHelmRepositoriesServiceHandler(svr))
+	opts.Mux.Handle(packagesConnect.NewHelmPackagesServiceHandler(svr, opts.HandlerOptions...))
+	opts.Mux.Handle(packagesConnect.NewHelmRepositoriesServiceHandler(svr, opts.HandlerOptions...))
+	opts.Mux.HandleFunc(ChartArchivePath, svr.ServeChartArchive)
 	return svr, nil
 }
 
@@ -53,3 +55,16 @@ func RegisterHTTPHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux,
 func GetPluginDetail() *pluginsgrpcv1alpha1.Plugin {
 	return &pluginDetail
 }
+
+// GetPluginCapabilities returns the core.plugins.PluginCapabilities declared by the plugin.
+func GetPluginCapabilities() *pluginsgrpcv1alpha1.PluginCapabilities {
+	return &pluginsgrpcv1alpha1.PluginCapabilities{
+		SupportsOci: true,
+		AuthTypes: []string{
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_BASIC_AUTH.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_BEARER.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_AUTHORIZATION_HEADER.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_DOCKER_CONFIG_JSON.String(),
+		},
+	}
+}
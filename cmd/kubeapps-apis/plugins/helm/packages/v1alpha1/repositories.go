@@ -145,6 +145,46 @@ func (s *Server) newRepo(ctx context.Context, headers http.Header, repo *HelmRep
 	}
 }
 
+// validateNewRepo checks that a successful connection can be made to a
+// repository which is not created yet, with its credentials. Neither the
+// repository nor its secrets are stored.
+func (s *Server) validateNewRepo(ctx context.Context, headers http.Header, repo *HelmRepository) (*corev1.ValidatePackageRepositoryResponse, error) {
+	if repo.url == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Repository url may not be empty"))
+	}
+	if repo.repoType == "" || !slices.Contains(ValidRepoTypes, repo.repoType) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Repository type [%s] not supported", repo.repoType))
+	}
+	typedClient, err := s.clientGetter.Typed(headers, repo.cluster)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// The secrets are only read when referenced, or else built from the
+	// request, without being stored.
+	secret, _, err := handleAuthSecretForCreate(ctx, typedClient, repo.name, repo.tlsConfig, repo.auth)
+	if err != nil {
+		return nil, err
+	}
+	imagePullSecret, _, err := handleImagesPullSecretForCreate(ctx, typedClient, repo.name, repo.customDetail)
+	if err != nil {
+		return nil, err
+	}
+	helmRepoCrd, err := newHelmRepoCrd(repo, secret, imagePullSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.validateRepository(ctx, helmRepoCrd, secret)
+	if err != nil {
+		return nil, err
+	} else if resp.Code >= 400 {
+		log.Infof("Failed validation of the repository [%s]: %+v", repo.name, resp)
+		return &corev1.ValidatePackageRepositoryResponse{Reason: resp.Message}, nil
+	}
+	return &corev1.ValidatePackageRepositoryResponse{Valid: true}, nil
+}
+
 func newHelmRepoCrd(repo *HelmRepository, secret *k8scorev1.Secret, imagePullSecret *k8scorev1.Secret) (*apprepov1alpha1.AppRepository, error) {
 	appRepoCrd := &apprepov1alpha1.AppRepository{
 		ObjectMeta: metav1.ObjectMeta{
@@ -645,6 +685,33 @@ func (s *Server) deleteRepo(ctx context.Context, headers http.Header, cluster st
 	}
 }
 
+// invalidateRepoCache requests a new sync of the AppRepository. Any change to the spec of
+// the AppRepository makes the apprepository-controller launch a new sync job, so it is
+// enough to bump the ResyncRequests counter.
+func (s *Server) invalidateRepoCache(ctx context.Context, headers http.Header, cluster string, repoRef *corev1.PackageRepositoryReference) error {
+	client, err := s.getClient(headers, cluster, repoRef.Context.Namespace)
+	if err != nil {
+		return err
+	}
+
+	key := types.NamespacedName{
+		Name:      repoRef.Identifier,
+		Namespace: repoRef.Context.Namespace,
+	}
+	appRepo := &apprepov1alpha1.AppRepository{}
+	if err = client.Get(ctx, key, appRepo); err != nil {
+		return connecterror.FromK8sError("get", AppRepositoryKind, key.String(), err)
+	}
+
+	log.V(4).Infof("Requesting resync of AppRepository: [%s]", key.String())
+
+	appRepo.Spec.ResyncRequests++
+	if err = client.Update(ctx, appRepo); err != nil {
+		return connecterror.FromK8sError("update", AppRepositoryKind, key.String(), err)
+	}
+	return nil
+}
+
 func (s *Server) GetPackageRepositoryPermissions(ctx context.Context, request *connect.Request[corev1.GetPackageRepositoryPermissionsRequest]) (*connect.Response[corev1.GetPackageRepositoryPermissionsResponse], error) {
 	log.Infof("+helm GetPackageRepositoryPermissions [%v]", request)
 
@@ -685,3 +752,14 @@ func (s *Server) GetPackageRepositoryPermissions(ctx context.Context, request *c
 		Permissions: []*corev1.PackageRepositoriesPermissions{permissions},
 	}), nil
 }
+
+// GetRepositoryTypes returns the types of the repositories which can be added with the 'helm' plugin
+func (s *Server) GetRepositoryTypes(ctx context.Context, request *connect.Request[corev1.GetRepositoryTypesRequest]) (*connect.Response[corev1.GetRepositoryTypesResponse], error) {
+	log.Infof("+helm GetRepositoryTypes [%v]", request)
+	return connect.NewResponse(&corev1.GetRepositoryTypesResponse{
+		RepositoryTypes: []*corev1.PackageRepositoryTypes{{
+			Plugin: GetPluginDetail(),
+			Types:  []string{HelmRepoType, OCIRepoType},
+		}},
+	}), nil
+}
@@ -870,6 +870,106 @@ func TestAddPackageRepository(t *testing.T) {
 	}
 }
 
+func TestValidatePackageRepository(t *testing.T) {
+	// The repository only serves its index to the user "baz".
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, _, ok := r.BasicAuth(); !ok || username != "baz" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, "Unauthorized")
+			return
+		}
+		if r.URL.Path != "/index.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "apiVersion: v1")
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		name             string
+		request          *corev1.AddPackageRepositoryRequest
+		repoUrl          string
+		expectedResponse *corev1.ValidatePackageRepositoryResponse
+		errorCode        connect.Code
+	}{
+		{
+			name:             "valid with the right credentials",
+			request:          addRepoReqBasicAuth("baz", "zot"),
+			repoUrl:          ts.URL,
+			expectedResponse: &corev1.ValidatePackageRepositoryResponse{Valid: true},
+		},
+		{
+			name:    "invalid with the wrong credentials",
+			request: addRepoReqBasicAuth("foo", "zot"),
+			repoUrl: ts.URL,
+			expectedResponse: &corev1.ValidatePackageRepositoryResponse{
+				Reason: "Unauthorized",
+			},
+		},
+		{
+			name:      "returns invalid argument if the url is missing",
+			request:   addRepoReqBasicAuth("baz", "zot"),
+			errorCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:      "returns invalid argument if the type is not supported",
+			request:   addRepoReqWrongType,
+			repoUrl:   ts.URL,
+			errorCode: connect.CodeInvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServerWithSecretsAndRepos(t, nil, nil)
+			s.repoClientGetter = newRepositoryClient
+			tc.request.Url = tc.repoUrl
+
+			response, err := s.ValidatePackageRepository(context.Background(), connect.NewRequest(tc.request))
+
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if tc.errorCode != 0 {
+				if err == nil {
+					t.Fatalf("got: nil, want: error with code %+v", tc.errorCode)
+				}
+				return
+			}
+
+			opts := cmpopts.IgnoreUnexported(corev1.ValidatePackageRepositoryResponse{})
+			if got, want := response.Msg, tc.expectedResponse; !cmp.Equal(got, want, opts) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, opts))
+			}
+
+			// Neither the repository nor its secret are stored.
+			ctrlClient, err := s.clientGetter.ControllerRuntime(http.Header{}, s.kubeappsCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var repos appRepov1alpha1.AppRepositoryList
+			if err := ctrlClient.List(context.Background(), &repos); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(repos.Items), 0; got != want {
+				t.Errorf("got: %d repositories, want: %d", got, want)
+			}
+			typedClient, err := s.clientGetter.Typed(http.Header{}, s.kubeappsCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			secrets, err := typedClient.CoreV1().Secrets(tc.request.Context.Namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(secrets.Items), 0; got != want {
+				t.Errorf("got: %d secrets, want: %d", got, want)
+			}
+		})
+	}
+}
+
 func TestGetPackageRepositorySummaries(t *testing.T) {
 	// some prep
 	indexYAMLBytes, err := os.ReadFile(testYaml("valid-index.yaml"))
@@ -2082,6 +2182,75 @@ func TestDeletePackageRepository(t *testing.T) {
 	}
 }
 
+func TestInvalidatePackageRepositoryCache(t *testing.T) {
+
+	repos := []*appRepov1alpha1.AppRepository{repo1}
+
+	testCases := []struct {
+		name                   string
+		request                *corev1.InvalidatePackageRepositoryCacheRequest
+		expectedErrorCode      connect.Code
+		expectedResyncRequests int
+	}{
+		{
+			name: "no context provided",
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Plugin:     plugin,
+					Identifier: "repo-1",
+				},
+			},
+			expectedErrorCode: connect.CodeInvalidArgument,
+		},
+		{
+			name: "invalidate - success",
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Plugin:     plugin,
+					Context:    &corev1.Context{Namespace: "ns-1", Cluster: KubeappsCluster},
+					Identifier: "repo-1",
+				},
+			},
+			expectedResyncRequests: 1,
+		},
+		{
+			name: "invalidate - not found",
+			request: &corev1.InvalidatePackageRepositoryCacheRequest{
+				PackageRepoRef: &corev1.PackageRepositoryReference{
+					Plugin:     plugin,
+					Context:    &corev1.Context{Namespace: "unknown-1", Cluster: KubeappsCluster},
+					Identifier: "repo-1",
+				},
+			},
+			expectedErrorCode: connect.CodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServerWithSecretsAndRepos(t, nil, repos)
+
+			_, err := s.InvalidatePackageRepositoryCache(context.Background(), connect.NewRequest(tc.request))
+
+			// checks
+			if got, want := connect.CodeOf(err), tc.expectedErrorCode; err != nil && got != want {
+				t.Fatalf("got error: %d, want: %d, err: %+v", got, want, err)
+			} else if got != 0 {
+				return
+			}
+
+			repoRef := tc.request.GetPackageRepoRef()
+			appRepo, _, _, err := s.getPkgRepository(context.Background(), http.Header{}, repoRef.Context.Cluster, repoRef.Context.Namespace, repoRef.Identifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := appRepo.Spec.ResyncRequests, tc.expectedResyncRequests; got != want {
+				t.Errorf("got ResyncRequests: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
 func TestGetPackageRepositoryPermissions(t *testing.T) {
 
 	testCases := []struct {
@@ -2246,6 +2415,32 @@ func TestGetPackageRepositoryPermissions(t *testing.T) {
 	}
 }
 
+func TestGetRepositoryTypes(t *testing.T) {
+	s := newServerWithAppRepoReactors(nil, nil, nil, nil, nil)
+
+	response, err := s.GetRepositoryTypes(context.Background(), connect.NewRequest(&corev1.GetRepositoryTypesRequest{}))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	expectedResponse := &corev1.GetRepositoryTypesResponse{
+		RepositoryTypes: []*corev1.PackageRepositoryTypes{
+			{
+				Plugin: GetPluginDetail(),
+				Types:  []string{"helm", "oci"},
+			},
+		},
+	}
+	opts := cmpopts.IgnoreUnexported(
+		plugins.Plugin{},
+		corev1.GetRepositoryTypesResponse{},
+		corev1.PackageRepositoryTypes{},
+	)
+	if got, want := response.Msg, expectedResponse; !cmp.Equal(want, got, opts) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, opts))
+	}
+}
+
 func checkRepoSecrets(s *Server, t *testing.T, userManagedSecrets bool,
 	actualRepo *appRepov1alpha1.AppRepository, expectedRepo *appRepov1alpha1.AppRepository,
 	expectedAuthSecret *apiv1.Secret, expectedDockerSecret *apiv1.Secret,
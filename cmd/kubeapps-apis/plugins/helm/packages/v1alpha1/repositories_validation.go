@@ -34,17 +34,7 @@ const OCIDistributionAPIProto = "https"
 
 // ValidateRepository Checks that successful connection can be made to repository
 func (s *Server) ValidateRepository(ctx context.Context, appRepo *apprepov1alpha1.AppRepository, secret *corev1.Secret) error {
-	if len(appRepo.Spec.DockerRegistrySecrets) > 0 && appRepo.Namespace == s.GetGlobalPackagingNamespace() {
-		// TODO(mnelson): we may also want to validate that any docker registry secrets listed
-		// already exist in the namespace.
-		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("The docker registry secrets cannot be set for app repositories available in all namespaces"))
-	}
-
-	validator, err := s.getValidator(appRepo, secret)
-	if err != nil {
-		return err
-	}
-	resp, err := validator.Validate(ctx)
+	resp, err := s.validateRepository(ctx, appRepo, secret)
 	if err != nil {
 		return err
 	} else if resp.Code >= 400 {
@@ -55,6 +45,22 @@ func (s *Server) ValidateRepository(ctx context.Context, appRepo *apprepov1alpha
 	}
 }
 
+// validateRepository returns the response of the validation of the repository,
+// whose code is 400 or more when no successful connection can be made to it.
+func (s *Server) validateRepository(ctx context.Context, appRepo *apprepov1alpha1.AppRepository, secret *corev1.Secret) (*ValidationResponse, error) {
+	if len(appRepo.Spec.DockerRegistrySecrets) > 0 && appRepo.Namespace == s.GetGlobalPackagingNamespace() {
+		// TODO(mnelson): we may also want to validate that any docker registry secrets listed
+		// already exist in the namespace.
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("The docker registry secrets cannot be set for app repositories available in all namespaces"))
+	}
+
+	validator, err := s.getValidator(appRepo, secret)
+	if err != nil {
+		return nil, err
+	}
+	return validator.Validate(ctx)
+}
+
 // getValidator return appropriate RepositoryValidator interface for OCI and
 // non-OCI Repos
 func (s *Server) getValidator(appRepo *apprepov1alpha1.AppRepository, secret *corev1.Secret) (RepositoryValidator, error) {
@@ -1083,28 +1083,86 @@ func (s *Server) GetInstalledPackageResourceRefs(ctx context.Context, request *c
 	}
 }
 
+// StreamInstalledPackageResourceRefs streams the references for the Kubernetes
+// resources created by an installed package, as they are decoded from the
+// manifest of its release.
+func (s *Server) StreamInstalledPackageResourceRefs(ctx context.Context, request *connect.Request[corev1.GetInstalledPackageResourceRefsRequest], stream *connect.ServerStream[corev1.StreamInstalledPackageResourceRefsResponse]) error {
+	pkgRef := request.Msg.GetInstalledPackageRef()
+	identifier := pkgRef.GetIdentifier()
+	log.InfoS("+helm StreamInstalledPackageResourceRefs", "cluster", pkgRef.GetContext().GetCluster(), "namespace", pkgRef.GetContext().GetNamespace(), "id", identifier)
+
+	fn := func(headers http.Header, namespace string) (*action.Configuration, error) {
+		actionGetter, err := s.actionConfigGetter(headers, pkgRef.GetContext())
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to create Helm action config: %w", err))
+		}
+		return actionGetter, nil
+	}
+
+	return resourcerefs.StreamInstalledPackageResourceRefs(
+		request.Header(), types.NamespacedName{Name: identifier, Namespace: pkgRef.Context.Namespace}, fn,
+		func(refs []*corev1.ResourceRef) error {
+			return stream.Send(&corev1.StreamInstalledPackageResourceRefsResponse{
+				Context:      pkgRef.GetContext(),
+				ResourceRefs: refs,
+			})
+		})
+}
+
 func (s *Server) AddPackageRepository(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.AddPackageRepositoryResponse], error) {
 
 	if request == nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request provided"))
 	}
-	if request.Msg.Name == "" {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No package repository Name provided"))
+	log.Infof("+helm AddPackageRepository '%s'", request.Msg.Name)
+
+	helmRepo, err := s.helmRepositoryFromRequest(request.Msg)
+	if err != nil {
+		return nil, err
 	}
+	if repoRef, err := s.newRepo(ctx, request.Header(), helmRepo); err != nil {
+		return nil, err
+	} else {
+		return connect.NewResponse(&corev1.AddPackageRepositoryResponse{PackageRepoRef: repoRef}), nil
+	}
+}
+
+// ValidatePackageRepository checks that the repository of the request can be
+// reached with its credentials, without adding it.
+func (s *Server) ValidatePackageRepository(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.ValidatePackageRepositoryResponse], error) {
+	if request == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request provided"))
+	}
+	log.Infof("+helm ValidatePackageRepository '%s'", request.Msg.Name)
 
-	repoName := request.Msg.Name
-	log.Infof("+helm AddPackageRepository '%s'", repoName)
+	helmRepo, err := s.helmRepositoryFromRequest(request.Msg)
+	if err != nil {
+		return nil, err
+	}
+	if response, err := s.validateNewRepo(ctx, request.Header(), helmRepo); err != nil {
+		return nil, err
+	} else {
+		return connect.NewResponse(response), nil
+	}
+}
 
-	cluster := request.Msg.GetContext().GetCluster()
+// helmRepositoryFromRequest returns the repository to be added by the request.
+func (s *Server) helmRepositoryFromRequest(request *corev1.AddPackageRepositoryRequest) (*HelmRepository, error) {
+	if request.Name == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No package repository Name provided"))
+	}
+	repoName := request.Name
+
+	cluster := request.GetContext().GetCluster()
 	if cluster == "" {
 		cluster = s.globalPackagingCluster
 	}
 
-	namespace := request.Msg.GetContext().GetNamespace()
+	namespace := request.GetContext().GetNamespace()
 	if namespace == "" {
 		namespace = s.GetGlobalPackagingNamespace()
 	}
-	if request.Msg.GetNamespaceScoped() != (namespace != s.GetGlobalPackagingNamespace()) {
+	if request.GetNamespaceScoped() != (namespace != s.GetGlobalPackagingNamespace()) {
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Namespace Scope is inconsistent with the provided Namespace"))
 	}
 	name := types.NamespacedName{
@@ -1114,10 +1172,10 @@ func (s *Server) AddPackageRepository(ctx context.Context, request *connect.Requ
 
 	// Get Helm-specific values
 	var customDetail *helmv1.HelmPackageRepositoryCustomDetail
-	if request.Msg.CustomDetail != nil {
+	if request.CustomDetail != nil {
 		customDetail = &helmv1.HelmPackageRepositoryCustomDetail{}
-		if err := request.Msg.CustomDetail.UnmarshalTo(customDetail); err != nil {
-			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("The custom details could not be parsed: [%v]", request.Msg.CustomDetail))
+		if err := request.CustomDetail.UnmarshalTo(customDetail); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("The custom details could not be parsed: [%v]", request.CustomDetail))
 		}
 		log.Infof("+helm customDetail [%v]", customDetail)
 	}
@@ -1125,19 +1183,15 @@ func (s *Server) AddPackageRepository(ctx context.Context, request *connect.Requ
 	helmRepo := &HelmRepository{
 		cluster:      cluster,
 		name:         name,
-		url:          request.Msg.GetUrl(),
-		repoType:     request.Msg.GetType(),
-		description:  request.Msg.GetDescription(),
-		interval:     request.Msg.GetInterval(),
-		tlsConfig:    request.Msg.GetTlsConfig(),
-		auth:         request.Msg.GetAuth(),
+		url:          request.GetUrl(),
+		repoType:     request.GetType(),
+		description:  request.GetDescription(),
+		interval:     request.GetInterval(),
+		tlsConfig:    request.GetTlsConfig(),
+		auth:         request.GetAuth(),
 		customDetail: customDetail,
 	}
-	if repoRef, err := s.newRepo(ctx, request.Header(), helmRepo); err != nil {
-		return nil, err
-	} else {
-		return connect.NewResponse(&corev1.AddPackageRepositoryResponse{PackageRepoRef: repoRef}), nil
-	}
+	return helmRepo, nil
 }
 
 func (s *Server) getClient(headers http.Header, cluster string, namespace string) (ctrlclient.Client, error) {
@@ -1269,6 +1323,25 @@ func (s *Server) DeletePackageRepository(ctx context.Context, request *connect.R
 	}
 }
 
+func (s *Server) InvalidatePackageRepositoryCache(ctx context.Context, request *connect.Request[corev1.InvalidatePackageRepositoryCacheRequest]) (*connect.Response[corev1.InvalidatePackageRepositoryCacheResponse], error) {
+	log.Infof("+helm InvalidatePackageRepositoryCache [%v]", request)
+
+	if request == nil || request.Msg.PackageRepoRef == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No request PackageRepoRef provided"))
+	}
+	repoRef := request.Msg.GetPackageRepoRef()
+	if repoRef.GetContext() == nil || repoRef.GetContext().GetNamespace() == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("No valid context provided"))
+	}
+	cluster := repoRef.GetContext().GetCluster()
+
+	if err := s.invalidateRepoCache(ctx, request.Header(), cluster, repoRef); err != nil {
+		return nil, err
+	} else {
+		return connect.NewResponse(&corev1.InvalidatePackageRepositoryCacheResponse{}), nil
+	}
+}
+
 func (s *Server) GetAvailablePackageMetadatas(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageMetadatasRequest]) (*connect.Response[corev1.GetAvailablePackageMetadatasResponse], error) {
 	chartID := request.Msg.GetAvailablePackageRef().GetIdentifier()
 	repoNamespace := request.Msg.GetAvailablePackageRef().GetContext().GetNamespace()
@@ -1334,3 +1407,15 @@ func (s *Server) GetAvailablePackageMetadatas(ctx context.Context, request *conn
 		PackageMetadata:     package_metadatas,
 	}), nil
 }
+
+// GetAvailablePackageDetailsBatch is not implemented by the plugin, since the
+// batches are fanned out as individual GetAvailablePackageDetail requests by the core.
+func (s *Server) GetAvailablePackageDetailsBatch(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailsBatchRequest]) (*connect.Response[corev1.GetAvailablePackageDetailsBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
+// StreamAvailablePackageDetail is not implemented by the plugin, since the
+// details are streamed by the core from the GetAvailablePackageDetail responses.
+func (s *Server) StreamAvailablePackageDetail(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailRequest], stream *connect.ServerStream[corev1.StreamAvailablePackageDetailResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
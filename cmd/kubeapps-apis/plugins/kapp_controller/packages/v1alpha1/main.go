@@ -10,6 +10,7 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	pluginsv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/plugins/v1alpha1"
+	corev1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
 	pluginsgrpcv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/plugins/kapp_controller/packages/v1alpha1"
 	packagesConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/plugins/kapp_controller/packages/v1alpha1/v1alpha1connect"
@@ -32,8 +33,8 @@ func init() {
 //nolint:deadcode
 func RegisterWithGRPCServer(opts pluginsv1alpha1.GRPCPluginRegistrationOptions) (interface{}, error) {
 	svr := NewServer(opts.ConfigGetter, opts.ClientQPS, opts.ClientBurst, opts.ClustersConfig.KubeappsClusterName, opts.PluginConfigPath)
-	opts.Mux.Handle(packagesConnect.NewKappControllerPackagesServiceHandler(svr))
-	opts.Mux.Handle(packagesConnect.NewKappControllerRepositoriesServiceHandler(svr))
+	opts.Mux.Handle(packagesConnect.NewKappControllerPackagesServiceHandler(svr, opts.HandlerOptions...))
+	opts.Mux.Handle(packagesConnect.NewKappControllerRepositoriesServiceHandler(svr, opts.HandlerOptions...))
 	return svr, nil
 }
 
@@ -49,3 +50,16 @@ func RegisterHTTPHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux,
 func GetPluginDetail() *pluginsgrpcv1alpha1.Plugin {
 	return &pluginDetail
 }
+
+// GetPluginCapabilities returns the core.plugins.PluginCapabilities declared by the plugin.
+func GetPluginCapabilities() *pluginsgrpcv1alpha1.PluginCapabilities {
+	return &pluginsgrpcv1alpha1.PluginCapabilities{
+		SupportsOci: true,
+		AuthTypes: []string{
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_BASIC_AUTH.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_BEARER.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_DOCKER_CONFIG_JSON.String(),
+			corev1.PackageRepositoryAuth_PACKAGE_REPOSITORY_AUTH_TYPE_SSH.String(),
+		},
+	}
+}
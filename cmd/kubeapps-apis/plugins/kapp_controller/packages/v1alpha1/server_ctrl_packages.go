@@ -883,3 +883,22 @@ func (s *Server) GetInstalledPackageResourceRefs(ctx context.Context, request *c
 func (s *Server) GetAvailablePackageMetadatas(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageMetadatasRequest]) (*connect.Response[corev1.GetAvailablePackageMetadatasResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
 }
+
+// GetAvailablePackageDetailsBatch is not implemented by the plugin, since the
+// batches are fanned out as individual GetAvailablePackageDetail requests by the core.
+func (s *Server) GetAvailablePackageDetailsBatch(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailsBatchRequest]) (*connect.Response[corev1.GetAvailablePackageDetailsBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
+// StreamAvailablePackageDetail is not implemented by the plugin, since the
+// details are streamed by the core from the GetAvailablePackageDetail responses.
+func (s *Server) StreamAvailablePackageDetail(ctx context.Context, request *connect.Request[corev1.GetAvailablePackageDetailRequest], stream *connect.ServerStream[corev1.StreamAvailablePackageDetailResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
+// StreamInstalledPackageResourceRefs is not implemented by the plugin, since the
+// resource refs are streamed by the core from the GetInstalledPackageResourceRefs
+// responses.
+func (s *Server) StreamInstalledPackageResourceRefs(ctx context.Context, request *connect.Request[corev1.GetInstalledPackageResourceRefsRequest], stream *connect.ServerStream[corev1.StreamInstalledPackageResourceRefsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
@@ -341,6 +341,18 @@ func (s *Server) DeletePackageRepository(ctx context.Context, request *connect.R
 	return connect.NewResponse(response), nil
 }
 
+// InvalidatePackageRepositoryCache is not supported by the 'kapp_controller' plugin, as kapp-controller
+// only re-fetches a PackageRepository according to its own sync period.
+func (s *Server) InvalidatePackageRepositoryCache(ctx context.Context, request *connect.Request[corev1.InvalidatePackageRepositoryCacheRequest]) (*connect.Response[corev1.InvalidatePackageRepositoryCacheResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
+// ValidatePackageRepository is not supported by the 'kapp_controller' plugin, as the
+// package repository bundles are only fetched by kapp-controller itself.
+func (s *Server) ValidatePackageRepository(ctx context.Context, request *connect.Request[corev1.AddPackageRepositoryRequest]) (*connect.Response[corev1.ValidatePackageRepositoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("Unimplemented"))
+}
+
 // GetPackageRepositoryPermissions provides permissions available to manage package repository by the 'kapp_controller' plugin
 func (s *Server) GetPackageRepositoryPermissions(ctx context.Context, request *connect.Request[corev1.GetPackageRepositoryPermissionsRequest]) (*connect.Response[corev1.GetPackageRepositoryPermissionsResponse], error) {
 	log.Infof("+kapp-controller GetPackageRepositoryPermissions [%v]", request)
@@ -382,3 +394,15 @@ func (s *Server) GetPackageRepositoryPermissions(ctx context.Context, request *c
 		Permissions: []*corev1.PackageRepositoriesPermissions{permissions},
 	}), nil
 }
+
+// GetRepositoryTypes returns the types of the repositories which can be added with the 'kapp_controller' plugin.
+// Inline repositories are not included since they cannot be created through the plugin.
+func (s *Server) GetRepositoryTypes(ctx context.Context, request *connect.Request[corev1.GetRepositoryTypesRequest]) (*connect.Response[corev1.GetRepositoryTypesResponse], error) {
+	log.Infof("+kapp-controller GetRepositoryTypes [%v]", request)
+	return connect.NewResponse(&corev1.GetRepositoryTypesResponse{
+		RepositoryTypes: []*corev1.PackageRepositoryTypes{{
+			Plugin: GetPluginDetail(),
+			Types:  []string{typeImgPkgBundle, typeImage, typeGIT, typeHTTP},
+		}},
+	}), nil
+}
@@ -33,10 +33,27 @@ type yamlResource struct {
 	Items      []yamlResource `json:"items"`
 }
 
+// StreamBatchSize is the number of resource refs sent in each response of the
+// streamed resource refs.
+const StreamBatchSize = 50
+
 // resourceRefsFromManifest returns the resource refs for a given yaml manifest.
 func ResourceRefsFromManifest(m, pkgNamespace string) ([]*corev1.ResourceRef, error) {
-	decoder := yaml.NewYAMLToJSONDecoder(strings.NewReader(m))
 	refs := []*corev1.ResourceRef{}
+	err := VisitResourceRefsFromManifest(m, pkgNamespace, func(ref *corev1.ResourceRef) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// VisitResourceRefsFromManifest calls visit with each resource ref of a given
+// yaml manifest, as the manifest is decoded, stopping at the first error.
+func VisitResourceRefsFromManifest(m, pkgNamespace string, visit func(*corev1.ResourceRef) error) error {
+	decoder := yaml.NewYAMLToJSONDecoder(strings.NewReader(m))
 	doc := yamlResource{}
 	for {
 		err := decoder.Decode(&doc)
@@ -44,7 +61,7 @@ func ResourceRefsFromManifest(m, pkgNamespace string) ([]*corev1.ResourceRef, er
 			if goerrs.Is(err, io.EOF) {
 				break
 			}
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to decode yaml manifest: %w", err))
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to decode yaml manifest: %w", err))
 		}
 		if doc.Kind == "" {
 			continue
@@ -55,12 +72,14 @@ func ResourceRefsFromManifest(m, pkgNamespace string) ([]*corev1.ResourceRef, er
 				if namespace == "" {
 					namespace = pkgNamespace
 				}
-				refs = append(refs, &corev1.ResourceRef{
+				if err := visit(&corev1.ResourceRef{
 					ApiVersion: i.APIVersion,
 					Kind:       i.Kind,
 					Name:       i.Metadata.Name,
 					Namespace:  namespace,
-				})
+				}); err != nil {
+					return err
+				}
 			}
 			continue
 		}
@@ -78,15 +97,17 @@ func ResourceRefsFromManifest(m, pkgNamespace string) ([]*corev1.ResourceRef, er
 		if namespace == "" {
 			namespace = pkgNamespace
 		}
-		refs = append(refs, &corev1.ResourceRef{
+		if err := visit(&corev1.ResourceRef{
 			ApiVersion: doc.APIVersion,
 			Kind:       doc.Kind,
 			Name:       doc.Metadata.Name,
 			Namespace:  namespace,
-		})
+		}); err != nil {
+			return err
+		}
 	}
 
-	return refs, nil
+	return nil
 }
 
 func GetInstalledPackageResourceRefs(
@@ -96,9 +117,66 @@ func GetInstalledPackageResourceRefs(
 	log.InfoS("+resourcerefs GetInstalledPackageResourceRefs", "helmReleaseName", helmReleaseName)
 	namespace := helmReleaseName.Namespace
 
+	manifest, err := getReleaseManifest(headers, helmReleaseName, actionConfigGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := ResourceRefsFromManifest(manifest, namespace)
+	if err != nil {
+		return nil, err
+	} else {
+		return refs, nil
+	}
+}
+
+// StreamInstalledPackageResourceRefs calls send with the resource refs of the
+// Helm release, in batches of StreamBatchSize refs, as they are decoded from
+// the manifest of the release. send is called at least once, with no refs for
+// a release without resources.
+func StreamInstalledPackageResourceRefs(
+	headers http.Header,
+	helmReleaseName types.NamespacedName,
+	actionConfigGetter helm.HelmActionConfigGetterFunc,
+	send func([]*corev1.ResourceRef) error) error {
+	log.InfoS("+resourcerefs StreamInstalledPackageResourceRefs", "helmReleaseName", helmReleaseName)
+
+	manifest, err := getReleaseManifest(headers, helmReleaseName, actionConfigGetter)
+	if err != nil {
+		return err
+	}
+
+	batch := []*corev1.ResourceRef{}
+	sent := false
+	err = VisitResourceRefsFromManifest(manifest, helmReleaseName.Namespace, func(ref *corev1.ResourceRef) error {
+		batch = append(batch, ref)
+		if len(batch) < StreamBatchSize {
+			return nil
+		}
+		sent = true
+		err := send(batch)
+		batch = []*corev1.ResourceRef{}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(batch) > 0 || !sent {
+		return send(batch)
+	}
+	return nil
+}
+
+// getReleaseManifest returns the manifest of the Helm release.
+func getReleaseManifest(
+	headers http.Header,
+	helmReleaseName types.NamespacedName,
+	actionConfigGetter helm.HelmActionConfigGetterFunc) (string, error) {
+	namespace := helmReleaseName.Namespace
+
 	actionConfig, err := actionConfigGetter(headers, namespace)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to create Helm action config: %w", err))
+		return "", connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to create Helm action config: %w", err))
 	}
 
 	// Grab the released manifest from the release.
@@ -113,15 +191,9 @@ func GetInstalledPackageResourceRefs(
 	if err != nil {
 		if err == driver.ErrReleaseNotFound {
 			log.ErrorS(err, "resourcerefs GetInstalledPackageResourceRefs")
-			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("Unable to find Helm release %q in namespace %q: %w", helmReleaseName, namespace, err))
+			return "", connect.NewError(connect.CodeNotFound, fmt.Errorf("Unable to find Helm release %q in namespace %q: %w", helmReleaseName, namespace, err))
 		}
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to run Helm get action: %w", err))
-	}
-
-	refs, err := ResourceRefsFromManifest(release.Manifest, namespace)
-	if err != nil {
-		return nil, err
-	} else {
-		return refs, nil
+		return "", connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to run Helm get action: %w", err))
 	}
+	return release.Manifest, nil
 }
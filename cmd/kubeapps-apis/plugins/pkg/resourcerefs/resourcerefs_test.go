@@ -4,6 +4,10 @@
 package resourcerefs
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/bufbuild/connect-go"
@@ -11,6 +15,12 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
 	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/plugins/pkg/resourcerefs/resourcerefstest"
+	"helm.sh/helm/v3/pkg/action"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestGetInstalledPackageResourceRefs(t *testing.T) {
@@ -41,3 +51,71 @@ func TestGetInstalledPackageResourceRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamInstalledPackageResourceRefs(t *testing.T) {
+	configMaps := func(count int) string {
+		docs := []string{}
+		for i := 0; i < count; i++ {
+			docs = append(docs, fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-%d\n", i))
+		}
+		return strings.Join(docs, "---\n")
+	}
+
+	testCases := []struct {
+		name               string
+		manifest           string
+		expectedBatchSizes []int
+	}{
+		{
+			name:               "sends the resource refs in batches",
+			manifest:           configMaps(2*StreamBatchSize + 1),
+			expectedBatchSizes: []int{StreamBatchSize, StreamBatchSize, 1},
+		},
+		{
+			name:               "sends a full last batch once",
+			manifest:           configMaps(StreamBatchSize),
+			expectedBatchSizes: []int{StreamBatchSize},
+		},
+		{
+			name:               "sends a single batch without resource refs",
+			expectedBatchSizes: []int{0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releases := storage.Init(driver.NewMemory())
+			if err := releases.Create(&release.Release{Name: "my-release", Namespace: "default", Version: 1, Info: &release.Info{Status: release.StatusDeployed}, Manifest: tc.manifest}); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			actionConfigGetter := func(headers http.Header, namespace string) (*action.Configuration, error) {
+				return &action.Configuration{
+					Releases:   releases,
+					KubeClient: &kubefake.PrintingKubeClient{Out: io.Discard},
+				}, nil
+			}
+
+			batchSizes := []int{}
+			names := []string{}
+			err := StreamInstalledPackageResourceRefs(http.Header{}, types.NamespacedName{Name: "my-release", Namespace: "default"}, actionConfigGetter, func(refs []*corev1.ResourceRef) error {
+				batchSizes = append(batchSizes, len(refs))
+				for _, ref := range refs {
+					names = append(names, ref.GetName())
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			if got, want := batchSizes, tc.expectedBatchSizes; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			for i, name := range names {
+				if got, want := name, fmt.Sprintf("config-%d", i); got != want {
+					t.Errorf("got: %q, want: %q", got, want)
+				}
+			}
+		})
+	}
+}
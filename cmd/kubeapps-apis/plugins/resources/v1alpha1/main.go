@@ -35,7 +35,7 @@ func RegisterWithGRPCServer(opts pluginsv1alpha1.GRPCPluginRegistrationOptions)
 	if err != nil {
 		return nil, err
 	}
-	opts.Mux.Handle(resourcesConnect.NewResourcesServiceHandler(svr))
+	opts.Mux.Handle(resourcesConnect.NewResourcesServiceHandler(svr, opts.HandlerOptions...))
 	return svr, nil
 }
 
@@ -53,3 +53,12 @@ func RegisterHTTPHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux,
 func GetPluginDetail() *pluginsgrpcv1alpha1.Plugin {
 	return &pluginDetail
 }
+
+// GetPluginCapabilities returns the core.plugins.PluginCapabilities declared by the plugin.
+//
+//nolint:deadcode
+func GetPluginCapabilities() *pluginsgrpcv1alpha1.PluginCapabilities {
+	return &pluginsgrpcv1alpha1.PluginCapabilities{
+		SupportsWatch: true,
+	}
+}
@@ -52,7 +52,10 @@ type Server struct {
 	corePackagesClientGetter func() (pkgsConnectV1alpha1.PackagesServiceClient, error)
 
 	// We keep a restmapper to cache discovery of REST mappings from GVK->GVR.
-	restMapper meta.RESTMapper
+	// It is replaced, using newRESTMapper, when the APIs are rediscovered.
+	restMapperMu  sync.RWMutex
+	restMapper    meta.RESTMapper
+	newRESTMapper func() (meta.RESTMapper, error)
 
 	// kindToResource is a function to convert a GVK to GVR with
 	// namespace/cluster scope information. Can be replaced in tests with a
@@ -101,7 +104,10 @@ func createRESTMapper(clientQPS float32, clientBurst int) (meta.RESTMapper, erro
 }
 
 func NewServer(configGetter core.KubernetesConfigGetter, clientQPS float32, clientBurst int, pluginConfigPath string, clustersConfig kube.ClustersConfig, localPort int) (*Server, error) {
-	mapper, err := createRESTMapper(clientQPS, clientBurst)
+	newRESTMapper := func() (meta.RESTMapper, error) {
+		return createRESTMapper(clientQPS, clientBurst)
+	}
+	mapper, err := newRESTMapper()
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +147,8 @@ func NewServer(configGetter core.KubernetesConfigGetter, clientQPS float32, clie
 		corePackagesClientGetter: func() (pkgsConnectV1alpha1.PackagesServiceClient, error) {
 			return pkgsConnectV1alpha1.NewPackagesServiceClient(http.DefaultClient, fmt.Sprintf("http://localhost:%d/", localPort)), nil
 		},
-		restMapper: mapper,
+		restMapper:    mapper,
+		newRESTMapper: newRESTMapper,
 		kindToResource: func(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (schema.GroupVersionResource, meta.RESTScopeName, error) {
 			mapping, err := mapper.RESTMapping(gvk.GroupKind())
 			if err != nil {
@@ -155,6 +162,27 @@ func NewServer(configGetter core.KubernetesConfigGetter, clientQPS float32, clie
 	}, nil
 }
 
+// RediscoverAPIs replaces the REST mapper with one discovering the APIs of the
+// API server again, so that the resources of the CRDs installed since the
+// startup, such as those of Flux, can be mapped.
+func (s *Server) RediscoverAPIs(ctx context.Context) error {
+	mapper, err := s.newRESTMapper()
+	if err != nil {
+		return fmt.Errorf("unable to rediscover the APIs: %w", err)
+	}
+	s.restMapperMu.Lock()
+	defer s.restMapperMu.Unlock()
+	s.restMapper = mapper
+	return nil
+}
+
+// getRESTMapper returns the REST mapper of the APIs last discovered.
+func (s *Server) getRESTMapper() meta.RESTMapper {
+	s.restMapperMu.RLock()
+	defer s.restMapperMu.RUnlock()
+	return s.restMapper
+}
+
 func newClientGetter(configGetter core.KubernetesConfigGetter, useServiceAccount bool, clustersConfig kube.ClustersConfig) (clientgetter.ClientProviderInterface, error) {
 	customConfigGetter := func(headers http.Header, cluster string) (*rest.Config, error) {
 		if useServiceAccount {
@@ -190,9 +218,9 @@ func setupRestConfigForCluster(restConfig *rest.Config, cluster string, clusters
 	if cluster == clustersConfig.KubeappsClusterName {
 		log.Infof("Kubeapps cluster, should already have correct token for service acc: %q", restConfig.BearerTokenFile)
 	} else {
-		additionalCluster, ok := clustersConfig.Clusters[cluster]
-		if !ok {
-			return connect.NewError(connect.CodeInternal, fmt.Errorf("Cluster %q has no configuration", cluster))
+		additionalCluster, err := clustersConfig.Lookup(cluster)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, err)
 		}
 		// We *always* overwrite the token, even if it was configured empty.
 		restConfig.BearerToken = additionalCluster.ServiceToken
@@ -265,7 +293,7 @@ func (s *Server) GetResources(ctx context.Context, r *connect.Request[v1alpha1.G
 
 		// We need to get or watch a different endpoint depending on
 		// the scope of the resource (namespaced or not).
-		gvr, scopeName, err := s.kindToResource(s.restMapper, gvk)
+		gvr, scopeName, err := s.kindToResource(s.getRESTMapper(), gvk)
 		if err != nil {
 			return connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to map group-kind %v to resource: %w", gvk.GroupKind(), err))
 		}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/bufbuild/connect-go"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	typfake "k8s.io/client-go/kubernetes/fake"
@@ -607,3 +609,41 @@ func TestSetupConfigForCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestRediscoverAPIs(t *testing.T) {
+	initial := meta.NewDefaultRESTMapper(nil)
+	rediscovered := meta.NewDefaultRESTMapper(nil)
+
+	testCases := []struct {
+		name           string
+		newRESTMapper  func() (meta.RESTMapper, error)
+		expectedErr    bool
+		expectedMapper meta.RESTMapper
+	}{
+		{
+			name:           "replaces the REST mapper",
+			newRESTMapper:  func() (meta.RESTMapper, error) { return rediscovered, nil },
+			expectedMapper: rediscovered,
+		},
+		{
+			name:           "keeps the REST mapper when the discovery fails",
+			newRESTMapper:  func() (meta.RESTMapper, error) { return nil, fmt.Errorf("connection refused") },
+			expectedErr:    true,
+			expectedMapper: initial,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{restMapper: initial, newRESTMapper: tc.newRESTMapper}
+
+			err := s.RediscoverAPIs(context.Background())
+			if got, want := err != nil, tc.expectedErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if s.getRESTMapper() != tc.expectedMapper {
+				t.Errorf("got an unexpected REST mapper")
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// adminPathPrefix is the path under which the admin endpoints are served.
+const adminPathPrefix = "/admin/"
+
+// newAdminHandler returns a handler serving the given admin endpoints, which
+// requires the admin token as a bearer token. The admin endpoints are disabled
+// altogether when no token is configured.
+func newAdminHandler(token string, endpoints map[string]http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	for path, handler := range endpoints {
+		mux.Handle(adminPathPrefix+path, handler)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if !hasBearerToken(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// hasBearerToken returns whether the request has the given bearer token, which
+// is compared in constant time.
+func hasBearerToken(r *http.Request, token string) bool {
+	bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bufbuild/connect-go"
+)
+
+// apiVersionHeader is the header in which the clients, such as the dashboard,
+// send the version of the API they were built against, such as "2.9.0".
+const apiVersionHeader = "X-Kubeapps-Api-Version"
+
+// apiVersionRange is a connect interceptor failing the requests of the clients
+// built against an API version outside of the range supported by the server
+// with a FailedPrecondition error telling which of the client or the server to
+// upgrade, rather than with the errors of the fields missing deep in a plugin.
+// The requests without a version, such as those of the scripts using the REST
+// gateway, are let through.
+type apiVersionRange struct {
+	// min and max are the bounds of the supported versions, both included,
+	// either of them being nil when unbounded.
+	min, max *semver.Version
+}
+
+// newAPIVersionRange parses the bounds of the supported API versions, returning
+// nil when both are empty.
+func newAPIVersionRange(minVersion, maxVersion string) (*apiVersionRange, error) {
+	if minVersion == "" && maxVersion == "" {
+		return nil, nil
+	}
+	r := &apiVersionRange{}
+	var err error
+	if minVersion != "" {
+		if r.min, err = semver.NewVersion(minVersion); err != nil {
+			return nil, fmt.Errorf("invalid minimum API version %q: %w", minVersion, err)
+		}
+	}
+	if maxVersion != "" {
+		if r.max, err = semver.NewVersion(maxVersion); err != nil {
+			return nil, fmt.Errorf("invalid maximum API version %q: %w", maxVersion, err)
+		}
+	}
+	if r.min != nil && r.max != nil && r.max.LessThan(r.min) {
+		return nil, fmt.Errorf("invalid API version range, the maximum %q is lower than the minimum %q", maxVersion, minVersion)
+	}
+	return r, nil
+}
+
+// String returns the supported range, such as ">= 2.7.0, <= 2.9.0".
+func (r *apiVersionRange) String() string {
+	switch {
+	case r.min != nil && r.max != nil:
+		return fmt.Sprintf(">= %s, <= %s", r.min, r.max)
+	case r.min != nil:
+		return fmt.Sprintf(">= %s", r.min)
+	default:
+		return fmt.Sprintf("<= %s", r.max)
+	}
+}
+
+// check returns an error if the API version of the request headers is invalid
+// or not supported.
+func (r *apiVersionRange) check(header http.Header) error {
+	value := header.Get(apiVersionHeader)
+	if value == "" {
+		return nil
+	}
+	version, err := semver.NewVersion(value)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Invalid API version %q in the %s header: %w", value, apiVersionHeader, err))
+	}
+	if r.min != nil && version.LessThan(r.min) {
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("The API version %s of the client is no longer supported by this server, which supports the versions %s. Please upgrade the client, such as by reloading the dashboard", version, r))
+	}
+	if r.max != nil && version.GreaterThan(r.max) {
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("The API version %s of the client is not yet supported by this server, which supports the versions %s. Please upgrade the server", version, r))
+	}
+	return nil
+}
+
+func (r *apiVersionRange) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := r.check(req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (r *apiVersionRange) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (r *apiVersionRange) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := r.check(conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
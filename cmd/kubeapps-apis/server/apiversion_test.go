@@ -0,0 +1,106 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+)
+
+func TestAPIVersionRange(t *testing.T) {
+	testCases := []struct {
+		name            string
+		min, max        string
+		version         string
+		expectErr       bool
+		expectedCode    connect.Code
+		expectedMessage string
+	}{
+		{
+			name:    "allows a supported version",
+			min:     "2.7.0",
+			max:     "2.9.0",
+			version: "2.8.1",
+		},
+		{
+			name:    "allows the bounds of the range",
+			min:     "2.7.0",
+			max:     "2.9.0",
+			version: "2.9.0",
+		},
+		{
+			name: "allows the requests without a version",
+			min:  "2.7.0",
+		},
+		{
+			name:            "asks an older client to upgrade",
+			min:             "2.7.0",
+			max:             "2.9.0",
+			version:         "2.6.3",
+			expectedCode:    connect.CodeFailedPrecondition,
+			expectedMessage: "Please upgrade the client",
+		},
+		{
+			name:            "asks to upgrade the server for a newer client",
+			max:             "2.9.0",
+			version:         "3.0.0",
+			expectedCode:    connect.CodeFailedPrecondition,
+			expectedMessage: "Please upgrade the server",
+		},
+		{
+			name:         "rejects an invalid version",
+			min:          "2.7.0",
+			version:      "latest",
+			expectedCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:      "rejects an invalid bound",
+			min:       "two",
+			expectErr: true,
+		},
+		{
+			name:      "rejects a maximum lower than the minimum",
+			min:       "2.9.0",
+			max:       "2.7.0",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			versions, err := newAPIVersionRange(tc.min, tc.max)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if tc.expectErr {
+				return
+			}
+			header := http.Header{}
+			if tc.version != "" {
+				header.Set(apiVersionHeader, tc.version)
+			}
+			err = versions.check(header)
+			if tc.expectedCode == 0 {
+				if err != nil {
+					t.Errorf("got: %+v, want no error", err)
+				}
+				return
+			}
+			if got, want := connect.CodeOf(err), tc.expectedCode; got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+			if !strings.Contains(err.Error(), tc.expectedMessage) {
+				t.Errorf("got: %v, want it to contain: %s", err, tc.expectedMessage)
+			}
+		})
+	}
+
+	// The range is disabled without bounds.
+	if versions, err := newAPIVersionRange("", ""); versions != nil || err != nil {
+		t.Errorf("got: %v, %v, want: nil, nil", versions, err)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	log "k8s.io/klog/v2"
+)
+
+// auditLogSinkStdout is the audit log sink writing the records to stdout.
+const auditLogSinkStdout = "stdout"
+
+// auditRecord is the structured record of a mutating operation.
+type auditRecord struct {
+	Time time.Time `json:"time"`
+	// User is the validated caller identity or, failing that, the identity of
+	// the client certificate. It is empty when the caller was not validated.
+	User      string                     `json:"user,omitempty"`
+	ClientIP  string                     `json:"clientIP,omitempty"`
+	Procedure string                     `json:"procedure"`
+	Target    map[string]json.RawMessage `json:"target,omitempty"`
+	Result    string                     `json:"result"`
+	Error     string                     `json:"error,omitempty"`
+	Request   json.RawMessage            `json:"request,omitempty"`
+}
+
+// auditLogger is a connect interceptor writing an audit record, as a JSON line,
+// for each call to a mutating method. Reads are not audited. The audit log
+// file, if any, is closed with Close once the server is shut down.
+type auditLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+	// file is the audit log file, nil when writing to stdout.
+	file           *os.File
+	methods        []string
+	trustedProxies core.TrustedProxies
+}
+
+// newAuditLogger returns an audit logger writing to the given sink, which is
+// either "stdout" or the path of a file to which the records are appended.
+func newAuditLogger(sink string, methods []string, trustedProxies core.TrustedProxies) (*auditLogger, error) {
+	a := &auditLogger{
+		w:              os.Stdout,
+		methods:        methods,
+		trustedProxies: trustedProxies,
+	}
+	if sink != auditLogSinkStdout {
+		f, err := os.OpenFile(sink, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the audit log %q: %w", sink, err)
+		}
+		a.w = f
+		a.file = f
+	}
+	return a, nil
+}
+
+// Close syncs and closes the audit log file, if any, so that no record is lost
+// when the process exits. The records of the requests still in flight are not
+// written afterwards.
+func (a *auditLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	err := errors.Join(a.file.Sync(), a.file.Close())
+	a.w = io.Discard
+	a.file = nil
+	return err
+}
+
+func (a *auditLogger) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if !methodHasPrefix(req.Spec().Procedure, a.methods) {
+			return next(ctx, req)
+		}
+		res, err := next(ctx, req)
+		a.audit(ctx, req, res, err)
+		return res, err
+	}
+}
+
+func (a *auditLogger) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler does not audit the streaming methods, none of which mutates.
+func (a *auditLogger) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// audit writes the audit record of a call.
+func (a *auditLogger) audit(ctx context.Context, req connect.AnyRequest, res connect.AnyResponse, err error) {
+	record := auditRecord{
+		Time:      time.Now().UTC(),
+		User:      callerName(ctx),
+		ClientIP:  clientIP(a.trustedProxies, req.Peer().Addr, req.Header()),
+		Procedure: req.Spec().Procedure,
+		Target:    map[string]json.RawMessage{},
+		Result:    "ok",
+	}
+	if err != nil {
+		record.Result = connect.CodeOf(err).String()
+		record.Error = core.RedactString(err.Error())
+	}
+	if msg, ok := req.Any().(proto.Message); ok {
+		addAuditTarget(record.Target, msg)
+		if request, err := protojson.Marshal(core.RedactSensitiveFields(msg)); err == nil {
+			record.Request = request
+		}
+	}
+	// The reference of a created resource is only known from the response.
+	if res != nil {
+		if msg, ok := res.Any().(proto.Message); ok {
+			addAuditTarget(record.Target, msg)
+		}
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Errorf("Unable to marshal the audit record for %q: %v", record.Procedure, err)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		log.Errorf("Unable to write the audit record for %q: %v", record.Procedure, err)
+	}
+}
+
+// callerName returns the identity of the caller, if known, which identifies
+// the user in the audit records and the daily quota.
+func callerName(ctx context.Context) string {
+	if id, ok := core.CallerIdentityFromContext(ctx); ok {
+		return id.Username
+	}
+	if id, ok := core.ClientCertIdentityFromContext(ctx); ok {
+		return id.String()
+	}
+	return ""
+}
+
+// addAuditTarget adds the references and contexts of the message, such as the
+// installed_package_ref or the target_context and name of a new package, to
+// the target of the record.
+func addAuditTarget(target map[string]json.RawMessage, msg proto.Message) {
+	m := msg.ProtoReflect()
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		switch {
+		case fd.Message() != nil && !fd.IsList() && !fd.IsMap() && (strings.HasSuffix(name, "_ref") || strings.HasSuffix(name, "context")):
+			if value, err := protojson.Marshal(v.Message().Interface()); err == nil {
+				target[name] = value
+			}
+		case name == "name" && fd.Kind() == protoreflect.StringKind:
+			if value, err := json.Marshal(v.String()); err == nil {
+				target[name] = value
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+)
+
+func TestAuditLogger(t *testing.T) {
+	var buf strings.Builder
+	auditLogger := &auditLogger{w: &buf, methods: []string{"Create"}}
+
+	createProcedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage"
+	getProcedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	installedPackageRef := &packagesGRPCv1alpha1.InstalledPackageReference{
+		Context:    &packagesGRPCv1alpha1.Context{Namespace: "default"},
+		Identifier: "my-apache",
+	}
+
+	createServer := serveTestProcedure(t, createProcedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.CreateInstalledPackageRequest]) (*connect.Response[packagesGRPCv1alpha1.CreateInstalledPackageResponse], error) {
+			return connect.NewResponse(&packagesGRPCv1alpha1.CreateInstalledPackageResponse{InstalledPackageRef: installedPackageRef}), nil
+		},
+		connect.WithInterceptors(auditLogger),
+	)
+	getServer := serveTestProcedure(t, getProcedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest]) (*connect.Response[packagesGRPCv1alpha1.GetInstalledPackageDetailResponse], error) {
+			return connect.NewResponse(&packagesGRPCv1alpha1.GetInstalledPackageDetailResponse{}), nil
+		},
+		connect.WithInterceptors(auditLogger),
+	)
+
+	createClient := connect.NewClient[packagesGRPCv1alpha1.CreateInstalledPackageRequest, packagesGRPCv1alpha1.CreateInstalledPackageResponse](createServer.Client(), createServer.URL+createProcedure)
+	if _, err := createClient.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.CreateInstalledPackageRequest{
+		TargetContext: &packagesGRPCv1alpha1.Context{Namespace: "default"},
+		Name:          "my-apache",
+		Values:        "password: secret",
+	})); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	getClient := connect.NewClient[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest, packagesGRPCv1alpha1.GetInstalledPackageDetailResponse](getServer.Client(), getServer.URL+getProcedure)
+	if _, err := getClient.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetInstalledPackageDetailRequest{
+		InstalledPackageRef: installedPackageRef,
+	})); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got, want := len(lines), 1; got != want {
+		t.Fatalf("got: %d audit records, want: %d: %q", got, want, lines)
+	}
+	record := auditRecord{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := record.Procedure, createProcedure; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := record.Result, "ok"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := string(record.Target["name"]), `"my-apache"`; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if _, ok := record.Target["installed_package_ref"]; !ok {
+		t.Errorf("expected the installed package ref of the response in the target, got: %v", record.Target)
+	}
+	if strings.Contains(string(record.Request), "secret") {
+		t.Errorf("expected the values to be redacted, got: %s", record.Request)
+	}
+}
+
+func TestAuditLoggerClose(t *testing.T) {
+	sink := filepath.Join(t.TempDir(), "audit.log")
+	auditLogger, err := newAuditLogger(sink, []string{"Create"}, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	auditLogger.audit(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.CreateInstalledPackageRequest{Name: "my-apache"}), nil, nil)
+
+	// The chain closes its audit logger, and with it the audit log file.
+	interceptorChain{audit: auditLogger}.close()
+
+	contents, err := os.ReadFile(sink)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := strings.Count(string(contents), "\n"), 1; got != want {
+		t.Errorf("got: %d audit records, want: %d: %q", got, want, contents)
+	}
+	// The records of the requests still in flight are discarded.
+	auditLogger.audit(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.CreateInstalledPackageRequest{Name: "my-apache"}), nil, nil)
+	if err := auditLogger.Close(); err != nil {
+		t.Errorf("got: %+v, want no error", err)
+	}
+}
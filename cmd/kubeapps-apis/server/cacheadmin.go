@@ -0,0 +1,65 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	packagesv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/packages/v1alpha1"
+	log "k8s.io/klog/v2"
+)
+
+// cacheStatsHandler reports the statistics of the caches of the plugins, by
+// plugin name, with GET requests. There are no caches when the cache is disabled.
+func cacheStatsHandler(cacheAdmin *packagesv1alpha1.CacheAdmin) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cacheAdmin.Stats()); err != nil {
+			log.Errorf("Unable to write the cache statistics: %v", err)
+		}
+	})
+}
+
+// cacheFlushHandler flushes the caches of the plugins with POST requests,
+// reporting the number of cached responses removed.
+func cacheFlushHandler(cacheAdmin *packagesv1alpha1.CacheAdmin) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		flushed := cacheAdmin.Flush()
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]int{"flushed": flushed}); err != nil {
+			log.Errorf("Unable to write the cache flush response: %v", err)
+		}
+	})
+}
+
+// restoreCacheSnapshot restores the caches of the plugins from the snapshot,
+// starting with empty caches when it cannot be loaded, such as when it is
+// corrupted.
+func restoreCacheSnapshot(cacheAdmin *packagesv1alpha1.CacheAdmin, path string) {
+	restored, err := cacheAdmin.LoadSnapshot(path)
+	if err != nil {
+		log.Errorf("Unable to restore the cache snapshot %q, starting with empty caches: %v", path, err)
+		return
+	}
+	log.InfoS("Restored the cache snapshot", "path", path, "entries", restored)
+}
+
+// saveCacheSnapshot saves the snapshot of the caches of the plugins.
+func saveCacheSnapshot(cacheAdmin *packagesv1alpha1.CacheAdmin, path string) {
+	saved, err := cacheAdmin.SaveSnapshot(path)
+	if err != nil {
+		log.Errorf("Unable to save the cache snapshot %q: %v", path, err)
+		return
+	}
+	log.InfoS("Saved the cache snapshot", "path", path, "entries", saved)
+}
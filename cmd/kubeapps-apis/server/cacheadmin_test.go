@@ -0,0 +1,59 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	packagesv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/packages/v1alpha1"
+)
+
+func TestCacheAdminHandlers(t *testing.T) {
+	cacheAdmin := packagesv1alpha1.NewCacheAdmin()
+
+	testCases := []struct {
+		name           string
+		handler        http.Handler
+		method         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "reports the statistics of the caches",
+			handler:        cacheStatsHandler(cacheAdmin),
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{}\n",
+		},
+		{
+			name:           "rejects the flush with GET",
+			handler:        cacheFlushHandler(cacheAdmin),
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method not allowed\n",
+		},
+		{
+			name:           "flushes the caches",
+			handler:        cacheFlushHandler(cacheAdmin),
+			method:         http.MethodPost,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"flushed\":0}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rec, httptest.NewRequest(tc.method, "/admin/cache", nil))
+			if got, want := rec.Code, tc.expectedStatus; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+			if got, want := rec.Body.String(), tc.expectedBody; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
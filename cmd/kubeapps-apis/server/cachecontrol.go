@@ -0,0 +1,80 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	cacheControlHeader = "Cache-Control"
+
+	// defaultCacheControl is the Cache-Control header of the gateway responses
+	// of the methods for which none is configured, so that intermediate proxies
+	// do not cache them unless explicitly allowed.
+	defaultCacheControl = "no-store"
+)
+
+// cacheControl sets the Cache-Control header of the responses of the gateway,
+// configured by method name prefix, so that the responses of the read methods
+// can be cached by intermediate proxies where it is safe.
+type cacheControl struct {
+	directives map[string]string
+}
+
+// newCacheControl parses the Cache-Control directives of the methods, in the
+// form <method name prefix>=<directives>, such as
+// "GetAvailablePackageVersions=public, max-age=60".
+func newCacheControl(values []string) (*cacheControl, error) {
+	c := &cacheControl{directives: map[string]string{}}
+	for _, value := range values {
+		prefix, directives, found := strings.Cut(value, "=")
+		prefix, directives = strings.TrimSpace(prefix), strings.TrimSpace(directives)
+		if !found || prefix == "" || directives == "" {
+			return nil, fmt.Errorf("invalid cache control %q, expected <method name prefix>=<directives>", value)
+		}
+		c.directives[prefix] = directives
+	}
+	return c, nil
+}
+
+// forMethod returns the Cache-Control header of the responses of the method, as
+// configured for the longest matching method name prefix.
+func (c *cacheControl) forMethod(procedure string) string {
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	header, matched := defaultCacheControl, ""
+	for prefix, directives := range c.directives {
+		if strings.HasPrefix(method, prefix) && len(prefix) > len(matched) {
+			header, matched = directives, prefix
+		}
+	}
+	return header
+}
+
+// forwardResponseOption sets the Cache-Control header of the successful
+// responses of the gateway.
+func (c *cacheControl) forwardResponseOption(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	method, ok := runtime.RPCMethod(ctx)
+	if !ok {
+		w.Header().Set(cacheControlHeader, defaultCacheControl)
+		return nil
+	}
+	w.Header().Set(cacheControlHeader, c.forMethod(method))
+	return nil
+}
+
+// errorHandler prevents the error responses of the gateway from being cached,
+// and forwards the retry hint of the errors, before handling the error as by
+// default.
+func (c *cacheControl) errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set(cacheControlHeader, defaultCacheControl)
+	setRetryAfterHeader(ctx, w, err)
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
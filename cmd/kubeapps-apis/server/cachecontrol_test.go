@@ -0,0 +1,80 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestCacheControl(t *testing.T) {
+	cacheControl, err := newCacheControl([]string{
+		"Get=private, max-age=10",
+		"GetAvailablePackageVersions=public, max-age=60",
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	testCases := []struct {
+		name           string
+		procedure      string
+		expectedHeader string
+	}{
+		{
+			name:           "uses the directives of the longest matching prefix",
+			procedure:      "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageVersions",
+			expectedHeader: "public, max-age=60",
+		},
+		{
+			name:           "uses the directives of a shorter matching prefix",
+			procedure:      "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries",
+			expectedHeader: "private, max-age=10",
+		},
+		{
+			name:           "does not cache the responses of the other methods",
+			procedure:      "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage",
+			expectedHeader: defaultCacheControl,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/core/packages/v1alpha1/availablepackages", nil)
+			ctx, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(), req, tc.procedure)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			w := httptest.NewRecorder()
+			if err := cacheControl.forwardResponseOption(ctx, w, &emptypb.Empty{}); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := w.Header().Get(cacheControlHeader), tc.expectedHeader; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+
+	t.Run("does not cache the errors", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/core/packages/v1alpha1/availablepackages", nil)
+		w := httptest.NewRecorder()
+		cacheControl.errorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, req, connect.NewError(connect.CodeNotFound, errors.New("not found")))
+		if got, want := w.Header().Get(cacheControlHeader), defaultCacheControl; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+
+	t.Run("errors for an invalid configuration", func(t *testing.T) {
+		if _, err := newCacheControl([]string{"GetAvailablePackageVersions"}); err == nil {
+			t.Errorf("got: nil, want: error")
+		}
+	})
+}
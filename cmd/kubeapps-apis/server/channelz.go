@@ -0,0 +1,37 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	channelzservice "google.golang.org/grpc/channelz/service"
+	log "k8s.io/klog/v2"
+)
+
+// startChannelz serves the gRPC channelz service on its own address, since the
+// connect handlers are not served by a gRPC server, so that the state of the
+// channels dialed by the gateway to this server and to the plugins can be
+// inspected with the gRPC debugging tools, such as grpcdebug. It returns the
+// function stopping the channelz server. The channelz service is not
+// authenticated, so the address should only be reachable from the pod, such as
+// localhost:50052, with kubectl port-forward.
+func startChannelz(addr string, sup *supervisor) (func(), error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %q: %w", addr, err)
+	}
+	return serveChannelz(lis, sup), nil
+}
+
+// serveChannelz serves the channelz service on the listener.
+func serveChannelz(lis net.Listener, sup *supervisor) func() {
+	server := grpc.NewServer()
+	channelzservice.RegisterChannelzServiceToServer(server)
+	log.Infof("Starting the channelz server on %q", lis.Addr().String())
+	go supervise(sup, func() error { return server.Serve(lis) })
+	return server.Stop
+}
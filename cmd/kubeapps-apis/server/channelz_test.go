@@ -0,0 +1,50 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestChannelz(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sup := newSupervisor()
+	stop := serveChannelz(lis, sup)
+	defer stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// The channel of the client itself is listed among the top channels.
+	response, err := channelzpb.NewChannelzClient(conn).GetTopChannels(ctx, &channelzpb.GetTopChannelsRequest{})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(response.GetChannel()) == 0 {
+		t.Errorf("got no channel, want at least one")
+	}
+
+	// Stopping the channelz server does not fail the supervisor.
+	stop()
+	select {
+	case err := <-sup.failed():
+		t.Errorf("got: %+v, want no failure", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
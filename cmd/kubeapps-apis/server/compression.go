@@ -0,0 +1,44 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// gzipCompressionName is the name of the gzip compression, for all the
+// protocols served by the connect handlers.
+const gzipCompressionName = "gzip"
+
+// validateGzipCompressionLevel returns an error unless the level is one of the
+// levels of compress/gzip: from -2 (Huffman only) to 9 (best compression), -1
+// being the default one.
+func validateGzipCompressionLevel(level int) error {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return fmt.Errorf("invalid gRPC compression level %d, expected a gzip level from %d to %d", level, gzip.HuffmanOnly, gzip.BestCompression)
+	}
+	return nil
+}
+
+// gzipCompression returns the handler option replacing the gzip compression of
+// the connect handlers, which uses the default level, with one using the given
+// level, trading the compression ratio of the responses for CPU time. The
+// compressors are pooled by the handlers.
+func gzipCompression(level int) connect.HandlerOption {
+	return connect.WithCompression(
+		gzipCompressionName,
+		func() connect.Decompressor {
+			return &gzip.Reader{}
+		},
+		func() connect.Compressor {
+			// The level is validated on startup, so it does not fail.
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		},
+	)
+}
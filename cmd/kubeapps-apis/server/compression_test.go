@@ -0,0 +1,117 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHandlerOptionsGzipCompressionLevel(t *testing.T) {
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+	response := packageListPayload(100)
+	ts := serveTestProcedure(t, procedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest]) (*connect.Response[packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse], error) {
+			return connect.NewResponse(response), nil
+		},
+		newHandlerOptions(core.ServeOptions{GRPCCompressionLevel: gzip.BestSpeed}, nil)...,
+	)
+
+	encodings := []string{}
+	httpClient := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		res, err := http.DefaultTransport.RoundTrip(r)
+		if err == nil {
+			encodings = append(encodings, res.Header.Get("Content-Encoding"))
+		}
+		return res, err
+	})}
+	client := connect.NewClient[packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest, packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse](httpClient, ts.URL+procedure, connect.WithSendGzip())
+	res, err := client.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{}))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !proto.Equal(res.Msg, response) {
+		t.Errorf("got: %v, want: %v", res.Msg, response)
+	}
+	if got, want := encodings, []string{"gzip"}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	for _, level := range []int{gzip.HuffmanOnly - 1, gzip.BestCompression + 1} {
+		if err := validateGzipCompressionLevel(level); err == nil {
+			t.Errorf("got: nil, want: an error for the level %d", level)
+		}
+	}
+}
+
+// roundTripperFunc is an http.RoundTripper calling the function.
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// BenchmarkGzipCompressionLevels compares the gzip compression levels on a
+// page of available packages, reporting the compression ratio of each.
+func BenchmarkGzipCompressionLevels(b *testing.B) {
+	payload, err := proto.Marshal(packageListPayload(100))
+	if err != nil {
+		b.Fatalf("%+v", err)
+	}
+	for _, level := range []int{gzip.HuffmanOnly, gzip.BestSpeed, gzip.DefaultCompression, gzip.BestCompression} {
+		b.Run(fmt.Sprintf("level=%d", level), func(b *testing.B) {
+			buf := &bytes.Buffer{}
+			w, err := gzip.NewWriterLevel(buf, level)
+			if err != nil {
+				b.Fatalf("%+v", err)
+			}
+			b.SetBytes(int64(len(payload)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				w.Reset(buf)
+				if _, err := w.Write(payload); err != nil {
+					b.Fatalf("%+v", err)
+				}
+				if err := w.Close(); err != nil {
+					b.Fatalf("%+v", err)
+				}
+			}
+			b.ReportMetric(float64(len(payload))/float64(buf.Len()), "ratio")
+		})
+	}
+}
+
+// packageListPayload returns a page of available packages similar to those of
+// a chart repository.
+func packageListPayload(count int) *packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse {
+	response := &packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse{Categories: []string{"Analytics", "Database", "Infrastructure"}}
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("package-%d", i)
+		response.AvailablePackageSummaries = append(response.AvailablePackageSummaries, &packagesGRPCv1alpha1.AvailablePackageSummary{
+			AvailablePackageRef: &packagesGRPCv1alpha1.AvailablePackageReference{
+				Context:    &packagesGRPCv1alpha1.Context{Cluster: "default", Namespace: "kubeapps"},
+				Identifier: "bitnami/" + name,
+			},
+			Name:             name,
+			DisplayName:      name,
+			LatestVersion:    &packagesGRPCv1alpha1.PackageAppVersion{PkgVersion: fmt.Sprintf("%d.2.3", i), AppVersion: fmt.Sprintf("%d.0.0", i)},
+			IconUrl:          fmt.Sprintf("https://bitnami.com/assets/stacks/%s/img/%s-stack-220x234.png", name, name),
+			ShortDescription: fmt.Sprintf("%s is a popular package, packaged by Bitnami for the deployment on Kubernetes.", name),
+			Categories:       []string{"Infrastructure"},
+		})
+	}
+	return response
+}
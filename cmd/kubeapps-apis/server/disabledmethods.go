@@ -0,0 +1,68 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// disabledMethods is a connect interceptor failing the requests of the
+// disabled methods with an Unimplemented error, so that some capabilities,
+// such as the deletions, can be disabled in locked-down deployments without
+// removing the plugins serving them.
+type disabledMethods map[string]bool
+
+// newDisabledMethods parses the full names of the disabled methods, such as
+// "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage".
+func newDisabledMethods(values []string) (disabledMethods, error) {
+	methods := disabledMethods{}
+	for _, value := range values {
+		if !isMethodFullName(value) {
+			return nil, fmt.Errorf("invalid disabled method %q, expected /<service full name>/<method name>", value)
+		}
+		methods[value] = true
+	}
+	return methods, nil
+}
+
+// isMethodFullName returns whether value is the full name of a method, in the
+// form /<service full name>/<method name>.
+func isMethodFullName(value string) bool {
+	service, method, found := strings.Cut(strings.TrimPrefix(value, "/"), "/")
+	return strings.HasPrefix(value, "/") && found && service != "" && method != "" && !strings.Contains(method, "/")
+}
+
+// check returns an Unimplemented error if the procedure is disabled.
+func (d disabledMethods) check(procedure string) error {
+	if d[procedure] {
+		return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("The method %q is disabled on this server", procedure))
+	}
+	return nil
+}
+
+func (d disabledMethods) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := d.check(req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (d disabledMethods) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (d disabledMethods) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := d.check(conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
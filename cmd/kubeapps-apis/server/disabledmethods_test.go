@@ -0,0 +1,71 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+)
+
+func TestDisabledMethods(t *testing.T) {
+	deleteMethod := "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage"
+
+	testCases := []struct {
+		name         string
+		values       []string
+		procedure    string
+		expectErr    bool
+		expectedCode connect.Code
+	}{
+		{
+			name:         "rejects a disabled method",
+			values:       []string{deleteMethod},
+			procedure:    deleteMethod,
+			expectedCode: connect.CodeUnimplemented,
+		},
+		{
+			name:      "allows the other methods",
+			values:    []string{deleteMethod},
+			procedure: "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail",
+		},
+		{
+			name:      "allows the methods of the same name in other services",
+			values:    []string{deleteMethod},
+			procedure: "/kubeappsapis.plugins.helm.packages.v1alpha1.HelmPackagesService/DeleteInstalledPackage",
+		},
+		{
+			name:      "rejects a method name without its service",
+			values:    []string{"DeleteInstalledPackage"},
+			expectErr: true,
+		},
+		{
+			name:      "rejects a service without a method name",
+			values:    []string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			disabled, err := newDisabledMethods(tc.values)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if tc.expectErr {
+				return
+			}
+			err = disabled.check(tc.procedure)
+			if tc.expectedCode == 0 {
+				if err != nil {
+					t.Errorf("got: %+v, want no error", err)
+				}
+				return
+			}
+			if got, want := connect.CodeOf(err), tc.expectedCode; got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
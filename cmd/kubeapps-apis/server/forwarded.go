@@ -0,0 +1,147 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	log "k8s.io/klog/v2"
+)
+
+// externalURL is the externally-visible URL of the server, as forwarded by a
+// reverse proxy serving it at a subpath, such as an ingress.
+type externalURL struct {
+	scheme string
+	host   string
+	prefix string
+}
+
+// forwardedURL returns the external URL set by the X-Forwarded-Proto,
+// X-Forwarded-Host and X-Forwarded-Prefix headers of the request, which are
+// only honored when sent by a trusted proxy.
+func forwardedURL(r *http.Request, trustedProxies core.TrustedProxies) (externalURL, bool) {
+	if !trustedProxies.IsTrustedPeer(r.RemoteAddr) {
+		return externalURL{}, false
+	}
+	u := externalURL{
+		scheme: firstForwardedValue(r.Header.Get("X-Forwarded-Proto")),
+		host:   firstForwardedValue(r.Header.Get("X-Forwarded-Host")),
+		prefix: strings.TrimSuffix(firstForwardedValue(r.Header.Get("X-Forwarded-Prefix")), "/"),
+	}
+	if u.host == "" && u.prefix == "" {
+		return externalURL{}, false
+	}
+	if u.scheme == "" {
+		u.scheme = "http"
+		if r.TLS != nil {
+			u.scheme = "https"
+		}
+	}
+	return u, true
+}
+
+// firstForwardedValue returns the value set by the proxy closest to the client,
+// when several proxies appended theirs.
+func firstForwardedValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(first)
+}
+
+// rewrite returns the external URL of the given absolute path, which is only
+// made an absolute URL when the external host is known.
+func (u externalURL) rewrite(path string) string {
+	if u.host == "" {
+		return u.prefix + path
+	}
+	return u.scheme + "://" + u.host + u.prefix + path
+}
+
+// withForwardedLocation rewrites the Location headers of the responses, such as
+// those of the redirects to the path with a trailing slash, so that they point
+// to the external URL forwarded by a trusted proxy rather than the internal path.
+func withForwardedLocation(h http.Handler, trustedProxies core.TrustedProxies) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, ok := forwardedURL(r, trustedProxies)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(&forwardedLocationWriter{ResponseWriter: w, url: u}, r)
+	})
+}
+
+// forwardedLocationWriter rewrites the Location header when the header is written.
+type forwardedLocationWriter struct {
+	http.ResponseWriter
+	url         externalURL
+	wroteHeader bool
+}
+
+func (w *forwardedLocationWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		// Only absolute paths are rewritten, not relative or external URLs.
+		if location := w.Header().Get("Location"); strings.HasPrefix(location, "/") && !strings.HasPrefix(location, "//") {
+			w.Header().Set("Location", w.url.rewrite(location))
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *forwardedLocationWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush is required by the streaming responses.
+func (w *forwardedLocationWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap allows the http.ResponseController to access the wrapped writer.
+func (w *forwardedLocationWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// serveOpenAPI serves the OpenAPI document. When forwarded by a trusted proxy,
+// its host and base path are those of the external URL, so that the swagger UI
+// sends its requests to the externally-visible URL.
+func serveOpenAPI(path string, trustedProxies core.TrustedProxies) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := forwardedURL(r, trustedProxies)
+		if !ok {
+			http.ServeFile(w, r, path)
+			return
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			http.Error(w, "Unable to read the OpenAPI document", http.StatusInternalServerError)
+			return
+		}
+		doc := map[string]json.RawMessage{}
+		if err := json.Unmarshal(content, &doc); err != nil {
+			http.Error(w, "Unable to parse the OpenAPI document", http.StatusInternalServerError)
+			return
+		}
+		if u.host != "" {
+			doc["host"], _ = json.Marshal(u.host)
+			doc["schemes"], _ = json.Marshal([]string{u.scheme})
+		}
+		if u.prefix != "" {
+			doc["basePath"], _ = json.Marshal(u.prefix)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(doc); err != nil {
+			log.Errorf("Unable to write the OpenAPI document: %v", err)
+		}
+	}
+}
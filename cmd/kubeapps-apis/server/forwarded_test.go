@@ -0,0 +1,138 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+)
+
+func TestWithForwardedLocation(t *testing.T) {
+	trustedProxies, err := core.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/admin/", http.NotFoundHandler())
+	handler := withForwardedLocation(mux, trustedProxies)
+
+	testCases := []struct {
+		name             string
+		remoteAddr       string
+		header           map[string]string
+		expectedLocation string
+	}{
+		{
+			name:             "keeps the internal path without forwarded headers",
+			remoteAddr:       "10.0.0.1:54321",
+			expectedLocation: "/admin/",
+		},
+		{
+			name:             "prefixes the path with the forwarded prefix",
+			remoteAddr:       "10.0.0.1:54321",
+			header:           map[string]string{"X-Forwarded-Prefix": "/apis/"},
+			expectedLocation: "/apis/admin/",
+		},
+		{
+			name:       "uses the forwarded host and scheme",
+			remoteAddr: "10.0.0.1:54321",
+			header: map[string]string{
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Host":   "kubeapps.example.com, internal.example.com",
+				"X-Forwarded-Prefix": "/apis",
+			},
+			expectedLocation: "https://kubeapps.example.com/apis/admin/",
+		},
+		{
+			name:             "ignores the forwarded headers of an untrusted peer",
+			remoteAddr:       "192.168.1.1:54321",
+			header:           map[string]string{"X-Forwarded-Prefix": "/apis"},
+			expectedLocation: "/admin/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got, want := rec.Code, http.StatusMovedPermanently; got != want {
+				t.Fatalf("got: %d, want: %d", got, want)
+			}
+			if got, want := rec.Header().Get("Location"), tc.expectedLocation; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestServeOpenAPIWithForwardedURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, []byte(`{"swagger": "2.0", "host": "127.0.0.1:8080", "basePath": "/apis"}`), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	trustedProxies, err := core.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	handler := serveOpenAPI(path, trustedProxies)
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "uses the external URL forwarded by a trusted proxy",
+			remoteAddr: "10.0.0.1:54321",
+			expected: map[string]interface{}{
+				"swagger":  "2.0",
+				"host":     "kubeapps.example.com",
+				"basePath": "/kubeapps/apis",
+				"schemes":  []interface{}{"https"},
+			},
+		},
+		{
+			name:       "ignores the forwarded headers of a loopback peer which is not a trusted proxy",
+			remoteAddr: "127.0.0.1:54321",
+			expected: map[string]interface{}{
+				"swagger":  "2.0",
+				"host":     "127.0.0.1:8080",
+				"basePath": "/apis",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Forwarded-Host", "kubeapps.example.com")
+			req.Header.Set("X-Forwarded-Prefix", "/kubeapps/apis")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			doc := map[string]interface{}{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := doc, tc.expected; !cmp.Equal(want, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
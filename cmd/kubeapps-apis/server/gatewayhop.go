@@ -0,0 +1,86 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	log "k8s.io/klog/v2"
+)
+
+// gatewayHopMetadataKey is the metadata key of the token identifying the calls
+// of the gateway to the handlers of this same process.
+const gatewayHopMetadataKey = "x-kubeapps-gateway-hop"
+
+// gatewayHopToken is generated for each process, so that only its own gateway
+// can send it. The loopback peers are not trusted as such, since any process
+// of the pod, or a port-forward, connects over the loopback interface too.
+var gatewayHopToken = newGatewayHopToken()
+
+func newGatewayHopToken() string {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		log.Fatalf("Unable to generate the gateway hop token: %v", err)
+	}
+	return hex.EncodeToString(token)
+}
+
+// gatewayHopDialOptions returns the interceptors attaching the gateway hop
+// token to the calls of the gateway to the given address of this server. The
+// calls to the plugins served at other addresses do not carry it.
+func gatewayHopDialOptions(addr string) []grpc.DialOption {
+	withHop := func(ctx context.Context, cc *grpc.ClientConn) context.Context {
+		if cc.Target() != addr {
+			return ctx
+		}
+		return metadata.AppendToOutgoingContext(ctx, gatewayHopMetadataKey, gatewayHopToken)
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withHop(ctx, cc), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withHop(ctx, cc), desc, cc, method, opts...)
+		}),
+	}
+}
+
+// isGatewayHop returns whether the request is a call of the gateway of this
+// process, carrying its gateway hop token. A REST client can forward a value
+// of its own as Grpc-Metadata-X-Kubeapps-Gateway-Hop, so any other value
+// alongside the token is rejected too.
+func isGatewayHop(header http.Header) bool {
+	tokens := header.Values(gatewayHopMetadataKey)
+	return len(tokens) == 1 && subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(gatewayHopToken)) == 1
+}
+
+// clientIP returns the IP address of the client of the request. For the calls
+// of the gateway, the peer is the gateway itself, which appended the address
+// of its own peer to the X-Forwarded-For entries, so that the latter is the
+// peer whose address is resolved with the trusted proxies.
+func clientIP(trustedProxies core.TrustedProxies, peerAddr string, header http.Header) string {
+	forwardedFor := header.Values("X-Forwarded-For")
+	if isGatewayHop(header) {
+		addrs := []string{}
+		for _, value := range forwardedFor {
+			for _, addr := range strings.Split(value, ",") {
+				if addr = strings.TrimSpace(addr); addr != "" {
+					addrs = append(addrs, addr)
+				}
+			}
+		}
+		if len(addrs) > 0 {
+			peerAddr, forwardedFor = addrs[len(addrs)-1], addrs[:len(addrs)-1]
+		}
+	}
+	return trustedProxies.ClientIP(peerAddr, forwardedFor)
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestClientIPOfGatewayHops(t *testing.T) {
+	trustedProxies, err := core.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		peerAddr   string
+		header     http.Header
+		expectedIP string
+	}{
+		{
+			name:       "returns a loopback peer without the gateway hop token",
+			peerAddr:   "127.0.0.1:51234",
+			header:     http.Header{"X-Forwarded-For": {"6.6.6.6"}},
+			expectedIP: "127.0.0.1",
+		},
+		{
+			name:       "returns a loopback peer with another gateway hop token",
+			peerAddr:   "127.0.0.1:51234",
+			header:     http.Header{"X-Forwarded-For": {"6.6.6.6"}, "X-Kubeapps-Gateway-Hop": {"forged"}},
+			expectedIP: "127.0.0.1",
+		},
+		{
+			name:       "returns a loopback peer with another gateway hop token alongside the token",
+			peerAddr:   "127.0.0.1:51234",
+			header:     http.Header{"X-Forwarded-For": {"6.6.6.6"}, "X-Kubeapps-Gateway-Hop": {"forged", gatewayHopToken}},
+			expectedIP: "127.0.0.1",
+		},
+		{
+			name:       "returns the peer of the gateway for its calls",
+			peerAddr:   "127.0.0.1:51234",
+			header:     http.Header{"X-Forwarded-For": {"6.6.6.6, 1.2.3.4"}, "X-Kubeapps-Gateway-Hop": {gatewayHopToken}},
+			expectedIP: "1.2.3.4",
+		},
+		{
+			name:       "skips the trusted proxies in front of the gateway",
+			peerAddr:   "127.0.0.1:51234",
+			header:     http.Header{"X-Forwarded-For": {"6.6.6.6", "1.2.3.4, 10.0.0.1"}, "X-Kubeapps-Gateway-Hop": {gatewayHopToken}},
+			expectedIP: "1.2.3.4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := clientIP(trustedProxies, tc.peerAddr, tc.header), tc.expectedIP; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGatewayHopDialOptions(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	addr := lis.Addr().String()
+	hops := make(chan bool, 1)
+	s := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		hops <- isGatewayHop(http.Header{http.CanonicalHeaderKey(gatewayHopMetadataKey): md.Get(gatewayHopMetadataKey)})
+		return stream.SendMsg(&emptypb.Empty{})
+	}))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	call := func(target string) bool {
+		dialOptions := append(gatewayHopDialOptions(addr),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+			}),
+		)
+		conn, err := grpc.Dial(target, dialOptions...)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer conn.Close()
+		if err := conn.Invoke(context.Background(), "/test.v1.TestService/GetThing", &emptypb.Empty{}, &emptypb.Empty{}); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return <-hops
+	}
+
+	if got, want := call(addr), true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+	// The calls to the plugins served at other addresses do not carry the token.
+	if got, want := call("plugin.example.com:50051"), false; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// pluginJSONOptions maps the names of the plugins to the JSON marshaling
+// options of their gateway responses.
+type pluginJSONOptions map[string]protojson.MarshalOptions
+
+// parsePluginJSONOptions parses the JSON marshaling options of the plugins from
+// values such as "fluxv2.packages=use-proto-names,emit-unpopulated". The
+// options of a plugin start from the global ones, given by useProtoNames.
+func parsePluginJSONOptions(values []string, useProtoNames bool) (pluginJSONOptions, error) {
+	options := pluginJSONOptions{}
+	for _, value := range values {
+		pluginName, optionValues, found := strings.Cut(value, "=")
+		pluginName = strings.TrimSpace(pluginName)
+		if !found || pluginName == "" {
+			return nil, fmt.Errorf("invalid plugin JSON options %q, expected <plugin name>=<option>[,<option>...]", value)
+		}
+		marshalOptions := protojson.MarshalOptions{UseProtoNames: useProtoNames}
+		for _, option := range strings.Split(optionValues, ",") {
+			switch strings.TrimSpace(option) {
+			case "use-proto-names":
+				marshalOptions.UseProtoNames = true
+			case "use-camel-case":
+				marshalOptions.UseProtoNames = false
+			case "emit-unpopulated":
+				marshalOptions.EmitUnpopulated = true
+			case "use-enum-numbers":
+				marshalOptions.UseEnumNumbers = true
+			default:
+				return nil, fmt.Errorf("invalid plugin JSON option %q of %q, expected one of use-proto-names, use-camel-case, emit-unpopulated or use-enum-numbers", option, value)
+			}
+		}
+		options[pluginName] = marshalOptions
+	}
+	return options, nil
+}
+
+// pluginGatewayMuxes creates the gateway muxes of the plugins having their own
+// JSON marshaling options. The marshaler of a gateway mux is chosen by the
+// content type rather than by the route, so the handlers of such a plugin are
+// registered with a mux of their own, to which the shared mux forwards the
+// requests of the http rules of the plugin services.
+type pluginGatewayMuxes struct {
+	gwmux   *runtime.ServeMux
+	files   *protoregistry.Files
+	newMux  func(marshaler runtime.Marshaler) *runtime.ServeMux
+	options pluginJSONOptions
+
+	mu    sync.Mutex
+	muxes map[string]*runtime.ServeMux
+}
+
+func newPluginGatewayMuxes(gwmux *runtime.ServeMux, files *protoregistry.Files, newMux func(marshaler runtime.Marshaler) *runtime.ServeMux, options pluginJSONOptions) *pluginGatewayMuxes {
+	return &pluginGatewayMuxes{
+		gwmux:   gwmux,
+		files:   files,
+		newMux:  newMux,
+		options: options,
+		muxes:   map[string]*runtime.ServeMux{},
+	}
+}
+
+// muxFor returns the gateway mux of the plugin, which is the shared mux unless
+// the plugin has its own JSON marshaling options. The services of the plugin
+// must be in the registry, which is the case once the plugin is opened.
+func (m *pluginGatewayMuxes) muxFor(pluginName string) (*runtime.ServeMux, error) {
+	options, ok := m.options[pluginName]
+	if !ok {
+		return m.gwmux, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if mux, ok := m.muxes[pluginName]; ok {
+		return mux, nil
+	}
+
+	mux := m.newMux(jsonMarshaler(options))
+	forward := func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		mux.ServeHTTP(w, r)
+	}
+	var err error
+	rangeHTTPRules(m.files, func(method protoreflect.MethodDescriptor, httpMethod, path string) {
+		if err != nil || pluginOfProcedure("/"+string(method.Parent().FullName())+"/"+string(method.Name())) != pluginName {
+			return
+		}
+		if handleErr := m.gwmux.HandlePath(httpMethod, path, forward); handleErr != nil {
+			err = fmt.Errorf("failed to forward the route %q of plugin %q: %w", httpMethod+" "+path, pluginName, handleErr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.muxes[pluginName] = mux
+	return mux, nil
+}
+
+// marshalSemaphore bounds the number of gateway responses marshaled
+// concurrently, so that the memory used by the serialization of large responses
+// is predictable under load, at the cost of the latency of the responses
+// waiting for their turn. A nil semaphore does not limit the marshals.
+type marshalSemaphore chan struct{}
+
+// newMarshalSemaphore returns the semaphore allowing up to max concurrent
+// marshals, or nil when max is 0.
+func newMarshalSemaphore(max int) marshalSemaphore {
+	if max <= 0 {
+		return nil
+	}
+	return make(marshalSemaphore, max)
+}
+
+// limit returns the marshaler whose marshals, including those of the messages
+// of the streamed responses, are limited by the semaphore.
+func (s marshalSemaphore) limit(marshaler runtime.Marshaler) runtime.Marshaler {
+	if s == nil {
+		return marshaler
+	}
+	return &limitedMarshaler{Marshaler: marshaler, sem: s}
+}
+
+// limitedMarshaler is a gateway marshaler whose marshals are limited by a
+// semaphore. The unmarshals of the requests are not limited.
+type limitedMarshaler struct {
+	runtime.Marshaler
+	sem marshalSemaphore
+}
+
+func (m *limitedMarshaler) Marshal(v interface{}) ([]byte, error) {
+	m.sem <- struct{}{}
+	defer func() { <-m.sem }()
+	return m.Marshaler.Marshal(v)
+}
+
+func (m *limitedMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	encoder := m.Marshaler.NewEncoder(w)
+	return runtime.EncoderFunc(func(v interface{}) error {
+		m.sem <- struct{}{}
+		defer func() { <-m.sem }()
+		return encoder.Encode(v)
+	})
+}
+
+// Delimiter is required by the streamed responses, which separate their
+// messages with the delimiter of the marshaler.
+func (m *limitedMarshaler) Delimiter() []byte {
+	if d, ok := m.Marshaler.(runtime.Delimited); ok {
+		return d.Delimiter()
+	}
+	return []byte("\n")
+}
@@ -0,0 +1,304 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	_ "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/plugins/helm/packages/v1alpha1"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestGatewayMarshalerFieldNames(t *testing.T) {
+	testCases := []struct {
+		name          string
+		useProtoNames bool
+		expectedKey   string
+	}{
+		{
+			name:          "uses lowerCamelCase field names by default",
+			useProtoNames: false,
+			expectedKey:   `"nextPageToken"`,
+		},
+		{
+			name:          "uses the original proto field names when requested",
+			useProtoNames: true,
+			expectedKey:   `"next_page_token"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			marshaler := gatewayMarshaler(tc.useProtoNames)
+
+			bytes, err := marshaler.Marshal(&packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse{
+				NextPageToken: "token",
+			})
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			if got, want := string(bytes), tc.expectedKey; !strings.Contains(got, want) {
+				t.Errorf("got: %s, want it to contain: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestParsePluginJSONOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		values        []string
+		useProtoNames bool
+		expected      pluginJSONOptions
+		expectErr     bool
+	}{
+		{
+			name:     "no values",
+			expected: pluginJSONOptions{},
+		},
+		{
+			name:   "options of several plugins",
+			values: []string{"fluxv2.packages=use-proto-names,emit-unpopulated", " helm.packages = use-enum-numbers "},
+			expected: pluginJSONOptions{
+				"fluxv2.packages": {UseProtoNames: true, EmitUnpopulated: true},
+				"helm.packages":   {UseEnumNumbers: true},
+			},
+		},
+		{
+			name:          "options start from the global ones",
+			values:        []string{"fluxv2.packages=emit-unpopulated", "helm.packages=use-camel-case"},
+			useProtoNames: true,
+			expected: pluginJSONOptions{
+				"fluxv2.packages": {UseProtoNames: true, EmitUnpopulated: true},
+				"helm.packages":   {},
+			},
+		},
+		{
+			name:      "missing options",
+			values:    []string{"fluxv2.packages"},
+			expectErr: true,
+		},
+		{
+			name:      "missing plugin name",
+			values:    []string{"=use-proto-names"},
+			expectErr: true,
+		},
+		{
+			name:      "unknown option",
+			values:    []string{"fluxv2.packages=indent"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := parsePluginJSONOptions(tc.values, tc.useProtoNames)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if got, want := options, tc.expected; !cmp.Equal(got, want, cmpopts.IgnoreUnexported(protojson.MarshalOptions{})) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, cmpopts.IgnoreUnexported(protojson.MarshalOptions{})))
+			}
+		})
+	}
+}
+
+// slowMarshaler records the maximum number of concurrent marshals.
+type slowMarshaler struct {
+	runtime.Marshaler
+	running, maxRunning atomic.Int32
+}
+
+func (m *slowMarshaler) Marshal(v interface{}) ([]byte, error) {
+	running := m.running.Add(1)
+	defer m.running.Add(-1)
+	for {
+		maxRunning := m.maxRunning.Load()
+		if running <= maxRunning || m.maxRunning.CompareAndSwap(maxRunning, running) {
+			break
+		}
+	}
+	// Gives the other marshals the time to run concurrently.
+	time.Sleep(10 * time.Millisecond)
+	return m.Marshaler.Marshal(v)
+}
+
+func TestMarshalSemaphore(t *testing.T) {
+	testCases := []struct {
+		name string
+		max  int
+	}{
+		{
+			name: "limits the concurrent marshals",
+			max:  2,
+		},
+		{
+			name: "does not limit the marshals when disabled",
+			max:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			slow := &slowMarshaler{Marshaler: gatewayMarshaler(false)}
+			marshaler := newMarshalSemaphore(tc.max).limit(slow)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					data, err := marshaler.Marshal(&packagesGRPCv1alpha1.Context{Namespace: "default"})
+					if err != nil {
+						t.Errorf("%+v", err)
+						return
+					}
+					if got, want := string(data), `{"namespace":"default"}`; got != want {
+						t.Errorf("got: %s, want: %s", got, want)
+					}
+				}()
+			}
+			wg.Wait()
+
+			maxRunning := slow.maxRunning.Load()
+			if tc.max > 0 && maxRunning > int32(tc.max) {
+				t.Errorf("got: %d concurrent marshals, want at most: %d", maxRunning, tc.max)
+			}
+			if tc.max == 0 && maxRunning < 2 {
+				t.Errorf("got: %d concurrent marshals, want several", maxRunning)
+			}
+		})
+	}
+}
+
+func TestPluginGatewayMuxes(t *testing.T) {
+	newMux := func(marshaler runtime.Marshaler) *runtime.ServeMux {
+		return runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, marshaler))
+	}
+	gwmux := newMux(gatewayMarshaler(false))
+	muxes := newPluginGatewayMuxes(gwmux, protoregistry.GlobalFiles, newMux, pluginJSONOptions{
+		"helm.packages": {UseProtoNames: true},
+	})
+
+	fluxMux, err := muxes.muxFor("fluxv2.packages")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if fluxMux != gwmux {
+		t.Errorf("got a mux of its own for a plugin without JSON options")
+	}
+	helmMux, err := muxes.muxFor("helm.packages")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if helmMux == gwmux {
+		t.Fatalf("got the shared mux for a plugin with JSON options")
+	}
+	if again, err := muxes.muxFor("helm.packages"); err != nil || again != helmMux {
+		t.Errorf("got another mux for the same plugin, err: %v", err)
+	}
+
+	marshal := func(mux *runtime.ServeMux) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			bytes, err := outbound.Marshal(&packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse{NextPageToken: "token"})
+			if err != nil {
+				t.Errorf("%+v", err)
+			}
+			w.Write(bytes)
+		}
+	}
+	// The handlers stand for those registered by the plugins.
+	if err := helmMux.HandlePath(http.MethodGet, "/plugins/helm/packages/v1alpha1/availablepackages", marshal(helmMux)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := gwmux.HandlePath(http.MethodGet, "/core/packages/v1alpha1/availablepackages", marshal(gwmux)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		path        string
+		expectedKey string
+	}{
+		{
+			name:        "plugin with its own JSON options",
+			path:        "/plugins/helm/packages/v1alpha1/availablepackages",
+			expectedKey: `"next_page_token"`,
+		},
+		{
+			name:        "core service with the global JSON options",
+			path:        "/core/packages/v1alpha1/availablepackages",
+			expectedKey: `"nextPageToken"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			gwmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if got, want := w.Code, http.StatusOK; got != want {
+				t.Fatalf("got: %d, want: %d", got, want)
+			}
+			if got, want := w.Body.String(), tc.expectedKey; !strings.Contains(got, want) {
+				t.Errorf("got: %s, want it to contain: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestGatewayMarshalerWritesRawHTTPBodies(t *testing.T) {
+	marshaler := gatewayMarshaler(false)
+	body := &httpbody.HttpBody{
+		ContentType: "application/x-tar",
+		Data:        []byte{0x1f, 0x8b, 0x08},
+	}
+
+	bytes, err := marshaler.Marshal(body)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := bytes, body.Data; !cmp.Equal(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if got, want := marshaler.ContentType(body), body.ContentType; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := marshaler.ContentType(&packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse{}), "application/json"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestGatewayMarshalerUnmarshalsBothFieldNames(t *testing.T) {
+	inputs := []string{
+		`{"nextPageToken": "token"}`,
+		`{"next_page_token": "token"}`,
+	}
+
+	for _, useProtoNames := range []bool{false, true} {
+		marshaler := gatewayMarshaler(useProtoNames)
+		for _, input := range inputs {
+			response := &packagesGRPCv1alpha1.GetAvailablePackageSummariesResponse{}
+			if err := marshaler.Unmarshal([]byte(input), response); err != nil {
+				t.Fatalf("useProtoNames: %t, input: %s, err: %+v", useProtoNames, input, err)
+			}
+			if got, want := response.NextPageToken, "token"; got != want {
+				t.Errorf("useProtoNames: %t, input: %s, got: %q, want: %q", useProtoNames, input, got, want)
+			}
+		}
+	}
+}
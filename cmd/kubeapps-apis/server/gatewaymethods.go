@@ -0,0 +1,77 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gatewayExposedMethods is the allowlist of the methods reachable through the
+// REST gateway, so that the internal or admin methods are only served with the
+// gRPC, gRPC-web and connect protocols. The methods are listed by their full
+// name, or by the full name of their service followed by "/*" for all of its
+// methods. When empty, all the methods are exposed.
+type gatewayExposedMethods map[string]bool
+
+// newGatewayExposedMethods parses the methods exposed through the gateway, such as
+// "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+// or "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/*".
+func newGatewayExposedMethods(values []string) (gatewayExposedMethods, error) {
+	methods := gatewayExposedMethods{}
+	for _, value := range values {
+		service, method, found := strings.Cut(strings.TrimPrefix(value, "/"), "/")
+		if !strings.HasPrefix(value, "/") || !found || service == "" || method == "" || strings.Contains(method, "/") {
+			return nil, fmt.Errorf("invalid gateway exposed method %q, expected /<service full name>/<method name> or /<service full name>/*", value)
+		}
+		methods[value] = true
+	}
+	return methods, nil
+}
+
+// exposed returns whether the method, given by its full name, is reachable
+// through the gateway.
+func (m gatewayExposedMethods) exposed(procedure string) bool {
+	if len(m) == 0 || m[procedure] {
+		return true
+	}
+	service := procedure[:strings.LastIndex(procedure, "/")+1]
+	return m[service+"*"]
+}
+
+// check returns a NotFound error, which the gateway reports with the 404 status
+// of an unknown route, if the method is not exposed.
+func (m gatewayExposedMethods) check(procedure string) error {
+	if !m.exposed(procedure) {
+		return status.Errorf(codes.NotFound, "The method %q is not exposed through the REST gateway", procedure)
+	}
+	return nil
+}
+
+// dialOptions returns the interceptors of the gateway's calls to the handlers,
+// failing the calls of the methods which are not exposed before they are sent.
+func (m gatewayExposedMethods) dialOptions() []grpc.DialOption {
+	if len(m) == 0 {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			if err := m.check(method); err != nil {
+				return err
+			}
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			if err := m.check(method); err != nil {
+				return nil, err
+			}
+			return streamer(ctx, desc, cc, method, opts...)
+		}),
+	}
+}
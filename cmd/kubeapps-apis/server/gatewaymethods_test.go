@@ -0,0 +1,109 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestGatewayExposedMethods(t *testing.T) {
+	summariesMethod := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+
+	testCases := []struct {
+		name          string
+		values        []string
+		procedure     string
+		expectErr     bool
+		expectExposed bool
+	}{
+		{
+			name:          "exposes all the methods by default",
+			procedure:     "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/DeletePackageRepository",
+			expectExposed: true,
+		},
+		{
+			name:          "exposes a listed method",
+			values:        []string{summariesMethod},
+			procedure:     summariesMethod,
+			expectExposed: true,
+		},
+		{
+			name:      "does not expose the other methods",
+			values:    []string{summariesMethod},
+			procedure: "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage",
+		},
+		{
+			name:          "exposes all the methods of a listed service",
+			values:        []string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/*"},
+			procedure:     "/kubeappsapis.core.packages.v1alpha1.PackagesService/DeleteInstalledPackage",
+			expectExposed: true,
+		},
+		{
+			name:      "does not expose the methods of the other services",
+			values:    []string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/*"},
+			procedure: "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/DeletePackageRepository",
+		},
+		{
+			name:      "rejects a method name without its service",
+			values:    []string{"GetAvailablePackageSummaries"},
+			expectErr: true,
+		},
+		{
+			name:      "rejects a service without a method name",
+			values:    []string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exposed, err := newGatewayExposedMethods(tc.values)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if tc.expectErr {
+				return
+			}
+			if got, want := exposed.exposed(tc.procedure), tc.expectExposed; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+			// The gateway reports the methods which are not exposed as not found.
+			err = exposed.check(tc.procedure)
+			if got, want := status.Code(err), codes.OK; tc.expectExposed && got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+			if got, want := status.Code(err), codes.NotFound; !tc.expectExposed && got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRouteTableGatewayExposedMethods(t *testing.T) {
+	exposed, err := newGatewayExposedMethods([]string{"/kubeappsapis.core.packages.v1alpha1.PackagesService/*"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	routes := newRouteTable(http.NewServeMux(), protoregistry.GlobalFiles, true, exposed)
+
+	found := false
+	for _, r := range routes.routes() {
+		if r.Mux != gatewayMuxName {
+			continue
+		}
+		if !strings.HasPrefix(r.Handler, "kubeappsapis.core.packages.v1alpha1.PackagesService.") {
+			t.Errorf("got: %+v, want only the routes of the exposed methods", r)
+		}
+		found = true
+	}
+	if !found {
+		t.Errorf("got: no gateway route, want the routes of the packages service")
+	}
+}
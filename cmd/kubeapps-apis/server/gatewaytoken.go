@@ -0,0 +1,49 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// gatewayTokenMetadataKey is the metadata key of the token attached by the
+// gateway to its calls. The authorization metadata is not used, since it
+// carries the token of the user forwarded by the gateway.
+const gatewayTokenMetadataKey = "x-gateway-authorization"
+
+// tokenSourceCredentials are the per-RPC credentials attaching a bearer token,
+// obtained from the token source for each call, to the calls of the gateway.
+type tokenSourceCredentials struct {
+	tokenSource func() (string, error)
+}
+
+// newFileTokenSource returns a token source reading the token from a file for
+// each call, so that a rotated token, such as a projected service account
+// token, is picked up.
+func newFileTokenSource(path string) func() (string, error) {
+	return func() (string, error) {
+		token, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the gateway token: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+}
+
+func (c tokenSourceCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.tokenSource()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{gatewayTokenMetadataKey: "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity does not require TLS, since the gateway connects to
+// the server over the loopback interface.
+func (c tokenSourceCredentials) RequireTransportSecurity() bool {
+	return false
+}
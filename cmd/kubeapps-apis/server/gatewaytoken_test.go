@@ -0,0 +1,36 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenSourceCredentials(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	creds := tokenSourceCredentials{tokenSource: newFileTokenSource(tokenFile)}
+
+	if _, err := creds.GetRequestMetadata(context.Background()); err == nil {
+		t.Errorf("got: nil, want: an error for a missing token file")
+	}
+
+	// The token is read again for each call, so that it can be rotated.
+	for _, token := range []string{"foo", "bar"} {
+		if err := os.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		got, err := creds.GetRequestMetadata(context.Background())
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if want := map[string]string{gatewayTokenMetadataKey: "Bearer " + token}; !cmp.Equal(got, want) {
+			t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"golang.org/x/net/http2"
+)
+
+// minHTTP2WindowSize is the smallest flow-control window allowed by HTTP/2.
+const minHTTP2WindowSize = 65535
+
+// validateHTTP2WindowSizes checks the configured HTTP/2 flow-control windows,
+// which the HTTP/2 and gRPC libraries would otherwise silently ignore when too small.
+func validateHTTP2WindowSizes(serveOpts core.ServeOptions) error {
+	for name, size := range map[string]int32{
+		"initial window size":            serveOpts.HTTP2InitialWindowSize,
+		"initial connection window size": serveOpts.HTTP2InitialConnWindowSize,
+	} {
+		if size != 0 && size < minHTTP2WindowSize {
+			return fmt.Errorf("invalid HTTP/2 %s %d, expected 0 or at least %d bytes", name, size, minHTTP2WindowSize)
+		}
+	}
+	return nil
+}
+
+// newHTTP2Server returns the HTTP/2 server of the connections, with or without
+// TLS. Its flow-control windows bound how much a client can send before waiting
+// for an acknowledgment, so that larger windows speed up the requests over
+// links with a high latency.
+func newHTTP2Server(serveOpts core.ServeOptions) *http2.Server {
+	return &http2.Server{
+		IdleTimeout:                  serveOpts.ConnectionIdleTimeout,
+		MaxUploadBufferPerStream:     serveOpts.HTTP2InitialWindowSize,
+		MaxUploadBufferPerConnection: serveOpts.HTTP2InitialConnWindowSize,
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"golang.org/x/net/http2"
+)
+
+func TestNewHTTPServerWindowSizes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server := newHTTPServer(listener.Addr().String(), http.NotFoundHandler(), core.ServeOptions{
+		ConnectionIdleTimeout:      time.Minute,
+		HTTP2InitialWindowSize:     1 << 21,
+		HTTP2InitialConnWindowSize: 1 << 22,
+	})
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.Errorf("%+v", err)
+		}
+	}()
+	defer server.Close()
+
+	// An h2c client with prior knowledge reads the settings of the server.
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := io.WriteString(conn, http2.ClientPreface); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	framer := http2.NewFramer(conn, conn)
+	if err := framer.WriteSettings(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var streamWindowSize, connWindowIncrement uint32
+	for streamWindowSize == 0 || connWindowIncrement == 0 {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		switch f := frame.(type) {
+		case *http2.SettingsFrame:
+			if size, ok := f.Value(http2.SettingInitialWindowSize); ok {
+				streamWindowSize = size
+			}
+		case *http2.WindowUpdateFrame:
+			if f.StreamID == 0 {
+				connWindowIncrement = f.Increment
+			}
+		}
+	}
+	if got, want := streamWindowSize, uint32(1<<21); got != want {
+		t.Errorf("got stream window size: %d, want: %d", got, want)
+	}
+	// The connection window is raised from the initial window of HTTP/2.
+	if got, want := connWindowIncrement, uint32(1<<22-minHTTP2WindowSize); got != want {
+		t.Errorf("got connection window increment: %d, want: %d", got, want)
+	}
+}
+
+func TestValidateHTTP2WindowSizes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		serveOpts     core.ServeOptions
+		expectedError bool
+	}{
+		{
+			name: "default window sizes",
+		},
+		{
+			name:      "large window sizes",
+			serveOpts: core.ServeOptions{HTTP2InitialWindowSize: 1 << 20, HTTP2InitialConnWindowSize: minHTTP2WindowSize},
+		},
+		{
+			name:          "stream window size too small",
+			serveOpts:     core.ServeOptions{HTTP2InitialWindowSize: 1024},
+			expectedError: true,
+		},
+		{
+			name:          "negative connection window size",
+			serveOpts:     core.ServeOptions{HTTP2InitialConnWindowSize: -1},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateHTTP2WindowSizes(tc.serveOpts)
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Errorf("got error: %v, want error: %t", err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// tokenReviewFunc reviews a bearer token with the Kubernetes API server.
+type tokenReviewFunc func(ctx context.Context, token string) (*authenticationv1.TokenReviewStatus, error)
+
+// callerIdentityReviewer is a connect interceptor which validates the bearer
+// token of the requests with a TokenReview, adding the authenticated identity
+// to the request context so that plugins can impersonate the caller. Requests
+// without a bearer token are passed through without an identity.
+type callerIdentityReviewer struct {
+	review tokenReviewFunc
+}
+
+// newCallerIdentityReviewer returns a reviewer using the service account of
+// the server to review the tokens.
+func newCallerIdentityReviewer(timeout time.Duration) (*callerIdentityReviewer, error) {
+	restConfig, err := core.InClusterConfig(timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve in cluster configuration: %w", err)
+	}
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve clientset: %w", err)
+	}
+	return &callerIdentityReviewer{
+		review: func(ctx context.Context, token string) (*authenticationv1.TokenReviewStatus, error) {
+			review, err := clientSet.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+				Spec: authenticationv1.TokenReviewSpec{Token: token},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return &review.Status, nil
+		},
+	}, nil
+}
+
+func (r *callerIdentityReviewer) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, err := r.withCallerIdentity(ctx, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (r *callerIdentityReviewer) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (r *callerIdentityReviewer) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := r.withCallerIdentity(ctx, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// withCallerIdentity returns a copy of ctx carrying the identity authenticated
+// for the bearer token of the request, failing if the token is not valid.
+func (r *callerIdentityReviewer) withCallerIdentity(ctx context.Context, header http.Header) (context.Context, error) {
+	token, found := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return ctx, nil
+	}
+	status, err := r.review(ctx, token)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("Unable to review the token of the request: %w", err))
+	}
+	if !status.Authenticated || status.User.Username == "" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("The token of the request is not valid: %s", status.Error))
+	}
+	id := &core.CallerIdentity{
+		Username: status.User.Username,
+		UID:      status.User.UID,
+		Groups:   status.User.Groups,
+	}
+	if len(status.User.Extra) > 0 {
+		id.Extra = map[string][]string{}
+		for k, v := range status.User.Extra {
+			id.Extra[k] = v
+		}
+	}
+	return core.ContextWithCallerIdentity(ctx, id), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/protobuf/types/known/emptypb"
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+func TestCallerIdentityReviewer(t *testing.T) {
+	reviewer := &callerIdentityReviewer{
+		review: func(ctx context.Context, token string) (*authenticationv1.TokenReviewStatus, error) {
+			if token != "valid" {
+				return &authenticationv1.TokenReviewStatus{Error: "invalid bearer token"}, nil
+			}
+			return &authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "jane",
+					Groups:   []string{"developers"},
+				},
+			}, nil
+		},
+	}
+
+	var gotIdentity *core.CallerIdentity
+	procedure := "/test.v1.TestService/GetThing"
+	ts := serveTestProcedure(t, procedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			gotIdentity, _ = core.CallerIdentityFromContext(ctx)
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(reviewer),
+	)
+
+	testCases := []struct {
+		name             string
+		authorization    string
+		expectedCode     connect.Code
+		expectedIdentity *core.CallerIdentity
+	}{
+		{
+			name: "passes requests without a token through without identity",
+		},
+		{
+			name:          "adds the identity of a valid token",
+			authorization: "Bearer valid",
+			expectedIdentity: &core.CallerIdentity{
+				Username: "jane",
+				Groups:   []string{"developers"},
+			},
+		},
+		{
+			name:          "rejects an invalid token",
+			authorization: "Bearer forged",
+			expectedCode:  connect.CodeUnauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotIdentity = nil
+			client := connect.NewClient[emptypb.Empty, emptypb.Empty](ts.Client(), ts.URL+procedure)
+			req := connect.NewRequest(&emptypb.Empty{})
+			if tc.authorization != "" {
+				req.Header().Set("Authorization", tc.authorization)
+			}
+			_, err := client.CallUnary(context.Background(), req)
+			if got, want := connect.CodeOf(err), tc.expectedCode; err != nil && got != want || err == nil && want != 0 {
+				t.Fatalf("got: %+v, want: %+v, err: %+v", got, want, err)
+			}
+			if got, want := gotIdentity, tc.expectedIdentity; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	log "k8s.io/klog/v2"
+)
+
+// interceptorChain holds the interceptors of the connect handlers, including
+// those registered by the plugins. They are chained in a fixed order, from the
+// outermost, which sees the request first and the response last:
+//
+//   - recovery, so that a panic in any other interceptor is recovered,
+//   - target, tagging the context with the cluster and namespace of the
+//     request, so that all the following interceptors read the same values,
+//   - metrics, logging and last errors, so that they cover every request,
+//     including those rejected by the following interceptors,
+//   - API version, so that the requests of the incompatible clients fail
+//     before reaching the plugins,
+//   - required auth, rejecting the anonymous requests before their token, if
+//     any, is reviewed,
+//   - auth, reviewing the caller identity, which is needed for auditing,
+//   - audit, so that the requests of the disabled methods and the writes
+//     rejected in maintenance mode are still audited,
+//   - disabled methods,
+//   - maintenance,
+//   - validation of the requests with validation rules,
+//   - daily quota, so that only the requests which are let through count,
+//   - timeout, so that only the handling of the request counts towards it.
+//
+// The nil interceptors, which are disabled, are skipped.
+type interceptorChain struct {
+	recovery    connect.Interceptor
+	target      connect.Interceptor
+	metrics     connect.Interceptor
+	logging     connect.Interceptor
+	lastErrors  connect.Interceptor
+	apiVersion  connect.Interceptor
+	requireAuth connect.Interceptor
+	auth        connect.Interceptor
+	audit       connect.Interceptor
+	disabled    connect.Interceptor
+	maintenance connect.Interceptor
+	validation  connect.Interceptor
+	quota       connect.Interceptor
+	timeout     connect.Interceptor
+}
+
+// newInterceptorChain returns the interceptors configured by the serve options.
+func newInterceptorChain(serveOpts core.ServeOptions, trustedProxies core.TrustedProxies, metrics *metrics, maintenance *maintenanceMode, targets *requestTargets, lastErrors *lastErrors) (interceptorChain, error) {
+	chain := interceptorChain{
+		recovery:    recoverer{},
+		target:      targets,
+		metrics:     metrics,
+		lastErrors:  lastErrors,
+		maintenance: maintenance.interceptor(),
+		validation:  requestValidator{},
+	}
+
+	requestLogger, err := newRequestLogger(serveOpts, trustedProxies)
+	if err != nil {
+		return chain, fmt.Errorf("failed to create the request logger: %w", err)
+	}
+	chain.logging = requestLogger
+
+	apiVersions, err := newAPIVersionRange(serveOpts.MinAPIVersion, serveOpts.MaxAPIVersion)
+	if err != nil {
+		return chain, fmt.Errorf("failed to parse the supported API versions: %w", err)
+	}
+	if apiVersions != nil {
+		chain.apiVersion = apiVersions
+	}
+
+	if serveOpts.RequireAuth {
+		requireAuth, err := newRequiredAuth(serveOpts.PublicMethods)
+		if err != nil {
+			return chain, fmt.Errorf("failed to parse the public methods: %w", err)
+		}
+		chain.requireAuth = requireAuth
+	}
+
+	if serveOpts.ImpersonateUsers {
+		reviewer, err := newCallerIdentityReviewer(serveOpts.KubernetesClientTimeout)
+		if err != nil {
+			return chain, fmt.Errorf("failed to create the caller identity reviewer: %w", err)
+		}
+		chain.auth = reviewer
+	}
+
+	if serveOpts.AuditLogSink != "" {
+		auditLogger, err := newAuditLogger(serveOpts.AuditLogSink, serveOpts.AuditLogMethods, trustedProxies)
+		if err != nil {
+			return chain, fmt.Errorf("failed to create the audit logger: %w", err)
+		}
+		chain.audit = auditLogger
+	}
+
+	disabled, err := newDisabledMethods(serveOpts.DisabledMethods)
+	if err != nil {
+		return chain, fmt.Errorf("failed to parse the disabled methods: %w", err)
+	}
+	if len(disabled) > 0 {
+		chain.disabled = disabled
+	}
+
+	if serveOpts.DailyQuota > 0 {
+		if !serveOpts.ImpersonateUsers && serveOpts.TLSClientCAFile == "" {
+			return chain, fmt.Errorf("the daily quota requires the identity of the callers, with the impersonation of the users or a client CA file")
+		}
+		chain.quota = newDailyQuota(serveOpts, metrics.quotaStoreErrors)
+	}
+
+	timeouts, err := newRequestTimeouts(serveOpts.RequestTimeout, serveOpts.PluginTimeouts)
+	if err != nil {
+		return chain, fmt.Errorf("failed to parse the plugin timeouts: %w", err)
+	}
+	if timeouts.enabled() {
+		chain.timeout = timeouts
+	}
+	return chain, nil
+}
+
+// ordered returns the enabled interceptors, from the outermost.
+func (c interceptorChain) ordered() []connect.Interceptor {
+	interceptors := []connect.Interceptor{}
+	for _, interceptor := range []connect.Interceptor{c.recovery, c.target, c.metrics, c.logging, c.lastErrors, c.apiVersion, c.requireAuth, c.auth, c.audit, c.disabled, c.maintenance, c.validation, c.quota, c.timeout} {
+		if interceptor != nil {
+			interceptors = append(interceptors, interceptor)
+		}
+	}
+	return interceptors
+}
+
+// close closes the interceptors holding resources, such as the audit log file,
+// once the server is shut down.
+func (c interceptorChain) close() {
+	for _, interceptor := range c.ordered() {
+		if closer, ok := interceptor.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Errorf("Unable to close the interceptor %T: %v", interceptor, err)
+			}
+		}
+	}
+}
+
+// recoverer is a connect interceptor recovering from the panics of the
+// handlers, and of the inner interceptors, failing the request with an
+// Internal error rather than aborting the connection. As for net/http, the
+// http.ErrAbortHandler panics are not recovered.
+type recoverer struct{}
+
+func (recoverer) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (res connect.AnyResponse, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(req.Spec().Procedure, r)
+			}
+		}()
+		return next(ctx, req)
+	}
+}
+
+func (recoverer) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (recoverer) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(conn.Spec().Procedure, r)
+			}
+		}()
+		return next(ctx, conn)
+	}
+}
+
+// recoveredError logs a recovered panic with its stack, returning the error of
+// the request.
+func recoveredError(procedure string, r any) error {
+	if r == http.ErrAbortHandler {
+		panic(r)
+	}
+	log.Errorf("Recovered from a panic while handling %q: %v\n%s", procedure, r, debug.Stack())
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("Internal error while handling %q", procedure))
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+)
+
+func TestInterceptorChainOrder(t *testing.T) {
+	calls := []string{}
+	recording := func(name string) connect.Interceptor {
+		return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+			return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+				calls = append(calls, name)
+				return next(ctx, req)
+			}
+		})
+	}
+	chain := interceptorChain{
+		recovery:    recoverer{},
+		target:      recording("target"),
+		metrics:     recording("metrics"),
+		logging:     recording("logging"),
+		lastErrors:  recording("last errors"),
+		apiVersion:  recording("api version"),
+		requireAuth: recording("require auth"),
+		auth:        recording("auth"),
+		audit:       recording("audit"),
+		disabled:    recording("disabled"),
+		maintenance: recording("maintenance"),
+		validation:  recording("validation"),
+		quota:       recording("quota"),
+		timeout:     recording("timeout"),
+	}
+
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	ts := serveTestProcedure(t, procedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest]) (*connect.Response[packagesGRPCv1alpha1.GetInstalledPackageDetailResponse], error) {
+			panic("boom")
+		},
+		newHandlerOptions(core.ServeOptions{}, chain.ordered())...,
+	)
+
+	client := connect.NewClient[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest, packagesGRPCv1alpha1.GetInstalledPackageDetailResponse](ts.Client(), ts.URL+procedure)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetInstalledPackageDetailRequest{}))
+
+	// The panic of the handler is recovered by the outermost interceptor.
+	if got, want := connect.CodeOf(err), connect.CodeInternal; got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if got, want := calls, []string{"target", "metrics", "logging", "last errors", "api version", "require auth", "auth", "audit", "disabled", "maintenance", "validation", "quota", "timeout"}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	// The disabled interceptors are skipped.
+	if got, want := len(interceptorChain{recovery: recoverer{}, timeout: recording("timeout")}.ordered()), 2; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strings"
+
+	log "k8s.io/klog/v2"
+)
+
+// landingPageTemplate is the page served to the browsers opening the root of
+// the server, which otherwise get an error from the gateway.
+var landingPageTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Kubeapps APIs</title></head>
+<body>
+<h1>Kubeapps APIs</h1>
+<p>Version: {{.Version}}</p>
+<ul>
+{{- if .RESTGateway}}
+<li><a href="docs">API documentation</a></li>
+<li><a href="openapi.json">OpenAPI document</a></li>
+{{- end}}
+<li><a href="livez">Liveness</a></li>
+<li><a href="metrics">Metrics</a></li>
+</ul>
+</body>
+</html>
+`))
+
+// withLandingPage serves a landing page, with the version of the server and
+// the links to its documentation and probes, to the browsers requesting the
+// root of the server. The other requests, such as the gRPC calls and the REST
+// requests, are passed to the next handler. The links are relative, so that
+// they follow the prefix of a reverse proxy.
+func withLandingPage(next http.Handler, version string, restGateway bool) http.Handler {
+	page := bytes.Buffer{}
+	if err := landingPageTemplate.Execute(&page, struct {
+		Version     string
+		RESTGateway bool
+	}{version, restGateway}); err != nil {
+		log.Errorf("Unable to render the landing page: %v", err)
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isBrowserRootRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write(page.Bytes())
+	})
+}
+
+// isBrowserRootRequest returns whether the request is the one of a browser
+// opening the root of the server: a GET request of the root accepting HTML,
+// which is neither a gRPC call nor a REST request of the gateway.
+func isBrowserRootRequest(r *http.Request) bool {
+	if r.URL.Path != "/" || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLandingPage(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		headers     map[string]string
+		restGateway bool
+		expectPage  bool
+		expectDocs  bool
+	}{
+		{
+			name:        "it serves the page to a browser opening the root",
+			method:      http.MethodGet,
+			path:        "/",
+			headers:     map[string]string{"Accept": "text/html,application/xhtml+xml"},
+			restGateway: true,
+			expectPage:  true,
+			expectDocs:  true,
+		},
+		{
+			name:       "it does not link the docs without the gateway",
+			method:     http.MethodGet,
+			path:       "/",
+			headers:    map[string]string{"Accept": "text/html"},
+			expectPage: true,
+		},
+		{
+			name:    "it passes the JSON requests to the next handler",
+			method:  http.MethodGet,
+			path:    "/",
+			headers: map[string]string{"Accept": "application/json"},
+		},
+		{
+			name:    "it passes the gRPC calls to the next handler",
+			method:  http.MethodGet,
+			path:    "/",
+			headers: map[string]string{"Accept": "text/html", "Content-Type": "application/grpc"},
+		},
+		{
+			name:    "it passes the other paths to the next handler",
+			method:  http.MethodGet,
+			path:    "/core/packages/v1alpha1/availablepackages",
+			headers: map[string]string{"Accept": "text/html"},
+		},
+		{
+			name:    "it passes the other methods to the next handler",
+			method:  http.MethodPost,
+			path:    "/",
+			headers: map[string]string{"Accept": "text/html"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			withLandingPage(next, "v1.2.3", tc.restGateway).ServeHTTP(w, req)
+
+			if !tc.expectPage {
+				if got, want := w.Code, http.StatusTeapot; got != want {
+					t.Errorf("got: %d, want: %d", got, want)
+				}
+				return
+			}
+			if got, want := w.Code, http.StatusOK; got != want {
+				t.Fatalf("got: %d, want: %d", got, want)
+			}
+			if got, want := w.Header().Get("Content-Type"), "text/html; charset=utf-8"; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+			body := w.Body.String()
+			if !strings.Contains(body, "v1.2.3") {
+				t.Errorf("got: %q, want the version", body)
+			}
+			if got, want := strings.Contains(body, `href="docs"`), tc.expectDocs; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	log "k8s.io/klog/v2"
+)
+
+// lastErrorsMaxMethods bounds the number of methods whose last error is kept,
+// the method whose last error is the oldest being evicted first.
+const lastErrorsMaxMethods = 256
+
+// lastError is the most recent error of a method, along with the number of
+// errors of the method.
+type lastError struct {
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+	Count   int       `json:"count"`
+}
+
+// lastErrors is a connect interceptor recording the last error of each method,
+// by procedure, so that the recent failures can be triaged with the admin
+// endpoint without searching the logs.
+type lastErrors struct {
+	max int
+	now func() time.Time
+
+	mu     sync.Mutex
+	errors map[string]*lastError
+}
+
+func newLastErrors() *lastErrors {
+	return &lastErrors{
+		max:    lastErrorsMaxMethods,
+		now:    time.Now,
+		errors: map[string]*lastError{},
+	}
+}
+
+// record records the error of a call to the procedure, if any.
+func (l *lastErrors) record(procedure string, err error) {
+	if err == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	last, ok := l.errors[procedure]
+	if !ok {
+		if len(l.errors) >= l.max {
+			l.evictOldest()
+		}
+		last = &lastError{}
+		l.errors[procedure] = last
+	}
+	last.Code = connect.CodeOf(err).String()
+	last.Message = err.Error()
+	last.Time = l.now()
+	last.Count++
+}
+
+// evictOldest removes the method whose last error is the oldest.
+func (l *lastErrors) evictOldest() {
+	oldest := ""
+	for procedure, last := range l.errors {
+		if oldest == "" || last.Time.Before(l.errors[oldest].Time) {
+			oldest = procedure
+		}
+	}
+	delete(l.errors, oldest)
+}
+
+// snapshot returns a copy of the last errors by procedure, resetting them if
+// requested.
+func (l *lastErrors) snapshot(reset bool) map[string]lastError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	errors := make(map[string]lastError, len(l.errors))
+	for procedure, last := range l.errors {
+		errors[procedure] = *last
+	}
+	if reset {
+		l.errors = map[string]*lastError{}
+	}
+	return errors
+}
+
+func (l *lastErrors) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		res, err := next(ctx, req)
+		l.record(req.Spec().Procedure, err)
+		return res, err
+	}
+}
+
+func (l *lastErrors) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (l *lastErrors) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		err := next(ctx, conn)
+		l.record(conn.Spec().Procedure, err)
+		return err
+	}
+}
+
+// ServeHTTP reports the last error of each method, by procedure, with GET
+// requests. With the "reset=true" query parameter, the errors are reset once
+// reported.
+func (l *lastErrors) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reset := false
+	if value := r.URL.Query().Get("reset"); value != "" {
+		var err error
+		if reset, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "Invalid reset parameter, expected true or false", http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(l.snapshot(reset)); err != nil {
+		log.Errorf("Unable to write the last errors: %v", err)
+	}
+}
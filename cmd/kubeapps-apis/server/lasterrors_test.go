@@ -0,0 +1,67 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLastErrors(t *testing.T) {
+	summaries := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+	detail := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail"
+	versions := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageVersions"
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := newLastErrors()
+	l.max = 2
+	l.now = func() time.Time { return now }
+
+	l.record(summaries, connect.NewError(connect.CodeUnavailable, fmt.Errorf("repository unreachable")))
+	l.record(summaries, nil)
+	now = now.Add(time.Minute)
+	l.record(detail, connect.NewError(connect.CodeNotFound, fmt.Errorf("package not found")))
+	now = now.Add(time.Minute)
+	l.record(summaries, connect.NewError(connect.CodeInternal, fmt.Errorf("boom")))
+	now = now.Add(time.Minute)
+	// The method whose last error is the oldest is evicted.
+	l.record(versions, connect.NewError(connect.CodeNotFound, fmt.Errorf("package not found")))
+
+	expected := map[string]lastError{
+		summaries: {Code: "internal", Message: "internal: boom", Time: time.Date(2023, 6, 1, 12, 2, 0, 0, time.UTC), Count: 2},
+		versions:  {Code: "not_found", Message: "not_found: package not found", Time: time.Date(2023, 6, 1, 12, 3, 0, 0, time.UTC), Count: 1},
+	}
+
+	w := httptest.NewRecorder()
+	l.ServeHTTP(w, httptest.NewRequest(http.MethodGet, adminPathPrefix+"errors", nil))
+	got := map[string]lastError{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !cmp.Equal(got, expected) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(expected, got))
+	}
+
+	// The errors are only reset when requested.
+	w = httptest.NewRecorder()
+	l.ServeHTTP(w, httptest.NewRequest(http.MethodGet, adminPathPrefix+"errors?reset=true", nil))
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	if got := l.snapshot(false); len(got) != 0 {
+		t.Errorf("got: %v, want: no errors once reset", got)
+	}
+
+	w = httptest.NewRecorder()
+	l.ServeHTTP(w, httptest.NewRequest(http.MethodPost, adminPathPrefix+"errors", nil))
+	if got, want := w.Code, http.StatusMethodNotAllowed; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+}
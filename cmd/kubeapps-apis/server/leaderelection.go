@@ -0,0 +1,96 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	log "k8s.io/klog/v2"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// startLeaderElection returns a channel which is closed once this replica is
+// elected as leader. If leader election is disabled, every replica is considered
+// a leader and the returned channel is already closed. Losing the lease is
+// reported to the supervisor.
+func startLeaderElection(ctx context.Context, serveOpts core.ServeOptions, sup *supervisor) (<-chan struct{}, error) {
+	elected := make(chan struct{})
+	if !serveOpts.EnableLeaderElection {
+		close(elected)
+		return elected, nil
+	}
+
+	namespace := serveOpts.LeaderElectionNamespace
+	if namespace == "" {
+		nsBytes, err := os.ReadFile(serviceAccountNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the leader election namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(nsBytes))
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine the leader election identity: %w", err)
+	}
+
+	restConfig, err := core.InClusterConfig(serveOpts.KubernetesClientTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve in cluster configuration: %w", err)
+	}
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve clientset: %w", err)
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      serveOpts.LeaderElectionLeaseName,
+				Namespace: namespace,
+			},
+			Client:     clientSet.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		},
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				log.Infof("Elected as leader with the lease %s/%s", namespace, serveOpts.LeaderElectionLeaseName)
+				close(elected)
+			},
+			OnStoppedLeading: func() {
+				// The background work started while leading cannot be safely
+				// stopped, so shut down and let a new replica wait for the lease.
+				if ctx.Err() == nil {
+					sup.fail(fmt.Errorf("lost the leader election lease %s/%s", namespace, serveOpts.LeaderElectionLeaseName))
+				}
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure leader election: %w", err)
+	}
+
+	log.Infof("Waiting for the leader election lease %s/%s as %q", namespace, serveOpts.LeaderElectionLeaseName, identity)
+	go elector.Run(ctx)
+	return elected, nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+)
+
+func TestStartLeaderElectionDisabled(t *testing.T) {
+	elected, err := startLeaderElection(context.Background(), core.ServeOptions{}, newSupervisor())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	select {
+	case <-elected:
+	default:
+		t.Errorf("expected every replica to be a leader when leader election is disabled")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "k8s.io/klog/v2"
+)
+
+// logLevel changes the klog verbosity at runtime, resetting it to the initial
+// verbosity after a while so that verbose logging is not left enabled. Every
+// change restarts the reset timer.
+type logLevel struct {
+	mu           sync.Mutex
+	initialLevel string
+	currentLevel string
+	resetAfter   time.Duration
+	resetAt      time.Time
+	timer        *time.Timer
+	// generation identifies the latest change, so that the timer of a previous
+	// change which fired concurrently does not reset the latest one.
+	generation int
+	// set sets the klog verbosity, which is the "v" flag registered by klog.
+	set func(level string) error
+}
+
+func newLogLevel(resetAfter time.Duration) *logLevel {
+	initialLevel := "0"
+	if f := flag.Lookup("v"); f != nil {
+		initialLevel = f.Value.String()
+	}
+	return &logLevel{
+		initialLevel: initialLevel,
+		currentLevel: initialLevel,
+		resetAfter:   resetAfter,
+		set: func(level string) error {
+			return flag.Set("v", level)
+		},
+	}
+}
+
+// setLevel sets the verbosity, scheduling a reset to the initial verbosity.
+func (l *logLevel) setLevel(level int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.set(strconv.Itoa(level)); err != nil {
+		return err
+	}
+	l.currentLevel = strconv.Itoa(level)
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	l.generation++
+	generation := l.generation
+	l.resetAt = time.Now().Add(l.resetAfter)
+	l.timer = time.AfterFunc(l.resetAfter, func() { l.reset(generation) })
+	log.Infof("Log verbosity set to %d until %s", level, l.resetAt.Format(time.RFC3339))
+	return nil
+}
+
+// setInitialLevel changes the verbosity to which the log level is reset, which
+// also becomes the current verbosity unless a temporary change is in effect.
+func (l *logLevel) setInitialLevel(level int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.initialLevel = strconv.Itoa(level)
+	if l.timer != nil {
+		return nil
+	}
+	if err := l.set(l.initialLevel); err != nil {
+		return err
+	}
+	l.currentLevel = l.initialLevel
+	return nil
+}
+
+// reset sets the verbosity back to the initial verbosity, unless the verbosity
+// was changed again since the given generation.
+func (l *logLevel) reset(generation int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if generation != l.generation {
+		return
+	}
+	if err := l.set(l.initialLevel); err != nil {
+		log.Errorf("Unable to reset the log verbosity to %s: %v", l.initialLevel, err)
+		return
+	}
+	l.currentLevel = l.initialLevel
+	l.timer = nil
+	l.resetAt = time.Time{}
+	log.Infof("Log verbosity reset to %s", l.initialLevel)
+}
+
+// ServeHTTP reports the verbosity with GET requests and changes it with PUT
+// requests such as `PUT /admin/loglevel?level=5`.
+func (l *logLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		level, err := strconv.Atoi(r.URL.Query().Get("level"))
+		if err != nil || level < 0 {
+			http.Error(w, "The query parameter 'level' must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		if err := l.setLevel(level); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	l.mu.Lock()
+	response := map[string]string{"level": l.currentLevel}
+	if !l.resetAt.IsZero() {
+		response["resetAt"] = l.resetAt.Format(time.RFC3339)
+	}
+	l.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Unable to write the log level response: %v", err)
+	}
+}
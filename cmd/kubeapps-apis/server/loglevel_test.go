@@ -0,0 +1,83 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLogLevelIsResetAfterADuration(t *testing.T) {
+	levels := make(chan string, 3)
+	l := &logLevel{
+		initialLevel: "3",
+		currentLevel: "3",
+		resetAfter:   50 * time.Millisecond,
+		set: func(level string) error {
+			levels <- level
+			return nil
+		},
+	}
+	handler := newAdminHandler("secret", map[string]http.Handler{"loglevel": l})
+
+	for _, level := range []string{"5", "6"} {
+		req := httptest.NewRequest(http.MethodPut, adminPathPrefix+"loglevel?level="+level, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if got, want := rec.Code, http.StatusOK; got != want {
+			t.Fatalf("got: %d, want: %d", got, want)
+		}
+	}
+
+	// The second change restarts the timer, so the verbosity is reset only once.
+	for _, want := range []string{"5", "6", "3"} {
+		select {
+		case got := <-levels:
+			if got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for the verbosity to be set to %q", want)
+		}
+	}
+	select {
+	case got := <-levels:
+		t.Errorf("unexpected verbosity change to %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestLogLevelSetInitialLevelKeepsTemporaryChange(t *testing.T) {
+	levels := make(chan string, 3)
+	l := &logLevel{
+		initialLevel: "3",
+		currentLevel: "3",
+		resetAfter:   50 * time.Millisecond,
+		set: func(level string) error {
+			levels <- level
+			return nil
+		},
+	}
+	if err := l.setLevel(6); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// The temporary change is in effect, so the new initial verbosity is only
+	// set when it is reset.
+	if err := l.setInitialLevel(4); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, want := range []string{"6", "4"} {
+		select {
+		case got := <-levels:
+			if got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for the verbosity to be set to %q", want)
+		}
+	}
+}
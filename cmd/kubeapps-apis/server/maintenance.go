@@ -0,0 +1,96 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bufbuild/connect-go"
+	log "k8s.io/klog/v2"
+)
+
+// maintenanceMode rejects the write requests while enabled, so that the
+// server keeps serving reads during, for instance, cluster upgrades.
+type maintenanceMode struct {
+	enabled atomic.Bool
+	mu      sync.RWMutex
+	// writeMethods are the prefixes of the method names considered as writes.
+	writeMethods []string
+}
+
+func newMaintenanceMode(enabled bool, writeMethods []string) *maintenanceMode {
+	m := &maintenanceMode{writeMethods: writeMethods}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// isWrite returns whether the given procedure is considered a write.
+func (m *maintenanceMode) isWrite(procedure string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return methodHasPrefix(procedure, m.writeMethods)
+}
+
+// setWriteMethods changes the prefixes of the method names considered as writes.
+func (m *maintenanceMode) setWriteMethods(writeMethods []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeMethods = writeMethods
+}
+
+// methodHasPrefix returns whether the method name of the given procedure, such
+// as "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage",
+// starts with any of the given prefixes.
+func methodHasPrefix(procedure string, prefixes []string) bool {
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// interceptor returns a connect interceptor which fails the write requests
+// with an Unavailable error while the maintenance mode is enabled.
+func (m *maintenanceMode) interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if m.enabled.Load() && m.isWrite(req.Spec().Procedure) {
+				return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("The server is in maintenance mode, %q is not available until the maintenance is over", req.Spec().Procedure))
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// ServeHTTP reports the maintenance mode with GET requests and toggles it with
+// PUT requests such as `PUT /admin/maintenance?enabled=true`.
+func (m *maintenanceMode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		enabled, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+		if err != nil {
+			http.Error(w, "The query parameter 'enabled' must be a boolean", http.StatusBadRequest)
+			return
+		}
+		m.enabled.Store(enabled)
+		log.Infof("Maintenance mode set to %t", enabled)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]bool{"enabled": m.enabled.Load()}); err != nil {
+		log.Errorf("Unable to write the maintenance mode response: %v", err)
+	}
+}
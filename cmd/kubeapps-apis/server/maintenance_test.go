@@ -0,0 +1,113 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestMaintenanceModeInterceptor(t *testing.T) {
+	maintenance := newMaintenanceMode(true, []string{"Create", "Update", "Delete"})
+
+	call := func(procedure string) error {
+		ts := serveTestProcedure(t, procedure,
+			func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+				return connect.NewResponse(&emptypb.Empty{}), nil
+			},
+			connect.WithInterceptors(maintenance.interceptor()),
+		)
+		client := connect.NewClient[emptypb.Empty, emptypb.Empty](ts.Client(), ts.URL+procedure)
+		_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+		return err
+	}
+
+	if got, want := connect.CodeOf(call("/test.v1.TestService/CreateThing")), connect.CodeUnavailable; got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if err := call("/test.v1.TestService/GetThing"); err != nil {
+		t.Errorf("expected reads to be allowed in maintenance mode, got: %+v", err)
+	}
+
+	maintenance.enabled.Store(false)
+	if err := call("/test.v1.TestService/CreateThing"); err != nil {
+		t.Errorf("expected writes to be allowed without maintenance mode, got: %+v", err)
+	}
+}
+
+func TestAdminMaintenanceEndpoint(t *testing.T) {
+	testCases := []struct {
+		name            string
+		token           string
+		method          string
+		authorization   string
+		query           string
+		expectedStatus  int
+		expectedEnabled bool
+	}{
+		{
+			name:           "admin endpoints are disabled without a token",
+			method:         http.MethodPut,
+			authorization:  "Bearer ",
+			query:          "enabled=true",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "requests with an invalid token are rejected",
+			token:          "secret",
+			method:         http.MethodPut,
+			authorization:  "Bearer other",
+			query:          "enabled=true",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "the maintenance mode can be read",
+			token:          "secret",
+			method:         http.MethodGet,
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:            "the maintenance mode can be enabled",
+			token:           "secret",
+			method:          http.MethodPut,
+			authorization:   "Bearer secret",
+			query:           "enabled=true",
+			expectedStatus:  http.StatusOK,
+			expectedEnabled: true,
+		},
+		{
+			name:           "the maintenance mode requires a boolean",
+			token:          "secret",
+			method:         http.MethodPut,
+			authorization:  "Bearer secret",
+			query:          "enabled=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maintenance := newMaintenanceMode(false, nil)
+			handler := newAdminHandler(tc.token, map[string]http.Handler{"maintenance": maintenance})
+
+			req := httptest.NewRequest(tc.method, adminPathPrefix+"maintenance?"+tc.query, nil)
+			req.Header.Set("Authorization", tc.authorization)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got, want := rec.Code, tc.expectedStatus; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+			if got, want := maintenance.enabled.Load(), tc.expectedEnabled; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	log "k8s.io/klog/v2"
+)
+
+// cgroupRoot is where the cgroup filesystem of the container is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// setMaxProcs sets GOMAXPROCS to maxProcs or, when it is 0, to the CPU quota of
+// the cgroup of the container, so that the runtime does not schedule goroutines
+// on more threads than the CPUs it is allowed to use and get throttled. The
+// GOMAXPROCS environment variable, when set, takes precedence over the quota.
+func setMaxProcs(maxProcs int, cgroupRoot string) {
+	source := "option"
+	switch {
+	case maxProcs > 0:
+	case os.Getenv("GOMAXPROCS") != "":
+		source = "environment"
+		maxProcs = runtime.GOMAXPROCS(0)
+	default:
+		quota, found, err := cgroupCPUQuota(cgroupRoot)
+		if err != nil {
+			log.Warningf("Unable to read the CPU quota of the cgroup, keeping GOMAXPROCS=%d: %v", runtime.GOMAXPROCS(0), err)
+			return
+		}
+		if !found {
+			log.InfoS("+core No CPU quota, keeping GOMAXPROCS", "maxProcs", runtime.GOMAXPROCS(0))
+			return
+		}
+		source = "cgroup"
+		maxProcs = quotaToMaxProcs(quota)
+	}
+	runtime.GOMAXPROCS(maxProcs)
+	log.InfoS("+core Set GOMAXPROCS", "maxProcs", maxProcs, "source", source)
+}
+
+// quotaToMaxProcs rounds the quota down, since a fraction of a CPU cannot be used
+// without being throttled, but keeps at least one thread.
+func quotaToMaxProcs(quota float64) int {
+	maxProcs := int(math.Floor(quota))
+	if maxProcs < 1 {
+		return 1
+	}
+	return maxProcs
+}
+
+// cgroupCPUQuota returns the CPU quota, in CPUs, of the cgroup v2 (cpu.max) or,
+// failing that, of the cgroup v1 (cpu.cfs_quota_us and cpu.cfs_period_us).
+// It returns false when the cgroup has no quota.
+func cgroupCPUQuota(root string) (float64, bool, error) {
+	if content, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(content))
+		if len(fields) != 2 {
+			return 0, false, fmt.Errorf("invalid cpu.max %q", strings.TrimSpace(string(content)))
+		}
+		if fields[0] == "max" {
+			return 0, false, nil
+		}
+		return parseCPUQuota(fields[0], fields[1])
+	} else if !os.IsNotExist(err) {
+		return 0, false, err
+	}
+
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false, err
+	}
+	if strings.TrimSpace(string(quota)) == "-1" {
+		return 0, false, nil
+	}
+	return parseCPUQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func parseCPUQuota(quota, period string) (float64, bool, error) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid CPU quota %q: %w", quota, err)
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false, fmt.Errorf("invalid CPU period %q", period)
+	}
+	return q / p, true, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCgroupCPUQuota(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string]string
+		expectedQuota float64
+		expectedFound bool
+	}{
+		{
+			name:          "cgroup v2 quota",
+			files:         map[string]string{"cpu.max": "150000 100000\n"},
+			expectedQuota: 1.5,
+			expectedFound: true,
+		},
+		{
+			name:  "cgroup v2 without quota",
+			files: map[string]string{"cpu.max": "max 100000\n"},
+		},
+		{
+			name: "cgroup v1 quota",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "400000\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+			expectedQuota: 4,
+			expectedFound: true,
+		},
+		{
+			name: "cgroup v1 without quota",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "-1\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+		},
+		{
+			name: "no cgroup",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tc.files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatalf("%+v", err)
+				}
+			}
+
+			quota, found, err := cgroupCPUQuota(root)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := found, tc.expectedFound; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+			if got, want := quota, tc.expectedQuota; got != want {
+				t.Errorf("got: %f, want: %f", got, want)
+			}
+		})
+	}
+}
+
+func TestQuotaToMaxProcs(t *testing.T) {
+	for quota, want := range map[float64]int{0.5: 1, 1: 1, 1.5: 1, 2.9: 2, 8: 8} {
+		if got := quotaToMaxProcs(quota); got != want {
+			t.Errorf("%f: got: %d, want: %d", quota, got, want)
+		}
+	}
+}
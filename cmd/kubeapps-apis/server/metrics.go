@@ -0,0 +1,167 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// metricsPath is the path of the Prometheus metrics endpoint.
+const metricsPath = "/metrics"
+
+// payloadSizeBuckets range from 64B to 64MiB, since listings of large
+// repositories can be several MiB.
+var payloadSizeBuckets = prometheus.ExponentialBuckets(64, 4, 11)
+
+// metrics is a connect interceptor recording the sizes of the request and
+// response messages, labeled by procedure, so that abnormally large payloads
+// can be alerted on. The sizes across all procedures are the sum over the label.
+// Each message of a streaming call is recorded separately.
+type metrics struct {
+	registry     *prometheus.Registry
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	// quotaStoreErrors counts the requests which could not be counted for the
+	// daily quota, such as while Redis is down.
+	quotaStoreErrors prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "kubeapps_apis",
+			Name:      "request_size_bytes",
+			Help:      "Size of the request messages, in bytes.",
+			Buckets:   payloadSizeBuckets,
+		}, []string{"procedure"}),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "kubeapps_apis",
+			Name:      "response_size_bytes",
+			Help:      "Size of the response messages, in bytes.",
+			Buckets:   payloadSizeBuckets,
+		}, []string{"procedure"}),
+		quotaStoreErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "kubeapps_apis",
+			Name:      "daily_quota_store_errors_total",
+			Help:      "Number of requests which could not be counted for the daily quota.",
+		}),
+	}
+	m.registry.MustRegister(m.requestSize, m.responseSize, m.quotaStoreErrors)
+	return m
+}
+
+// registerBuildInfo registers the kubeapps_apis_build_info gauge, labeled with
+// the version and commit of the build, and the kubeapps_apis_up gauge, both
+// set to 1, so that the dashboards can show the versions running across the
+// replicas and alert on the replicas which are not scraped. The up gauge is
+// prefixed so as not to clash with the up series of the scrapers.
+func (m *metrics) registerBuildInfo(version string) {
+	buildInfo := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kubeapps_apis",
+		Name:      "build_info",
+		Help:      "Build information of the kubeapps-apis server, always 1.",
+	}, []string{"version", "commit", "goversion"})
+	buildInfo.WithLabelValues(version, buildCommit(), runtime.Version()).Set(1)
+	up := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kubeapps_apis",
+		Name:      "up",
+		Help:      "Whether the kubeapps-apis server is up, always 1.",
+	})
+	up.Set(1)
+	m.registry.MustRegister(buildInfo, up)
+}
+
+// buildCommit returns the VCS revision of the build, or "unknown" if the
+// binary was built without the VCS information.
+func buildCommit() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
+
+// observe records the size of a message, if it is a proto message.
+func observe(histogram *prometheus.HistogramVec, procedure string, msg any) {
+	if msg, ok := msg.(proto.Message); ok {
+		histogram.WithLabelValues(procedure).Observe(float64(proto.Size(msg)))
+	}
+}
+
+func (m *metrics) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		procedure := req.Spec().Procedure
+		observe(m.requestSize, procedure, req.Any())
+		res, err := next(ctx, req)
+		if err == nil && res != nil {
+			observe(m.responseSize, procedure, res.Any())
+		}
+		return res, err
+	}
+}
+
+func (m *metrics) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (m *metrics) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, &metricsStreamingHandlerConn{StreamingHandlerConn: conn, metrics: m})
+	}
+}
+
+// metricsStreamingHandlerConn records the size of each message received and sent.
+type metricsStreamingHandlerConn struct {
+	connect.StreamingHandlerConn
+	metrics *metrics
+}
+
+func (c *metricsStreamingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	observe(c.metrics.requestSize, c.Spec().Procedure, msg)
+	return nil
+}
+
+func (c *metricsStreamingHandlerConn) Send(msg any) error {
+	if err := c.StreamingHandlerConn.Send(msg); err != nil {
+		return err
+	}
+	observe(c.metrics.responseSize, c.Spec().Procedure, msg)
+	return nil
+}
+
+// handler returns the handler serving the metrics in the Prometheus format.
+// When a token is given, or a client certificate is required, the metrics are
+// only served to the scrapers having either of them, so that the names of the
+// endpoints and the traffic are not exposed to everyone. Otherwise, the
+// metrics are open, such as for a Prometheus scraping within a trusted mesh.
+func (m *metrics) handler(token string, requireClientCert bool) http.Handler {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	if token == "" && !requireClientCert {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasClientCert := core.ClientCertIdentityFromContext(r.Context())
+		if !(requireClientCert && hasClientCert) && !(token != "" && hasBearerToken(r, token)) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
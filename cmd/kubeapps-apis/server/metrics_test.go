@@ -0,0 +1,175 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetricsRecordPayloadSizes(t *testing.T) {
+	metrics := newMetrics()
+
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	request := &packagesGRPCv1alpha1.GetInstalledPackageDetailRequest{
+		InstalledPackageRef: &packagesGRPCv1alpha1.InstalledPackageReference{
+			Context:    &packagesGRPCv1alpha1.Context{Namespace: "default"},
+			Identifier: "my-apache",
+		},
+	}
+	response := &packagesGRPCv1alpha1.GetInstalledPackageDetailResponse{
+		InstalledPackageDetail: &packagesGRPCv1alpha1.InstalledPackageDetail{Name: "my-apache", ValuesApplied: "foo: bar"},
+	}
+
+	ts := serveTestProcedure(t, procedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest]) (*connect.Response[packagesGRPCv1alpha1.GetInstalledPackageDetailResponse], error) {
+			return connect.NewResponse(response), nil
+		},
+		connect.WithInterceptors(metrics),
+	)
+
+	client := connect.NewClient[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest, packagesGRPCv1alpha1.GetInstalledPackageDetailResponse](ts.Client(), ts.URL+procedure)
+	if _, err := client.CallUnary(context.Background(), connect.NewRequest(request)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	w := httptest.NewRecorder()
+	metrics.handler("", false).ServeHTTP(w, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	body := w.Body.Bytes()
+	for _, want := range []string{
+		fmt.Sprintf("kubeapps_apis_request_size_bytes_sum{procedure=%q} %d", procedure, proto.Size(request)),
+		fmt.Sprintf("kubeapps_apis_request_size_bytes_count{procedure=%q} 1", procedure),
+		fmt.Sprintf("kubeapps_apis_response_size_bytes_sum{procedure=%q} %d", procedure, proto.Size(response)),
+		fmt.Sprintf("kubeapps_apis_response_size_bytes_count{procedure=%q} 1", procedure),
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %q in the metrics, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsHandlerAuth(t *testing.T) {
+	testCases := []struct {
+		name              string
+		token             string
+		requireClientCert bool
+		authorization     string
+		clientCert        bool
+		expectedStatus    int
+	}{
+		{
+			name:           "open by default",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "rejects a scraper without the token",
+			token:          "secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects a scraper with another token",
+			token:          "secret",
+			authorization:  "Bearer other",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "accepts a scraper with the token",
+			token:          "secret",
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:              "rejects a scraper without a client certificate",
+			requireClientCert: true,
+			expectedStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:              "accepts a scraper with a client certificate",
+			requireClientCert: true,
+			clientCert:        true,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:              "accepts a scraper with the token but no client certificate",
+			token:             "secret",
+			requireClientCert: true,
+			authorization:     "Bearer secret",
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:           "rejects a scraper with only a client certificate when not required",
+			token:          "secret",
+			clientCert:     true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := newMetrics().handler(tc.token, tc.requireClientCert)
+			req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			if tc.clientCert {
+				req = req.WithContext(core.ContextWithClientCertIdentity(req.Context(), &core.ClientCertIdentity{CommonName: "prometheus"}))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if got, want := w.Code, tc.expectedStatus; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestMetricsBuildInfo(t *testing.T) {
+	testCases := []struct {
+		name      string
+		buildInfo bool
+		expected  []string
+		absent    []string
+	}{
+		{
+			name:   "not exposed by default",
+			absent: []string{"kubeapps_apis_build_info", "kubeapps_apis_up"},
+		},
+		{
+			name:      "exposes the build info and up gauges",
+			buildInfo: true,
+			expected:  []string{`kubeapps_apis_build_info{commit="`, `version="v2.9.0"} 1`, "kubeapps_apis_up 1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMetrics()
+			if tc.buildInfo {
+				m.registerBuildInfo("v2.9.0")
+			}
+			w := httptest.NewRecorder()
+			m.handler("", false).ServeHTTP(w, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+			body := w.Body.String()
+			for _, expected := range tc.expected {
+				if !strings.Contains(body, expected) {
+					t.Errorf("got: %s, want it to contain: %s", body, expected)
+				}
+			}
+			for _, absent := range tc.absent {
+				if strings.Contains(body, absent) {
+					t.Errorf("got: %s, want it not to contain: %s", body, absent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	log "k8s.io/klog/v2"
+)
+
+// openAPIPath is the path of the statically generated OpenAPI document.
+const openAPIPath = "docs/kubeapps-apis.swagger.json"
+
+// pathParamRegex matches the path parameters, which are named after the proto
+// fields in the http rules but after their JSON names in the OpenAPI document.
+var pathParamRegex = regexp.MustCompile(`\{[^}]*\}`)
+
+// route identifies an HTTP endpoint, such as "GET /core/plugins/v1alpha1/configured-plugins".
+func route(method, path string) string {
+	return strings.ToUpper(method) + " " + pathParamRegex.ReplaceAllString(path, "{}")
+}
+
+// documentedRoutes returns the operation ids of the OpenAPI document by route.
+func documentedRoutes(openAPI []byte) (map[string]string, error) {
+	doc := struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+	}{}
+	if err := json.Unmarshal(openAPI, &doc); err != nil {
+		return nil, err
+	}
+	routes := map[string]string{}
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			routes[route(method, path)] = operation.OperationID
+		}
+	}
+	return routes, nil
+}
+
+// registeredRoutes returns the full names of the methods, by route, of the
+// kubeappsapis services registered in the protobuf registry, which includes
+// the services of the loaded plugins. Methods without http rules are skipped.
+func registeredRoutes(files *protoregistry.Files) map[string]string {
+	routes := map[string]string{}
+	rangeHTTPRules(files, func(method protoreflect.MethodDescriptor, httpMethod, path string) {
+		routes[route(httpMethod, path)] = string(method.FullName())
+	})
+	return routes
+}
+
+// rangeServices calls fn for each kubeappsapis service of the registry.
+func rangeServices(files *protoregistry.Files, fn func(service protoreflect.ServiceDescriptor)) {
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		if !strings.HasPrefix(string(file.Package()), "kubeappsapis.") {
+			return true
+		}
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			fn(services.Get(i))
+		}
+		return true
+	})
+}
+
+// rangeHTTPRules calls fn for each http rule, including the additional bindings,
+// of the methods of the kubeappsapis services of the registry.
+func rangeHTTPRules(files *protoregistry.Files, fn func(method protoreflect.MethodDescriptor, httpMethod, path string)) {
+	rangeServices(files, func(service protoreflect.ServiceDescriptor) {
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+			if !ok || rule == nil {
+				continue
+			}
+			for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+				if httpMethod, path := httpRuleRoute(r); path != "" {
+					fn(method, httpMethod, path)
+				}
+			}
+		}
+	})
+}
+
+// httpRuleRoute returns the HTTP method and path of an http rule.
+func httpRuleRoute(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	}
+	return "", ""
+}
+
+// openAPIDiscrepancies compares the registered routes with the documented ones.
+// Documented routes are only reported as missing when their service, which
+// prefixes the operation id, is registered, so that the services of plugins
+// which are not loaded are not reported.
+func openAPIDiscrepancies(registered, documented map[string]string) []string {
+	registeredServices := map[string]bool{}
+	for _, fullName := range registered {
+		service := protoreflect.FullName(fullName).Parent().Name()
+		registeredServices[string(service)] = true
+	}
+
+	discrepancies := []string{}
+	for r, fullName := range registered {
+		if _, ok := documented[r]; !ok {
+			discrepancies = append(discrepancies, fmt.Sprintf("%s (%s) is not documented", r, fullName))
+		}
+	}
+	for r, operationID := range documented {
+		service, _, _ := strings.Cut(operationID, "_")
+		if _, ok := registered[r]; !ok && registeredServices[service] {
+			discrepancies = append(discrepancies, fmt.Sprintf("%s (%s) is documented but not registered", r, operationID))
+		}
+	}
+	sort.Strings(discrepancies)
+	return discrepancies
+}
+
+// validateOpenAPI logs the discrepancies between the registered services and
+// the OpenAPI document, so that a drift of the document is caught early.
+func validateOpenAPI(path string) {
+	openAPI, err := os.ReadFile(path)
+	if err != nil {
+		log.Warningf("Unable to read the OpenAPI document %q: %v", path, err)
+		return
+	}
+	documented, err := documentedRoutes(openAPI)
+	if err != nil {
+		log.Warningf("Unable to parse the OpenAPI document %q: %v", path, err)
+		return
+	}
+	discrepancies := openAPIDiscrepancies(registeredRoutes(protoregistry.GlobalFiles), documented)
+	for _, d := range discrepancies {
+		log.Warningf("OpenAPI drift: %s", d)
+	}
+	if len(discrepancies) == 0 {
+		log.Infof("The OpenAPI document %q matches the registered services", path)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestOpenAPIDiscrepancies(t *testing.T) {
+	registered := map[string]string{
+		"GET /core/packages/v1alpha1/availablepackages":    "kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageSummaries",
+		"POST /core/packages/v1alpha1/installedpackages":   "kubeappsapis.core.packages.v1alpha1.PackagesService.CreateInstalledPackage",
+		"GET /core/plugins/v1alpha1/configured-plugins":    "kubeappsapis.core.plugins.v1alpha1.PluginsService.GetConfiguredPlugins",
+		"GET /core/packages/v1alpha1/installedpackages/{}": "kubeappsapis.core.packages.v1alpha1.PackagesService.GetInstalledPackageDetail",
+	}
+	documented := map[string]string{
+		"GET /core/packages/v1alpha1/availablepackages":    "PackagesService_GetAvailablePackageSummaries",
+		"GET /core/plugins/v1alpha1/configured-plugins":    "PluginsService_GetConfiguredPlugins",
+		"GET /core/packages/v1alpha1/installedpackages/{}": "PackagesService_GetInstalledPackageDetail",
+		"DELETE /core/packages/v1alpha1/installedpackages": "PackagesService_DeleteInstalledPackage",
+		"GET /plugins/helm/packages/v1alpha1/repositories": "HelmRepositoriesService_GetPackageRepositorySummaries",
+	}
+
+	expected := []string{
+		"DELETE /core/packages/v1alpha1/installedpackages (PackagesService_DeleteInstalledPackage) is documented but not registered",
+		"POST /core/packages/v1alpha1/installedpackages (kubeappsapis.core.packages.v1alpha1.PackagesService.CreateInstalledPackage) is not documented",
+	}
+	if got, want := openAPIDiscrepancies(registered, documented), expected; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestOpenAPIDocumentMatchesCoreServices(t *testing.T) {
+	openAPI, err := os.ReadFile(filepath.Join("..", openAPIPath))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	documented, err := documentedRoutes(openAPI)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// Only the core services are registered in the test binary.
+	registered := registeredRoutes(protoregistry.GlobalFiles)
+	if len(registered) == 0 {
+		t.Fatalf("got no registered routes")
+	}
+	if got := openAPIDiscrepancies(registered, documented); len(got) != 0 {
+		t.Errorf("got discrepancies: %v", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/kubernetes"
+	log "k8s.io/klog/v2"
+)
+
+const (
+	// operatorLogoMaxWait bounds the wait for the rate limiter of the operator
+	// logo proxy, after which the request is throttled.
+	operatorLogoMaxWait = 5 * time.Second
+
+	// operatorLogoRetryAfter is the hint to retry the throttled requests.
+	operatorLogoRetryAfter = time.Second
+)
+
+// errOperatorLogoThrottled is returned when the API server calls of the
+// operator logo proxy exceed its rate limit.
+var errOperatorLogoThrottled = errors.New("too many operator logo requests")
+
+// operatorLogoFetchFunc returns the icon of the package manifest of an operator.
+type operatorLogoFetchFunc func(ctx context.Context, namespace, name string) ([]byte, error)
+
+type operatorLogo struct {
+	data        []byte
+	contentType string
+	fetchedAt   time.Time
+}
+
+// operatorLogos proxies the operator icons to the API server, so that a page
+// showing many operators does not overload it: the icons are cached for the
+// TTL, the concurrent requests of the same icon share a single API server call
+// and the calls are rate limited. The icons are fetched with the credentials of
+// the service account rather than of the caller, so they are cached for all.
+type operatorLogos struct {
+	fetch   operatorLogoFetchFunc
+	ttl     time.Duration
+	limiter *rate.Limiter
+	calls   singleflight.Group
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]operatorLogo
+}
+
+// newOperatorLogos returns the operator logo proxy. A TTL of 0 disables the
+// cache and a QPS of 0 disables the rate limit.
+func newOperatorLogos(fetch operatorLogoFetchFunc, ttl time.Duration, qps float64, burst int) *operatorLogos {
+	limit := rate.Inf
+	if qps > 0 {
+		limit = rate.Limit(qps)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &operatorLogos{
+		fetch:   fetch,
+		ttl:     ttl,
+		limiter: rate.NewLimiter(limit, burst),
+		now:     time.Now,
+		cache:   map[string]operatorLogo{},
+	}
+}
+
+// fetchOperatorLogoFunc returns the function fetching the icons of the package
+// manifests with the given clientset.
+func fetchOperatorLogoFunc(clientSet *kubernetes.Clientset) operatorLogoFetchFunc {
+	return func(ctx context.Context, namespace, name string) ([]byte, error) {
+		return clientSet.RESTClient().Get().AbsPath(fmt.Sprintf("/apis/packages.operators.coreos.com/v1/namespaces/%s/packagemanifests/%s/icon", namespace, name)).Do(ctx).Raw()
+	}
+}
+
+// get returns the icon of an operator, from the cache when fresh.
+func (l *operatorLogos) get(ctx context.Context, namespace, name string) (operatorLogo, error) {
+	key := namespace + "/" + name
+	if logo, ok := l.cached(key); ok {
+		return logo, nil
+	}
+	// The call is shared by the concurrent requests of the icon, so it is not
+	// cancelled when the request starting it is.
+	v, err, _ := l.calls.Do(key, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		waitCtx, cancel := context.WithTimeout(ctx, operatorLogoMaxWait)
+		defer cancel()
+		if err := l.limiter.Wait(waitCtx); err != nil {
+			return operatorLogo{}, errOperatorLogoThrottled
+		}
+		data, err := l.fetch(ctx, namespace, name)
+		if err != nil {
+			return operatorLogo{}, err
+		}
+		logo := operatorLogo{data: data, contentType: logoContentType(data), fetchedAt: l.now()}
+		l.store(key, logo)
+		return logo, nil
+	})
+	return v.(operatorLogo), err
+}
+
+// cached returns the icon of the cache, if fresh.
+func (l *operatorLogos) cached(key string) (operatorLogo, bool) {
+	if l.ttl <= 0 {
+		return operatorLogo{}, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	logo, ok := l.cache[key]
+	if !ok || l.now().Sub(logo.fetchedAt) >= l.ttl {
+		return operatorLogo{}, false
+	}
+	return logo, true
+}
+
+// store caches the icon, evicting the expired ones.
+func (l *operatorLogos) store(key string, logo operatorLogo) {
+	if l.ttl <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for k, cached := range l.cache {
+		if l.now().Sub(cached.fetchedAt) >= l.ttl {
+			delete(l.cache, k)
+		}
+	}
+	l.cache[key] = logo
+}
+
+// serveHTTP writes the icon of the operator given by the "namespace" and "name"
+// path parameters.
+func (l *operatorLogos) serveHTTP(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	logo, err := l.get(r.Context(), pathParams["namespace"], pathParams["name"])
+	if errors.Is(err, errOperatorLogoThrottled) {
+		w.Header().Set(retryAfterHeader, strconv.Itoa(int(operatorLogoRetryAfter.Seconds())))
+		http.Error(w, fmt.Sprintf("Unable to retrieve operator logo: %v", err), http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to retrieve operator logo: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", logo.contentType)
+	if _, err := w.Write(logo.data); err != nil {
+		log.V(4).Infof("Unable to write the operator logo: %v", err)
+	}
+}
+
+// logoContentType returns the content type of an icon.
+func logoContentType(data []byte) string {
+	contentType := http.DetectContentType(data)
+	if strings.Contains(contentType, "text/") {
+		// DetectContentType is unable to return svg icons since they are in fact text
+		contentType = "image/svg+xml"
+	}
+	return contentType
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOperatorLogos(t *testing.T) {
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	testCases := []struct {
+		name            string
+		ttl             time.Duration
+		qps             float64
+		requests        []string
+		advance         time.Duration
+		fetchErr        error
+		expectedStatus  []int
+		expectedFetches int
+	}{
+		{
+			name:            "cached icon",
+			ttl:             time.Minute,
+			requests:        []string{"foo", "foo"},
+			expectedStatus:  []int{http.StatusOK, http.StatusOK},
+			expectedFetches: 1,
+		},
+		{
+			name:            "expired icon",
+			ttl:             time.Minute,
+			requests:        []string{"foo", "foo"},
+			advance:         time.Minute,
+			expectedStatus:  []int{http.StatusOK, http.StatusOK},
+			expectedFetches: 2,
+		},
+		{
+			name:            "cache disabled",
+			requests:        []string{"foo", "foo"},
+			expectedStatus:  []int{http.StatusOK, http.StatusOK},
+			expectedFetches: 2,
+		},
+		{
+			name:            "throttled icon",
+			ttl:             time.Minute,
+			qps:             0.001,
+			requests:        []string{"foo", "bar", "foo"},
+			expectedStatus:  []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+			expectedFetches: 1,
+		},
+		{
+			name:            "errors are not cached",
+			ttl:             time.Minute,
+			requests:        []string{"foo", "foo"},
+			fetchErr:        fmt.Errorf("boom"),
+			expectedStatus:  []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedFetches: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fetches := 0
+			logos := newOperatorLogos(func(ctx context.Context, namespace, name string) ([]byte, error) {
+				fetches++
+				return svg, tc.fetchErr
+			}, tc.ttl, tc.qps, 1)
+			now := time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC)
+			logos.now = func() time.Time { return now }
+
+			status := []int{}
+			for _, name := range tc.requests {
+				w := httptest.NewRecorder()
+				logos.serveHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"namespace": "operators", "name": name})
+				status = append(status, w.Code)
+				if w.Code == http.StatusOK {
+					if got, want := w.Header().Get("Content-Type"), "image/svg+xml"; got != want {
+						t.Errorf("got: %q, want: %q", got, want)
+					}
+				}
+				if w.Code == http.StatusTooManyRequests {
+					if got, want := w.Header().Get(retryAfterHeader), "1"; got != want {
+						t.Errorf("got: %q, want: %q", got, want)
+					}
+				}
+				now = now.Add(tc.advance)
+			}
+			if got, want := status, tc.expectedStatus; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if got, want := fetches, tc.expectedFetches; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestOperatorLogosCoalescesCalls(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	logos := newOperatorLogos(func(ctx context.Context, namespace, name string) ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		return []byte("logo"), nil
+	}, 0, 0, 1)
+
+	const requests = 5
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := logos.get(context.Background(), "operators", "foo"); err != nil {
+				t.Errorf("%+v", err)
+			}
+		}()
+	}
+	// Waits for the first call to be in flight before releasing it.
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got, want := fetches.Load(), int32(1); got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/url"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/protobuf/proto"
+)
+
+// filterOptionsField is the name of the field used by list requests to filter results.
+const filterOptionsField = "filter_options"
+
+// filterQueryParamAliases maps the convenience query parameters accepted by list
+// endpoints to the corresponding fields of the FilterOptions message, so that
+// REST clients can use, for instance, `?repo=bitnami&category=Database` rather
+// than `?filter_options.repositories=bitnami&filter_options.categories=Database`.
+var filterQueryParamAliases = map[string]string{
+	"repo":     filterOptionsField + ".repositories",
+	"category": filterOptionsField + ".categories",
+	"q":        filterOptionsField + ".query",
+}
+
+// aliasingQueryParser is a runtime.QueryParameterParser which expands the
+// filterQueryParamAliases for requests with filter options, before delegating to
+// the default grpc-gateway parser.
+type aliasingQueryParser struct {
+	runtime.DefaultQueryParser
+}
+
+func (p *aliasingQueryParser) Parse(msg proto.Message, values url.Values, filter *utilities.DoubleArray) error {
+	if msg.ProtoReflect().Descriptor().Fields().ByName(filterOptionsField) != nil {
+		values = expandFilterQueryParamAliases(values)
+	}
+	return p.DefaultQueryParser.Parse(msg, values, filter)
+}
+
+// expandFilterQueryParamAliases returns a copy of values in which the aliased
+// parameters have been replaced by their canonical names. Values of repeated
+// aliases are appended to any value already set with the canonical name.
+func expandFilterQueryParamAliases(values url.Values) url.Values {
+	expanded := url.Values{}
+	for key, vals := range values {
+		if _, ok := filterQueryParamAliases[key]; !ok {
+			expanded[key] = append(expanded[key], vals...)
+		}
+	}
+	for alias, canonical := range filterQueryParamAliases {
+		if vals, ok := values[alias]; ok {
+			expanded[canonical] = append(expanded[canonical], vals...)
+		}
+	}
+	return expanded
+}
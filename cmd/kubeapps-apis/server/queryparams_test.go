@@ -0,0 +1,80 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+)
+
+func TestAliasingQueryParser(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedQuery string
+		expectedRepos []string
+		expectedCats  []string
+	}{
+		{
+			name:          "maps the aliases onto the filter options",
+			query:         "repo=bitnami&category=Database&q=redis",
+			expectedQuery: "redis",
+			expectedRepos: []string{"bitnami"},
+			expectedCats:  []string{"Database"},
+		},
+		{
+			name:          "supports repeated aliases",
+			query:         "repo=bitnami&repo=jetstack&category=Database&category=Infrastructure",
+			expectedRepos: []string{"bitnami", "jetstack"},
+			expectedCats:  []string{"Database", "Infrastructure"},
+		},
+		{
+			name:          "combines aliases with the canonical parameters",
+			query:         "filter_options.repositories=jetstack&repo=bitnami&filterOptions.query=redis",
+			expectedQuery: "redis",
+			expectedRepos: []string{"jetstack", "bitnami"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			request := &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{}
+			parser := &aliasingQueryParser{}
+			if err := parser.Parse(request, values, &utilities.DoubleArray{}); err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			if got, want := request.GetFilterOptions().GetQuery(), tc.expectedQuery; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+			if got, want := request.GetFilterOptions().GetRepositories(), tc.expectedRepos; !cmp.Equal(want, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if got, want := request.GetFilterOptions().GetCategories(), tc.expectedCats; !cmp.Equal(want, got) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestAliasingQueryParserIgnoresRequestsWithoutFilters(t *testing.T) {
+	values, err := url.ParseQuery("q=redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := &packagesGRPCv1alpha1.GetInstalledPackageSummariesRequest{}
+	parser := &aliasingQueryParser{}
+	// The default parser ignores unknown parameters, so this must not fail.
+	if err := parser.Parse(request, values, &utilities.DoubleArray{}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	log "k8s.io/klog/v2"
+)
+
+const (
+	// quotaKeyPrefix prefixes the keys of the counts of the requests in Redis,
+	// such as "kubeapps-apis:quota:2023-11-20:alice".
+	quotaKeyPrefix = "kubeapps-apis:quota:"
+
+	// quotaKeyExpiration is the expiration of the counts in Redis, beyond the
+	// day they count the requests of.
+	quotaKeyExpiration = 48 * time.Hour
+
+	// anonymousQuotaUser is the user whose quota is shared by the callers whose
+	// identity is not known.
+	anonymousQuotaUser = "system:anonymous"
+)
+
+// quotaStore counts the requests of the users per day.
+type quotaStore interface {
+	// increment increments the count of the requests of the user on the given
+	// day, such as "2023-11-20", returning the new count.
+	increment(ctx context.Context, day, user string) (int64, error)
+}
+
+// memoryQuotaStore counts the requests of the current day in memory, so the
+// quota applies to each replica separately.
+type memoryQuotaStore struct {
+	mu     sync.Mutex
+	day    string
+	counts map[string]int64
+}
+
+func (s *memoryQuotaStore) increment(_ context.Context, day, user string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day != s.day {
+		s.day = day
+		s.counts = map[string]int64{}
+	}
+	s.counts[user]++
+	return s.counts[user], nil
+}
+
+// redisQuotaStore counts the requests in Redis, so the quota applies to all
+// the replicas together.
+type redisQuotaStore struct {
+	client *redis.Client
+}
+
+func (s redisQuotaStore) increment(ctx context.Context, day, user string) (int64, error) {
+	key := quotaKeyPrefix + day + ":" + user
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, quotaKeyExpiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// dailyQuota is a connect interceptor capping the number of requests of the
+// quota methods, such as the installations, per user and per day (UTC). Every
+// request counts, including those which then fail. The identity of the callers
+// requires the impersonation or the client certificates, and the callers whose
+// identity is not known share the quota of the anonymous user.
+type dailyQuota struct {
+	limit int64
+	// methods are the prefixes of the names of the methods subject to the quota.
+	methods []string
+	store   quotaStore
+	// failOpen lets the requests through when they cannot be counted, rather
+	// than failing them.
+	failOpen bool
+	// storeErrors counts the requests which could not be counted.
+	storeErrors prometheus.Counter
+	// now returns the current time, and is only replaced in tests.
+	now func() time.Time
+}
+
+// newDailyQuota returns the daily quota configured by the serve options,
+// counting the requests in Redis when an address is configured, with the
+// password of the REDIS_PASSWORD environment variable, or else in memory. The
+// requests which cannot be counted are recorded by storeErrors.
+func newDailyQuota(serveOpts core.ServeOptions, storeErrors prometheus.Counter) *dailyQuota {
+	var store quotaStore = &memoryQuotaStore{}
+	if serveOpts.DailyQuotaRedisAddr != "" {
+		store = redisQuotaStore{client: redis.NewClient(&redis.Options{
+			Addr:     serveOpts.DailyQuotaRedisAddr,
+			Password: os.Getenv("REDIS_PASSWORD"),
+		})}
+	}
+	return &dailyQuota{
+		limit:       int64(serveOpts.DailyQuota),
+		methods:     serveOpts.DailyQuotaMethods,
+		store:       store,
+		failOpen:    serveOpts.DailyQuotaFailOpen,
+		storeErrors: storeErrors,
+		now:         time.Now,
+	}
+}
+
+// check counts the request of the procedure, if subject to the quota, failing
+// with a ResourceExhausted error once the quota of the caller is exceeded, with
+// a hint to retry on the next day. The requests which cannot be counted, such
+// as while Redis is down, fail with an Unavailable error, unless the quota
+// fails open, in which case they are let through.
+func (q *dailyQuota) check(ctx context.Context, procedure string) error {
+	if !methodHasPrefix(procedure, q.methods) {
+		return nil
+	}
+	user := callerName(ctx)
+	if user == "" {
+		user = anonymousQuotaUser
+	}
+	now := q.now().UTC()
+	count, err := q.store.increment(ctx, now.Format(time.DateOnly), user)
+	if err != nil {
+		log.Errorf("Unable to count the request of %q for the daily quota of %q: %v", procedure, user, err)
+		q.storeErrors.Inc()
+		if q.failOpen {
+			return nil
+		}
+		return connect.NewError(connect.CodeUnavailable, fmt.Errorf("Unable to count the request of %q for the daily quota, please retry later", procedure))
+	}
+	if count > q.limit {
+		err := connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("The daily quota of %d requests of %q is exceeded for the user %q", q.limit, procedure, user))
+		nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return withRetryAfter(err, nextDay.Sub(now))
+	}
+	return nil
+}
+
+func (q *dailyQuota) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := q.check(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (q *dailyQuota) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (q *dailyQuota) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := q.check(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/go-redis/redismock/v8"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+)
+
+func TestDailyQuota(t *testing.T) {
+	createProcedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage"
+	alice := core.ContextWithCallerIdentity(context.Background(), &core.CallerIdentity{Username: "alice"})
+	bob := core.ContextWithCallerIdentity(context.Background(), &core.CallerIdentity{Username: "bob"})
+
+	now := time.Date(2023, 11, 20, 23, 0, 0, 0, time.UTC)
+	quota := &dailyQuota{
+		limit:   2,
+		methods: []string{"CreateInstalledPackage"},
+		store:   &memoryQuotaStore{},
+		now:     func() time.Time { return now },
+	}
+
+	steps := []struct {
+		name         string
+		ctx          context.Context
+		procedure    string
+		advance      time.Duration
+		expectedCode connect.Code
+		// The seconds until the next day, after which the request can be retried.
+		expectedRetryAfter string
+	}{
+		{name: "first request of alice", ctx: alice, procedure: createProcedure},
+		{name: "second request of alice", ctx: alice, procedure: createProcedure},
+		{name: "third request of alice", ctx: alice, procedure: createProcedure, expectedCode: connect.CodeResourceExhausted, expectedRetryAfter: "3600"},
+		{name: "other methods are not limited", ctx: alice, procedure: "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"},
+		{name: "other users have their own quota", ctx: bob, procedure: createProcedure},
+		{name: "first request of an unknown caller", ctx: context.Background(), procedure: createProcedure},
+		{name: "unknown callers share the same quota", ctx: context.Background(), procedure: createProcedure},
+		{name: "third request of the unknown callers", ctx: context.Background(), procedure: createProcedure, expectedCode: connect.CodeResourceExhausted, expectedRetryAfter: "3600"},
+		{name: "the quota is reset the next day", ctx: alice, procedure: createProcedure, advance: 2 * time.Hour},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		err := quota.check(step.ctx, step.procedure)
+		if step.expectedCode == 0 {
+			if err != nil {
+				t.Errorf("%s: got: %+v, want no error", step.name, err)
+			}
+			continue
+		}
+		if got, want := connect.CodeOf(err), step.expectedCode; got != want {
+			t.Errorf("%s: got: %v, want: %v", step.name, got, want)
+		}
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			if got, want := connectErr.Meta().Get(retryAfterHeader), step.expectedRetryAfter; got != want {
+				t.Errorf("%s: got: %q, want: %q", step.name, got, want)
+			}
+		}
+	}
+}
+
+// failingQuotaStore fails to count the requests, such as while Redis is down.
+type failingQuotaStore struct{}
+
+func (failingQuotaStore) increment(context.Context, string, string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestDailyQuotaStoreErrors(t *testing.T) {
+	createProcedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage"
+	testCases := []struct {
+		name         string
+		failOpen     bool
+		expectedCode connect.Code
+	}{
+		{
+			name:         "fails the requests which cannot be counted by default",
+			expectedCode: connect.CodeUnavailable,
+		},
+		{
+			name:     "lets the requests which cannot be counted through when failing open",
+			failOpen: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMetrics()
+			quota := &dailyQuota{
+				limit:       2,
+				methods:     []string{"CreateInstalledPackage"},
+				store:       failingQuotaStore{},
+				failOpen:    tc.failOpen,
+				storeErrors: m.quotaStoreErrors,
+				now:         time.Now,
+			}
+
+			err := quota.check(context.Background(), createProcedure)
+			if tc.expectedCode == 0 {
+				if err != nil {
+					t.Errorf("got: %+v, want no error", err)
+				}
+			} else if got, want := connect.CodeOf(err), tc.expectedCode; got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+			if got, want := testutil.ToFloat64(m.quotaStoreErrors), float64(1); got != want {
+				t.Errorf("got: %v store errors, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDailyQuotaRequiresCallerIdentity(t *testing.T) {
+	testCases := []struct {
+		name      string
+		serveOpts core.ServeOptions
+		expectErr bool
+	}{
+		{
+			name:      "fails without the identity of the callers",
+			serveOpts: core.ServeOptions{DailyQuota: 10},
+			expectErr: true,
+		},
+		{
+			name:      "succeeds with the client certificates",
+			serveOpts: core.ServeOptions{DailyQuota: 10, TLSClientCAFile: "ca.crt"},
+		},
+		{
+			name:      "succeeds without a quota",
+			serveOpts: core.ServeOptions{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain, err := newInterceptorChain(tc.serveOpts, nil, newMetrics(), newMaintenanceMode(false, nil), &requestTargets{}, newLastErrors())
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if got, want := chain.quota != nil, !tc.expectErr && tc.serveOpts.DailyQuota > 0; got != want {
+				t.Errorf("got quota: %t, want quota: %t", got, want)
+			}
+		})
+	}
+}
+
+func TestRedisQuotaStore(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	key := "kubeapps-apis:quota:2023-11-20:alice"
+	mock.ExpectTxPipeline()
+	mock.ExpectIncr(key).SetVal(3)
+	mock.ExpectExpire(key, quotaKeyExpiration).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	count, err := redisQuotaStore{client: client}.increment(context.Background(), "2023-11-20", "alice")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := count, int64(3); got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
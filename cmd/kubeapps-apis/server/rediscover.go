@@ -0,0 +1,53 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	log "k8s.io/klog/v2"
+)
+
+// rediscoverAPIsFunc re-runs the API discovery of the plugins, returning the
+// error of each plugin by name.
+type rediscoverAPIsFunc func(ctx context.Context) map[string]error
+
+// rediscoverAPIsHandler re-runs the API discovery of the plugins with POST
+// requests, such as once CRDs are installed after the startup, reporting the
+// plugins whose APIs were rediscovered and the errors of the others. It fails
+// with an Internal Server Error when any plugin failed.
+func rediscoverAPIsHandler(rediscover rediscoverAPIsFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		response := struct {
+			Rediscovered []string          `json:"rediscovered"`
+			Errors       map[string]string `json:"errors,omitempty"`
+		}{Rediscovered: []string{}}
+		for plugin, err := range rediscover(r.Context()) {
+			if err != nil {
+				if response.Errors == nil {
+					response.Errors = map[string]string{}
+				}
+				response.Errors[plugin] = err.Error()
+				continue
+			}
+			response.Rediscovered = append(response.Rediscovered, plugin)
+		}
+		sort.Strings(response.Rediscovered)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(response.Errors) > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Errorf("Unable to write the API rediscovery response: %v", err)
+		}
+	})
+}
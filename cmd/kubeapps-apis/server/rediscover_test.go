@@ -0,0 +1,66 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRediscoverAPIsHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		results        map[string]error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "rejects the rediscovery with GET",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method not allowed\n",
+		},
+		{
+			name:           "no plugin to rediscover",
+			method:         http.MethodPost,
+			results:        map[string]error{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"rediscovered\":[]}\n",
+		},
+		{
+			name:           "rediscovers the APIs of the plugins",
+			method:         http.MethodPost,
+			results:        map[string]error{"resources": nil, "kapp_controller.packages": nil},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"rediscovered\":[\"kapp_controller.packages\",\"resources\"]}\n",
+		},
+		{
+			name:           "reports the plugins failing",
+			method:         http.MethodPost,
+			results:        map[string]error{"resources": fmt.Errorf("connection refused"), "kapp_controller.packages": nil},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "{\"rediscovered\":[\"kapp_controller.packages\"],\"errors\":{\"resources\":\"connection refused\"}}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := rediscoverAPIsHandler(func(ctx context.Context) map[string]error {
+				return tc.results
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, "/admin/apis/rediscover", nil))
+			if got, want := rec.Code, tc.expectedStatus; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+			if got, want := rec.Body.String(), tc.expectedBody; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	log "k8s.io/klog/v2"
+)
+
+// configReloader reloads the serve options on SIGHUP, applying the reloadable
+// ones to the running server without dropping connections. The other options
+// only take effect after a restart. It also serves the effective options, with
+// the secrets redacted, for the admin endpoint.
+type configReloader struct {
+	mu sync.Mutex
+	// current are the options loaded last.
+	current     core.ServeOptions
+	load        func() (core.ServeOptions, error)
+	logLevel    *logLevel
+	maintenance *maintenanceMode
+}
+
+// optionChange is a change of a reloadable option.
+type optionChange struct {
+	name     string
+	from, to interface{}
+}
+
+// reloadableChanges returns the changes of the reloadable options.
+func reloadableChanges(current, next core.ServeOptions) []optionChange {
+	changes := []optionChange{}
+	if current.LogVerbosity != next.LogVerbosity {
+		changes = append(changes, optionChange{"log-verbosity", current.LogVerbosity, next.LogVerbosity})
+	}
+	if current.MaintenanceMode != next.MaintenanceMode {
+		changes = append(changes, optionChange{"maintenance-mode", current.MaintenanceMode, next.MaintenanceMode})
+	}
+	if !reflect.DeepEqual(current.MaintenanceWriteMethods, next.MaintenanceWriteMethods) {
+		changes = append(changes, optionChange{"maintenance-write-methods", current.MaintenanceWriteMethods, next.MaintenanceWriteMethods})
+	}
+	return changes
+}
+
+// withReloadableOptions returns a copy of the options with the reloadable
+// options of next.
+func withReloadableOptions(opts, next core.ServeOptions) core.ServeOptions {
+	opts.LogVerbosity = next.LogVerbosity
+	opts.MaintenanceMode = next.MaintenanceMode
+	opts.MaintenanceWriteMethods = next.MaintenanceWriteMethods
+	return opts
+}
+
+// nonReloadableChanges returns the names of the options, other than the
+// reloadable ones, which differ between current and next.
+func nonReloadableChanges(current, next core.ServeOptions) []string {
+	names := []string{}
+	currentValue := reflect.ValueOf(withReloadableOptions(current, next))
+	nextValue := reflect.ValueOf(next)
+	for i := 0; i < currentValue.NumField(); i++ {
+		if !reflect.DeepEqual(currentValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			names = append(names, currentValue.Type().Field(i).Name)
+		}
+	}
+	return names
+}
+
+// run reloads the options on each SIGHUP until the context is done.
+func (r *configReloader) run(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.reload()
+		}
+	}
+}
+
+// reload loads the options, applying and logging the changes of the reloadable
+// ones. The current options are kept if they cannot be loaded.
+func (r *configReloader) reload() {
+	next, err := r.load()
+	if err != nil {
+		log.Errorf("Unable to reload the configuration, keeping the current one: %v", err)
+		return
+	}
+
+	if names := nonReloadableChanges(r.current, next); len(names) > 0 {
+		log.Warningf("The changed options %s are not reloadable and only take effect after a restart", strings.Join(names, ", "))
+	}
+
+	changes := reloadableChanges(r.current, next)
+	if len(changes) == 0 {
+		log.Info("Configuration reloaded without changes to the reloadable options")
+		return
+	}
+	for _, change := range changes {
+		switch change.name {
+		case "log-verbosity":
+			if err := r.logLevel.setInitialLevel(next.LogVerbosity); err != nil {
+				log.Errorf("Unable to set the log verbosity to %d: %v", next.LogVerbosity, err)
+				continue
+			}
+		case "maintenance-mode":
+			r.maintenance.enabled.Store(next.MaintenanceMode)
+		case "maintenance-write-methods":
+			r.maintenance.setWriteMethods(next.MaintenanceWriteMethods)
+		}
+		log.InfoS("+core Reloaded option", "option", change.name, "from", change.from, "to", change.to)
+	}
+	r.mu.Lock()
+	r.current = withReloadableOptions(r.current, next)
+	r.mu.Unlock()
+}
+
+// RedactedServeOptions returns a copy of the options in which the secrets, and
+// the paths of the files holding them, are redacted, so that they can be
+// logged or served.
+func RedactedServeOptions(opts core.ServeOptions) core.ServeOptions {
+	for _, secret := range []*string{&opts.AdminToken, &opts.MetricsAuthToken, &opts.TLSKeyFile, &opts.GatewayTokenFile} {
+		if *secret != "" {
+			*secret = core.Redacted
+		}
+	}
+	return opts
+}
+
+// ServeHTTP serves the effective options, with the secrets redacted, as JSON.
+func (r *configReloader) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.mu.Lock()
+	opts := RedactedServeOptions(r.current)
+	r.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(opts); err != nil {
+		log.Errorf("Unable to encode the configuration: %v", err)
+	}
+}
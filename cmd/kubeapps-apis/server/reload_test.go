@@ -0,0 +1,127 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+)
+
+func TestAdminConfigEndpointRedactsSecrets(t *testing.T) {
+	r := &configReloader{
+		current: core.ServeOptions{
+			Port:        50051,
+			AdminToken:  "secret",
+			TLSCertFile: "/etc/tls/tls.crt",
+			TLSKeyFile:  "/etc/tls/tls.key",
+		},
+	}
+	handler := newAdminHandler("secret", map[string]http.Handler{"config": r})
+
+	req := httptest.NewRequest(http.MethodGet, adminPathPrefix+"config", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got: %d, want: %d", got, want)
+	}
+	got := core.ServeOptions{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	want := core.ServeOptions{
+		Port:        50051,
+		AdminToken:  core.Redacted,
+		TLSCertFile: "/etc/tls/tls.crt",
+		TLSKeyFile:  core.Redacted,
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	// The current options are not modified.
+	if got, want := r.current.AdminToken, "secret"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestConfigReloaderAppliesReloadableOptions(t *testing.T) {
+	levels := make(chan string, 3)
+	l := &logLevel{
+		initialLevel: "3",
+		currentLevel: "3",
+		resetAfter:   time.Minute,
+		set: func(level string) error {
+			levels <- level
+			return nil
+		},
+	}
+	current := core.ServeOptions{
+		Port:                    50051,
+		LogVerbosity:            3,
+		MaintenanceWriteMethods: []string{"Create"},
+	}
+	next := core.ServeOptions{
+		Port:                    50052,
+		LogVerbosity:            5,
+		MaintenanceMode:         true,
+		MaintenanceWriteMethods: []string{"Create", "Delete"},
+	}
+	r := &configReloader{
+		current:     current,
+		load:        func() (core.ServeOptions, error) { return next, nil },
+		logLevel:    l,
+		maintenance: newMaintenanceMode(false, current.MaintenanceWriteMethods),
+	}
+
+	r.reload()
+
+	if got, want := <-levels, "5"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := r.maintenance.enabled.Load(), true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+	if got, want := r.maintenance.isWrite("/foo.Service/DeleteFoo"), true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+	// The port is not reloadable.
+	want := current
+	want.LogVerbosity = 5
+	want.MaintenanceMode = true
+	want.MaintenanceWriteMethods = []string{"Create", "Delete"}
+	if got := r.current; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	// The options are kept when they cannot be loaded.
+	r.load = func() (core.ServeOptions, error) { return core.ServeOptions{}, fmt.Errorf("boom") }
+	r.reload()
+	if got := r.current; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if got, want := r.maintenance.enabled.Load(), true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+}
+
+func TestNonReloadableChanges(t *testing.T) {
+	current := core.ServeOptions{Port: 50051, AdminToken: "foo", LogVerbosity: 3}
+	next := core.ServeOptions{Port: 50052, AdminToken: "bar", LogVerbosity: 5, MaintenanceMode: true}
+
+	// The reloadable options are not listed.
+	if got, want := nonReloadableChanges(current, next), []string{"Port", "AdminToken"}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if got, want := nonReloadableChanges(current, current), []string{}; !cmp.Equal(got, want) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	log "k8s.io/klog/v2"
+)
+
+const (
+	// repositoryStatusEventsPath is the path of the Server-Sent Events endpoint
+	// of the gateway streaming the status changes of the package repositories.
+	repositoryStatusEventsPath = "/core/packages/v1alpha1/repositories/status-events"
+
+	// repositoryStatusEventsInterval is the interval at which the statuses of
+	// the package repositories are checked for changes.
+	repositoryStatusEventsInterval = 10 * time.Second
+
+	// The events sent to the client: the status of a repository, sent when first
+	// seen and then on each change, the deletion of a repository and an error
+	// after which the stream ends.
+	repositoryStatusEvent  = "status"
+	repositoryDeletedEvent = "deleted"
+	repositoryErrorEvent   = "error"
+)
+
+// repositorySummariesFunc returns the summaries of the package repositories.
+type repositorySummariesFunc func(ctx context.Context, req *packagesGRPCv1alpha1.GetPackageRepositorySummariesRequest) (*packagesGRPCv1alpha1.GetPackageRepositorySummariesResponse, error)
+
+// repositoryStatusEvents streams the status changes of the package repositories
+// as Server-Sent Events, which the browser can consume with an EventSource
+// rather than a grpc-web stream. The summaries are requested through the same
+// loopback gRPC connection as the other gateway requests, with the credentials
+// of the caller, and compared at each interval.
+type repositoryStatusEvents struct {
+	summaries repositorySummariesFunc
+	interval  time.Duration
+	marshaler runtime.Marshaler
+}
+
+// registerRepositoryStatusEvents registers the Server-Sent Events endpoint of
+// the repository status changes with the gateway, unless it is disabled.
+func registerRepositoryStatusEvents(routes *routeTable, gwArgs core.GatewayHandlerArgs, serveOpts core.ServeOptions) error {
+	if gwArgs.Mux == nil {
+		return nil
+	}
+	// The connection is only established by the first request.
+	conn, err := grpc.DialContext(gwArgs.Ctx, gwArgs.Addr, gwArgs.DialOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to dial the repositories service: %w", err)
+	}
+	go func() {
+		<-gwArgs.Ctx.Done()
+		if err := conn.Close(); err != nil {
+			log.Errorf("Failed to close the connection to the repositories service: %v", err)
+		}
+	}()
+	client := packagesGRPCv1alpha1.NewRepositoriesServiceClient(conn)
+	events := &repositoryStatusEvents{
+		summaries: func(ctx context.Context, req *packagesGRPCv1alpha1.GetPackageRepositorySummariesRequest) (*packagesGRPCv1alpha1.GetPackageRepositorySummariesResponse, error) {
+			return client.GetPackageRepositorySummaries(ctx, req)
+		},
+		interval:  repositoryStatusEventsInterval,
+		marshaler: gatewayMarshaler(serveOpts.JSONUseProtoNames),
+	}
+	return routes.handleGateway(gwArgs.Mux, http.MethodGet, repositoryStatusEventsPath, "repository status events", events.serveHTTP)
+}
+
+// serveHTTP streams the status changes of the package repositories of the
+// context given by the "context.cluster" and "context.namespace" query
+// parameters, until the client disconnects or the summaries cannot be fetched.
+func (e *repositoryStatusEvents) serveHTTP(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+	}
+	req := &packagesGRPCv1alpha1.GetPackageRepositorySummariesRequest{
+		Context: &packagesGRPCv1alpha1.Context{
+			Cluster:   r.URL.Query().Get("context.cluster"),
+			Namespace: r.URL.Query().Get("context.namespace"),
+		},
+	}
+
+	// The first request fails with an HTTP error, so that the client does not
+	// reconnect when it is not allowed to list the repositories.
+	res, err := e.summaries(ctx, req)
+	if err != nil {
+		setRetryAfterHeader(ctx, w, err)
+		http.Error(w, status.Convert(err).Message(), runtime.HTTPStatusFromCode(status.Code(err)))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set(cacheControlHeader, defaultCacheControl)
+	// Prevents proxies such as nginx from buffering the events.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	seen := map[string]*packagesGRPCv1alpha1.PackageRepositorySummary{}
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		if err := e.writeChanges(w, seen, res.GetPackageRepositorySummaries()); err != nil {
+			log.Errorf("Unable to write the repository status events: %v", err)
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if res, err = e.summaries(ctx, req); err != nil {
+			if ctx.Err() == nil {
+				e.writeEvent(w, repositoryErrorEvent, status.Convert(err).Proto())
+				flusher.Flush()
+			}
+			return
+		}
+	}
+}
+
+// writeChanges writes the events of the repositories whose status changed since
+// the previous summaries, updating the seen repositories.
+func (e *repositoryStatusEvents) writeChanges(w http.ResponseWriter, seen map[string]*packagesGRPCv1alpha1.PackageRepositorySummary, summaries []*packagesGRPCv1alpha1.PackageRepositorySummary) error {
+	current := map[string]bool{}
+	for _, summary := range summaries {
+		key := repositoryKey(summary.GetPackageRepoRef())
+		current[key] = true
+		if previous, ok := seen[key]; ok && proto.Equal(previous.GetStatus(), summary.GetStatus()) {
+			continue
+		}
+		seen[key] = summary
+		if err := e.writeEvent(w, repositoryStatusEvent, summary); err != nil {
+			return err
+		}
+	}
+	for key, summary := range seen {
+		if current[key] {
+			continue
+		}
+		delete(seen, key)
+		if err := e.writeEvent(w, repositoryDeletedEvent, summary.GetPackageRepoRef()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEvent writes a Server-Sent Event with the message as JSON data.
+func (e *repositoryStatusEvents) writeEvent(w http.ResponseWriter, event string, msg proto.Message) error {
+	data, err := e.marshaler.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+// repositoryKey identifies a package repository across the summaries.
+func repositoryKey(ref *packagesGRPCv1alpha1.PackageRepositoryReference) string {
+	return fmt.Sprintf("%s/%s/%s/%s", ref.GetPlugin().GetName(), ref.GetContext().GetCluster(), ref.GetContext().GetNamespace(), ref.GetIdentifier())
+}
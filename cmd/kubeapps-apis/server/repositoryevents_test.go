@@ -0,0 +1,127 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	pluginsGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestRepositoryStatusEvents(t *testing.T) {
+	repoSummary := func(identifier string, ready bool) *packagesGRPCv1alpha1.PackageRepositorySummary {
+		return &packagesGRPCv1alpha1.PackageRepositorySummary{
+			PackageRepoRef: &packagesGRPCv1alpha1.PackageRepositoryReference{
+				Context:    &packagesGRPCv1alpha1.Context{Cluster: "default", Namespace: "kubeapps"},
+				Identifier: identifier,
+				Plugin:     &pluginsGRPCv1alpha1.Plugin{Name: "helm.packages", Version: "v1alpha1"},
+			},
+			Status: &packagesGRPCv1alpha1.PackageRepositoryStatus{Ready: ready},
+		}
+	}
+
+	t.Run("streams the status changes", func(t *testing.T) {
+		responses := [][]*packagesGRPCv1alpha1.PackageRepositorySummary{
+			{repoSummary("bitnami", false)},
+			{repoSummary("bitnami", false)},
+			{repoSummary("bitnami", true), repoSummary("jetstack", true)},
+			{repoSummary("jetstack", true)},
+		}
+		calls := 0
+		events := &repositoryStatusEvents{
+			summaries: func(ctx context.Context, req *packagesGRPCv1alpha1.GetPackageRepositorySummariesRequest) (*packagesGRPCv1alpha1.GetPackageRepositorySummariesResponse, error) {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				if got, want := md.Get("authorization"), []string{"Bearer foo"}; !cmp.Equal(got, want) {
+					t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+				}
+				if got, want := req.GetContext().GetNamespace(), "kubeapps"; got != want {
+					t.Errorf("got: %q, want: %q", got, want)
+				}
+				res := responses[len(responses)-1]
+				if calls < len(responses) {
+					res = responses[calls]
+				}
+				calls++
+				return &packagesGRPCv1alpha1.GetPackageRepositorySummariesResponse{PackageRepositorySummaries: res}, nil
+			},
+			interval:  10 * time.Millisecond,
+			marshaler: gatewayMarshaler(false),
+		}
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			events.serveHTTP(w, r, nil)
+		}))
+		defer srv.Close()
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"?context.cluster=default&context.namespace=kubeapps", nil)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		req.Header.Set("Authorization", "Bearer foo")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer res.Body.Close()
+		if got, want := res.Header.Get("Content-Type"), "text/event-stream"; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+
+		got := []string{}
+		scanner := bufio.NewScanner(res.Body)
+		for len(got) < 4 && scanner.Scan() {
+			if event, found := strings.CutPrefix(scanner.Text(), "event: "); found {
+				scanner.Scan()
+				data := strings.TrimPrefix(scanner.Text(), "data: ")
+				summary := &packagesGRPCv1alpha1.PackageRepositorySummary{}
+				ref := &packagesGRPCv1alpha1.PackageRepositoryReference{}
+				if event == repositoryDeletedEvent {
+					summary.PackageRepoRef = ref
+					if err := protojson.Unmarshal([]byte(data), ref); err != nil {
+						t.Fatalf("%+v", err)
+					}
+				} else if err := protojson.Unmarshal([]byte(data), summary); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				got = append(got, fmt.Sprintf("%s %s %t", event, summary.GetPackageRepoRef().GetIdentifier(), summary.GetStatus().GetReady()))
+			}
+		}
+		want := []string{
+			"status bitnami false",
+			"status bitnami true",
+			"status jetstack true",
+			"deleted bitnami false",
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("fails with the HTTP status of the first error", func(t *testing.T) {
+		events := &repositoryStatusEvents{
+			summaries: func(ctx context.Context, req *packagesGRPCv1alpha1.GetPackageRepositorySummariesRequest) (*packagesGRPCv1alpha1.GetPackageRepositorySummariesResponse, error) {
+				return nil, status.Error(codes.PermissionDenied, "forbidden")
+			},
+			interval:  10 * time.Millisecond,
+			marshaler: gatewayMarshaler(false),
+		}
+		w := httptest.NewRecorder()
+		events.serveHTTP(w, httptest.NewRequest(http.MethodGet, repositoryStatusEventsPath, nil), nil)
+		if got, want := w.Code, http.StatusForbidden; got != want {
+			t.Errorf("got: %d, want: %d", got, want)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	log "k8s.io/klog/v2"
+)
+
+const (
+	// requestIDHeader identifies a REST request of the gateway. It is forwarded
+	// as metadata to the gRPC call of the gateway, so that the request logs of
+	// both can be correlated.
+	requestIDHeader = "X-Request-Id"
+
+	// maxRequestIDLength bounds the length of the request IDs sent by the
+	// clients, which are otherwise replaced.
+	maxRequestIDLength = 128
+)
+
+// newRequestID returns a random request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Errorf("Unable to generate a request ID: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// validRequestID returns whether the request ID sent by a client can be kept:
+// it must be short and only made of printable ASCII characters without
+// spaces, so that it cannot forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// withGatewayRequestID identifies the REST requests of the gateway with the
+// request ID sent by the client, such as an ingress controller, or else with a
+// new one. The request ID is returned in the response and logged with the REST
+// request, as well as with its gRPC call, to which it is forwarded.
+// Format string : [status code] [duration] [http method] [path] [request id]
+// 200 1.52ms GET /apis/core/packages/v1alpha1/availablepackages 0f4f1c6b3e9d2a7c8b5e4d3c2b1a0f9e
+func withGatewayRequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+			r.Header.Set(requestIDHeader, id)
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		log.V(getLogLevelOfEndpoint(r.URL.Path)).Info(strings.Join([]string{strconv.Itoa(sw.status), time.Since(start).String(), r.Method, r.URL.Path, id}, " "))
+	})
+}
+
+// gatewayIncomingHeaderMatcher forwards the request ID and the API version of
+// the REST requests to the gRPC calls of the gateway, along with the headers
+// forwarded by default.
+func gatewayIncomingHeaderMatcher(key string) (string, bool) {
+	switch http.CanonicalHeaderKey(key) {
+	case requestIDHeader:
+		return strings.ToLower(requestIDHeader), true
+	case apiVersionHeader:
+		return strings.ToLower(apiVersionHeader), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// statusWriter records the status code of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush is required by the streaming responses.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap allows the http.ResponseController to access the wrapped writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
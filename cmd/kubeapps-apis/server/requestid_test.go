@@ -0,0 +1,78 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGatewayRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		requestID  string
+		expectedID string
+	}{
+		{
+			name: "generates a request ID",
+		},
+		{
+			name:       "keeps the request ID of the client",
+			requestID:  "my-ingress-id-1",
+			expectedID: "my-ingress-id-1",
+		},
+		{
+			name:      "replaces a request ID with spaces",
+			requestID: "forged id",
+		},
+		{
+			name:      "replaces a request ID too long",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gwmux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(gatewayIncomingHeaderMatcher))
+			var forwarded []string
+			handler := withGatewayRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx, err := runtime.AnnotateContext(r.Context(), gwmux, r, "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries")
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				md, _ := metadata.FromOutgoingContext(ctx)
+				forwarded = md.Get(requestIDHeader)
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/apis/core/packages/v1alpha1/availablepackages", nil)
+			if tc.requestID != "" {
+				req.Header.Set(requestIDHeader, tc.requestID)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			id := w.Header().Get(requestIDHeader)
+			if tc.expectedID != "" && id != tc.expectedID {
+				t.Errorf("got: %q, want: %q", id, tc.expectedID)
+			}
+			if tc.expectedID == "" && (len(id) != 32 || id == tc.requestID) {
+				t.Errorf("got: %q, want: a new request ID", id)
+			}
+			// The request ID of the response is the one forwarded to the gRPC call.
+			if got, want := forwarded, []string{id}; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if got, want := w.Code, http.StatusTeapot; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	log "k8s.io/klog/v2"
+)
+
+func getLogLevelOfEndpoint(endpoint string) log.Level {
+
+	// Add all endpoint function names which you want to suppress in interceptor logging
+	suppressLoggingOfEndpoints := []string{"GetConfiguredPlugins"}
+	var level log.Level
+
+	// level=3 is default logging level
+	level = 3
+	for i := 0; i < len(suppressLoggingOfEndpoints); i++ {
+		if strings.Contains(endpoint, suppressLoggingOfEndpoints[i]) {
+			level = 4
+			break
+		}
+	}
+
+	return level
+}
+
+const (
+	// requestMessageLogLevel is the verbosity at which the messages of the
+	// unary requests are logged, with their sensitive fields redacted.
+	requestMessageLogLevel log.Level = 5
+
+	// The encodings of the logged request messages.
+	requestLogMessageFormatJSON      = "json"
+	requestLogMessageFormatPrototext = "prototext"
+)
+
+// requestLogger is a connect interceptor that logs the API calls, together with
+// the address of the caller unless disabled for privacy. At high request rates,
+// only a sample of the successful calls of each method is logged, while the
+// failed and slow calls are always logged.
+type requestLogger struct {
+	logClientIPs   bool
+	trustedProxies core.TrustedProxies
+	encodeMessage  func(proto.Message) ([]byte, error)
+
+	sampleRate    uint64
+	slowThreshold time.Duration
+	// calls counts the successful calls by procedure, for the sampling.
+	calls sync.Map
+}
+
+// newRequestLogger returns the request logger configured by the serve options.
+func newRequestLogger(serveOpts core.ServeOptions, trustedProxies core.TrustedProxies) (*requestLogger, error) {
+	l := &requestLogger{
+		logClientIPs:   serveOpts.LogRequestClientIPs,
+		trustedProxies: trustedProxies,
+		sampleRate:     1,
+		slowThreshold:  serveOpts.LogSlowRequestThreshold,
+	}
+	if serveOpts.LogSampleRate > 1 {
+		l.sampleRate = uint64(serveOpts.LogSampleRate)
+	}
+	switch serveOpts.RequestLogMessageFormat {
+	case "", requestLogMessageFormatJSON:
+		l.encodeMessage = protojson.Marshal
+	case requestLogMessageFormatPrototext:
+		l.encodeMessage = prototext.MarshalOptions{Multiline: true}.Marshal
+	default:
+		return nil, fmt.Errorf("unsupported request log message format %q, expected %q or %q", serveOpts.RequestLogMessageFormat, requestLogMessageFormatJSON, requestLogMessageFormatPrototext)
+	}
+	return l, nil
+}
+
+func (l *requestLogger) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx = contextWithPluginOfRequest(ctx, req.Spec().Procedure, req.Any())
+		l.logMessage(ctx, req)
+		start := time.Now()
+		res, err := next(ctx, req)
+		duration := time.Since(start)
+		setServerTiming(res, err, duration)
+		l.log(ctx, duration, err, req.HTTPMethod(), req.Spec().Procedure, req.Peer().Addr, req.Header())
+		return res, err
+	}
+}
+
+func (l *requestLogger) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (l *requestLogger) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx = contextWithPluginOfRequest(ctx, conn.Spec().Procedure, nil)
+		start := time.Now()
+		err := next(ctx, conn)
+		// Streaming requests are always sent with POST.
+		l.log(ctx, time.Since(start), err, http.MethodPost, conn.Spec().Procedure, conn.Peer().Addr, conn.RequestHeader())
+		return err
+	}
+}
+
+// log logs a single API call, with the request ID of the REST request of the
+// gateway making the call, if any.
+// Format string : [status code] [duration] [http method] [full path] [peer address] [client ip] [client cert identity] [request id]
+// ok 97.752µs GET /kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries 127.0.0.1:51234 10.0.0.12 CN=client 0f4f1c6b3e9d2a7c8b5e4d3c2b1a0f9e
+func (l *requestLogger) log(ctx context.Context, duration time.Duration, err error, httpMethod, procedure, peerAddr string, header http.Header) {
+	if !l.sampled(procedure, duration, err) {
+		return
+	}
+	code := "ok"
+	if err != nil {
+		code = connect.CodeOf(err).String()
+	}
+	fields := []string{code, duration.String(), httpMethod, procedure}
+	if l.logClientIPs {
+		fields = append(fields, peerAddr, clientIP(l.trustedProxies, peerAddr, header))
+	}
+	if id, ok := core.ClientCertIdentityFromContext(ctx); ok {
+		fields = append(fields, id.String())
+	}
+	if id := header.Get(requestIDHeader); validRequestID(id) {
+		fields = append(fields, id)
+	}
+	core.V(ctx, getLogLevelOfEndpoint(procedure)).Info(strings.Join(fields, " "))
+}
+
+// sampled returns whether a call is logged: the failed and slow calls always
+// are, while only the first of every sample rate successful calls of each
+// method is.
+func (l *requestLogger) sampled(procedure string, duration time.Duration, err error) bool {
+	if l.sampleRate <= 1 || err != nil || (l.slowThreshold > 0 && duration >= l.slowThreshold) {
+		return true
+	}
+	counter, _ := l.calls.LoadOrStore(procedure, &atomic.Uint64{})
+	return (counter.(*atomic.Uint64).Add(1)-1)%l.sampleRate == 0
+}
+
+// logMessage logs the message of a unary request, with its sensitive fields
+// redacted, at a high verbosity intended for debugging.
+func (l *requestLogger) logMessage(ctx context.Context, req connect.AnyRequest) {
+	logger := core.V(ctx, requestMessageLogLevel)
+	if !logger.Enabled() {
+		return
+	}
+	msg, ok := req.Any().(proto.Message)
+	if !ok {
+		return
+	}
+	encoded, err := l.encodeMessage(core.RedactSensitiveFields(msg))
+	if err != nil {
+		log.Errorf("Unable to encode the request message of %q: %v", req.Spec().Procedure, err)
+		return
+	}
+	logger.InfoS("+core request message", "procedure", req.Spec().Procedure, "message", string(encoded))
+}
+
+// contextWithPluginOfRequest tags the context with the plugin of the request,
+// if any, so that its logs, including those of the core services dispatching
+// the request to the plugin, follow the log level of the plugin. The plugin is
+// either the one of the procedure or the one referenced by the request message.
+func contextWithPluginOfRequest(ctx context.Context, procedure string, msg any) context.Context {
+	plugin := pluginOfProcedure(procedure)
+	if m, ok := msg.(proto.Message); ok && plugin == "" {
+		plugin = pluginOfMessage(m.ProtoReflect())
+	}
+	if plugin == "" {
+		return ctx
+	}
+	return core.ContextWithPluginName(ctx, plugin)
+}
+
+// serverTimingHeader is the header reporting the processing time of a unary
+// call, such as "total;dur=12.345" (in milliseconds), so that it is shown by
+// the developer tools of the browsers. The gateway forwards it as is.
+const serverTimingHeader = "Server-Timing"
+
+// setServerTiming sets the processing time of a unary call on its response or,
+// when it failed, on the metadata of its error.
+func setServerTiming(res connect.AnyResponse, err error, duration time.Duration) {
+	value := fmt.Sprintf("total;dur=%.3f", float64(duration)/float64(time.Millisecond))
+	if err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			connectErr.Meta().Set(serverTimingHeader, value)
+		}
+		return
+	}
+	if res != nil {
+		res.Header().Set(serverTimingHeader, value)
+	}
+}
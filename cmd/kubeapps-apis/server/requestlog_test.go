@@ -0,0 +1,229 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	pluginsGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+)
+
+func TestRequestLoggerSetsServerTiming(t *testing.T) {
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "sets the server timing of a successful call",
+		},
+		{
+			name: "sets the server timing of a failed call",
+			err:  connect.NewError(connect.CodeNotFound, fmt.Errorf("not found")),
+		},
+	}
+
+	requestLogger, err := newRequestLogger(core.ServeOptions{}, core.TrustedProxies{})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := serveTestProcedure(t, procedure,
+				func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest]) (*connect.Response[packagesGRPCv1alpha1.GetInstalledPackageDetailResponse], error) {
+					if tc.err != nil {
+						return nil, tc.err
+					}
+					return connect.NewResponse(&packagesGRPCv1alpha1.GetInstalledPackageDetailResponse{}), nil
+				},
+				connect.WithInterceptors(requestLogger),
+			)
+
+			client := connect.NewClient[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest, packagesGRPCv1alpha1.GetInstalledPackageDetailResponse](ts.Client(), ts.URL+procedure)
+			res, err := client.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetInstalledPackageDetailRequest{}))
+			var header http.Header
+			if tc.err != nil {
+				var connectErr *connect.Error
+				if !errors.As(err, &connectErr) {
+					t.Fatalf("got: %+v, want: a connect error", err)
+				}
+				header = connectErr.Meta()
+			} else {
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				header = res.Header()
+			}
+
+			if got := header.Get(serverTimingHeader); !regexp.MustCompile(`^total;dur=\d+\.\d{3}$`).MatchString(got) {
+				t.Errorf("got: %q, want: total;dur=<milliseconds>", got)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerSampling(t *testing.T) {
+	summaries := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+	detail := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail"
+	type call struct {
+		procedure string
+		duration  time.Duration
+		err       error
+	}
+	testCases := []struct {
+		name       string
+		sampleRate int
+		calls      []call
+		expected   []bool
+	}{
+		{
+			name:       "logs every call without sampling",
+			sampleRate: 0,
+			calls:      []call{{procedure: summaries}, {procedure: summaries}},
+			expected:   []bool{true, true},
+		},
+		{
+			name:       "logs 1 in N successful calls of each method",
+			sampleRate: 3,
+			calls: []call{
+				{procedure: summaries},
+				{procedure: summaries},
+				{procedure: detail},
+				{procedure: summaries},
+				{procedure: summaries},
+			},
+			expected: []bool{true, false, true, false, true},
+		},
+		{
+			name:       "always logs the failed and slow calls",
+			sampleRate: 3,
+			calls: []call{
+				{procedure: summaries},
+				{procedure: summaries, err: connect.NewError(connect.CodeInternal, fmt.Errorf("boom"))},
+				{procedure: summaries, duration: 2 * time.Second},
+				{procedure: summaries},
+			},
+			expected: []bool{true, true, true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := newRequestLogger(core.ServeOptions{LogSampleRate: tc.sampleRate, LogSlowRequestThreshold: time.Second}, core.TrustedProxies{})
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			got := []bool{}
+			for _, c := range tc.calls {
+				got = append(got, l.sampled(c.procedure, c.duration, c.err))
+			}
+			if want := tc.expected; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestRequestLoggerMessageFormat(t *testing.T) {
+	msg := &packagesGRPCv1alpha1.UpdateInstalledPackageRequest{
+		InstalledPackageRef: &packagesGRPCv1alpha1.InstalledPackageReference{Identifier: "my-apache"},
+		Values:              "password: secret",
+	}
+
+	testCases := []struct {
+		name            string
+		format          string
+		expectedMessage string
+		expectedError   bool
+	}{
+		{
+			name:            "encodes the messages as JSON by default",
+			expectedMessage: `{"installedPackageRef":{"identifier":"my-apache"},"values":"REDACTED"}`,
+		},
+		{
+			name:            "encodes the messages as protobuf text",
+			format:          requestLogMessageFormatPrototext,
+			expectedMessage: "installed_package_ref: {\n  identifier: \"my-apache\"\n}\nvalues: \"REDACTED\"\n",
+		},
+		{
+			name:          "errors for an unknown format",
+			format:        "yaml",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, err := newRequestLogger(core.ServeOptions{RequestLogMessageFormat: tc.format}, core.TrustedProxies{})
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got: %t, want: %t: err: %+v", got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			encoded, err := logger.encodeMessage(core.RedactSensitiveFields(msg))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			// The encodings are not stable, so the spaces are ignored.
+			if got, want := strings.Join(strings.Fields(string(encoded)), ""), strings.Join(strings.Fields(tc.expectedMessage), ""); got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestContextWithPluginOfRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		procedure      string
+		msg            any
+		expectedPlugin string
+	}{
+		{
+			name:           "tags the plugin of the procedure",
+			procedure:      "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2PackagesService/GetAvailablePackageSummaries",
+			expectedPlugin: "fluxv2.packages",
+		},
+		{
+			name:      "tags the plugin referenced by the request",
+			procedure: "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail",
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &packagesGRPCv1alpha1.AvailablePackageReference{
+					Identifier: "bitnami/apache",
+					Plugin:     &pluginsGRPCv1alpha1.Plugin{Name: "helm.packages", Version: "v1alpha1"},
+				},
+			},
+			expectedPlugin: "helm.packages",
+		},
+		{
+			name:           "does not tag the requests across plugins",
+			procedure:      "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries",
+			msg:            &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+			expectedPlugin: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := contextWithPluginOfRequest(context.Background(), tc.procedure, tc.msg)
+			plugin, _ := core.PluginNameFromContext(ctx)
+			if got, want := plugin, tc.expectedPlugin; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/packages/v1alpha1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// contextMessageName is the message giving the cluster and namespace targeted
+// by the requests of the core services and of the plugins.
+const contextMessageName = "kubeappsapis.core.packages.v1alpha1.Context"
+
+// The policies resolving the namespace of the requests omitting it.
+const (
+	// namespacePolicyError rejects the requests omitting the namespace.
+	namespacePolicyError = "error"
+	// namespacePolicyDefault sets the namespace to the default namespace.
+	namespacePolicyDefault = "default"
+	// namespacePolicyAll sets the namespace to all the namespaces.
+	namespacePolicyAll = "all"
+)
+
+// namespaceWriteMethods are the prefixes of the names of the methods creating
+// or changing resources, such as CreateInstalledPackage or AddPackageRepository,
+// whose namespace is never resolved to all the namespaces. It includes the
+// methods taking the request of a write, such as ValidatePackageRepository.
+var namespaceWriteMethods = []string{"Create", "Update", "Delete", "Add", "Validate", "Invalidate"}
+
+// namespaceListMethods are the suffixes of the names of the methods listing
+// resources or permissions, for which an empty namespace already means all the
+// namespaces.
+var namespaceListMethods = []string{"Summaries", "Permissions"}
+
+// requestTargets is a connect interceptor tagging the context of the unary
+// requests with the cluster and namespace they target, see core.RequestTarget.
+// The default cluster is the cluster on which Kubeapps is installed, which is
+// only known once the clusters config is parsed, before serving. When a
+// namespace policy is configured, the namespace omitted by a request is
+// resolved by the policy, in the request message itself, so that all the
+// plugins handle it the same way. Otherwise, the plugins handle it as they see
+// fit.
+type requestTargets struct {
+	defaultCluster   string
+	namespacePolicy  string
+	defaultNamespace string
+}
+
+// newRequestTargets returns the request targets resolving the omitted
+// namespaces with the given policy, if any.
+func newRequestTargets(namespacePolicy, defaultNamespace string) (*requestTargets, error) {
+	switch namespacePolicy {
+	case "", namespacePolicyError, namespacePolicyAll:
+	case namespacePolicyDefault:
+		if defaultNamespace == "" {
+			return nil, fmt.Errorf("a default namespace is required by the namespace policy %q", namespacePolicy)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported namespace policy %q, expected %q, %q or %q", namespacePolicy, namespacePolicyError, namespacePolicyDefault, namespacePolicyAll)
+	}
+	return &requestTargets{namespacePolicy: namespacePolicy, defaultNamespace: defaultNamespace}, nil
+}
+
+// setDefaultCluster sets the cluster of the requests not giving one. It must be
+// called before serving.
+func (t *requestTargets) setDefaultCluster(cluster string) {
+	t.defaultCluster = cluster
+}
+
+// targetOf returns the target of a request message.
+func (t *requestTargets) targetOf(msg any) core.RequestTarget {
+	target := core.RequestTarget{}
+	if m, ok := msg.(proto.Message); ok {
+		target = targetOfMessage(m.ProtoReflect())
+	}
+	if target.Cluster == "" {
+		target.Cluster = t.defaultCluster
+	}
+	return target
+}
+
+// resolveNamespace applies the namespace policy to the contexts of the request
+// message of the given procedure omitting the namespace:
+//   - the writes are only resolved to the default namespace, if any, and are
+//     rejected otherwise, so that a resource is never created in all the
+//     namespaces;
+//   - the lists keep the empty namespace, meaning all the namespaces, with the
+//     error policy.
+func (t *requestTargets) resolveNamespace(procedure string, msg any) error {
+	m, ok := msg.(proto.Message)
+	if !ok || t.namespacePolicy == "" {
+		return nil
+	}
+	isWrite := methodHasPrefix(procedure, namespaceWriteMethods)
+	if t.namespacePolicy == namespacePolicyError && !isWrite && methodHasSuffix(procedure, namespaceListMethods) {
+		return nil
+	}
+	return rangeContextsWithoutNamespace(m.ProtoReflect(), func(c protoreflect.Message) error {
+		namespace := c.Descriptor().Fields().ByName("namespace")
+		switch {
+		case t.namespacePolicy == namespacePolicyDefault, isWrite && t.namespacePolicy == namespacePolicyAll && t.defaultNamespace != "":
+			c.Set(namespace, protoreflect.ValueOfString(t.defaultNamespace))
+		case t.namespacePolicy == namespacePolicyAll && !isWrite:
+			c.Set(namespace, protoreflect.ValueOfString(packagesv1alpha1.AllNamespaces))
+		default:
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("The namespace of the request is required"))
+		}
+		return nil
+	})
+}
+
+// methodHasSuffix returns whether the method name of the given procedure ends
+// with any of the given suffixes.
+func methodHasSuffix(procedure string, suffixes []string) bool {
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(method, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *requestTargets) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := t.resolveNamespace(req.Spec().Procedure, req.Any()); err != nil {
+			return nil, err
+		}
+		return next(core.ContextWithRequestTarget(ctx, t.targetOf(req.Any())), req)
+	}
+}
+
+func (t *requestTargets) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (t *requestTargets) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// targetOfMessage returns the target given by the context of the message or of
+// its nested messages, such as the context of the available_package_ref, if any.
+func targetOfMessage(m protoreflect.Message) core.RequestTarget {
+	if m.Descriptor().FullName() == contextMessageName {
+		fields := m.Descriptor().Fields()
+		return core.RequestTarget{
+			Cluster:   m.Get(fields.ByName("cluster")).String(),
+			Namespace: m.Get(fields.ByName("namespace")).String(),
+		}
+	}
+	target := core.RequestTarget{}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return true
+		}
+		target = targetOfMessage(v.Message())
+		return target == core.RequestTarget{}
+	})
+	return target
+}
+
+// rangeContextsWithoutNamespace calls f with the contexts of the message, or of
+// its nested messages, whose namespace is empty, such as the context of the
+// available_package_ref. A context field which is not set, such as the context
+// of a list request, is set to an empty context first.
+func rangeContextsWithoutNamespace(m protoreflect.Message, f func(c protoreflect.Message) error) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			continue
+		}
+		if fd.Message().FullName() == contextMessageName {
+			if m.Get(fd).Message().Get(fd.Message().Fields().ByName("namespace")).String() == "" {
+				if err := f(m.Mutable(fd).Message()); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if m.Has(fd) {
+			if err := rangeContextsWithoutNamespace(m.Mutable(fd).Message(), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core/packages/v1alpha1"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequestTargets(t *testing.T) {
+	testCases := []struct {
+		name     string
+		msg      any
+		expected core.RequestTarget
+	}{
+		{
+			name: "the context of the request",
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{
+				Context: &packagesGRPCv1alpha1.Context{Cluster: "other", Namespace: "kubeapps"},
+			},
+			expected: core.RequestTarget{Cluster: "other", Namespace: "kubeapps"},
+		},
+		{
+			name: "the context of a nested reference",
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &packagesGRPCv1alpha1.AvailablePackageReference{
+					Context:    &packagesGRPCv1alpha1.Context{Cluster: "other", Namespace: "kubeapps"},
+					Identifier: "bitnami/apache",
+				},
+			},
+			expected: core.RequestTarget{Cluster: "other", Namespace: "kubeapps"},
+		},
+		{
+			name: "the default cluster when the cluster is empty",
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{
+				Context: &packagesGRPCv1alpha1.Context{Namespace: "kubeapps"},
+			},
+			expected: core.RequestTarget{Cluster: "default", Namespace: "kubeapps"},
+		},
+		{
+			name:     "the default cluster when the context is missing",
+			msg:      &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+			expected: core.RequestTarget{Cluster: "default"},
+		},
+		{
+			name:     "the default cluster without a message",
+			msg:      nil,
+			expected: core.RequestTarget{Cluster: "default"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targets := &requestTargets{}
+			targets.setDefaultCluster("default")
+			if got, want := targets.targetOf(tc.msg), tc.expected; got != want {
+				t.Errorf("got: %+v, want: %+v", got, want)
+			}
+		})
+	}
+
+	// The context of the unary requests is tagged with their target.
+	targets := &requestTargets{defaultCluster: "default"}
+	var got core.RequestTarget
+	next := targets.WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		got, _ = core.RequestTargetFromContext(ctx)
+		return nil, nil
+	})
+	if _, err := next(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{
+		Context: &packagesGRPCv1alpha1.Context{Namespace: "kubeapps"},
+	})); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if want := (core.RequestTarget{Cluster: "default", Namespace: "kubeapps"}); got != want {
+		t.Errorf("got: %+v, want: %+v", got, want)
+	}
+}
+
+func TestRequestTargetsNamespacePolicy(t *testing.T) {
+	const (
+		listProcedure   = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries"
+		getProcedure    = "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail"
+		createProcedure = "/kubeappsapis.core.packages.v1alpha1.PackagesService/CreateInstalledPackage"
+		addProcedure    = "/kubeappsapis.core.packages.v1alpha1.RepositoriesService/AddPackageRepository"
+	)
+	getRequest := func() proto.Message {
+		return &packagesGRPCv1alpha1.GetAvailablePackageDetailRequest{
+			AvailablePackageRef: &packagesGRPCv1alpha1.AvailablePackageReference{Context: &packagesGRPCv1alpha1.Context{Cluster: "default"}},
+		}
+	}
+	createRequest := func() proto.Message {
+		return &packagesGRPCv1alpha1.CreateInstalledPackageRequest{
+			TargetContext: &packagesGRPCv1alpha1.Context{Cluster: "default"},
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		policy            string
+		defaultNamespace  string
+		procedure         string
+		msg               proto.Message
+		expectedNamespace string
+		expectedCode      connect.Code
+	}{
+		{
+			name:      "leaves the omitted namespace without a policy",
+			policy:    "",
+			procedure: listProcedure,
+			msg:       &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+		},
+		{
+			name:      "keeps the omitted namespace of a list, meaning all the namespaces, with the error policy",
+			policy:    namespacePolicyError,
+			procedure: listProcedure,
+			msg:       &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+		},
+		{
+			name:         "rejects the empty namespace of a reference",
+			policy:       namespacePolicyError,
+			procedure:    getProcedure,
+			msg:          getRequest(),
+			expectedCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:         "rejects the omitted namespace of a create with the error policy",
+			policy:       namespacePolicyError,
+			procedure:    createProcedure,
+			msg:          createRequest(),
+			expectedCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:      "accepts the namespace with the error policy",
+			policy:    namespacePolicyError,
+			procedure: listProcedure,
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{
+				Context: &packagesGRPCv1alpha1.Context{Namespace: "kubeapps"},
+			},
+			expectedNamespace: "kubeapps",
+		},
+		{
+			name:              "sets the omitted namespace to the default one",
+			policy:            namespacePolicyDefault,
+			defaultNamespace:  "apps",
+			procedure:         listProcedure,
+			msg:               &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+			expectedNamespace: "apps",
+		},
+		{
+			name:              "sets the empty namespace of a reference to the default one",
+			policy:            namespacePolicyDefault,
+			defaultNamespace:  "apps",
+			procedure:         getProcedure,
+			msg:               getRequest(),
+			expectedNamespace: "apps",
+		},
+		{
+			name:              "sets the omitted namespace of a create to the default one",
+			policy:            namespacePolicyDefault,
+			defaultNamespace:  "apps",
+			procedure:         createProcedure,
+			msg:               createRequest(),
+			expectedNamespace: "apps",
+		},
+		{
+			name:              "sets the omitted namespace of a list to all the namespaces",
+			policy:            namespacePolicyAll,
+			procedure:         listProcedure,
+			msg:               &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+			expectedNamespace: packagesv1alpha1.AllNamespaces,
+		},
+		{
+			name:              "sets the empty namespace of a reference to all the namespaces",
+			policy:            namespacePolicyAll,
+			procedure:         getProcedure,
+			msg:               getRequest(),
+			expectedNamespace: packagesv1alpha1.AllNamespaces,
+		},
+		{
+			name:         "rejects the omitted namespace of a create with the all policy",
+			policy:       namespacePolicyAll,
+			procedure:    createProcedure,
+			msg:          createRequest(),
+			expectedCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:         "rejects the omitted namespace of a repository added with the all policy",
+			policy:       namespacePolicyAll,
+			procedure:    addProcedure,
+			msg:          &packagesGRPCv1alpha1.AddPackageRepositoryRequest{},
+			expectedCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:              "sets the omitted namespace of a create to the default one with the all policy",
+			policy:            namespacePolicyAll,
+			defaultNamespace:  "apps",
+			procedure:         createProcedure,
+			msg:               createRequest(),
+			expectedNamespace: "apps",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targets, err := newRequestTargets(tc.policy, tc.defaultNamespace)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			targets.setDefaultCluster("default")
+			err = targets.resolveNamespace(tc.procedure, tc.msg)
+			if tc.expectedCode != 0 {
+				if got, want := connect.CodeOf(err), tc.expectedCode; got != want {
+					t.Errorf("got: %v, want: %v, err: %+v", got, want, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			// The namespace is resolved in the request message, for the plugins,
+			// before the target of the request is found.
+			if want := (core.RequestTarget{Cluster: "default", Namespace: tc.expectedNamespace}); targets.targetOf(tc.msg) != want {
+				t.Errorf("got: %+v, want: %+v", targets.targetOf(tc.msg), want)
+			}
+		})
+	}
+
+	if _, err := newRequestTargets(namespacePolicyDefault, ""); err == nil {
+		t.Errorf("got: nil, want: an error for the default policy without a default namespace")
+	}
+	if _, err := newRequestTargets("user", ""); err == nil {
+		t.Errorf("got: nil, want: an error for an unsupported policy")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+)
+
+// requiredAuth is a connect interceptor failing the requests without a bearer
+// token with an Unauthenticated error, except for those of the public methods,
+// so that the anonymous requests do not reach the plugins, which may or may
+// not enforce the authentication themselves. The token itself is checked by
+// the Kubernetes API server, or by the caller identity reviewer when enabled.
+type requiredAuth struct {
+	publicMethods map[string]bool
+}
+
+// newRequiredAuth parses the full names of the public methods, such as
+// "/kubeappsapis.core.plugins.v1alpha1.PluginsService/GetConfiguredPlugins".
+func newRequiredAuth(publicMethods []string) (*requiredAuth, error) {
+	r := &requiredAuth{publicMethods: map[string]bool{}}
+	for _, value := range publicMethods {
+		if !isMethodFullName(value) {
+			return nil, fmt.Errorf("invalid public method %q, expected /<service full name>/<method name>", value)
+		}
+		r.publicMethods[value] = true
+	}
+	return r, nil
+}
+
+// check returns an Unauthenticated error if the procedure is not public and
+// the request has no bearer token.
+func (r *requiredAuth) check(procedure string, header http.Header) error {
+	if r.publicMethods[procedure] {
+		return nil
+	}
+	if token, found := strings.CutPrefix(header.Get("Authorization"), "Bearer "); found && token != "" {
+		return nil
+	}
+	return connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("The method %q requires a bearer token", procedure))
+}
+
+func (r *requiredAuth) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := r.check(req.Spec().Procedure, req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (r *requiredAuth) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (r *requiredAuth) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := r.check(conn.Spec().Procedure, conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+)
+
+func TestRequiredAuth(t *testing.T) {
+	publicMethod := "/kubeappsapis.core.plugins.v1alpha1.PluginsService/GetConfiguredPlugins"
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+
+	testCases := []struct {
+		name          string
+		values        []string
+		procedure     string
+		authorization string
+		expectErr     bool
+		expectedCode  connect.Code
+	}{
+		{
+			name:         "rejects a request without a bearer token",
+			values:       []string{publicMethod},
+			procedure:    procedure,
+			expectedCode: connect.CodeUnauthenticated,
+		},
+		{
+			name:          "rejects a request with an empty bearer token",
+			values:        []string{publicMethod},
+			procedure:     procedure,
+			authorization: "Bearer ",
+			expectedCode:  connect.CodeUnauthenticated,
+		},
+		{
+			name:          "rejects a request with another authorization scheme",
+			values:        []string{publicMethod},
+			procedure:     procedure,
+			authorization: "Basic dXNlcjpwYXNz",
+			expectedCode:  connect.CodeUnauthenticated,
+		},
+		{
+			name:          "allows a request with a bearer token",
+			values:        []string{publicMethod},
+			procedure:     procedure,
+			authorization: "Bearer token",
+		},
+		{
+			name:      "allows a public method without a bearer token",
+			values:    []string{publicMethod},
+			procedure: publicMethod,
+		},
+		{
+			name:      "rejects an invalid public method",
+			values:    []string{"GetConfiguredPlugins"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requireAuth, err := newRequiredAuth(tc.values)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if tc.expectErr {
+				return
+			}
+			header := http.Header{}
+			if tc.authorization != "" {
+				header.Set("Authorization", tc.authorization)
+			}
+			err = requireAuth.check(tc.procedure, header)
+			if tc.expectedCode == 0 {
+				if err != nil {
+					t.Errorf("got: %+v, want no error", err)
+				}
+				return
+			}
+			if got, want := connect.CodeOf(err), tc.expectedCode; got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
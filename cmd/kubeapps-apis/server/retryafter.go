@@ -0,0 +1,65 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// retryAfterHeader is the metadata, sent with the errors of the requests
+	// failing for a transient reason, giving the number of seconds after which
+	// the client can retry. The gateway responds with the same HTTP header.
+	retryAfterHeader = "Retry-After"
+
+	// timeoutRetryAfter is the retry hint of the requests exceeding their
+	// timeout, which mostly time out while the server or the plugins are busy.
+	timeoutRetryAfter = 10 * time.Second
+
+	// resourceExhaustedRetryAfter is the retry hint of the ResourceExhausted
+	// errors without one, such as those of a saturated plugin, so that the
+	// clients back off rather than retrying right away.
+	resourceExhaustedRetryAfter = 5 * time.Second
+)
+
+// withRetryAfter returns the error with a hint of the duration after which the
+// client can retry, rounded up to the second, in its metadata.
+func withRetryAfter(err error, after time.Duration) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		connectErr = connect.NewError(connect.CodeOf(err), err)
+		err = connectErr
+	}
+	connectErr.Meta().Set(retryAfterHeader, strconv.Itoa(int(math.Ceil(after.Seconds()))))
+	return err
+}
+
+// setRetryAfterHeader sets the Retry-After header of an error response of the
+// gateway from the metadata of the error, if any. The metadata of the errors
+// is received in the trailers, since the responses have no message. The
+// ResourceExhausted errors, to which the gateway responds with a 429 Too Many
+// Requests, always have a retry hint.
+func setRetryAfterHeader(ctx context.Context, w http.ResponseWriter, err error) {
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		for _, values := range [][]string{md.TrailerMD.Get(retryAfterHeader), md.HeaderMD.Get(retryAfterHeader)} {
+			if len(values) > 0 {
+				w.Header().Set(retryAfterHeader, values[0])
+				return
+			}
+		}
+	}
+	if status.Code(err) == codes.ResourceExhausted {
+		w.Header().Set(retryAfterHeader, strconv.Itoa(int(resourceExhaustedRetryAfter.Seconds())))
+	}
+}
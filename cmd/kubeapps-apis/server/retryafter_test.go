@@ -0,0 +1,110 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetRetryAfterHeader(t *testing.T) {
+	testCases := []struct {
+		name           string
+		md             *runtime.ServerMetadata
+		err            error
+		expectedHeader string
+	}{
+		{
+			name: "no server metadata",
+		},
+		{
+			name: "error without retry hint",
+			md:   &runtime.ServerMetadata{TrailerMD: metadata.Pairs("x-custom", "value")},
+		},
+		{
+			name:           "retry hint in the trailers",
+			md:             &runtime.ServerMetadata{TrailerMD: metadata.Pairs("retry-after", "10")},
+			expectedHeader: "10",
+		},
+		{
+			name:           "retry hint in the headers",
+			md:             &runtime.ServerMetadata{HeaderMD: metadata.Pairs("retry-after", "3600")},
+			expectedHeader: "3600",
+		},
+		{
+			name:           "resource exhausted without retry hint",
+			err:            status.Error(codes.ResourceExhausted, "too many requests"),
+			expectedHeader: "5",
+		},
+		{
+			name:           "resource exhausted with a retry hint",
+			md:             &runtime.ServerMetadata{TrailerMD: metadata.Pairs("retry-after", "3600")},
+			err:            status.Error(codes.ResourceExhausted, "daily quota exceeded"),
+			expectedHeader: "3600",
+		},
+		{
+			name: "other errors without retry hint",
+			err:  status.Error(codes.Internal, "boom"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.md != nil {
+				ctx = runtime.NewServerMetadataContext(ctx, *tc.md)
+			}
+			w := httptest.NewRecorder()
+			setRetryAfterHeader(ctx, w, tc.err)
+			if got, want := w.Header().Get(retryAfterHeader), tc.expectedHeader; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGatewayErrorHandlerStatus(t *testing.T) {
+	testCases := []struct {
+		name               string
+		err                error
+		expectedStatus     int
+		expectedRetryAfter string
+	}{
+		{
+			name:               "saturated backend",
+			err:                status.Error(codes.ResourceExhausted, "too many requests"),
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "5",
+		},
+		{
+			name:           "internal error",
+			err:            status.Error(codes.Internal, "boom"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	cacheControl, err := newCacheControl(nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			cacheControl.errorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, httptest.NewRequest(http.MethodGet, "/", nil), tc.err)
+			if got, want := w.Code, tc.expectedStatus; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+			if got, want := w.Header().Get(retryAfterHeader), tc.expectedRetryAfter; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
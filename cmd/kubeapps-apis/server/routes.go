@@ -0,0 +1,185 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	log "k8s.io/klog/v2"
+)
+
+const (
+	// anyMethod is the method of the routes handling every method.
+	anyMethod = "*"
+
+	// The muxes routing the requests. The server mux takes precedence, passing
+	// the requests it does not route to the gateway.
+	serverMuxName  = "server"
+	gatewayMuxName = "gateway"
+)
+
+// routeEntry is a route handled by the server, such as the POST requests to
+// "/kubeappsapis.core.packages.v1alpha1.PackagesService/" routed by the server
+// mux to the connect handler of the service.
+type routeEntry struct {
+	Mux     string `json:"mux"`
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+	Handler string `json:"handler"`
+}
+
+// routeTable reports the routes handled by the server, so that it is clear
+// which handler serves a given URL. The routes registered by the server itself
+// are recorded, while those of the connect handlers and of the gateway for the
+// services, including the services of the plugins, are found in the protobuf
+// registry.
+type routeTable struct {
+	mu       sync.Mutex
+	mux      *http.ServeMux
+	files    *protoregistry.Files
+	gateway  bool
+	exposed  gatewayExposedMethods
+	recorded []routeEntry
+}
+
+// newRouteTable returns a route table for the server mux. The gateway routes of
+// the services are only reported when the REST gateway is enabled, and for the
+// methods exposed through it.
+func newRouteTable(mux *http.ServeMux, files *protoregistry.Files, gateway bool, exposed gatewayExposedMethods) *routeTable {
+	return &routeTable{mux: mux, files: files, gateway: gateway, exposed: exposed}
+}
+
+// handle registers the handler with the server mux, recording its route.
+func (t *routeTable) handle(pattern, name string, handler http.Handler) {
+	t.mux.Handle(pattern, handler)
+	t.record(serverMuxName, anyMethod, pattern, name)
+}
+
+// handleGateway registers the handler with the gateway, recording its route.
+func (t *routeTable) handleGateway(gwmux *runtime.ServeMux, method, pattern, name string, handler runtime.HandlerFunc) error {
+	if err := gwmux.HandlePath(method, pattern, handler); err != nil {
+		return err
+	}
+	t.record(gatewayMuxName, method, pattern, name)
+	return nil
+}
+
+// gatewayConflicts returns the gateway routes registered by more than one
+// handler, such as when two plugins bind the same path, along with their
+// handlers. The gateway silently routes the requests of a conflicting route to
+// only one of its handlers. The path parameters are ignored when comparing
+// the routes, since they do not change the routing.
+func (t *routeTable) gatewayConflicts() map[string][]string {
+	handlers := map[string][]string{}
+	t.mu.Lock()
+	for _, r := range t.recorded {
+		if r.Mux == gatewayMuxName {
+			handlers[route(r.Method, r.Pattern)] = append(handlers[route(r.Method, r.Pattern)], r.Handler)
+		}
+	}
+	t.mu.Unlock()
+	rangeHTTPRules(t.files, func(method protoreflect.MethodDescriptor, httpMethod, path string) {
+		handlers[route(httpMethod, path)] = append(handlers[route(httpMethod, path)], string(method.FullName()))
+	})
+
+	conflicts := map[string][]string{}
+	for r, names := range handlers {
+		if len(names) > 1 {
+			sort.Strings(names)
+			conflicts[r] = names
+		}
+	}
+	return conflicts
+}
+
+func (t *routeTable) record(mux, method, pattern, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.recorded = append(t.recorded, routeEntry{Mux: mux, Method: method, Pattern: pattern, Handler: name})
+}
+
+// routes returns the route table, with the routes of the server mux first and
+// its catch-all route, passing the other requests to the gateway, last among them.
+func (t *routeTable) routes() []routeEntry {
+	t.mu.Lock()
+	routes := append([]routeEntry{}, t.recorded...)
+	t.mu.Unlock()
+
+	rangeServices(t.files, func(service protoreflect.ServiceDescriptor) {
+		// Only the services whose connect handler is registered are routed by
+		// the server mux, the requests to the others reaching the gateway.
+		path := "/" + string(service.FullName()) + "/"
+		if _, pattern := t.mux.Handler(&http.Request{Method: http.MethodPost, URL: &url.URL{Path: path}}); pattern == path {
+			routes = append(routes, routeEntry{Mux: serverMuxName, Method: http.MethodPost, Pattern: path, Handler: string(service.FullName())})
+		}
+	})
+	if t.gateway {
+		rangeHTTPRules(t.files, func(method protoreflect.MethodDescriptor, httpMethod, path string) {
+			if !t.exposed.exposed("/" + string(method.Parent().FullName()) + "/" + string(method.Name())) {
+				return
+			}
+			routes = append(routes, routeEntry{Mux: gatewayMuxName, Method: httpMethod, Pattern: path, Handler: string(method.FullName())})
+		})
+	}
+
+	rank := func(r routeEntry) int {
+		switch {
+		case r.Mux == serverMuxName && r.Pattern == "/":
+			return 1
+		case r.Mux == gatewayMuxName:
+			return 2
+		}
+		return 0
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		if rank(routes[i]) != rank(routes[j]) {
+			return rank(routes[i]) < rank(routes[j])
+		}
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// ServeHTTP reports the route table as JSON.
+func (t *routeTable) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(t.routes()); err != nil {
+		log.Errorf("Unable to encode the route table: %v", err)
+	}
+}
+
+// checkGatewayConflicts logs an error for each gateway route registered more
+// than once, failing in strict mode, so that the conflicts between the plugins
+// are found on startup rather than as requests reaching the wrong handler.
+func checkGatewayConflicts(routes *routeTable, strict bool) error {
+	conflicts := routes.gatewayConflicts()
+	keys := make([]string, 0, len(conflicts))
+	for r := range conflicts {
+		keys = append(keys, r)
+	}
+	sort.Strings(keys)
+	for _, r := range keys {
+		log.Errorf("The gateway route %q is registered more than once, by %s", r, strings.Join(conflicts[r], ", "))
+	}
+	if strict && len(conflicts) > 0 {
+		return fmt.Errorf("gateway routes registered more than once: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
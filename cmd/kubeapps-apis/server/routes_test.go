@@ -0,0 +1,102 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	packagesConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1/v1alpha1connect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestRouteTable(t *testing.T) {
+	mux := http.NewServeMux()
+	routes := newRouteTable(mux, protoregistry.GlobalFiles, true, nil)
+	routes.handle(livezPath, "liveness", http.NotFoundHandler())
+	routes.handle("/", gatewayMuxName, http.NotFoundHandler())
+	// The connect handlers are found without being recorded.
+	mux.Handle(packagesConnect.NewPackagesServiceHandler(packagesConnect.UnimplementedPackagesServiceHandler{}))
+	gwmux := runtime.NewServeMux()
+	if err := routes.handleGateway(gwmux, http.MethodGet, "/docs", "openapi docs", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	got := routes.routes()
+
+	// The routes of the server mux come first, its catch-all route last among them.
+	wantFirst := []routeEntry{
+		{Mux: serverMuxName, Method: http.MethodPost, Pattern: "/kubeappsapis.core.packages.v1alpha1.PackagesService/", Handler: "kubeappsapis.core.packages.v1alpha1.PackagesService"},
+		{Mux: serverMuxName, Method: anyMethod, Pattern: livezPath, Handler: "liveness"},
+		{Mux: serverMuxName, Method: anyMethod, Pattern: "/", Handler: gatewayMuxName},
+	}
+	if len(got) < len(wantFirst) {
+		t.Fatalf("got: %d routes, want at least %d", len(got), len(wantFirst))
+	}
+	if diff := cmp.Diff(wantFirst, got[:len(wantFirst)]); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	wantGateway := []routeEntry{
+		{Mux: gatewayMuxName, Method: http.MethodGet, Pattern: "/docs", Handler: "openapi docs"},
+		{Mux: gatewayMuxName, Method: http.MethodGet, Pattern: "/core/packages/v1alpha1/availablepackages", Handler: "kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageSummaries"},
+	}
+	for _, want := range wantGateway {
+		found := false
+		for _, r := range got[len(wantFirst):] {
+			found = found || r == want
+		}
+		if !found {
+			t.Errorf("got: no route %+v", want)
+		}
+	}
+	// The services without a connect handler are only routed by the gateway.
+	for _, r := range got {
+		if r.Mux == serverMuxName && strings.Contains(r.Pattern, "RepositoriesService") {
+			t.Errorf("got: %+v, want no server route for the repositories service", r)
+		}
+	}
+}
+
+func TestGatewayConflicts(t *testing.T) {
+	routes := newRouteTable(http.NewServeMux(), protoregistry.GlobalFiles, true, nil)
+	gwmux := runtime.NewServeMux()
+	noop := func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {}
+
+	// The services of the registry do not conflict with one another.
+	if got := routes.gatewayConflicts(); len(got) != 0 {
+		t.Fatalf("got: %v, want no conflict", got)
+	}
+	if err := routes.handleGateway(gwmux, http.MethodGet, "/docs", "openapi docs", noop); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got := routes.gatewayConflicts(); len(got) != 0 {
+		t.Fatalf("got: %v, want no conflict", got)
+	}
+
+	// The path parameters are ignored when comparing the routes.
+	if err := routes.handleGateway(gwmux, http.MethodGet, "/core/packages/v1alpha1/availablepackages/plugin/{plugin}/{version}/c/{cluster}/ns/{namespace}/{identifier}/versions", "shadowing handler", noop); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	got := routes.gatewayConflicts()
+	want := map[string][]string{
+		"GET /core/packages/v1alpha1/availablepackages/plugin/{}/{}/c/{}/ns/{}/{}/versions": {
+			"kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageVersions",
+			"shadowing handler",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := checkGatewayConflicts(routes, false); err != nil {
+		t.Errorf("got: %+v, want no error unless strict", err)
+	}
+	if err := checkGatewayConflicts(routes, true); err == nil {
+		t.Errorf("got: no error, want an error in strict mode")
+	}
+}
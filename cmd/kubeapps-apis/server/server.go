@@ -4,18 +4,22 @@
 package server
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
-	"time"
 
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 
+	"github.com/bufbuild/connect-go"
 	grpchealth "github.com/bufbuild/connect-grpchealth-go"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -27,59 +31,130 @@ import (
 	pluginsGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
 	pluginsConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1/v1alpha1connect"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	log "k8s.io/klog/v2"
 )
 
-func getLogLevelOfEndpoint(endpoint string) log.Level {
+// inMemoryListenerBufferSize is the size of the buffer of each connection of the
+// in-memory listener.
+const inMemoryListenerBufferSize = 1024 * 1024
 
-	// Add all endpoint function names which you want to suppress in interceptor logging
-	suppressLoggingOfEndpoints := []string{"GetConfiguredPlugins"}
-	var level log.Level
-
-	// level=3 is default logging level
-	level = 3
-	for i := 0; i < len(suppressLoggingOfEndpoints); i++ {
-		if strings.Contains(endpoint, suppressLoggingOfEndpoints[i]) {
-			level = 4
-			break
-		}
+// Serve is the root command that is run when no other sub-commands are present.
+// It runs the gRPC service, registering the configured plugins. The options are
+// reloaded with loadServeOpts on SIGHUP, applying the reloadable ones. The
+// server is shut down gracefully, and its background work stopped, once the
+// parent context is done.
+func Serve(parentCtx context.Context, serveOpts core.ServeOptions, loadServeOpts func() (core.ServeOptions, error)) error {
+	listenAddr := fmt.Sprintf(":%d", serveOpts.Port)
+	listen := func() (net.Listener, error) {
+		return net.Listen("tcp", listenAddr)
 	}
+	return serve(parentCtx, serveOpts, loadServeOpts, listenAddr, listen, nil)
+}
 
-	return level
+// NewInMemoryListener returns an in-memory listener for ServeInMemory, whose
+// DialContext connects the clients to the server.
+func NewInMemoryListener() *bufconn.Listener {
+	return bufconn.Listen(inMemoryListenerBufferSize)
 }
 
-// LogRequest is a gRPC UnaryServerInterceptor that will log the API call
-func LogRequest(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+// ServeInMemory is like Serve, but serves on the given in-memory listener
+// rather than on the port of the options, so that the tests can exercise the
+// whole server, including the interceptors, without sockets. The gateway, when
+// enabled, connects to the handlers through the listener too.
+func ServeInMemory(parentCtx context.Context, lis *bufconn.Listener, serveOpts core.ServeOptions, loadServeOpts func() (core.ServeOptions, error)) error {
+	listen := func() (net.Listener, error) {
+		return lis, nil
+	}
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	return serve(parentCtx, serveOpts, loadServeOpts, lis.Addr().String(), listen, dialer)
+}
 
-	start := time.Now()
-	res, err := handler(ctx, req)
+// serve runs the server, listening with listen once the plugins and the core
+// services are registered. The gateway dials the listen address, with the
+// dialer when not nil.
+func serve(parentCtx context.Context, serveOpts core.ServeOptions, loadServeOpts func() (core.ServeOptions, error), listenAddr string, listen func() (net.Listener, error), dialer func(context.Context, string) (net.Conn, error)) error {
+	if err := flag.Set("v", strconv.Itoa(serveOpts.LogVerbosity)); err != nil {
+		return fmt.Errorf("failed to set the log verbosity: %w", err)
+	}
+	pluginLogLevels, err := core.ParsePluginLogLevels(serveOpts.PluginLogLevels)
+	if err != nil {
+		return fmt.Errorf("failed to parse the plugin log levels: %w", err)
+	}
+	core.SetPluginLogLevels(pluginLogLevels)
+	redactPatterns, err := core.ParseRedactPatterns(serveOpts.RedactPatterns)
+	if err != nil {
+		return fmt.Errorf("failed to parse the redaction patterns: %w", err)
+	}
+	core.SetRedactPatterns(redactPatterns)
+	setMaxProcs(serveOpts.MaxProcs, cgroupRoot)
+	ctx, cancel := context.WithCancel(parentCtx)
+	defer cancel()
+	startup := newStartupTimer(serveOpts.StartupWarningThreshold)
 
-	level := getLogLevelOfEndpoint(info.FullMethod)
+	trustedProxies, err := core.ParseTrustedProxies(serveOpts.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("failed to parse the trusted proxies: %w", err)
+	}
 
-	// Format string : [status code] [duration] [full path]
-	// OK 97.752µs /kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageSummaries
-	log.V(level).Infof("%v %s %s\n",
-		status.Code(err),
-		time.Since(start),
-		info.FullMethod)
+	if err := validateHTTP2WindowSizes(serveOpts); err != nil {
+		return err
+	}
+	if serveOpts.PersistCache && serveOpts.CacheSnapshotInterval <= 0 {
+		return fmt.Errorf("invalid cache snapshot interval %s, expected a positive duration", serveOpts.CacheSnapshotInterval)
+	}
+	if err := validateGzipCompressionLevel(serveOpts.GRPCCompressionLevel); err != nil {
+		return err
+	}
+	if serveOpts.MetricsRequireClientCert && serveOpts.TLSClientCAFile == "" {
+		return fmt.Errorf("a client CA file is required to verify the client certificates of the metrics scrapers")
+	}
 
-	return res, err
-}
+	// The TLS key pair is loaded before anything else, so that a certificate not
+	// matching its key is reported right away rather than once the plugins are
+	// registered or, worse, on the first connection.
+	var tlsConfig *tls.Config
+	if tlsEnabled(serveOpts) {
+		if tlsConfig, err = serverTLSConfig(serveOpts); err != nil {
+			return fmt.Errorf("failed to configure TLS: %w", err)
+		}
+	}
 
-// Serve is the root command that is run when no other sub-commands are present.
-// It runs the gRPC service, registering the configured plugins.
-func Serve(serveOpts core.ServeOptions) error {
-	listenAddr := fmt.Sprintf(":%d", serveOpts.Port)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	exposedMethods, err := newGatewayExposedMethods(serveOpts.GatewayExposedMethods)
+	if err != nil {
+		return fmt.Errorf("failed to parse the gateway exposed methods: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	routes := newRouteTable(mux, protoregistry.GlobalFiles, serveOpts.EnableRESTGateway, exposedMethods)
+
+	// The gateway is left nil when disabled, so that no handler is registered for it.
+	var gw *runtime.ServeMux
+	var pluginMux func(pluginName string) (*runtime.ServeMux, error)
+	if serveOpts.EnableRESTGateway {
+		var pluginMuxes *pluginGatewayMuxes
+		gw, pluginMuxes, err = gatewayMux(serveOpts, trustedProxies, routes)
+		if err != nil {
+			return fmt.Errorf("failed to create gRPC gateway: %w", err)
+		}
+		pluginMux = pluginMuxes.muxFor
+	} else {
+		log.Info("The REST gateway is disabled, the API is only served with the gRPC, gRPC-web and connect protocols")
+	}
 
-	gw, err := gatewayMux()
+	dialOptions, err := gatewayDialOptions(serveOpts)
 	if err != nil {
-		return fmt.Errorf("failed to create gRPC gateway: %w", err)
+		return fmt.Errorf("failed to configure the gateway: %w", err)
 	}
+	if dialer != nil {
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialer))
+	}
+	dialOptions = append(dialOptions, gatewayHopDialOptions(listenAddr)...)
 
 	// Note: we point the gateway at our *new* gRPC handler, so that we can continue to use
 	// the gateway for a ReST-ish API
@@ -87,51 +162,260 @@ func Serve(serveOpts core.ServeOptions) error {
 		Ctx:         ctx,
 		Mux:         gw,
 		Addr:        listenAddr,
-		DialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		DialOptions: dialOptions,
+		PluginMux:   pluginMux,
 	}
 
-	mux := http.NewServeMux()
+	// The supervisor is notified when the background goroutines die.
+	sup := newSupervisor()
+	routes.handle(livezPath, "liveness", sup)
+
+	if serveOpts.ChannelzAddr != "" {
+		stopChannelz, err := startChannelz(serveOpts.ChannelzAddr, sup)
+		if err != nil {
+			return fmt.Errorf("failed to start the channelz server: %w", err)
+		}
+		defer stopChannelz()
+	}
+
+	maintenance := newMaintenanceMode(serveOpts.MaintenanceMode, serveOpts.MaintenanceWriteMethods)
+
+	metrics := newMetrics()
+	if serveOpts.MetricsBuildInfo {
+		metrics.registerBuildInfo(serveOpts.Version)
+	}
+	routes.handle(metricsPath, "metrics", metrics.handler(serveOpts.MetricsAuthToken, serveOpts.MetricsRequireClientCert))
+
+	// The options for all the connect handlers, including those registered by the plugins.
+	// The default cluster of the request targets is set once the clusters config
+	// is parsed, when registering the plugins.
+	targets, err := newRequestTargets(serveOpts.DefaultNamespacePolicy, serveOpts.DefaultNamespace)
+	if err != nil {
+		return err
+	}
+	lastErrors := newLastErrors()
+	interceptors, err := newInterceptorChain(serveOpts, trustedProxies, metrics, maintenance, targets, lastErrors)
+	if err != nil {
+		return err
+	}
+	// The interceptors are closed once the server is shut down, when serve
+	// returns.
+	defer interceptors.close()
+	handlerOpts := newHandlerOptions(serveOpts, interceptors.ordered())
+
+	// All replicas serve requests, but plugins only start their watch-heavy
+	// background work once this replica is elected as leader.
+	startup.begin("leader election")
+	leaderElected, err := startLeaderElection(ctx, serveOpts, sup)
+	if err != nil {
+		return fmt.Errorf("failed to start leader election: %w", err)
+	}
 
 	// Create the core.plugins.v1alpha1 server which handles registration of
 	// plugins, and register it for both grpc and http.
-	pluginsServer, err := pluginsv1alpha1.NewPluginsServer(serveOpts, gwArgs, mux)
+	startup.begin("plugins registration")
+	pluginsServer, err := pluginsv1alpha1.NewPluginsServer(serveOpts, gwArgs, mux, leaderElected, handlerOpts)
 	if err != nil {
 		return fmt.Errorf("failed to initialize plugins server: %v", err)
 	}
-	if err := registerPluginsServiceServer(mux, pluginsServer, gwArgs); err != nil {
+	targets.setDefaultCluster(pluginsServer.DefaultCluster())
+	if serveOpts.RequireAtLeastOnePlugin && pluginsServer.NumPlugins() == 0 {
+		return fmt.Errorf("no plugin registered from the plugin dirs %v, while at least one is required", serveOpts.PluginDirs)
+	}
+	startup.begin("core.plugins service registration")
+	if err := registerPluginsServiceServer(mux, pluginsServer, gwArgs, handlerOpts); err != nil {
 		return fmt.Errorf("failed to register plugins server: %v", err)
 	}
-	if err := registerPackagesServiceServer(mux, pluginsServer, gwArgs); err != nil {
+	startup.begin("core.packages service registration")
+	// The caches of the plugins are added to the cache admin, for the admin endpoints.
+	cacheAdmin := packagesv1alpha1.NewCacheAdmin()
+	if err := registerPackagesServiceServer(mux, pluginsServer, gwArgs, serveOpts, metrics, cacheAdmin, handlerOpts); err != nil {
+		return err
+	}
+	persistCache := serveOpts.PersistCache && serveOpts.CacheFreshTTL > 0
+	if persistCache {
+		restoreCacheSnapshot(cacheAdmin, serveOpts.CacheSnapshotPath)
+		go cacheAdmin.PersistSnapshots(ctx, serveOpts.CacheSnapshotPath, serveOpts.CacheSnapshotInterval)
+	}
+	startup.begin("core.repositories service registration")
+	if err := registerRepositoriesServiceServer(mux, pluginsServer, gwArgs, handlerOpts); err != nil {
 		return err
 	}
-	if err := registerRepositoriesServiceServer(mux, pluginsServer, gwArgs); err != nil {
+	if err := registerRepositoryStatusEvents(routes, gwArgs, serveOpts); err != nil {
 		return err
 	}
+	if err := registerPluginHTTPRoutes(routes, pluginsServer, gwArgs); err != nil {
+		return err
+	}
+	if gwArgs.Mux != nil {
+		if err := checkGatewayConflicts(routes, serveOpts.StrictGatewayRoutes); err != nil {
+			return err
+		}
+	}
+
+	startup.done()
+
+	if serveOpts.ValidateOpenAPI {
+		validateOpenAPI(openAPIPath)
+	}
 
 	// The gRPC Health checker reports on all connected services.
 	checker := grpchealth.NewStaticChecker(
 		pluginsConnect.PluginsServiceName,
 	)
-	mux.Handle(grpchealth.NewHandler(checker))
+	healthPath, healthHandler := grpchealth.NewHandler(checker)
+	routes.handle(healthPath, "grpc health", healthHandler)
+
+	logLevel := newLogLevel(serveOpts.LogLevelResetAfter)
 
-	// Finally, link the new mux so that all other requests are handled by the gateway
-	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		gwArgs.Mux.ServeHTTP(w, r)
+	// SIGHUP reloads the reloadable options without dropping connections.
+	reloader := &configReloader{
+		current:     serveOpts,
+		load:        loadServeOpts,
+		logLevel:    logLevel,
+		maintenance: maintenance,
+	}
+	go reloader.run(ctx)
+
+	routes.handle(adminPathPrefix, "admin", newAdminHandler(serveOpts.AdminToken, map[string]http.Handler{
+		"maintenance":     maintenance,
+		"loglevel":        logLevel,
+		"config":          reloader,
+		"routes":          routes,
+		"cache/stats":     cacheStatsHandler(cacheAdmin),
+		"cache/flush":     cacheFlushHandler(cacheAdmin),
+		"apis/rediscover": rediscoverAPIsHandler(pluginsServer.RediscoverAPIs),
+		"errors":          lastErrors,
 	}))
 
+	// Finally, link the new mux so that all other requests are handled by the
+	// gateway, except for the browsers opening the root, which get a landing page.
+	if gwArgs.Mux != nil {
+		routes.handle("/", gatewayMuxName, withLandingPage(withGatewayRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gwArgs.Mux.ServeHTTP(w, r)
+		})), serveOpts.Version, true))
+	} else {
+		routes.handle("/", "landing page", withLandingPage(http.NotFoundHandler(), serveOpts.Version, false))
+	}
+
 	if serveOpts.UnsafeLocalDevKubeconfig {
 		log.Warning("Using the local Kubeconfig file instead of the actual in-cluster's config. This is not recommended except for development purposes.")
 	}
 
-	log.Infof("Starting server on %q", listenAddr)
-	if err := http.ListenAndServe(listenAddr, h2c.NewHandler(mux, &http2.Server{})); err != nil {
-		log.Fatalf("Failed to server: %+v", err)
+	inFlight := &inFlightRequests{}
+	server := newHTTPServer(listenAddr, inFlight.wrap(withClientCertIdentity(withForwardedLocation(mux, trustedProxies))), serveOpts)
+
+	lis, err := listen()
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", listenAddr, err)
+	}
+	if tlsConfig != nil {
+		server.TLSConfig = tlsConfig
+		if err := http2.ConfigureServer(server, newHTTP2Server(serveOpts)); err != nil {
+			return fmt.Errorf("failed to configure HTTP/2: %w", err)
+		}
+
+		log.Infof("Starting server with TLS on %q", listenAddr)
+		go supervise(sup, func() error { return server.ServeTLS(lis, "", "") })
+	} else {
+		log.Infof("Starting server on %q", listenAddr)
+		go supervise(sup, func() error { return server.Serve(lis) })
 	}
 
+	err = serveUntilDone(ctx, server, sup, inFlight, serveOpts)
+	// The snapshot is saved once the in-flight requests are done, so that the
+	// next replica starts with the freshest responses.
+	if persistCache {
+		saveCacheSnapshot(cacheAdmin, serveOpts.CacheSnapshotPath)
+	}
+	return err
+}
+
+// serveUntilDone waits until the context is done or a background goroutine
+// dies, then shuts the server down gracefully so that the in-flight requests
+// complete before the process exits. The failure of the goroutine, if any, is
+// returned.
+func serveUntilDone(ctx context.Context, server *http.Server, sup *supervisor, inFlight *inFlightRequests, serveOpts core.ServeOptions) error {
+	var failure error
+	select {
+	case <-ctx.Done():
+		log.Info("Shutting down the server")
+	case failure = <-sup.failed():
+	}
+	shutdown(server, inFlight, serveOpts.ShutdownGracePeriod, serveOpts.ShutdownHardTimeout)
+	if failure != nil {
+		return fmt.Errorf("failed to serve: %w", failure)
+	}
 	return nil
 }
 
-func registerPackagesServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs) error {
+// newHandlerOptions returns the options of the connect handlers. The maximum
+// size of the received messages and the gzip compression level apply to all
+// the protocols, including the gRPC-web requests which are not framed by a
+// gRPC server.
+func newHandlerOptions(serveOpts core.ServeOptions, interceptors []connect.Interceptor) []connect.HandlerOption {
+	handlerOpts := []connect.HandlerOption{
+		connect.WithInterceptors(interceptors...),
+	}
+	if serveOpts.MaxReceiveMessageSize > 0 {
+		handlerOpts = append(handlerOpts, connect.WithReadMaxBytes(serveOpts.MaxReceiveMessageSize))
+	}
+	if serveOpts.GRPCCompressionLevel != gzip.DefaultCompression {
+		handlerOpts = append(handlerOpts, gzipCompression(serveOpts.GRPCCompressionLevel))
+	}
+	return handlerOpts
+}
+
+// newHTTPServer returns the server of the handler, over HTTP/1 and HTTP/2 with
+// TLS when configured, or else without (h2c). With TLS, h2c is not served at
+// all, so that no client ends up sending its requests in cleartext, nor
+// upgrading an HTTP/1 request to h2c. Connections are closed once idle for the connection idle
+// timeout, including new connections on which the client sends nothing, so that
+// idle or half-open connections do not pile up. Connections with in-flight
+// streams are not idle. The opening and closing of the connections are logged
+// at the connection log verbosity. The size of the request headers is limited
+// to the max header bytes, for HTTP/2 too. Over TLS, the HTTP/2 server must
+// still be configured with http2.ConfigureServer once the TLS config is set.
+func newHTTPServer(addr string, handler http.Handler, serveOpts core.ServeOptions) *http.Server {
+	idleTimeout := serveOpts.ConnectionIdleTimeout
+	if !tlsEnabled(serveOpts) {
+		handler = h2c.NewHandler(handler, newHTTP2Server(serveOpts))
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: idleTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxHeaderBytes:    serveOpts.MaxHeaderBytes,
+		ConnState:         logConnState(log.Level(serveOpts.ConnectionLogVerbosity)),
+	}
+}
+
+// logConnState returns a hook logging the connections accepted and closed by
+// the server, with the address of their peer. The HTTP/2 connections without
+// TLS (h2c) are taken over by the HTTP/2 server once upgraded, so that only
+// their upgrade is logged.
+func logConnState(verbosity log.Level) func(net.Conn, http.ConnState) {
+	return func(conn net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			log.V(verbosity).InfoS("+core Connection opened", "peer", conn.RemoteAddr().String())
+		case http.StateHijacked:
+			log.V(verbosity).InfoS("+core Connection upgraded", "peer", conn.RemoteAddr().String())
+		case http.StateClosed:
+			log.V(verbosity).InfoS("+core Connection closed", "peer", conn.RemoteAddr().String())
+		}
+	}
+}
+
+// supervise runs the given serving function, reporting its failure to the supervisor.
+func supervise(sup *supervisor, serve func() error) {
+	if err := serve(); err != nil && err != http.ErrServerClosed {
+		sup.fail(err)
+	}
+}
+
+func registerPackagesServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs, serveOpts core.ServeOptions, metrics *metrics, cacheAdmin *packagesv1alpha1.CacheAdmin, handlerOpts []connect.HandlerOption) error {
 	// Ask the plugins server for plugins with GRPC servers that fulfil the core
 	// packaging v1alpha1 API, then pass to the constructor below.
 	// The argument for the reflect.TypeOf is based on what grpc-go
@@ -139,112 +423,208 @@ func registerPackagesServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1a
 	// https://github.com/grpc/grpc-go/blob/v1.38.0/server.go#L621
 	packagingPlugins := pluginsServer.GetPluginsSatisfyingInterface(reflect.TypeOf((*packagesConnect.PackagesServiceHandler)(nil)).Elem())
 
+	retryCodes, err := packagesv1alpha1.ParseRetryableCodes(serveOpts.PluginReadRetryCodes)
+	if err != nil {
+		return fmt.Errorf("failed to parse plugin read retry codes: %w", err)
+	}
+	retryPolicy := packagesv1alpha1.RetryPolicy{
+		MaxAttempts:      serveOpts.PluginReadRetryMaxAttempts,
+		InitialBackoff:   serveOpts.PluginReadRetryInitialBackoff,
+		MaxBackoff:       serveOpts.PluginReadRetryMaxBackoff,
+		RetryableCodes:   retryCodes,
+		BudgetMaxTokens:  serveOpts.PluginReadRetryBudgetMaxTokens,
+		BudgetTokenRatio: serveOpts.PluginReadRetryBudgetTokenRatio,
+	}
+
+	cachePolicy := packagesv1alpha1.CachePolicy{
+		FreshTTL: serveOpts.CacheFreshTTL,
+		MaxStale: serveOpts.CacheMaxStale,
+		Admin:    cacheAdmin,
+	}
+	if cachePolicy.FreshTTL > 0 {
+		cachePolicy.Metrics = packagesv1alpha1.NewCacheMetrics(metrics.registry)
+	}
+
 	// Create the core.packages server and register it for both grpc and http.
-	packagesServer, err := packagesv1alpha1.NewPackagesServer(packagingPlugins)
+	packagesServer, err := packagesv1alpha1.NewPackagesServer(packagingPlugins, pluginsServer.PluginNamespaces(), retryPolicy, cachePolicy, serveOpts.PartialResults, serveOpts.PluginPriority, pluginsServer.AccessibleNamespaces)
 	if err != nil {
 		return fmt.Errorf("failed to create core.packages.v1alpha1 server: %w", err)
 	}
 
-	mux.Handle(packagesConnect.NewPackagesServiceHandler(packagesServer))
+	mux.Handle(packagesConnect.NewPackagesServiceHandler(packagesServer, handlerOpts...))
 
-	err = packagesGRPCv1alpha1.RegisterPackagesServiceHandlerFromEndpoint(gwArgs.Ctx, gwArgs.Mux, gwArgs.Addr, gwArgs.DialOptions)
+	err = gwArgs.Register(packagesGRPCv1alpha1.RegisterPackagesServiceHandlerFromEndpoint)
 	if err != nil {
 		return fmt.Errorf("failed to register core.packages handler for gateway: %v", err)
 	}
 	return nil
 }
 
-func registerRepositoriesServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs) error {
+func registerRepositoriesServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs, handlerOpts []connect.HandlerOption) error {
 	// see comment in registerPackagesServiceServer
 	repositoriesPlugins := pluginsServer.GetPluginsSatisfyingInterface(reflect.TypeOf((*packagesConnect.RepositoriesServiceHandler)(nil)).Elem())
 
 	// Create the core.packages server and register it for both grpc and http.
-	repoServer, err := packagesv1alpha1.NewRepositoriesServer(repositoriesPlugins)
+	repoServer, err := packagesv1alpha1.NewRepositoriesServer(repositoriesPlugins, pluginsServer.PluginNamespaces())
 	if err != nil {
 		return fmt.Errorf("failed to create core.packages.v1alpha1 server: %w", err)
 	}
-	mux.Handle(packagesConnect.NewRepositoriesServiceHandler(repoServer))
+	mux.Handle(packagesConnect.NewRepositoriesServiceHandler(repoServer, handlerOpts...))
 
-	err = packagesGRPCv1alpha1.RegisterRepositoriesServiceHandlerFromEndpoint(gwArgs.Ctx, gwArgs.Mux, gwArgs.Addr, gwArgs.DialOptions)
+	err = gwArgs.Register(packagesGRPCv1alpha1.RegisterRepositoriesServiceHandlerFromEndpoint)
 	if err != nil {
 		return fmt.Errorf("failed to register core.packages handler for gateway: %v", err)
 	}
 	return nil
 }
 
-// Create a gateway mux that does not emit unpopulated fields.
-func gatewayMux() (*runtime.ServeMux, error) {
-	gwmux := runtime.NewServeMux(
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				EmitUnpopulated: false,
-			},
+// gatewayMarshaler returns the JSON marshaler used by the gateway. Field names are
+// emitted in lowerCamelCase unless useProtoNames is set, in which case the original
+// proto field names are used instead. Unmarshaling accepts both forms regardless.
+//
+// Endpoints returning binary payloads, such as chart tarballs or logos, should
+// return a google.api.HttpBody, whose raw data is written with its content type
+// rather than being base64-encoded in JSON.
+func gatewayMarshaler(useProtoNames bool) *runtime.HTTPBodyMarshaler {
+	return jsonMarshaler(protojson.MarshalOptions{
+		EmitUnpopulated: false,
+		UseProtoNames:   useProtoNames,
+	})
+}
+
+// jsonMarshaler returns a gateway marshaler with the given JSON marshaling
+// options, such as those of a plugin.
+func jsonMarshaler(options protojson.MarshalOptions) *runtime.HTTPBodyMarshaler {
+	return &runtime.HTTPBodyMarshaler{
+		Marshaler: &runtime.JSONPb{
+			MarshalOptions: options,
 			UnmarshalOptions: protojson.UnmarshalOptions{
 				DiscardUnknown: true,
 			},
-		}),
-	)
+		},
+	}
+}
+
+// gatewayOutgoingHeaderMatcher forwards the Server-Timing header of the gRPC
+// responses as is, prefixing the other headers as by default.
+func gatewayOutgoingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, serverTimingHeader) {
+		return serverTimingHeader, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// forwardPaginationHeaders sets the pagination headers of the gateway responses
+// of the list methods from the trailers of the same name, which the gateway
+// only forwards as trailers, when requested, otherwise.
+func forwardPaginationHeaders(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, key := range []string{core.TotalCountHeader, core.NextPageTokenTrailer} {
+		if values := md.TrailerMD.Get(key); len(values) > 0 {
+			w.Header().Set(key, values[0])
+		}
+	}
+	return nil
+}
+
+// Create a gateway mux that does not emit unpopulated fields, along with the
+// muxes of the plugins having their own JSON marshaling options.
+func gatewayMux(serveOpts core.ServeOptions, trustedProxies core.TrustedProxies, routes *routeTable) (*runtime.ServeMux, *pluginGatewayMuxes, error) {
+	cacheControl, err := newCacheControl(serveOpts.CacheControl)
+	if err != nil {
+		return nil, nil, err
+	}
+	jsonOptions, err := parsePluginJSONOptions(serveOpts.PluginJSONOptions, serveOpts.JSONUseProtoNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	if serveOpts.MaxConcurrentMarshals < 0 {
+		return nil, nil, fmt.Errorf("invalid maximum of concurrent marshals %d, expected a positive number or 0", serveOpts.MaxConcurrentMarshals)
+	}
+	// The marshals are limited across the muxes of all the plugins.
+	marshals := newMarshalSemaphore(serveOpts.MaxConcurrentMarshals)
+	newMux := func(marshaler runtime.Marshaler) *runtime.ServeMux {
+		return runtime.NewServeMux(
+			runtime.WithMarshalerOption(runtime.MIMEWildcard, marshals.limit(marshaler)),
+			runtime.SetQueryParameterParser(&aliasingQueryParser{}),
+			runtime.WithIncomingHeaderMatcher(gatewayIncomingHeaderMatcher),
+			runtime.WithOutgoingHeaderMatcher(gatewayOutgoingHeaderMatcher),
+			runtime.WithForwardResponseOption(cacheControl.forwardResponseOption),
+			runtime.WithForwardResponseOption(forwardPaginationHeaders),
+			runtime.WithErrorHandler(cacheControl.errorHandler),
+		)
+	}
+	gwmux := newMux(gatewayMarshaler(serveOpts.JSONUseProtoNames))
+	pluginMuxes := newPluginGatewayMuxes(gwmux, routes.files, newMux, jsonOptions)
 
 	// TODO(agamez): remove these '/openapi.json' and '/docs' paths. They are serving a
 	// static 'swagger-ui' dashboard with hardcoded values just intended for development purposes.
 	// This docs will eventually converge into the docs already (properly) served by the dashboard
-	err := gwmux.HandlePath(http.MethodGet, "/openapi.json", runtime.HandlerFunc(func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
-		http.ServeFile(w, r, "docs/kubeapps-apis.swagger.json")
+	serveOpenAPIDocument := serveOpenAPI(openAPIPath, trustedProxies)
+	err = routes.handleGateway(gwmux, http.MethodGet, "/openapi.json", "openapi document", runtime.HandlerFunc(func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		serveOpenAPIDocument(w, r)
 	}))
 	if err != nil {
-		return nil, fmt.Errorf("failed to serve: %v", err)
+		return nil, nil, fmt.Errorf("failed to serve: %v", err)
 	}
 
-	err = gwmux.HandlePath(http.MethodGet, "/docs", runtime.HandlerFunc(func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	err = routes.handleGateway(gwmux, http.MethodGet, "/docs", "openapi docs", runtime.HandlerFunc(func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
 		http.ServeFile(w, r, "docs/index.html")
 	}))
 	if err != nil {
-		return nil, fmt.Errorf("failed to serve: %v", err)
+		return nil, nil, fmt.Errorf("failed to serve: %v", err)
 	}
 
-	svcRestConfig, err := rest.InClusterConfig()
+	svcRestConfig, err := core.InClusterConfig(serveOpts.KubernetesClientTimeout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve in cluster configuration: %v", err)
+		return nil, nil, fmt.Errorf("failed to retrieve in cluster configuration: %v", err)
 	}
 	coreClientSet, err := kubernetes.NewForConfig(svcRestConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve clientset: %v", err)
+		return nil, nil, fmt.Errorf("failed to retrieve clientset: %v", err)
 	}
 
 	// TODO(rcastelblanq) Move this endpoint to the Operators plugin when implementing #4920
 	// Proxies the operator icon request to K8s
-	err = gwmux.HandlePath(http.MethodGet, "/operators/namespaces/{namespace}/operator/{name}/logo", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
-		namespace := pathParams["namespace"]
-		name := pathParams["name"]
+	logos := newOperatorLogos(fetchOperatorLogoFunc(coreClientSet), serveOpts.OperatorLogoCacheTTL, serveOpts.OperatorLogoQPS, serveOpts.OperatorLogoBurst)
+	err = routes.handleGateway(gwmux, http.MethodGet, "/operators/namespaces/{namespace}/operator/{name}/logo", "operator logo proxy", logos.serveHTTP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serve: %v", err)
+	}
 
-		logoBytes, err := coreClientSet.RESTClient().Get().AbsPath(fmt.Sprintf("/apis/packages.operators.coreos.com/v1/namespaces/%s/packagemanifests/%s/icon", namespace, name)).Do(context.TODO()).Raw()
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Unable to retrieve operator logo: %v", err), http.StatusInternalServerError)
-			return
-		}
+	return gwmux, pluginMuxes, nil
+}
 
-		contentType := http.DetectContentType(logoBytes)
-		if strings.Contains(contentType, "text/") {
-			// DetectContentType is unable to return svg icons since they are in fact text
-			contentType = "image/svg+xml"
-		}
-		w.Header().Set("Content-Type", contentType)
-		_, err = w.Write(logoBytes)
-		if err != nil {
-			return
-		}
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to serve: %v", err)
+// registerPluginHTTPRoutes registers the REST routes of the plugins with the
+// gateway, unless disabled, recording them with the name of their plugin.
+func registerPluginHTTPRoutes(routes *routeTable, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs) error {
+	if gwArgs.Mux == nil {
+		return nil
 	}
+	return pluginsServer.RegisterHTTPRoutes(func(plugin *pluginsGRPCv1alpha1.Plugin) core.HTTPRouteMux {
+		return pluginRouteMux{routes: routes, gwmux: gwArgs.Mux, pluginName: plugin.GetName()}
+	})
+}
+
+// pluginRouteMux registers the REST routes of a plugin with the gateway,
+// recording them in the route table.
+type pluginRouteMux struct {
+	routes     *routeTable
+	gwmux      *runtime.ServeMux
+	pluginName string
+}
 
-	return gwmux, nil
+func (m pluginRouteMux) HandlePath(method, pattern string, handler runtime.HandlerFunc) error {
+	return m.routes.handleGateway(m.gwmux, method, pattern, "plugin "+m.pluginName, handler)
 }
 
 // Registers the pluginsServer with the mux and gateway.
-func registerPluginsServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs) error {
-	mux.Handle(pluginsConnect.NewPluginsServiceHandler(pluginsServer))
-	err := pluginsGRPCv1alpha1.RegisterPluginsServiceHandlerFromEndpoint(gwArgs.Ctx, gwArgs.Mux, gwArgs.Addr, gwArgs.DialOptions)
+func registerPluginsServiceServer(mux *http.ServeMux, pluginsServer *pluginsv1alpha1.PluginsServer, gwArgs core.GatewayHandlerArgs, handlerOpts []connect.HandlerOption) error {
+	mux.Handle(pluginsConnect.NewPluginsServiceHandler(pluginsServer, handlerOpts...))
+	err := gwArgs.Register(pluginsGRPCv1alpha1.RegisterPluginsServiceHandlerFromEndpoint)
 	if err != nil {
 		return fmt.Errorf("failed to register core.plugins handler for gateway: %v", err)
 	}
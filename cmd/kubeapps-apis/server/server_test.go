@@ -0,0 +1,401 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	grpchealth "github.com/bufbuild/connect-grpchealth-go"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	pluginsGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	pluginsConnect "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1/v1alpha1connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	log "k8s.io/klog/v2"
+)
+
+func TestNewHTTPServerClosesIdleConnections(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server := newHTTPServer(listener.Addr().String(), http.NotFoundHandler(), core.ServeOptions{ConnectionIdleTimeout: 100 * time.Millisecond})
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.Errorf("%+v", err)
+		}
+	}()
+	defer server.Close()
+
+	// A client opening a connection and sending nothing.
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer conn.Close()
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("got: %v, want: %v", err, io.EOF)
+	}
+}
+
+func TestNewHTTPServerLimitsHeaderBytes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		headerSize     int
+		expectedStatus int
+	}{
+		{
+			name:           "accepts headers within the limit",
+			headerSize:     1024,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "rejects headers over the limit",
+			headerSize:     16 * 1024,
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewUnstartedServer(nil)
+			ts.Config = newHTTPServer("", http.NotFoundHandler(), core.ServeOptions{MaxHeaderBytes: 8 * 1024})
+			ts.Start()
+			defer ts.Close()
+
+			req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			req.Header.Set("X-Large", strings.Repeat("a", tc.headerSize))
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			defer res.Body.Close()
+			if got, want := res.StatusCode, tc.expectedStatus; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestServeUntilDone(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failure       error
+		expectedError bool
+	}{
+		{
+			name: "shuts the server down when the context is done",
+		},
+		{
+			name:          "shuts the server down when a background goroutine fails",
+			failure:       fmt.Errorf("boom"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			server := newHTTPServer(listener.Addr().String(), http.NotFoundHandler(), core.ServeOptions{ConnectionIdleTimeout: time.Minute})
+			served := make(chan error, 1)
+			go func() { served <- server.Serve(listener) }()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			sup := newSupervisor()
+			if tc.failure != nil {
+				sup.fail(tc.failure)
+			} else {
+				cancel()
+			}
+			defer cancel()
+
+			err = serveUntilDone(ctx, server, sup, &inFlightRequests{}, core.ServeOptions{ShutdownGracePeriod: time.Second, ShutdownHardTimeout: time.Second})
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Errorf("got error: %v, want error: %t", err, want)
+			}
+			select {
+			case err := <-served:
+				if got, want := err, http.ErrServerClosed; got != want {
+					t.Errorf("got: %v, want: %v", got, want)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("timed out waiting for the server to be shut down")
+			}
+		})
+	}
+}
+
+func TestServeInMemory(t *testing.T) {
+	// The plugins server requires a kube config, which is not dialed without
+	// plugins. The gateway requires the in-cluster config, so it is disabled.
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: default
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: default
+  context:
+    cluster: default
+current-context: default
+`), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+	// The log verbosity is set with the klog flags, registered by the command.
+	if flag.Lookup("v") == nil {
+		log.InitFlags(nil)
+	}
+
+	serveOpts := core.ServeOptions{
+		UnsafeLocalDevKubeconfig: true,
+		ConnectionIdleTimeout:    time.Minute,
+		ShutdownGracePeriod:      time.Second,
+		ShutdownHardTimeout:      time.Second,
+	}
+	lis := NewInMemoryListener()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeInMemory(ctx, lis, serveOpts, func() (core.ServeOptions, error) { return serveOpts, nil })
+	}()
+	defer func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("%+v", err)
+		}
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		},
+	}}
+	reqCtx, reqCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer reqCancel()
+
+	t.Run("serves the connect handlers", func(t *testing.T) {
+		pluginsClient := pluginsConnect.NewPluginsServiceClient(client, "http://"+lis.Addr().String())
+		res, err := pluginsClient.GetConfiguredPlugins(reqCtx, connect.NewRequest(&pluginsGRPCv1alpha1.GetConfiguredPluginsRequest{}))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if got := len(res.Msg.GetPlugins()); got != 0 {
+			t.Errorf("got: %d plugins, want: 0", got)
+		}
+	})
+
+	t.Run("serves the other handlers", func(t *testing.T) {
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, "http://"+lis.Addr().String()+livezPath, nil)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer res.Body.Close()
+		if got, want := res.StatusCode, http.StatusOK; got != want {
+			t.Errorf("got: %d, want: %d", got, want)
+		}
+	})
+}
+
+func TestGatewayOutgoingHeaderMatcher(t *testing.T) {
+	for key, want := range map[string]string{
+		"server-timing": "Server-Timing",
+		"x-custom":      "Grpc-Metadata-x-custom",
+	} {
+		if got, ok := gatewayOutgoingHeaderMatcher(key); !ok || got != want {
+			t.Errorf("%s: got: %q, want: %q", key, got, want)
+		}
+	}
+}
+
+func TestForwardPaginationHeaders(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{
+		TrailerMD: metadata.Pairs("total-count", "42", "next-page-token", `{"helm.packages":10}`),
+	})
+	w := httptest.NewRecorder()
+	if err := forwardPaginationHeaders(ctx, w, nil); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := w.Header().Get(core.TotalCountHeader), "42"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := w.Header().Get(core.NextPageTokenTrailer), `{"helm.packages":10}`; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestHandlerOptionsLimitGRPCWebMessageSize(t *testing.T) {
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	ts := serveTestProcedure(t, procedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest]) (*connect.Response[packagesGRPCv1alpha1.GetInstalledPackageDetailResponse], error) {
+			return connect.NewResponse(&packagesGRPCv1alpha1.GetInstalledPackageDetailResponse{}), nil
+		},
+		newHandlerOptions(core.ServeOptions{MaxReceiveMessageSize: 1024}, nil)...,
+	)
+
+	client := connect.NewClient[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest, packagesGRPCv1alpha1.GetInstalledPackageDetailResponse](ts.Client(), ts.URL+procedure, connect.WithGRPCWeb())
+
+	testCases := []struct {
+		name       string
+		identifier string
+		errorCode  connect.Code
+	}{
+		{
+			name:       "accepts a message within the limit",
+			identifier: "my-apache",
+		},
+		{
+			name:       "rejects a message over the limit",
+			identifier: strings.Repeat("a", 2048),
+			errorCode:  connect.CodeResourceExhausted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetInstalledPackageDetailRequest{
+				InstalledPackageRef: &packagesGRPCv1alpha1.InstalledPackageReference{Identifier: tc.identifier},
+			}))
+			if got, want := connect.CodeOf(err), tc.errorCode; err != nil && got != want {
+				t.Fatalf("got: %v, want: %v, err: %+v", got, want, err)
+			}
+			if tc.errorCode != 0 && err == nil {
+				t.Fatalf("got: nil, want: error with code %v", tc.errorCode)
+			}
+		})
+	}
+}
+
+func TestPluginRouteMux(t *testing.T) {
+	routes := newRouteTable(http.NewServeMux(), protoregistry.GlobalFiles, true, nil)
+	gwmux := runtime.NewServeMux()
+	var mux core.HTTPRouteMux = pluginRouteMux{routes: routes, gwmux: gwmux, pluginName: "operators.packages"}
+
+	if err := mux.HandlePath(http.MethodGet, "/operators/{name}/logo", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		w.WriteHeader(http.StatusTeapot)
+	}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// The route of the plugin is served by the gateway and recorded with the
+	// name of the plugin.
+	w := httptest.NewRecorder()
+	gwmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/operators/my-operator/logo", nil))
+	if got, want := w.Code, http.StatusTeapot; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	want := routeEntry{Mux: gatewayMuxName, Method: http.MethodGet, Pattern: "/operators/{name}/logo", Handler: "plugin operators.packages"}
+	found := false
+	for _, r := range routes.routes() {
+		found = found || r == want
+	}
+	if !found {
+		t.Errorf("got: no route %+v", want)
+	}
+}
+
+func TestGatewayHandlerArgsRegister(t *testing.T) {
+	register := func(called *bool) func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error {
+		return func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error {
+			*called = true
+			return nil
+		}
+	}
+
+	called := false
+	if err := (core.GatewayHandlerArgs{Mux: runtime.NewServeMux()}).Register(register(&called)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := called, true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+
+	// Nothing is registered when the REST gateway is disabled.
+	called = false
+	if err := (core.GatewayHandlerArgs{}).Register(register(&called)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got, want := called, false; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+}
+
+func TestGRPCHealthCheckIsRoutedToTheHealthHandler(t *testing.T) {
+	// All the protocols are served by the same handler, the requests being
+	// routed by path, so the health probes reach the health handler whatever
+	// their content type, rather than the gateway catch-all.
+	mux := http.NewServeMux()
+	mux.Handle(grpchealth.NewHandler(grpchealth.NewStaticChecker(pluginsConnect.PluginsServiceName)))
+	mux.Handle("/", http.NotFoundHandler())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server := newHTTPServer(listener.Addr().String(), mux, core.ServeOptions{ConnectionIdleTimeout: time.Minute})
+	go server.Serve(listener)
+	defer server.Close()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, service := range []string{"", pluginsConnect.PluginsServiceName} {
+		res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if got, want := res.GetStatus(), healthpb.HealthCheckResponse_SERVING; got != want {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	}
+}
+
+// serveTestProcedure serves the unary handler of the procedure, with the given
+// handler options, until the end of the test, returning the test server to
+// which the clients of the procedure connect.
+func serveTestProcedure[Req, Res any](t *testing.T, procedure string, handler func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error), opts ...connect.HandlerOption) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, handler, opts...))
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
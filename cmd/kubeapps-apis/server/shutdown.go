@@ -0,0 +1,83 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "k8s.io/klog/v2"
+)
+
+// inFlightPollInterval is the interval at which the in-flight requests are
+// checked while draining.
+const inFlightPollInterval = 50 * time.Millisecond
+
+// inFlightRequests counts the requests being served. Unlike the http.Server,
+// which only tracks its own connections, it also counts the requests of the h2c
+// connections, which are hijacked from the http.Server.
+type inFlightRequests struct {
+	count atomic.Int64
+}
+
+// wrap returns a handler counting the in-flight requests of h.
+func (f *inFlightRequests) wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.count.Add(1)
+		defer f.count.Add(-1)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// current returns the number of in-flight requests.
+func (f *inFlightRequests) current() int64 {
+	return f.count.Load()
+}
+
+// drain waits until no request is in flight, returning false if the context
+// is done first.
+func (f *inFlightRequests) drain(ctx context.Context) bool {
+	ticker := time.NewTicker(inFlightPollInterval)
+	defer ticker.Stop()
+	for f.current() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// shutdown shuts the server down in two phases. The server stops accepting
+// connections and the in-flight requests are drained until the grace period.
+// The remaining requests are then given until the hard timeout, counted from
+// the start of the shutdown, after which the connections are force-closed.
+func shutdown(server *http.Server, inFlight *inFlightRequests, gracePeriod, hardTimeout time.Duration) {
+	start := time.Now()
+	softCtx, cancelSoft := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancelSoft()
+	if err := server.Shutdown(softCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		log.Errorf("Failed to shut down the server gracefully: %v", err)
+	}
+	if inFlight.drain(softCtx) {
+		log.Info("The server was shut down gracefully")
+		return
+	}
+	log.Warningf("%d requests still in flight after the shutdown grace period of %s", inFlight.current(), gracePeriod)
+
+	hardCtx, cancelHard := context.WithDeadline(context.Background(), start.Add(hardTimeout))
+	defer cancelHard()
+	if inFlight.drain(hardCtx) {
+		log.Info("The server was shut down after the shutdown grace period")
+		return
+	}
+	log.Warningf("Force-closing the server with %d requests still in flight after the shutdown hard timeout of %s", inFlight.current(), hardTimeout)
+	if err := server.Close(); err != nil {
+		log.Errorf("Failed to close the server: %v", err)
+	}
+}
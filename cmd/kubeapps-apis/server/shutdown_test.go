@@ -0,0 +1,80 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+)
+
+func TestShutdownInTwoPhases(t *testing.T) {
+	testCases := []struct {
+		name             string
+		requestDuration  time.Duration
+		expectedInFlight bool
+	}{
+		{
+			name:            "drains the requests completing after the grace period",
+			requestDuration: 150 * time.Millisecond,
+		},
+		{
+			name:             "force-closes the requests still in flight after the hard timeout",
+			requestDuration:  5 * time.Second,
+			expectedInFlight: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			inFlight := &inFlightRequests{}
+			started := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tc.requestDuration):
+				case <-release:
+				}
+			})
+			server := newHTTPServer(listener.Addr().String(), inFlight.wrap(handler), core.ServeOptions{ConnectionIdleTimeout: time.Minute})
+			go server.Serve(listener)
+
+			requestDone := make(chan error, 1)
+			go func() {
+				res, err := http.Get("http://" + listener.Addr().String())
+				if err == nil {
+					res.Body.Close()
+				}
+				requestDone <- err
+			}()
+			<-started
+
+			start := time.Now()
+			shutdown(server, inFlight, 50*time.Millisecond, 300*time.Millisecond)
+			if got, want := time.Since(start) < time.Second, true; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+			if got, want := inFlight.current() > 0, tc.expectedInFlight; got != want {
+				t.Errorf("got: %t, want: %t", got, want)
+			}
+			select {
+			case err := <-requestDone:
+				if got, want := err != nil, tc.expectedInFlight; got != want {
+					t.Errorf("got: %v, want error: %t", err, want)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("timed out waiting for the request to complete")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	log "k8s.io/klog/v2"
+)
+
+// startupTimer logs how long each step of the startup takes, such as the
+// registration of the plugins. When the startup exceeds the threshold, a
+// warning naming the current step is logged right away, so that a slow step
+// can be told apart from a hung one.
+type startupTimer struct {
+	mu        sync.Mutex
+	start     time.Time
+	stepStart time.Time
+	step      string
+	threshold time.Duration
+	watchdog  *time.Timer
+	warnf     func(format string, args ...interface{})
+}
+
+// newStartupTimer starts timing the startup. A threshold of 0 disables the
+// warnings.
+func newStartupTimer(threshold time.Duration) *startupTimer {
+	now := time.Now()
+	t := &startupTimer{
+		start:     now,
+		stepStart: now,
+		threshold: threshold,
+		warnf:     log.Warningf,
+	}
+	if threshold > 0 {
+		t.watchdog = time.AfterFunc(threshold, t.warnSlow)
+	}
+	return t
+}
+
+// begin ends the current step, if any, logging its duration, and starts the
+// given one.
+func (t *startupTimer) begin(step string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endStep()
+	t.step = step
+	log.InfoS("+core startup step started", "step", step)
+}
+
+// done ends the startup, logging its total duration, and returns it.
+func (t *startupTimer) done() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.watchdog != nil {
+		t.watchdog.Stop()
+	}
+	t.endStep()
+	total := time.Since(t.start)
+	if t.threshold > 0 && total > t.threshold {
+		t.warnf("Startup took %s, longer than the threshold of %s", total, t.threshold)
+	} else {
+		log.InfoS("+core startup completed", "duration", total)
+	}
+	return total
+}
+
+// endStep logs the duration of the current step. The lock must be held.
+func (t *startupTimer) endStep() {
+	now := time.Now()
+	if t.step != "" {
+		log.InfoS("+core startup step completed", "step", t.step, "duration", now.Sub(t.stepStart))
+	}
+	t.step = ""
+	t.stepStart = now
+}
+
+// warnSlow warns that the startup exceeds the threshold, naming the current step.
+func (t *startupTimer) warnSlow() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.warnf("Startup is taking longer than %s, the step %q has been running for %s", t.threshold, t.step, time.Since(t.stepStart))
+}
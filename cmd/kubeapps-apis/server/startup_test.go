@@ -0,0 +1,55 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartupTimerWarnsOfSlowSteps(t *testing.T) {
+	timer := newStartupTimer(20 * time.Millisecond)
+	warnings := make(chan string, 2)
+	timer.mu.Lock()
+	timer.warnf = func(format string, args ...interface{}) {
+		warnings <- fmt.Sprintf(format, args...)
+	}
+	timer.mu.Unlock()
+
+	timer.begin("fast step")
+	timer.begin("slow step")
+
+	// The warning is logged while the slow step is still running.
+	select {
+	case warning := <-warnings:
+		if !strings.Contains(warning, `the step "slow step" has been running`) {
+			t.Errorf("got: %q, want a warning naming the slow step", warning)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("got: no warning, want a warning while the slow step is running")
+	}
+
+	if got, want := timer.done() > 20*time.Millisecond, true; got != want {
+		t.Errorf("got: %t, want: %t", got, want)
+	}
+	select {
+	case warning := <-warnings:
+		if !strings.Contains(warning, "longer than the threshold") {
+			t.Errorf("got: %q, want a warning for the total duration", warning)
+		}
+	default:
+		t.Errorf("got: no warning, want a warning for the total duration")
+	}
+}
+
+func TestStartupTimerWithoutThreshold(t *testing.T) {
+	timer := newStartupTimer(0)
+	timer.warnf = func(format string, args ...interface{}) {
+		t.Errorf("got: warning %q, want none", fmt.Sprintf(format, args...))
+	}
+	timer.begin("step")
+	timer.done()
+}
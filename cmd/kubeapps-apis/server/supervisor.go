@@ -0,0 +1,59 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	log "k8s.io/klog/v2"
+)
+
+// livezPath is the path of the liveness endpoint.
+const livezPath = "/livez"
+
+// supervisor is notified by the background goroutines of the server when they
+// die. The first failure fails the liveness endpoint and is sent to the serve
+// loop, which then shuts the server down gracefully rather than exiting abruptly.
+type supervisor struct {
+	mu       sync.Mutex
+	err      error
+	failedCh chan error
+}
+
+func newSupervisor() *supervisor {
+	return &supervisor{failedCh: make(chan error, 1)}
+}
+
+// fail reports the death of a background goroutine. Only the first failure is
+// kept, since the server is shutting down from then on.
+func (s *supervisor) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		log.Errorf("Background goroutine failed while shutting down: %v", err)
+		return
+	}
+	log.Errorf("Background goroutine failed, shutting down: %v", err)
+	s.err = err
+	s.failedCh <- err
+}
+
+// failed returns a channel receiving the first failure.
+func (s *supervisor) failed() <-chan error {
+	return s.failedCh
+}
+
+// ServeHTTP reports the liveness, failing once a background goroutine died.
+func (s *supervisor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	err := s.err
+	s.mu.Unlock()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("not alive: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
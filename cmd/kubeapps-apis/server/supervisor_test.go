@@ -0,0 +1,40 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSupervisorFailsLiveness(t *testing.T) {
+	sup := newSupervisor()
+
+	livez := func() int {
+		rec := httptest.NewRecorder()
+		sup.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, livezPath, nil))
+		return rec.Code
+	}
+
+	if got, want := livez(), http.StatusOK; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+
+	sup.fail(fmt.Errorf("boom"))
+	sup.fail(fmt.Errorf("second failure"))
+
+	if got, want := livez(), http.StatusServiceUnavailable; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	select {
+	case err := <-sup.failed():
+		if got, want := err.Error(), "boom"; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	default:
+		t.Fatalf("expected the failure to be sent to the serve loop")
+	}
+}
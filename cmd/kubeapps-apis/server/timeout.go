@@ -0,0 +1,154 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// pluginPackagePrefix prefixes the proto packages of the plugins, such as
+	// kubeappsapis.plugins.fluxv2.packages.v1alpha1.
+	pluginPackagePrefix = "kubeappsapis.plugins."
+
+	// pluginMessageName is the message referencing a plugin in the requests to
+	// the core services, such as in the available_package_ref.
+	pluginMessageName = "kubeappsapis.core.plugins.v1alpha1.Plugin"
+)
+
+// requestTimeouts is a connect interceptor bounding the duration of the
+// requests. The timeout of a request is the one configured for its method
+// name, or else for its plugin, falling back to the default timeout. The
+// default timeout only applies to the unary methods, since the streaming ones
+// are long-lived. The requests failing once their timeout is exceeded have a
+// hint to retry later.
+type requestTimeouts struct {
+	defaultTimeout time.Duration
+	timeouts       map[string]time.Duration
+}
+
+// newRequestTimeouts parses the timeouts of the methods or plugins, in the
+// form <method name or plugin name>=<duration>, such as "fluxv2.packages=2m"
+// or "GetInstalledPackageResourceRefs=30s".
+func newRequestTimeouts(defaultTimeout time.Duration, values []string) (*requestTimeouts, error) {
+	t := &requestTimeouts{defaultTimeout: defaultTimeout, timeouts: map[string]time.Duration{}}
+	for _, value := range values {
+		key, durationValue, found := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid timeout %q, expected <method name or plugin name>=<duration>", value)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(durationValue))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q, expected a positive duration such as 30s", value)
+		}
+		t.timeouts[key] = timeout
+	}
+	return t, nil
+}
+
+// enabled returns whether any timeout is configured.
+func (t *requestTimeouts) enabled() bool {
+	return t.defaultTimeout > 0 || len(t.timeouts) > 0
+}
+
+// timeoutFor returns the timeout of a request, if any. The plugin of a request
+// is either the one of the procedure or, for the core services, the plugin
+// referenced by the request message.
+func (t *requestTimeouts) timeoutFor(procedure string, msg any, defaultTimeout time.Duration) time.Duration {
+	if timeout, ok := t.timeouts[procedure[strings.LastIndex(procedure, "/")+1:]]; ok {
+		return timeout
+	}
+	if plugin := pluginOfProcedure(procedure); plugin != "" {
+		if timeout, ok := t.timeouts[plugin]; ok {
+			return timeout
+		}
+	}
+	if m, ok := msg.(proto.Message); ok {
+		if plugin := pluginOfMessage(m.ProtoReflect()); plugin != "" {
+			if timeout, ok := t.timeouts[plugin]; ok {
+				return timeout
+			}
+		}
+	}
+	return defaultTimeout
+}
+
+func (t *requestTimeouts) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if timeout := t.timeoutFor(req.Spec().Procedure, req.Any(), t.defaultTimeout); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+			res, err := next(ctx, req)
+			return res, timeoutError(ctx, err)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (t *requestTimeouts) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler only bounds the streaming requests whose method or
+// plugin has a configured timeout, since the request message is not received yet.
+func (t *requestTimeouts) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if timeout := t.timeoutFor(conn.Spec().Procedure, nil, 0); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return timeoutError(ctx, next(ctx, conn))
+		}
+		return next(ctx, conn)
+	}
+}
+
+// timeoutError adds a retry hint to the error of a request whose timeout is
+// exceeded.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return withRetryAfter(err, timeoutRetryAfter)
+}
+
+// pluginOfProcedure returns the name of the plugin serving the procedure, such
+// as "fluxv2.packages" for
+// "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2PackagesService/GetAvailablePackageSummaries",
+// or "" for the core services.
+func pluginOfProcedure(procedure string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+	pkg, found := strings.CutPrefix(string(protoreflect.FullName(service).Parent()), pluginPackagePrefix)
+	if !found {
+		return ""
+	}
+	// The last element of the package is the version of the plugin.
+	return string(protoreflect.FullName(pkg).Parent())
+}
+
+// pluginOfMessage returns the name of the plugin referenced by the message or
+// by its nested messages, such as the available_package_ref, if any.
+func pluginOfMessage(m protoreflect.Message) string {
+	if m.Descriptor().FullName() == pluginMessageName {
+		return m.Get(m.Descriptor().Fields().ByName("name")).String()
+	}
+	plugin := ""
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return true
+		}
+		plugin = pluginOfMessage(v.Message())
+		return plugin == ""
+	})
+	return plugin
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	pluginsGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/plugins/v1alpha1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestRequestTimeouts(t *testing.T) {
+	timeouts, err := newRequestTimeouts(10*time.Second, []string{
+		"fluxv2.packages=2m",
+		"GetInstalledPackageResourceRefs=30s",
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	testCases := []struct {
+		name            string
+		procedure       string
+		msg             any
+		defaultTimeout  time.Duration
+		expectedTimeout time.Duration
+	}{
+		{
+			name:            "uses the timeout of the method",
+			procedure:       "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageResourceRefs",
+			defaultTimeout:  10 * time.Second,
+			expectedTimeout: 30 * time.Second,
+		},
+		{
+			name:            "uses the timeout of the plugin of the procedure",
+			procedure:       "/kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2PackagesService/GetAvailablePackageSummaries",
+			defaultTimeout:  10 * time.Second,
+			expectedTimeout: 2 * time.Minute,
+		},
+		{
+			name:      "uses the timeout of the plugin referenced by the request",
+			procedure: "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail",
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &packagesGRPCv1alpha1.AvailablePackageReference{
+					Identifier: "bitnami/apache",
+					Plugin:     &pluginsGRPCv1alpha1.Plugin{Name: "fluxv2.packages", Version: "v1alpha1"},
+				},
+			},
+			defaultTimeout:  10 * time.Second,
+			expectedTimeout: 2 * time.Minute,
+		},
+		{
+			name:      "falls back to the default timeout",
+			procedure: "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetAvailablePackageDetail",
+			msg: &packagesGRPCv1alpha1.GetAvailablePackageDetailRequest{
+				AvailablePackageRef: &packagesGRPCv1alpha1.AvailablePackageReference{
+					Identifier: "bitnami/apache",
+					Plugin:     &pluginsGRPCv1alpha1.Plugin{Name: "helm.packages", Version: "v1alpha1"},
+				},
+			},
+			defaultTimeout:  10 * time.Second,
+			expectedTimeout: 10 * time.Second,
+		},
+		{
+			name:            "does not bound the streaming requests without a configured timeout",
+			procedure:       "/kubeappsapis.plugins.resources.v1alpha1.ResourcesService/GetResources",
+			expectedTimeout: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := timeouts.timeoutFor(tc.procedure, tc.msg, tc.defaultTimeout), tc.expectedTimeout; got != want {
+				t.Errorf("got: %s, want: %s", got, want)
+			}
+		})
+	}
+
+	t.Run("bounds the context of the unary requests", func(t *testing.T) {
+		var remaining time.Duration
+		handler := timeouts.WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if deadline, ok := ctx.Deadline(); ok {
+				remaining = time.Until(deadline)
+			}
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		})
+		if _, err := handler(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if remaining <= 0 || remaining > 10*time.Second {
+			t.Errorf("got: %s until the deadline, want: at most 10s", remaining)
+		}
+	})
+
+	t.Run("hints to retry the requests exceeding their timeout", func(t *testing.T) {
+		shortTimeouts, err := newRequestTimeouts(time.Millisecond, nil)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		handler := shortTimeouts.WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			<-ctx.Done()
+			return nil, connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+		})
+		_, err = handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+		var connectErr *connect.Error
+		if !errors.As(err, &connectErr) {
+			t.Fatalf("got: %+v, want a connect error", err)
+		}
+		if got, want := connectErr.Meta().Get(retryAfterHeader), "10"; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+
+	t.Run("errors for an invalid timeout", func(t *testing.T) {
+		if _, err := newRequestTimeouts(0, []string{"fluxv2.packages=soon"}); err == nil {
+			t.Errorf("got: nil, want: error")
+		}
+	})
+}
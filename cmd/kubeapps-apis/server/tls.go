@@ -0,0 +1,147 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// tlsVersions are the TLS versions which can be configured as the minimum one.
+// Older versions are not supported since they are deprecated.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsEnabled returns whether the API should be served over TLS.
+func tlsEnabled(serveOpts core.ServeOptions) bool {
+	return serveOpts.TLSCertFile != "" || serveOpts.TLSKeyFile != ""
+}
+
+// serverTLSConfig returns the TLS config used to serve the API. When a client CA
+// is configured, client certificates are verified against it if presented, so that
+// their identity can be used for logging and authorization.
+func serverTLSConfig(serveOpts core.ServeOptions) (*tls.Config, error) {
+	if serveOpts.TLSCertFile == "" || serveOpts.TLSKeyFile == "" {
+		return nil, fmt.Errorf("both a TLS certificate and key are required to serve over TLS")
+	}
+	cert, err := loadTLSKeyPair(serveOpts.TLSCertFile, serveOpts.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsPolicyConfig(serveOpts)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	if serveOpts.TLSClientCAFile != "" {
+		caCert, err := os.ReadFile(serveOpts.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client CA certificate: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("unable to parse client CA certificate %q", serveOpts.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
+// loadTLSKeyPair loads the TLS certificate and its key, failing with an error
+// naming both files when they cannot be read or when they do not match.
+func loadTLSKeyPair(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid TLS key pair, the certificate %q and the key %q are unreadable or do not match: %w", certFile, keyFile, err)
+	}
+	return cert, nil
+}
+
+// tlsPolicyConfig returns a TLS config restricted to the configured minimum TLS
+// version and cipher suites.
+func tlsPolicyConfig(serveOpts core.ServeOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if serveOpts.TLSMinVersion != "" {
+		minVersion, ok := tlsVersions[serveOpts.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum TLS version %q, expected one of \"1.2\" or \"1.3\"", serveOpts.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = minVersion
+	}
+
+	if len(serveOpts.TLSCipherSuites) > 0 {
+		ids := map[string]uint16{}
+		for _, suite := range tls.CipherSuites() {
+			ids[suite.Name] = suite.ID
+		}
+		for _, name := range serveOpts.TLSCipherSuites {
+			id, ok := ids[name]
+			if !ok {
+				return nil, fmt.Errorf("unsupported TLS cipher suite %q", name)
+			}
+			tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+		}
+	}
+	return tlsConfig, nil
+}
+
+// gatewayDialOptions returns the dial options used by the gateway to reach the
+// gRPC handlers of this same server. When a gateway token file is configured,
+// the token is attached to each call of the gateway. The gateway receives the
+// responses with the same HTTP/2 flow-control windows as the server, and only
+// calls the exposed methods.
+func gatewayDialOptions(serveOpts core.ServeOptions) ([]grpc.DialOption, error) {
+	exposedMethods, err := newGatewayExposedMethods(serveOpts.GatewayExposedMethods)
+	if err != nil {
+		return nil, err
+	}
+	dialOptions := exposedMethods.dialOptions()
+	if serveOpts.HTTP2InitialWindowSize > 0 {
+		dialOptions = append(dialOptions, grpc.WithInitialWindowSize(serveOpts.HTTP2InitialWindowSize))
+	}
+	if serveOpts.HTTP2InitialConnWindowSize > 0 {
+		dialOptions = append(dialOptions, grpc.WithInitialConnWindowSize(serveOpts.HTTP2InitialConnWindowSize))
+	}
+	if serveOpts.GatewayTokenFile != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(tokenSourceCredentials{
+			tokenSource: newFileTokenSource(serveOpts.GatewayTokenFile),
+		}))
+	}
+	if !tlsEnabled(serveOpts) {
+		return append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials())), nil
+	}
+	tlsConfig, err := tlsPolicyConfig(serveOpts)
+	if err != nil {
+		return nil, err
+	}
+	// The gateway connects to this very process over the loopback interface, for which
+	// the serving certificate is generally not issued, so there is nothing to verify.
+	tlsConfig.InsecureSkipVerify = true // #nosec G402
+	return append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))), nil
+}
+
+// withClientCertIdentity adds the identity of the verified client certificate, if
+// any, to the request context so that it is available to interceptors and handlers.
+// Note that requests handled by the gateway reach the gRPC handlers through a new
+// loopback connection, so only the gateway itself sees the original identity.
+func withClientCertIdentity(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := core.ClientCertIdentityFromTLS(r.TLS); id != nil {
+			r = r.WithContext(core.ContextWithClientCertIdentity(r.Context(), id))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
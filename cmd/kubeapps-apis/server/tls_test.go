@@ -0,0 +1,358 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	"golang.org/x/net/http2"
+)
+
+func TestWithClientCertIdentity(t *testing.T) {
+	clientCert := &x509.Certificate{Subject: pkix.Name{CommonName: "client"}}
+
+	testCases := []struct {
+		name               string
+		tlsState           *tls.ConnectionState
+		expectedCommonName string
+	}{
+		{
+			name:     "request without TLS has no identity",
+			tlsState: nil,
+		},
+		{
+			name: "request with a verified client certificate has its identity",
+			tlsState: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{clientCert},
+				VerifiedChains:   [][]*x509.Certificate{{clientCert}},
+			},
+			expectedCommonName: "client",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotCommonName string
+			handler := withClientCertIdentity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if id, ok := core.ClientCertIdentityFromContext(r.Context()); ok {
+					gotCommonName = id.CommonName
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.TLS = tc.tlsState
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got, want := gotCommonName, tc.expectedCommonName; got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestServerTLSConfigRejectsOldTLSVersions(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t)
+
+	testCases := []struct {
+		name             string
+		minVersion       string
+		clientMaxVersion uint16
+		expectedError    bool
+	}{
+		{
+			name:             "rejects a TLS 1.0 client by default",
+			clientMaxVersion: tls.VersionTLS10,
+			expectedError:    true,
+		},
+		{
+			name:             "rejects a TLS 1.1 client by default",
+			clientMaxVersion: tls.VersionTLS11,
+			expectedError:    true,
+		},
+		{
+			name:             "accepts a TLS 1.2 client by default",
+			clientMaxVersion: tls.VersionTLS12,
+		},
+		{
+			name:             "rejects a TLS 1.2 client when the minimum version is 1.3",
+			minVersion:       "1.3",
+			clientMaxVersion: tls.VersionTLS12,
+			expectedError:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsConfig, err := serverTLSConfig(core.ServeOptions{
+				TLSCertFile:   certFile,
+				TLSKeyFile:    keyFile,
+				TLSMinVersion: tc.minVersion,
+			})
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			srv.TLS = tlsConfig
+			srv.StartTLS()
+			defer srv.Close()
+
+			conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{
+				InsecureSkipVerify: true, // #nosec G402
+				MinVersion:         tls.VersionTLS10,
+				MaxVersion:         tc.clientMaxVersion,
+			})
+			if err == nil {
+				conn.Close()
+			}
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Errorf("got: %t, want: %t: err: %+v", got, want, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPServerRefusesH2CWithTLS(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t)
+
+	testCases := []struct {
+		name          string
+		serveOpts     core.ServeOptions
+		expectedError bool
+	}{
+		{
+			name: "serves h2c without TLS",
+		},
+		{
+			name:          "refuses h2c with TLS",
+			serveOpts:     core.ServeOptions{TLSCertFile: certFile, TLSKeyFile: keyFile},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			server := newHTTPServer(listener.Addr().String(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), tc.serveOpts)
+			serve := func() error { return server.Serve(listener) }
+			if tlsEnabled(tc.serveOpts) {
+				if server.TLSConfig, err = serverTLSConfig(tc.serveOpts); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				if err := http2.ConfigureServer(server, newHTTP2Server(tc.serveOpts)); err != nil {
+					t.Fatalf("%+v", err)
+				}
+				serve = func() error { return server.ServeTLS(listener, "", "") }
+			}
+			go func() {
+				if err := serve(); err != nil && err != http.ErrServerClosed {
+					t.Errorf("%+v", err)
+				}
+			}()
+			defer server.Close()
+
+			// An h2c client with prior knowledge sends cleartext HTTP/2.
+			client := &http.Client{
+				Timeout: 5 * time.Second,
+				Transport: &http2.Transport{
+					AllowHTTP: true,
+					DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+						return (&net.Dialer{}).DialContext(ctx, network, addr)
+					},
+				},
+			}
+			res, err := client.Get("http://" + listener.Addr().String())
+			if err == nil {
+				res.Body.Close()
+			}
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got: %t, want: %t: err: %+v", got, want, err)
+			}
+			if !tlsEnabled(tc.serveOpts) {
+				return
+			}
+
+			// Nor is an HTTP/1 request over TLS upgraded to h2c.
+			client = &http.Client{
+				Timeout: 5 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402
+					TLSNextProto:    map[string]func(string, *tls.Conn) http.RoundTripper{},
+				},
+			}
+			req, err := http.NewRequest(http.MethodGet, "https://"+listener.Addr().String(), nil)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			req.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+			req.Header.Set("Upgrade", "h2c")
+			req.Header.Set("HTTP2-Settings", "AAMAAABkAARAAAAAAAIAAAAA")
+			res, err = client.Do(req)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			res.Body.Close()
+			if got, want := res.StatusCode, http.StatusOK; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestTLSPolicyConfig(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		serveOpts            core.ServeOptions
+		expectedMinVersion   uint16
+		expectedCipherSuites []uint16
+		expectedError        bool
+	}{
+		{
+			name:               "defaults to TLS 1.2",
+			expectedMinVersion: tls.VersionTLS12,
+		},
+		{
+			name: "restricts the cipher suites",
+			serveOpts: core.ServeOptions{
+				TLSMinVersion:   "1.2",
+				TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+			},
+			expectedMinVersion:   tls.VersionTLS12,
+			expectedCipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		},
+		{
+			name:          "errors for a deprecated TLS version",
+			serveOpts:     core.ServeOptions{TLSMinVersion: "1.0"},
+			expectedError: true,
+		},
+		{
+			name:          "errors for an insecure cipher suite",
+			serveOpts:     core.ServeOptions{TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsConfig, err := tlsPolicyConfig(tc.serveOpts)
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got: %t, want: %t: err: %+v", got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := tlsConfig.MinVersion, tc.expectedMinVersion; got != want {
+				t.Errorf("got: %d, want: %d", got, want)
+			}
+			if got, want := tlsConfig.CipherSuites, tc.expectedCipherSuites; !cmp.Equal(got, want) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestServerTLSConfigKeyPair(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t)
+	otherCertFile, otherKeyFile := writeTestKeyPair(t)
+
+	testCases := []struct {
+		name          string
+		certFile      string
+		keyFile       string
+		expectedError bool
+	}{
+		{
+			name:     "matching certificate and key",
+			certFile: certFile,
+			keyFile:  keyFile,
+		},
+		{
+			name:          "certificate not matching the key",
+			certFile:      certFile,
+			keyFile:       otherKeyFile,
+			expectedError: true,
+		},
+		{
+			name:          "key not matching the certificate",
+			certFile:      otherCertFile,
+			keyFile:       keyFile,
+			expectedError: true,
+		},
+		{
+			name:          "missing key",
+			certFile:      certFile,
+			keyFile:       filepath.Join(t.TempDir(), "missing.key"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := serverTLSConfig(core.ServeOptions{TLSCertFile: tc.certFile, TLSKeyFile: tc.keyFile})
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if err != nil && !strings.Contains(err.Error(), tc.keyFile) {
+				t.Errorf("got: %q, want an error naming the key %q", err, tc.keyFile)
+			}
+		})
+	}
+}
+
+// writeTestKeyPair writes a self-signed certificate for localhost and its key,
+// returning the paths of the files.
+func writeTestKeyPair(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return certFile, keyFile
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	log "k8s.io/klog/v2"
+)
+
+// The methods generated by protoc-gen-validate for the messages with
+// validation rules. ValidateAll reports every violation, rather than the first.
+type validator interface {
+	Validate() error
+}
+
+type allValidator interface {
+	ValidateAll() error
+}
+
+// The interfaces of the errors generated by protoc-gen-validate: the violation
+// of the rules of a field, and the violations of several fields.
+type fieldViolation interface {
+	Field() string
+	Reason() string
+}
+
+type multiError interface {
+	AllErrors() []error
+}
+
+// requestValidator is a connect interceptor validating the request messages
+// which have validation rules before they are dispatched, failing the invalid
+// ones with an InvalidArgument error detailing the field violations, so that
+// the plugins do not each validate them.
+type requestValidator struct{}
+
+func (requestValidator) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := validateMessage(req.Any()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (requestValidator) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (requestValidator) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, validatingHandlerConn{conn})
+	}
+}
+
+// validatingHandlerConn validates each message received from the client.
+type validatingHandlerConn struct {
+	connect.StreamingHandlerConn
+}
+
+func (c validatingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return validateMessage(msg)
+}
+
+// validateMessage validates the message if it has validation rules, returning
+// an InvalidArgument error with a BadRequest detail listing the violations.
+func validateMessage(msg any) error {
+	var err error
+	switch m := msg.(type) {
+	case allValidator:
+		err = m.ValidateAll()
+	case validator:
+		err = m.Validate()
+	}
+	if err == nil {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	violations := []error{err}
+	var multi multiError
+	if errors.As(err, &multi) {
+		violations = multi.AllErrors()
+	}
+	for _, violation := range violations {
+		var field fieldViolation
+		if errors.As(violation, &field) {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field(),
+				Description: field.Reason(),
+			})
+		}
+	}
+
+	connectErr := connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("Invalid request: %w", err))
+	if len(badRequest.FieldViolations) > 0 {
+		detail, detailErr := connect.NewErrorDetail(badRequest)
+		if detailErr != nil {
+			log.Errorf("Unable to add the field violations to the error: %v", detailErr)
+		} else {
+			connectErr.AddDetail(detail)
+		}
+	}
+	return connectErr
+}
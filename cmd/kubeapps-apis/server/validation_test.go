@@ -0,0 +1,134 @@
+// Copyright 2023 the Kubeapps contributors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/core"
+	packagesGRPCv1alpha1 "github.com/vmware-tanzu/kubeapps/cmd/kubeapps-apis/gen/core/packages/v1alpha1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// The types generated by protoc-gen-validate for a message with validation rules.
+type testValidationError struct {
+	field  string
+	reason string
+}
+
+func (e testValidationError) Field() string { return e.field }
+
+func (e testValidationError) Reason() string { return e.reason }
+
+func (e testValidationError) Error() string { return e.field + ": " + e.reason }
+
+type testMultiError []error
+
+func (m testMultiError) Error() string { return errors.Join(m...).Error() }
+
+func (m testMultiError) AllErrors() []error { return m }
+
+type testValidatedMessage struct {
+	errs []error
+}
+
+func (m testValidatedMessage) ValidateAll() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	return testMultiError(m.errs)
+}
+
+func TestValidateMessage(t *testing.T) {
+	testCases := []struct {
+		name               string
+		msg                any
+		expectedViolations []*errdetails.BadRequest_FieldViolation
+		expectErr          bool
+	}{
+		{
+			name: "message without validation rules",
+			msg:  &packagesGRPCv1alpha1.GetAvailablePackageSummariesRequest{},
+		},
+		{
+			name: "valid message",
+			msg:  testValidatedMessage{},
+		},
+		{
+			name: "invalid message",
+			msg: testValidatedMessage{errs: []error{
+				testValidationError{field: "Context", reason: "value is required"},
+				testValidationError{field: "PageSize", reason: "value must be less than 1000"},
+			}},
+			expectedViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "Context", Description: "value is required"},
+				{Field: "PageSize", Description: "value must be less than 1000"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMessage(tc.msg)
+			if got, want := err != nil, tc.expectErr; got != want {
+				t.Fatalf("got error: %v, want error: %t", err, want)
+			}
+			if !tc.expectErr {
+				return
+			}
+			if got, want := connect.CodeOf(err), connect.CodeInvalidArgument; got != want {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) || len(connectErr.Details()) != 1 {
+				t.Fatalf("got: %+v, want a single error detail", err)
+			}
+			detail, err := connectErr.Details()[0].Value()
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if got, want := detail.(*errdetails.BadRequest).GetFieldViolations(), tc.expectedViolations; !cmp.Equal(got, want, protocmp.Transform()) {
+				t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, protocmp.Transform()))
+			}
+		})
+	}
+}
+
+func TestValidationErrorDetailsOverGRPCWeb(t *testing.T) {
+	procedure := "/kubeappsapis.core.packages.v1alpha1.PackagesService/GetInstalledPackageDetail"
+	ts := serveTestProcedure(t, procedure,
+		func(ctx context.Context, req *connect.Request[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest]) (*connect.Response[packagesGRPCv1alpha1.GetInstalledPackageDetailResponse], error) {
+			return nil, validateMessage(testValidatedMessage{errs: []error{
+				testValidationError{field: "InstalledPackageRef", reason: "value is required"},
+			}})
+		},
+		newHandlerOptions(core.ServeOptions{}, nil)...,
+	)
+
+	// The details of the status are sent in the grpc-status-details-bin
+	// trailer, which the grpc-web clients such as the dashboard decode.
+	client := connect.NewClient[packagesGRPCv1alpha1.GetInstalledPackageDetailRequest, packagesGRPCv1alpha1.GetInstalledPackageDetailResponse](ts.Client(), ts.URL+procedure, connect.WithGRPCWeb())
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&packagesGRPCv1alpha1.GetInstalledPackageDetailRequest{}))
+	if got, want := connect.CodeOf(err), connect.CodeInvalidArgument; got != want {
+		t.Fatalf("got: %v, want: %v, err: %+v", got, want, err)
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || len(connectErr.Details()) != 1 {
+		t.Fatalf("got: %+v, want a single error detail", err)
+	}
+	detail, err := connectErr.Details()[0].Value()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	want := []*errdetails.BadRequest_FieldViolation{{Field: "InstalledPackageRef", Description: "value is required"}}
+	if got := detail.(*errdetails.BadRequest).GetFieldViolations(); !cmp.Equal(got, want, protocmp.Transform()) {
+		t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got, protocmp.Transform()))
+	}
+}
@@ -13,6 +13,8 @@ import {
   DeleteInstalledPackageResponse,
   GetAvailablePackageDetailRequest,
   GetAvailablePackageDetailResponse,
+  GetAvailablePackageDetailsBatchRequest,
+  GetAvailablePackageDetailsBatchResponse,
   GetAvailablePackageMetadatasRequest,
   GetAvailablePackageMetadatasResponse,
   GetAvailablePackageSummariesRequest,
@@ -25,6 +27,8 @@ import {
   GetInstalledPackageResourceRefsResponse,
   GetInstalledPackageSummariesRequest,
   GetInstalledPackageSummariesResponse,
+  StreamAvailablePackageDetailResponse,
+  StreamInstalledPackageResourceRefsResponse,
   UpdateInstalledPackageRequest,
   UpdateInstalledPackageResponse,
 } from "./packages_pb";
@@ -57,6 +61,24 @@ export const PackagesService = {
       O: GetAvailablePackageDetailResponse,
       kind: MethodKind.Unary,
     },
+    /**
+     * @generated from rpc kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageDetailsBatch
+     */
+    getAvailablePackageDetailsBatch: {
+      name: "GetAvailablePackageDetailsBatch",
+      I: GetAvailablePackageDetailsBatchRequest,
+      O: GetAvailablePackageDetailsBatchResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.core.packages.v1alpha1.PackagesService.StreamAvailablePackageDetail
+     */
+    streamAvailablePackageDetail: {
+      name: "StreamAvailablePackageDetail",
+      I: GetAvailablePackageDetailRequest,
+      O: StreamAvailablePackageDetailResponse,
+      kind: MethodKind.ServerStreaming,
+    },
     /**
      * @generated from rpc kubeappsapis.core.packages.v1alpha1.PackagesService.GetAvailablePackageVersions
      */
@@ -129,5 +151,14 @@ export const PackagesService = {
       O: GetInstalledPackageResourceRefsResponse,
       kind: MethodKind.Unary,
     },
+    /**
+     * @generated from rpc kubeappsapis.core.packages.v1alpha1.PackagesService.StreamInstalledPackageResourceRefs
+     */
+    streamInstalledPackageResourceRefs: {
+      name: "StreamInstalledPackageResourceRefs",
+      I: GetInstalledPackageResourceRefsRequest,
+      O: StreamInstalledPackageResourceRefsResponse,
+      kind: MethodKind.ServerStreaming,
+    },
   },
 } as const;
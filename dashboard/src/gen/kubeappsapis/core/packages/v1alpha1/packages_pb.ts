@@ -14,7 +14,7 @@ import type {
   PartialMessage,
   PlainMessage,
 } from "@bufbuild/protobuf";
-import { Any, Message, proto3 } from "@bufbuild/protobuf";
+import { Any, FieldMask, Message, proto3 } from "@bufbuild/protobuf";
 import { Plugin } from "../../plugins/v1alpha1/plugins_pb";
 
 /**
@@ -28,6 +28,9 @@ export class GetAvailablePackageSummariesRequest extends Message<GetAvailablePac
   /**
    * The context (cluster/namespace) for the request
    *
+   * The namespace "*" requests the available packages of every namespace
+   * accessible to the user, each package keeping the namespace of its reference.
+   *
    * @generated from field: kubeappsapis.core.packages.v1alpha1.Context context = 1;
    */
   context?: Context;
@@ -46,6 +49,16 @@ export class GetAvailablePackageSummariesRequest extends Message<GetAvailablePac
    */
   paginationOptions?: PaginationOptions;
 
+  /**
+   * Partial results
+   *
+   * When true, the plugins failing to return their summaries are reported in
+   * the warnings of the response instead of failing the whole request.
+   *
+   * @generated from field: bool partial_results = 4;
+   */
+  partialResults = false;
+
   constructor(data?: PartialMessage<GetAvailablePackageSummariesRequest>) {
     super();
     proto3.util.initPartial(data, this);
@@ -58,6 +71,7 @@ export class GetAvailablePackageSummariesRequest extends Message<GetAvailablePac
     { no: 1, name: "context", kind: "message", T: Context },
     { no: 2, name: "filter_options", kind: "message", T: FilterOptions },
     { no: 3, name: "pagination_options", kind: "message", T: PaginationOptions },
+    { no: 4, name: "partial_results", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
   ]);
 
   static fromBinary(
@@ -120,6 +134,15 @@ export class GetAvailablePackageDetailRequest extends Message<GetAvailablePackag
    */
   pkgVersion = "";
 
+  /**
+   * Optional mask of the fields of the available package detail to return,
+   * such as "name,version,icon_url". By default all the fields are returned,
+   * including the readme and default values which can be large.
+   *
+   * @generated from field: google.protobuf.FieldMask field_mask = 3;
+   */
+  fieldMask?: FieldMask;
+
   constructor(data?: PartialMessage<GetAvailablePackageDetailRequest>) {
     super();
     proto3.util.initPartial(data, this);
@@ -130,6 +153,7 @@ export class GetAvailablePackageDetailRequest extends Message<GetAvailablePackag
   static readonly fields: FieldList = proto3.util.newFieldList(() => [
     { no: 1, name: "available_package_ref", kind: "message", T: AvailablePackageReference },
     { no: 2, name: "pkg_version", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 3, name: "field_mask", kind: "message", T: FieldMask },
   ]);
 
   static fromBinary(
@@ -167,6 +191,76 @@ export class GetAvailablePackageDetailRequest extends Message<GetAvailablePackag
   }
 }
 
+/**
+ * GetAvailablePackageDetailsBatchRequest
+ *
+ * Request for GetAvailablePackageDetailsBatch
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.GetAvailablePackageDetailsBatchRequest
+ */
+export class GetAvailablePackageDetailsBatchRequest extends Message<GetAvailablePackageDetailsBatchRequest> {
+  /**
+   * Requests
+   *
+   * The available package detail requests to be fetched in a single round trip.
+   *
+   * @generated from field: repeated kubeappsapis.core.packages.v1alpha1.GetAvailablePackageDetailRequest requests = 1;
+   */
+  requests: GetAvailablePackageDetailRequest[] = [];
+
+  constructor(data?: PartialMessage<GetAvailablePackageDetailsBatchRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.GetAvailablePackageDetailsBatchRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    {
+      no: 1,
+      name: "requests",
+      kind: "message",
+      T: GetAvailablePackageDetailRequest,
+      repeated: true,
+    },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): GetAvailablePackageDetailsBatchRequest {
+    return new GetAvailablePackageDetailsBatchRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): GetAvailablePackageDetailsBatchRequest {
+    return new GetAvailablePackageDetailsBatchRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): GetAvailablePackageDetailsBatchRequest {
+    return new GetAvailablePackageDetailsBatchRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | GetAvailablePackageDetailsBatchRequest
+      | PlainMessage<GetAvailablePackageDetailsBatchRequest>
+      | undefined,
+    b:
+      | GetAvailablePackageDetailsBatchRequest
+      | PlainMessage<GetAvailablePackageDetailsBatchRequest>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(GetAvailablePackageDetailsBatchRequest, a, b);
+  }
+}
+
 /**
  * GetAvailablePackageVersionsRequest
  *
@@ -338,6 +432,16 @@ export class GetInstalledPackageSummariesRequest extends Message<GetInstalledPac
    */
   paginationOptions?: PaginationOptions;
 
+  /**
+   * Partial results
+   *
+   * When true, the plugins failing to return their summaries are reported in
+   * the warnings of the response instead of failing the whole request.
+   *
+   * @generated from field: bool partial_results = 3;
+   */
+  partialResults = false;
+
   constructor(data?: PartialMessage<GetInstalledPackageSummariesRequest>) {
     super();
     proto3.util.initPartial(data, this);
@@ -349,6 +453,7 @@ export class GetInstalledPackageSummariesRequest extends Message<GetInstalledPac
   static readonly fields: FieldList = proto3.util.newFieldList(() => [
     { no: 1, name: "context", kind: "message", T: Context },
     { no: 2, name: "pagination_options", kind: "message", T: PaginationOptions },
+    { no: 3, name: "partial_results", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
   ]);
 
   static fromBinary(
@@ -800,6 +905,16 @@ export class GetAvailablePackageSummariesResponse extends Message<GetAvailablePa
    */
   categories: string[] = [];
 
+  /**
+   * Warnings
+   *
+   * The plugins which failed to return their summaries when partial results
+   * were requested. The summaries of these plugins are missing from the results.
+   *
+   * @generated from field: repeated kubeappsapis.core.packages.v1alpha1.PluginWarning warnings = 4;
+   */
+  warnings: PluginWarning[] = [];
+
   constructor(data?: PartialMessage<GetAvailablePackageSummariesResponse>) {
     super();
     proto3.util.initPartial(data, this);
@@ -818,6 +933,7 @@ export class GetAvailablePackageSummariesResponse extends Message<GetAvailablePa
     },
     { no: 2, name: "next_page_token", kind: "scalar", T: 9 /* ScalarType.STRING */ },
     { no: 3, name: "categories", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+    { no: 4, name: "warnings", kind: "message", T: PluginWarning, repeated: true },
   ]);
 
   static fromBinary(
@@ -924,6 +1040,222 @@ export class GetAvailablePackageDetailResponse extends Message<GetAvailablePacka
   }
 }
 
+/**
+ * GetAvailablePackageDetailsBatchResponse
+ *
+ * Response for GetAvailablePackageDetailsBatch
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.GetAvailablePackageDetailsBatchResponse
+ */
+export class GetAvailablePackageDetailsBatchResponse extends Message<GetAvailablePackageDetailsBatchResponse> {
+  /**
+   * Available package details
+   *
+   * The requested AvailablePackageDetails, in the same order as the requests.
+   *
+   * @generated from field: repeated kubeappsapis.core.packages.v1alpha1.AvailablePackageDetail available_package_details = 1;
+   */
+  availablePackageDetails: AvailablePackageDetail[] = [];
+
+  constructor(data?: PartialMessage<GetAvailablePackageDetailsBatchResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.GetAvailablePackageDetailsBatchResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    {
+      no: 1,
+      name: "available_package_details",
+      kind: "message",
+      T: AvailablePackageDetail,
+      repeated: true,
+    },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): GetAvailablePackageDetailsBatchResponse {
+    return new GetAvailablePackageDetailsBatchResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): GetAvailablePackageDetailsBatchResponse {
+    return new GetAvailablePackageDetailsBatchResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): GetAvailablePackageDetailsBatchResponse {
+    return new GetAvailablePackageDetailsBatchResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | GetAvailablePackageDetailsBatchResponse
+      | PlainMessage<GetAvailablePackageDetailsBatchResponse>
+      | undefined,
+    b:
+      | GetAvailablePackageDetailsBatchResponse
+      | PlainMessage<GetAvailablePackageDetailsBatchResponse>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(GetAvailablePackageDetailsBatchResponse, a, b);
+  }
+}
+
+/**
+ * StreamAvailablePackageDetailResponse
+ *
+ * Response for StreamAvailablePackageDetail. The first response has the
+ * available package detail without its large text fields, the readme, default
+ * values and values schema, which then follow in chunks, so that they can be
+ * rendered progressively. Each response has either the detail or a chunk.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.StreamAvailablePackageDetailResponse
+ */
+export class StreamAvailablePackageDetailResponse extends Message<StreamAvailablePackageDetailResponse> {
+  /**
+   * Available package detail
+   *
+   * The available package detail, without its large text fields.
+   *
+   * @generated from field: kubeappsapis.core.packages.v1alpha1.AvailablePackageDetail available_package_detail = 1;
+   */
+  availablePackageDetail?: AvailablePackageDetail;
+
+  /**
+   * Chunk
+   *
+   * A chunk of a large text field of the available package detail.
+   *
+   * @generated from field: kubeappsapis.core.packages.v1alpha1.AvailablePackageDetailChunk chunk = 2;
+   */
+  chunk?: AvailablePackageDetailChunk;
+
+  constructor(data?: PartialMessage<StreamAvailablePackageDetailResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.StreamAvailablePackageDetailResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "available_package_detail", kind: "message", T: AvailablePackageDetail },
+    { no: 2, name: "chunk", kind: "message", T: AvailablePackageDetailChunk },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): StreamAvailablePackageDetailResponse {
+    return new StreamAvailablePackageDetailResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): StreamAvailablePackageDetailResponse {
+    return new StreamAvailablePackageDetailResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): StreamAvailablePackageDetailResponse {
+    return new StreamAvailablePackageDetailResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | StreamAvailablePackageDetailResponse
+      | PlainMessage<StreamAvailablePackageDetailResponse>
+      | undefined,
+    b:
+      | StreamAvailablePackageDetailResponse
+      | PlainMessage<StreamAvailablePackageDetailResponse>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(StreamAvailablePackageDetailResponse, a, b);
+  }
+}
+
+/**
+ * AvailablePackageDetailChunk
+ *
+ * A chunk of a large text field of an available package detail, such as its
+ * readme. The chunks of a field are sent in order and concatenated.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.AvailablePackageDetailChunk
+ */
+export class AvailablePackageDetailChunk extends Message<AvailablePackageDetailChunk> {
+  /**
+   * Field
+   *
+   * The name of the field of the AvailablePackageDetail, such as "readme",
+   * "default_values" or "values_schema".
+   *
+   * @generated from field: string field = 1;
+   */
+  field = "";
+
+  /**
+   * Data
+   *
+   * The data appended to the field.
+   *
+   * @generated from field: string data = 2;
+   */
+  data = "";
+
+  constructor(data?: PartialMessage<AvailablePackageDetailChunk>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName = "kubeappsapis.core.packages.v1alpha1.AvailablePackageDetailChunk";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "field", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 2, name: "data", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): AvailablePackageDetailChunk {
+    return new AvailablePackageDetailChunk().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): AvailablePackageDetailChunk {
+    return new AvailablePackageDetailChunk().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): AvailablePackageDetailChunk {
+    return new AvailablePackageDetailChunk().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a: AvailablePackageDetailChunk | PlainMessage<AvailablePackageDetailChunk> | undefined,
+    b: AvailablePackageDetailChunk | PlainMessage<AvailablePackageDetailChunk> | undefined,
+  ): boolean {
+    return proto3.util.equals(AvailablePackageDetailChunk, a, b);
+  }
+}
+
 /**
  * GetAvailablePackageVersionsResponse
  *
@@ -1114,6 +1446,16 @@ export class GetInstalledPackageSummariesResponse extends Message<GetInstalledPa
    */
   nextPageToken = "";
 
+  /**
+   * Warnings
+   *
+   * The plugins which failed to return their summaries when partial results
+   * were requested. The summaries of these plugins are missing from the results.
+   *
+   * @generated from field: repeated kubeappsapis.core.packages.v1alpha1.PluginWarning warnings = 3;
+   */
+  warnings: PluginWarning[] = [];
+
   constructor(data?: PartialMessage<GetInstalledPackageSummariesResponse>) {
     super();
     proto3.util.initPartial(data, this);
@@ -1131,6 +1473,7 @@ export class GetInstalledPackageSummariesResponse extends Message<GetInstalledPa
       repeated: true,
     },
     { no: 2, name: "next_page_token", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 3, name: "warnings", kind: "message", T: PluginWarning, repeated: true },
   ]);
 
   static fromBinary(
@@ -1467,6 +1810,75 @@ export class GetInstalledPackageResourceRefsResponse extends Message<GetInstalle
   }
 }
 
+/**
+ * StreamInstalledPackageResourceRefsResponse
+ *
+ * Response for StreamInstalledPackageResourceRefs. The references of the
+ * resources of the installed package are sent in batches, as they are
+ * discovered, so that the resource tree can be rendered progressively. The
+ * references of all the responses make up those of the installed package.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.StreamInstalledPackageResourceRefsResponse
+ */
+export class StreamInstalledPackageResourceRefsResponse extends Message<StreamInstalledPackageResourceRefsResponse> {
+  /**
+   * @generated from field: kubeappsapis.core.packages.v1alpha1.Context context = 1;
+   */
+  context?: Context;
+
+  /**
+   * @generated from field: repeated kubeappsapis.core.packages.v1alpha1.ResourceRef resource_refs = 2;
+   */
+  resourceRefs: ResourceRef[] = [];
+
+  constructor(data?: PartialMessage<StreamInstalledPackageResourceRefsResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.StreamInstalledPackageResourceRefsResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "context", kind: "message", T: Context },
+    { no: 2, name: "resource_refs", kind: "message", T: ResourceRef, repeated: true },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): StreamInstalledPackageResourceRefsResponse {
+    return new StreamInstalledPackageResourceRefsResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): StreamInstalledPackageResourceRefsResponse {
+    return new StreamInstalledPackageResourceRefsResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): StreamInstalledPackageResourceRefsResponse {
+    return new StreamInstalledPackageResourceRefsResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | StreamInstalledPackageResourceRefsResponse
+      | PlainMessage<StreamInstalledPackageResourceRefsResponse>
+      | undefined,
+    b:
+      | StreamInstalledPackageResourceRefsResponse
+      | PlainMessage<StreamInstalledPackageResourceRefsResponse>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(StreamInstalledPackageResourceRefsResponse, a, b);
+  }
+}
+
 /**
  * AvailablePackageSummary
  *
@@ -2479,6 +2891,73 @@ export class FilterOptions extends Message<FilterOptions> {
   }
 }
 
+/**
+ * PluginWarning
+ *
+ * A plugin which failed to return its results for an aggregated request.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.PluginWarning
+ */
+export class PluginWarning extends Message<PluginWarning> {
+  /**
+   * Plugin
+   *
+   * The plugin which failed.
+   *
+   * @generated from field: kubeappsapis.core.plugins.v1alpha1.Plugin plugin = 1;
+   */
+  plugin?: Plugin;
+
+  /**
+   * Message
+   *
+   * The error returned by the plugin.
+   *
+   * @generated from field: string message = 2;
+   */
+  message = "";
+
+  constructor(data?: PartialMessage<PluginWarning>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName = "kubeappsapis.core.packages.v1alpha1.PluginWarning";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "plugin", kind: "message", T: Plugin },
+    { no: 2, name: "message", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): PluginWarning {
+    return new PluginWarning().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): PluginWarning {
+    return new PluginWarning().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): PluginWarning {
+    return new PluginWarning().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a: PluginWarning | PlainMessage<PluginWarning> | undefined,
+    b: PluginWarning | PlainMessage<PluginWarning> | undefined,
+  ): boolean {
+    return proto3.util.equals(PluginWarning, a, b);
+  }
+}
+
 /**
  * PaginationOptions
  *
@@ -17,8 +17,13 @@ import {
   GetPackageRepositoryPermissionsResponse,
   GetPackageRepositorySummariesRequest,
   GetPackageRepositorySummariesResponse,
+  GetRepositoryTypesRequest,
+  GetRepositoryTypesResponse,
+  InvalidatePackageRepositoryCacheRequest,
+  InvalidatePackageRepositoryCacheResponse,
   UpdatePackageRepositoryRequest,
   UpdatePackageRepositoryResponse,
+  ValidatePackageRepositoryResponse,
 } from "./repositories_pb";
 import { MethodKind } from "@bufbuild/protobuf";
 
@@ -85,5 +90,32 @@ export const RepositoriesService = {
       O: GetPackageRepositoryPermissionsResponse,
       kind: MethodKind.Unary,
     },
+    /**
+     * @generated from rpc kubeappsapis.core.packages.v1alpha1.RepositoriesService.InvalidatePackageRepositoryCache
+     */
+    invalidatePackageRepositoryCache: {
+      name: "InvalidatePackageRepositoryCache",
+      I: InvalidatePackageRepositoryCacheRequest,
+      O: InvalidatePackageRepositoryCacheResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.core.packages.v1alpha1.RepositoriesService.GetRepositoryTypes
+     */
+    getRepositoryTypes: {
+      name: "GetRepositoryTypes",
+      I: GetRepositoryTypesRequest,
+      O: GetRepositoryTypesResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.core.packages.v1alpha1.RepositoriesService.ValidatePackageRepository
+     */
+    validatePackageRepository: {
+      name: "ValidatePackageRepository",
+      I: AddPackageRepositoryRequest,
+      O: ValidatePackageRepositoryResponse,
+      kind: MethodKind.Unary,
+    },
   },
 } as const;
@@ -2136,3 +2136,362 @@ export class GetPackageRepositoryPermissionsResponse extends Message<GetPackageR
     return proto3.util.equals(GetPackageRepositoryPermissionsResponse, a, b);
   }
 }
+
+/**
+ * InvalidatePackageRepositoryCacheRequest
+ *
+ * Request for InvalidatePackageRepositoryCache
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.InvalidatePackageRepositoryCacheRequest
+ */
+export class InvalidatePackageRepositoryCacheRequest extends Message<InvalidatePackageRepositoryCacheRequest> {
+  /**
+   * A reference uniquely identifying the package repository whose cached
+   * metadata should be discarded by the plugin serving it.
+   *
+   * @generated from field: kubeappsapis.core.packages.v1alpha1.PackageRepositoryReference package_repo_ref = 1;
+   */
+  packageRepoRef?: PackageRepositoryReference;
+
+  constructor(data?: PartialMessage<InvalidatePackageRepositoryCacheRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.InvalidatePackageRepositoryCacheRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "package_repo_ref", kind: "message", T: PackageRepositoryReference },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): InvalidatePackageRepositoryCacheRequest {
+    return new InvalidatePackageRepositoryCacheRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): InvalidatePackageRepositoryCacheRequest {
+    return new InvalidatePackageRepositoryCacheRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): InvalidatePackageRepositoryCacheRequest {
+    return new InvalidatePackageRepositoryCacheRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | InvalidatePackageRepositoryCacheRequest
+      | PlainMessage<InvalidatePackageRepositoryCacheRequest>
+      | undefined,
+    b:
+      | InvalidatePackageRepositoryCacheRequest
+      | PlainMessage<InvalidatePackageRepositoryCacheRequest>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(InvalidatePackageRepositoryCacheRequest, a, b);
+  }
+}
+
+/**
+ * InvalidatePackageRepositoryCacheResponse
+ *
+ * Response for InvalidatePackageRepositoryCache
+ *
+ * For future extensibility only.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.InvalidatePackageRepositoryCacheResponse
+ */
+export class InvalidatePackageRepositoryCacheResponse extends Message<InvalidatePackageRepositoryCacheResponse> {
+  constructor(data?: PartialMessage<InvalidatePackageRepositoryCacheResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.InvalidatePackageRepositoryCacheResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => []);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): InvalidatePackageRepositoryCacheResponse {
+    return new InvalidatePackageRepositoryCacheResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): InvalidatePackageRepositoryCacheResponse {
+    return new InvalidatePackageRepositoryCacheResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): InvalidatePackageRepositoryCacheResponse {
+    return new InvalidatePackageRepositoryCacheResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | InvalidatePackageRepositoryCacheResponse
+      | PlainMessage<InvalidatePackageRepositoryCacheResponse>
+      | undefined,
+    b:
+      | InvalidatePackageRepositoryCacheResponse
+      | PlainMessage<InvalidatePackageRepositoryCacheResponse>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(InvalidatePackageRepositoryCacheResponse, a, b);
+  }
+}
+
+/**
+ * GetRepositoryTypesRequest
+ *
+ * Request for GetRepositoryTypes
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.GetRepositoryTypesRequest
+ */
+export class GetRepositoryTypesRequest extends Message<GetRepositoryTypesRequest> {
+  /**
+   * The context (cluster/namespace) for the request
+   *
+   * @generated from field: kubeappsapis.core.packages.v1alpha1.Context context = 1;
+   */
+  context?: Context;
+
+  constructor(data?: PartialMessage<GetRepositoryTypesRequest>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName = "kubeappsapis.core.packages.v1alpha1.GetRepositoryTypesRequest";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "context", kind: "message", T: Context },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): GetRepositoryTypesRequest {
+    return new GetRepositoryTypesRequest().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): GetRepositoryTypesRequest {
+    return new GetRepositoryTypesRequest().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): GetRepositoryTypesRequest {
+    return new GetRepositoryTypesRequest().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a: GetRepositoryTypesRequest | PlainMessage<GetRepositoryTypesRequest> | undefined,
+    b: GetRepositoryTypesRequest | PlainMessage<GetRepositoryTypesRequest> | undefined,
+  ): boolean {
+    return proto3.util.equals(GetRepositoryTypesRequest, a, b);
+  }
+}
+
+/**
+ * PackageRepositoryTypes
+ *
+ * The types of package repositories, such as "helm" or "oci", which can be
+ * added with a plugin.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.PackageRepositoryTypes
+ */
+export class PackageRepositoryTypes extends Message<PackageRepositoryTypes> {
+  /**
+   * @generated from field: kubeappsapis.core.plugins.v1alpha1.Plugin plugin = 1;
+   */
+  plugin?: Plugin;
+
+  /**
+   * @generated from field: repeated string types = 2;
+   */
+  types: string[] = [];
+
+  constructor(data?: PartialMessage<PackageRepositoryTypes>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName = "kubeappsapis.core.packages.v1alpha1.PackageRepositoryTypes";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "plugin", kind: "message", T: Plugin },
+    { no: 2, name: "types", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): PackageRepositoryTypes {
+    return new PackageRepositoryTypes().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): PackageRepositoryTypes {
+    return new PackageRepositoryTypes().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): PackageRepositoryTypes {
+    return new PackageRepositoryTypes().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a: PackageRepositoryTypes | PlainMessage<PackageRepositoryTypes> | undefined,
+    b: PackageRepositoryTypes | PlainMessage<PackageRepositoryTypes> | undefined,
+  ): boolean {
+    return proto3.util.equals(PackageRepositoryTypes, a, b);
+  }
+}
+
+/**
+ * GetRepositoryTypesResponse
+ *
+ * Response for GetRepositoryTypes
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.GetRepositoryTypesResponse
+ */
+export class GetRepositoryTypesResponse extends Message<GetRepositoryTypesResponse> {
+  /**
+   * @generated from field: repeated kubeappsapis.core.packages.v1alpha1.PackageRepositoryTypes repository_types = 1;
+   */
+  repositoryTypes: PackageRepositoryTypes[] = [];
+
+  constructor(data?: PartialMessage<GetRepositoryTypesResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName = "kubeappsapis.core.packages.v1alpha1.GetRepositoryTypesResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "repository_types", kind: "message", T: PackageRepositoryTypes, repeated: true },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): GetRepositoryTypesResponse {
+    return new GetRepositoryTypesResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): GetRepositoryTypesResponse {
+    return new GetRepositoryTypesResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): GetRepositoryTypesResponse {
+    return new GetRepositoryTypesResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a: GetRepositoryTypesResponse | PlainMessage<GetRepositoryTypesResponse> | undefined,
+    b: GetRepositoryTypesResponse | PlainMessage<GetRepositoryTypesResponse> | undefined,
+  ): boolean {
+    return proto3.util.equals(GetRepositoryTypesResponse, a, b);
+  }
+}
+
+/**
+ * ValidatePackageRepositoryResponse
+ *
+ * Response for ValidatePackageRepository, which checks that the package
+ * repository of an AddPackageRepositoryRequest can be reached with its
+ * credentials, without adding it.
+ *
+ * @generated from message kubeappsapis.core.packages.v1alpha1.ValidatePackageRepositoryResponse
+ */
+export class ValidatePackageRepositoryResponse extends Message<ValidatePackageRepositoryResponse> {
+  /**
+   * Whether the metadata of the package repository could be fetched.
+   *
+   * @generated from field: bool valid = 1;
+   */
+  valid = false;
+
+  /**
+   * The reason why the package repository is not valid, such as the error
+   * returned when fetching its metadata. Empty when valid.
+   *
+   * @generated from field: string reason = 2;
+   */
+  reason = "";
+
+  constructor(data?: PartialMessage<ValidatePackageRepositoryResponse>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName =
+    "kubeappsapis.core.packages.v1alpha1.ValidatePackageRepositoryResponse";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "valid", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
+    { no: 2, name: "reason", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
+
+  static fromBinary(
+    bytes: Uint8Array,
+    options?: Partial<BinaryReadOptions>,
+  ): ValidatePackageRepositoryResponse {
+    return new ValidatePackageRepositoryResponse().fromBinary(bytes, options);
+  }
+
+  static fromJson(
+    jsonValue: JsonValue,
+    options?: Partial<JsonReadOptions>,
+  ): ValidatePackageRepositoryResponse {
+    return new ValidatePackageRepositoryResponse().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): ValidatePackageRepositoryResponse {
+    return new ValidatePackageRepositoryResponse().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a:
+      | ValidatePackageRepositoryResponse
+      | PlainMessage<ValidatePackageRepositoryResponse>
+      | undefined,
+    b:
+      | ValidatePackageRepositoryResponse
+      | PlainMessage<ValidatePackageRepositoryResponse>
+      | undefined,
+  ): boolean {
+    return proto3.util.equals(ValidatePackageRepositoryResponse, a, b);
+  }
+}
@@ -24,6 +24,15 @@ import { Message, proto3 } from "@bufbuild/protobuf";
  * @generated from message kubeappsapis.core.plugins.v1alpha1.GetConfiguredPluginsRequest
  */
 export class GetConfiguredPluginsRequest extends Message<GetConfiguredPluginsRequest> {
+  /**
+   * Namespace
+   *
+   * Optional namespace restricting the plugins returned to those enabled in it.
+   *
+   * @generated from field: string namespace = 1;
+   */
+  namespace = "";
+
   constructor(data?: PartialMessage<GetConfiguredPluginsRequest>) {
     super();
     proto3.util.initPartial(data, this);
@@ -31,7 +40,9 @@ export class GetConfiguredPluginsRequest extends Message<GetConfiguredPluginsReq
 
   static readonly runtime: typeof proto3 = proto3;
   static readonly typeName = "kubeappsapis.core.plugins.v1alpha1.GetConfiguredPluginsRequest";
-  static readonly fields: FieldList = proto3.util.newFieldList(() => []);
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "namespace", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+  ]);
 
   static fromBinary(
     bytes: Uint8Array,
@@ -145,6 +156,16 @@ export class Plugin extends Message<Plugin> {
    */
   version = "";
 
+  /**
+   * Plugin capabilities
+   *
+   * The capabilities declared by the plugin when it was registered. Only set in
+   * the GetConfiguredPlugins response, and unset for plugins declaring none.
+   *
+   * @generated from field: kubeappsapis.core.plugins.v1alpha1.PluginCapabilities capabilities = 3;
+   */
+  capabilities?: PluginCapabilities;
+
   constructor(data?: PartialMessage<Plugin>) {
     super();
     proto3.util.initPartial(data, this);
@@ -155,6 +176,7 @@ export class Plugin extends Message<Plugin> {
   static readonly fields: FieldList = proto3.util.newFieldList(() => [
     { no: 1, name: "name", kind: "scalar", T: 9 /* ScalarType.STRING */ },
     { no: 2, name: "version", kind: "scalar", T: 9 /* ScalarType.STRING */ },
+    { no: 3, name: "capabilities", kind: "message", T: PluginCapabilities },
   ]);
 
   static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): Plugin {
@@ -176,3 +198,86 @@ export class Plugin extends Message<Plugin> {
     return proto3.util.equals(Plugin, a, b);
   }
 }
+
+/**
+ * PluginCapabilities
+ *
+ * The capabilities declared by a plugin, so that clients can tailor their UI
+ * to each plugin without hardcoded knowledge of it.
+ *
+ * @generated from message kubeappsapis.core.plugins.v1alpha1.PluginCapabilities
+ */
+export class PluginCapabilities extends Message<PluginCapabilities> {
+  /**
+   * Supports dry-run
+   *
+   * Whether the plugin can validate writes without applying them.
+   *
+   * @generated from field: bool supports_dry_run = 1;
+   */
+  supportsDryRun = false;
+
+  /**
+   * Supports watch
+   *
+   * Whether the plugin can stream updates of the resources it returns.
+   *
+   * @generated from field: bool supports_watch = 2;
+   */
+  supportsWatch = false;
+
+  /**
+   * Supports OCI
+   *
+   * Whether the plugin supports package repositories in OCI registries.
+   *
+   * @generated from field: bool supports_oci = 3;
+   */
+  supportsOci = false;
+
+  /**
+   * Auth types
+   *
+   * The names of the package repository auth types supported by the plugin,
+   * such as `PACKAGE_REPOSITORY_AUTH_TYPE_BASIC_AUTH`.
+   *
+   * @generated from field: repeated string auth_types = 4;
+   */
+  authTypes: string[] = [];
+
+  constructor(data?: PartialMessage<PluginCapabilities>) {
+    super();
+    proto3.util.initPartial(data, this);
+  }
+
+  static readonly runtime: typeof proto3 = proto3;
+  static readonly typeName = "kubeappsapis.core.plugins.v1alpha1.PluginCapabilities";
+  static readonly fields: FieldList = proto3.util.newFieldList(() => [
+    { no: 1, name: "supports_dry_run", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
+    { no: 2, name: "supports_watch", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
+    { no: 3, name: "supports_oci", kind: "scalar", T: 8 /* ScalarType.BOOL */ },
+    { no: 4, name: "auth_types", kind: "scalar", T: 9 /* ScalarType.STRING */, repeated: true },
+  ]);
+
+  static fromBinary(bytes: Uint8Array, options?: Partial<BinaryReadOptions>): PluginCapabilities {
+    return new PluginCapabilities().fromBinary(bytes, options);
+  }
+
+  static fromJson(jsonValue: JsonValue, options?: Partial<JsonReadOptions>): PluginCapabilities {
+    return new PluginCapabilities().fromJson(jsonValue, options);
+  }
+
+  static fromJsonString(
+    jsonString: string,
+    options?: Partial<JsonReadOptions>,
+  ): PluginCapabilities {
+    return new PluginCapabilities().fromJsonString(jsonString, options);
+  }
+
+  static equals(
+    a: PluginCapabilities | PlainMessage<PluginCapabilities> | undefined,
+    b: PluginCapabilities | PlainMessage<PluginCapabilities> | undefined,
+  ): boolean {
+    return proto3.util.equals(PluginCapabilities, a, b);
+  }
+}
@@ -38,8 +38,13 @@ import {
   GetPackageRepositoryPermissionsResponse,
   GetPackageRepositorySummariesRequest,
   GetPackageRepositorySummariesResponse,
+  GetRepositoryTypesRequest,
+  GetRepositoryTypesResponse,
+  InvalidatePackageRepositoryCacheRequest,
+  InvalidatePackageRepositoryCacheResponse,
   UpdatePackageRepositoryRequest,
   UpdatePackageRepositoryResponse,
+  ValidatePackageRepositoryResponse,
 } from "../../../../core/packages/v1alpha1/repositories_pb";
 
 /**
@@ -214,5 +219,32 @@ export const FluxV2RepositoriesService = {
       O: GetPackageRepositoryPermissionsResponse,
       kind: MethodKind.Unary,
     },
+    /**
+     * @generated from rpc kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.InvalidatePackageRepositoryCache
+     */
+    invalidatePackageRepositoryCache: {
+      name: "InvalidatePackageRepositoryCache",
+      I: InvalidatePackageRepositoryCacheRequest,
+      O: InvalidatePackageRepositoryCacheResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.GetRepositoryTypes
+     */
+    getRepositoryTypes: {
+      name: "GetRepositoryTypes",
+      I: GetRepositoryTypesRequest,
+      O: GetRepositoryTypesResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.plugins.fluxv2.packages.v1alpha1.FluxV2RepositoriesService.ValidatePackageRepository
+     */
+    validatePackageRepository: {
+      name: "ValidatePackageRepository",
+      I: AddPackageRepositoryRequest,
+      O: ValidatePackageRepositoryResponse,
+      kind: MethodKind.Unary,
+    },
   },
 } as const;
@@ -39,8 +39,13 @@ import {
   GetPackageRepositoryPermissionsResponse,
   GetPackageRepositorySummariesRequest,
   GetPackageRepositorySummariesResponse,
+  GetRepositoryTypesRequest,
+  GetRepositoryTypesResponse,
+  InvalidatePackageRepositoryCacheRequest,
+  InvalidatePackageRepositoryCacheResponse,
   UpdatePackageRepositoryRequest,
   UpdatePackageRepositoryResponse,
+  ValidatePackageRepositoryResponse,
 } from "../../../../core/packages/v1alpha1/repositories_pb";
 
 /**
@@ -225,5 +230,32 @@ export const HelmRepositoriesService = {
       O: GetPackageRepositoryPermissionsResponse,
       kind: MethodKind.Unary,
     },
+    /**
+     * @generated from rpc kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.InvalidatePackageRepositoryCache
+     */
+    invalidatePackageRepositoryCache: {
+      name: "InvalidatePackageRepositoryCache",
+      I: InvalidatePackageRepositoryCacheRequest,
+      O: InvalidatePackageRepositoryCacheResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.GetRepositoryTypes
+     */
+    getRepositoryTypes: {
+      name: "GetRepositoryTypes",
+      I: GetRepositoryTypesRequest,
+      O: GetRepositoryTypesResponse,
+      kind: MethodKind.Unary,
+    },
+    /**
+     * @generated from rpc kubeappsapis.plugins.helm.packages.v1alpha1.HelmRepositoriesService.ValidatePackageRepository
+     */
+    validatePackageRepository: {
+      name: "ValidatePackageRepository",
+      I: AddPackageRepositoryRequest,
+      O: ValidatePackageRepositoryResponse,
+      kind: MethodKind.Unary,
+    },
   },
 } as const;
@@ -6,11 +6,15 @@ package kube
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 )
 
@@ -101,9 +105,9 @@ func NewClusterConfig(inClusterConfig *rest.Config, userToken string, cluster st
 		return config, nil
 	}
 
-	clusterConfig, ok := clustersConfig.Clusters[cluster]
-	if !ok {
-		return nil, fmt.Errorf("cluster %q has no configuration", cluster)
+	clusterConfig, err := clustersConfig.Lookup(cluster)
+	if err != nil {
+		return nil, err
 	}
 
 	if userToken != "" && (clusterConfig.PinnipedConfig.Enabled || clusterConfig.PinnipedConfig.Enable) {
@@ -178,6 +182,12 @@ func ParseClusterConfig(configPath, caFilesPrefix string, pinnipedProxyURL, Pinn
 	configs.PinnipedProxyURL = pinnipedProxyURL
 	configs.PinnipedProxyCACert = PinnipedProxyCACert
 	for _, c := range clusterConfigs {
+		if c.Name == "" {
+			return ClustersConfig{}, deferFn, fmt.Errorf("every cluster must be configured with a name, found one with apiServiceURL %q", c.APIServiceURL)
+		}
+		if _, ok := configs.Clusters[c.Name]; ok {
+			return ClustersConfig{}, deferFn, fmt.Errorf("cluster %q is configured more than once", c.Name)
+		}
 		// Select the cluster in which Kubeapps in installed. We look for either
 		// `isKubeappsCluster: true` or an empty `APIServiceURL`.
 		isKubeappsClusterCandidate := c.IsKubeappsCluster || c.APIServiceURL == ""
@@ -219,6 +229,50 @@ func ParseClusterConfig(configPath, caFilesPrefix string, pinnipedProxyURL, Pinn
 	return configs, deferFn, nil
 }
 
+// Names returns the sorted names of the configured clusters.
+func (c ClustersConfig) Names() []string {
+	names := make([]string, 0, len(c.Clusters))
+	for name := range c.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the configuration of the named cluster, failing with the names
+// of the configured clusters when it is not one of them.
+func (c ClustersConfig) Lookup(cluster string) (ClusterConfig, error) {
+	clusterConfig, ok := c.Clusters[cluster]
+	if !ok {
+		return ClusterConfig{}, fmt.Errorf("cluster %q has no configuration, the configured clusters are %q", cluster, c.Names())
+	}
+	return clusterConfig, nil
+}
+
+// ValidateClusters checks that the API server of each configured cluster is
+// reachable, requesting its version, which does not require credentials. The
+// errors of every unreachable cluster are returned together.
+func ValidateClusters(inClusterConfig *rest.Config, clustersConfig ClustersConfig, timeout time.Duration) error {
+	errs := []error{}
+	for _, name := range clustersConfig.Names() {
+		config, err := NewClusterConfig(inClusterConfig, "", name, clustersConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		config.Timeout = timeout
+		client, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to create a client for cluster %q: %w", name, err))
+			continue
+		}
+		if _, err := client.ServerVersion(); err != nil {
+			errs = append(errs, fmt.Errorf("cluster %q is unreachable at %q: %w", name, config.Host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // IsKubeappsClusterRef checks if the provided cluster name references the global packaging Kubeapps cluster
 func IsKubeappsClusterRef(cluster string) bool {
 	return cluster == "" || cluster == KUBEAPPS_GLOBAL_PACKAGING_CLUSTER_TOKEN
@@ -5,9 +5,12 @@ package kube
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -437,6 +440,23 @@ func TestParseClusterConfig(t *testing.T) {
 		]`,
 			expectedErr: true,
 		},
+		{
+			name: "errors if a cluster is configured without a name",
+			configJSON: `[
+       {"name": "cluster-1" },
+       {"apiServiceURL": "https://example.com/cluster-2"}
+]`,
+			expectedErr: true,
+		},
+		{
+			name: "errors if a cluster is configured more than once",
+			configJSON: `[
+       {"name": "cluster-1" },
+       {"name": "cluster-2", "apiServiceURL": "https://example.com/cluster-2"},
+       {"name": "cluster-2", "apiServiceURL": "https://example.com/cluster-3"}
+]`,
+			expectedErr: true,
+		},
 		{
 			name: "errors if both no APIServiceURL and isKubeappsCluster=true are configured",
 			configJSON: `[
@@ -485,6 +505,90 @@ func TestParseClusterConfig(t *testing.T) {
 	}
 }
 
+func TestClustersConfigLookup(t *testing.T) {
+	clustersConfig := ClustersConfig{
+		KubeappsClusterName: "default",
+		Clusters: map[string]ClusterConfig{
+			"default":   {Name: "default"},
+			"cluster-2": {Name: "cluster-2", APIServiceURL: "https://example.com"},
+		},
+	}
+
+	t.Run("returns the config of a configured cluster", func(t *testing.T) {
+		config, err := clustersConfig.Lookup("cluster-2")
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if got, want := config, clustersConfig.Clusters["cluster-2"]; !cmp.Equal(got, want) {
+			t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("errors with the configured clusters for an unknown cluster", func(t *testing.T) {
+		_, err := clustersConfig.Lookup("cluster-3")
+		if err == nil {
+			t.Fatalf("got: nil, want: error")
+		}
+		if got, want := err.Error(), `cluster "cluster-3" has no configuration, the configured clusters are ["cluster-2" "default"]`; got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+}
+
+func TestValidateClusters(t *testing.T) {
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"major": "1", "minor": "27", "gitVersion": "v1.27.0"}`))
+	}))
+	defer reachable.Close()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := unreachable.URL
+	unreachable.Close()
+
+	testCases := []struct {
+		name          string
+		clusters      map[string]ClusterConfig
+		expectedError bool
+	}{
+		{
+			name: "succeeds when every cluster is reachable",
+			clusters: map[string]ClusterConfig{
+				"default":   {Name: "default"},
+				"cluster-2": {Name: "cluster-2", APIServiceURL: reachable.URL},
+			},
+		},
+		{
+			name: "errors when a cluster is unreachable",
+			clusters: map[string]ClusterConfig{
+				"default":   {Name: "default"},
+				"cluster-2": {Name: "cluster-2", APIServiceURL: unreachableURL},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clustersConfig := ClustersConfig{
+				KubeappsClusterName: "default",
+				Clusters:            tc.clusters,
+			}
+			err := ValidateClusters(&rest.Config{Host: reachable.URL}, clustersConfig, time.Second)
+			if got, want := err != nil, tc.expectedError; got != want {
+				t.Fatalf("got: %t, want: %t: err: %+v", got, want, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), `cluster "cluster-2" is unreachable`) {
+				t.Errorf("got: %q, want the unreachable cluster", err.Error())
+			}
+		})
+	}
+}
+
 func createConfigFile(t *testing.T, content string) string {
 	tmpfile, err := os.CreateTemp("", "")
 	if err != nil {